@@ -0,0 +1,23 @@
+// Package access enforces the roles of users in front of the management and
+// workflow services. The services it returns act as a user, and only
+// perform the operations the user's role allows. Other operations fail with
+// an *api.PermissionDeniedError.
+//
+// Decisions made with Slack buttons, email decision links and email replies
+// do not go through this package. They are made by the approver the
+// request was sent to, who need not be a user, and the workflow service
+// only accepts them from that approver.
+package access
+
+import (
+	"github.com/KatrinSalt/backend-challenge-go/api"
+)
+
+// authorize returns an *api.PermissionDeniedError if the user's role does
+// not allow the action.
+func authorize(user api.User, action api.Action) error {
+	if user.Allows(action) {
+		return nil
+	}
+	return &api.PermissionDeniedError{User: user.Email, Role: user.Role, Action: action}
+}
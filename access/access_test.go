@@ -0,0 +1,194 @@
+package access
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestManagement(t *testing.T) {
+	operations := map[string]func(svc management.Service) error{
+		"list approvers": func(svc management.Service) error {
+			_, err := svc.ListApprovers()
+			return err
+		},
+		"update workflow rule": func(svc management.Service) error {
+			return svc.UpdateWorkflowRule(api.WorkflowRule{ID: 1})
+		},
+		"delete approver": func(svc management.Service) error {
+			return svc.DeleteApprover(1)
+		},
+		"delete company": func(svc management.Service) error {
+			return svc.DeleteCompany(1)
+		},
+		"replay dead letter": func(svc management.Service) error {
+			_, err := svc.ReplayDeadLetter(1)
+			return err
+		},
+		"create user": func(svc management.Service) error {
+			_, err := svc.CreateUser(api.User{Name: "Auditor", Email: "auditor@light.com", Role: api.RoleViewer})
+			return err
+		},
+	}
+
+	tests := []struct {
+		role api.Role
+		want []string
+	}{
+		{role: api.RoleAdmin, want: []string{"create user", "delete approver", "delete company", "list approvers", "replay dead letter", "update workflow rule"}},
+		{role: api.RoleRuleEditor, want: []string{"list approvers", "update workflow rule"}},
+		{role: api.RoleApprover, want: []string{"list approvers"}},
+		{role: api.RoleSubmitter, want: []string{"list approvers"}},
+		{role: api.RoleViewer, want: []string{"list approvers"}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.role), func(t *testing.T) {
+			mock := &mockManagementService{}
+			svc := Management(mock, api.User{ID: 1, Email: "user@light.com", Role: test.role})
+
+			for _, name := range slices.Sorted(maps.Keys(operations)) {
+				err := operations[name](svc)
+				allowed := slices.Contains(test.want, name)
+				if allowed && err != nil {
+					t.Errorf("%s: unexpected error: %v", name, err)
+				}
+				if !allowed {
+					var denied *api.PermissionDeniedError
+					if !errors.As(err, &denied) || !errors.Is(err, api.ErrPermissionDenied) {
+						t.Errorf("%s: error = %v, want a permission denied error", name, err)
+					} else if denied.User != "user@light.com" || denied.Role != test.role {
+						t.Errorf("%s: denied %+v, want user@light.com with role %s", name, denied, test.role)
+					}
+				}
+			}
+			if diff := cmp.Diff(test.want, mock.calls); diff != "" {
+				t.Errorf("calls mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkflow(t *testing.T) {
+	tests := []struct {
+		role api.Role
+		want []string
+	}{
+		{role: api.RoleAdmin, want: []string{"ProcessInvoice", "RecordDecision", "ApprovalRequestStatus"}},
+		{role: api.RoleRuleEditor, want: []string{"ApprovalRequestStatus"}},
+		{role: api.RoleApprover, want: []string{"RecordDecision", "ApprovalRequestStatus"}},
+		{role: api.RoleSubmitter, want: []string{"ProcessInvoice", "ApprovalRequestStatus"}},
+		{role: api.RoleViewer, want: []string{"ApprovalRequestStatus"}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.role), func(t *testing.T) {
+			mock := &mockWorkflowService{}
+			svc := Workflow(mock, api.User{ID: 1, Email: "user@light.com", Role: test.role})
+
+			_, processErr := svc.ProcessInvoice(api.InvoiceRequest{})
+			_, decideErr := svc.RecordDecision(api.ApprovalDecision{})
+			_, statusErr := svc.ApprovalRequestStatus(1)
+
+			for _, err := range []error{processErr, decideErr, statusErr} {
+				if err != nil && !errors.Is(err, api.ErrPermissionDenied) {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+			if diff := cmp.Diff(test.want, mock.calls); diff != "" {
+				t.Errorf("calls mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkflowRecordsUser(t *testing.T) {
+	mock := &mockWorkflowService{}
+	svc := Workflow(mock, api.User{ID: 1, Email: "user@light.com", Role: api.RoleAdmin})
+
+	if _, err := svc.ProcessInvoice(api.InvoiceRequest{SubmittedBy: "someone@light.com"}); err != nil {
+		t.Fatalf("ProcessInvoice() unexpected error: %v", err)
+	}
+	if _, err := svc.RecordDecision(api.ApprovalDecision{}); err != nil {
+		t.Fatalf("RecordDecision() unexpected error: %v", err)
+	}
+
+	if mock.submittedBy != "user@light.com" {
+		t.Errorf("ProcessInvoice() submitted by %q, want the user", mock.submittedBy)
+	}
+	if mock.decidedBy != "user@light.com" {
+		t.Errorf("RecordDecision() decided by %q, want the user", mock.decidedBy)
+	}
+}
+
+// mockManagementService records the names of the operations called on it.
+type mockManagementService struct {
+	management.Service
+	calls []string
+}
+
+func (m *mockManagementService) ListApprovers() ([]api.Approver, error) {
+	m.calls = append(m.calls, "list approvers")
+	return nil, nil
+}
+
+func (m *mockManagementService) UpdateWorkflowRule(rule api.WorkflowRule) error {
+	m.calls = append(m.calls, "update workflow rule")
+	return nil
+}
+
+func (m *mockManagementService) DeleteApprover(id int) error {
+	m.calls = append(m.calls, "delete approver")
+	return nil
+}
+
+func (m *mockManagementService) DeleteCompany(id int) error {
+	m.calls = append(m.calls, "delete company")
+	return nil
+}
+
+func (m *mockManagementService) GetOutboxMessageByID(id int) (api.OutboxMessage, error) {
+	m.calls = append(m.calls, "get outbox message")
+	return api.OutboxMessage{}, nil
+}
+
+func (m *mockManagementService) ReplayDeadLetter(id int) (api.OutboxMessage, error) {
+	m.calls = append(m.calls, "replay dead letter")
+	return api.OutboxMessage{}, nil
+}
+
+func (m *mockManagementService) CreateUser(user api.User) (api.User, error) {
+	m.calls = append(m.calls, "create user")
+	return user, nil
+}
+
+// mockWorkflowService records the names of the methods called on it.
+type mockWorkflowService struct {
+	workflow.Service
+	calls       []string
+	submittedBy string
+	decidedBy   string
+}
+
+func (m *mockWorkflowService) ProcessInvoice(invoice api.InvoiceRequest) (api.ApprovalResponse, error) {
+	m.calls = append(m.calls, "ProcessInvoice")
+	m.submittedBy = invoice.SubmittedBy
+	return api.ApprovalResponse{}, nil
+}
+
+func (m *mockWorkflowService) RecordDecision(decision api.ApprovalDecision) (api.ApprovalRequest, error) {
+	m.calls = append(m.calls, "RecordDecision")
+	m.decidedBy = decision.User
+	return api.ApprovalRequest{}, nil
+}
+
+func (m *mockWorkflowService) ApprovalRequestStatus(id int) (api.ApprovalRequestStatus, error) {
+	m.calls = append(m.calls, "ApprovalRequestStatus")
+	return api.ApprovalRequestStatus{}, nil
+}
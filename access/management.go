@@ -0,0 +1,233 @@
+package access
+
+import (
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+)
+
+// managementService is a management service that acts as a user.
+type managementService struct {
+	svc  management.Service
+	user api.User
+}
+
+// Management returns the management service acting as the user.
+func Management(svc management.Service, user api.User) management.Service {
+	return &managementService{svc: svc, user: user}
+}
+
+func (s *managementService) CreateCompany(company api.Company) (api.Company, error) {
+	if err := authorize(s.user, api.ActionManageCompanies); err != nil {
+		return api.Company{}, err
+	}
+	return s.svc.CreateCompany(company)
+}
+
+func (s *managementService) GetCompanyByID(id int) (api.Company, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return api.Company{}, err
+	}
+	return s.svc.GetCompanyByID(id)
+}
+
+func (s *managementService) UpdateCompany(company api.Company) error {
+	if err := authorize(s.user, api.ActionManageCompanies); err != nil {
+		return err
+	}
+	return s.svc.UpdateCompany(company)
+}
+
+func (s *managementService) DeleteCompany(id int) error {
+	if err := authorize(s.user, api.ActionManageCompanies); err != nil {
+		return err
+	}
+	return s.svc.DeleteCompany(id)
+}
+
+func (s *managementService) ListCompanies() ([]api.Company, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.svc.ListCompanies()
+}
+
+func (s *managementService) AddDepartment(name string) (string, error) {
+	if err := authorize(s.user, api.ActionManageRules); err != nil {
+		return "", err
+	}
+	return s.svc.AddDepartment(name)
+}
+
+func (s *managementService) RemoveDepartment(name string) error {
+	if err := authorize(s.user, api.ActionManageRules); err != nil {
+		return err
+	}
+	return s.svc.RemoveDepartment(name)
+}
+
+func (s *managementService) ListDepartments() ([]string, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.svc.ListDepartments()
+}
+
+func (s *managementService) CreateWorkflowRule(rule api.WorkflowRule) (api.WorkflowRule, error) {
+	if err := authorize(s.user, api.ActionManageRules); err != nil {
+		return api.WorkflowRule{}, err
+	}
+	return s.svc.CreateWorkflowRule(rule)
+}
+
+func (s *managementService) GetWorkflowRuleByID(id int) (api.WorkflowRule, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return api.WorkflowRule{}, err
+	}
+	return s.svc.GetWorkflowRuleByID(id)
+}
+
+func (s *managementService) UpdateWorkflowRule(rule api.WorkflowRule) error {
+	if err := authorize(s.user, api.ActionManageRules); err != nil {
+		return err
+	}
+	return s.svc.UpdateWorkflowRule(rule)
+}
+
+func (s *managementService) DeleteWorkflowRule(id int) error {
+	if err := authorize(s.user, api.ActionManageRules); err != nil {
+		return err
+	}
+	return s.svc.DeleteWorkflowRule(id)
+}
+
+func (s *managementService) ListWorkflowRules() ([]api.WorkflowRule, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.svc.ListWorkflowRules()
+}
+
+func (s *managementService) CreateApprover(approver api.Approver) (api.Approver, error) {
+	if err := authorize(s.user, api.ActionManageApprovers); err != nil {
+		return api.Approver{}, err
+	}
+	return s.svc.CreateApprover(approver)
+}
+
+func (s *managementService) GetApproverByID(id int) (api.Approver, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return api.Approver{}, err
+	}
+	return s.svc.GetApproverByID(id)
+}
+
+func (s *managementService) UpdateApprover(approver api.Approver) error {
+	if err := authorize(s.user, api.ActionManageApprovers); err != nil {
+		return err
+	}
+	return s.svc.UpdateApprover(approver)
+}
+
+func (s *managementService) DeleteApprover(id int) error {
+	if err := authorize(s.user, api.ActionManageApprovers); err != nil {
+		return err
+	}
+	return s.svc.DeleteApprover(id)
+}
+
+func (s *managementService) ListApprovers() ([]api.Approver, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.svc.ListApprovers()
+}
+
+func (s *managementService) OrgChart(id int) ([]api.OrgChartNode, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.svc.OrgChart(id)
+}
+
+// RecordDeliveryAttempt records the attempt without a check. Attempts are
+// recorded by the notification channels, not by users.
+func (s *managementService) RecordDeliveryAttempt(attempt api.DeliveryAttempt) error {
+	return s.svc.RecordDeliveryAttempt(attempt)
+}
+
+func (s *managementService) ListDeliveryAttempts(approvalRequestID int) ([]api.DeliveryAttempt, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.svc.ListDeliveryAttempts(approvalRequestID)
+}
+
+func (s *managementService) ListOutboxMessages(status api.OutboxStatus) ([]api.OutboxMessage, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.svc.ListOutboxMessages(status)
+}
+
+func (s *managementService) GetOutboxMessageByID(id int) (api.OutboxMessage, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return api.OutboxMessage{}, err
+	}
+	return s.svc.GetOutboxMessageByID(id)
+}
+
+func (s *managementService) ReplayDeadLetter(id int) (api.OutboxMessage, error) {
+	if err := authorize(s.user, api.ActionManageNotifications); err != nil {
+		return api.OutboxMessage{}, err
+	}
+	return s.svc.ReplayDeadLetter(id)
+}
+
+func (s *managementService) CreateAPIKey(key api.APIKey) (api.APIKey, string, error) {
+	if err := authorize(s.user, api.ActionManageAccess); err != nil {
+		return api.APIKey{}, "", err
+	}
+	return s.svc.CreateAPIKey(key)
+}
+
+func (s *managementService) ListAPIKeys() ([]api.APIKey, error) {
+	if err := authorize(s.user, api.ActionManageAccess); err != nil {
+		return nil, err
+	}
+	return s.svc.ListAPIKeys()
+}
+
+func (s *managementService) RevokeAPIKey(id int) error {
+	if err := authorize(s.user, api.ActionManageAccess); err != nil {
+		return err
+	}
+	return s.svc.RevokeAPIKey(id)
+}
+
+func (s *managementService) CreateUser(user api.User) (api.User, error) {
+	if err := authorize(s.user, api.ActionManageAccess); err != nil {
+		return api.User{}, err
+	}
+	return s.svc.CreateUser(user)
+}
+
+func (s *managementService) GetUserByEmail(email string) (api.User, error) {
+	if err := authorize(s.user, api.ActionManageAccess); err != nil {
+		return api.User{}, err
+	}
+	return s.svc.GetUserByEmail(email)
+}
+
+func (s *managementService) DeleteUser(id int) error {
+	if err := authorize(s.user, api.ActionManageAccess); err != nil {
+		return err
+	}
+	return s.svc.DeleteUser(id)
+}
+
+func (s *managementService) ListUsers() ([]api.User, error) {
+	if err := authorize(s.user, api.ActionManageAccess); err != nil {
+		return nil, err
+	}
+	return s.svc.ListUsers()
+}
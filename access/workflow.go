@@ -0,0 +1,82 @@
+package access
+
+import (
+	"context"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+)
+
+// workflowService is a workflow service that acts as a user.
+type workflowService struct {
+	svc  workflow.Service
+	user api.User
+}
+
+// Workflow returns the workflow service acting as the user.
+func Workflow(svc workflow.Service, user api.User) workflow.Service {
+	return &workflowService{svc: svc, user: user}
+}
+
+func (s *workflowService) ValidateCompany() error {
+	return s.svc.ValidateCompany()
+}
+
+// Run processes invoices interactively, which submits them as the user.
+func (s *workflowService) Run(submitter string) error {
+	if err := authorize(s.user, api.ActionSubmitInvoices); err != nil {
+		return err
+	}
+	return s.svc.Run(s.user.Email)
+}
+
+// ProcessInvoice submits the invoice as the user, whoever it names as its
+// submitter.
+func (s *workflowService) ProcessInvoice(invoice api.InvoiceRequest) (api.ApprovalResponse, error) {
+	if err := authorize(s.user, api.ActionSubmitInvoices); err != nil {
+		return api.ApprovalResponse{}, err
+	}
+	invoice.SubmittedBy = s.user.Email
+	return s.svc.ProcessInvoice(invoice)
+}
+
+// RecordDecision records the decision as the user, so that it is refused
+// if they submitted the invoice.
+func (s *workflowService) RecordDecision(decision api.ApprovalDecision) (api.ApprovalRequest, error) {
+	if err := authorize(s.user, api.ActionDecide); err != nil {
+		return api.ApprovalRequest{}, err
+	}
+	decision.User = s.user.Email
+	return s.svc.RecordDecision(decision)
+}
+
+func (s *workflowService) ApprovalRequestsByMessageID(channel, messageID string) ([]int, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.svc.ApprovalRequestsByMessageID(channel, messageID)
+}
+
+func (s *workflowService) ApprovalRequestStatus(id int) (api.ApprovalRequestStatus, error) {
+	if err := authorize(s.user, api.ActionRead); err != nil {
+		return api.ApprovalRequestStatus{}, err
+	}
+	return s.svc.ApprovalRequestStatus(id)
+}
+
+func (s *workflowService) DispatchOutbox() (workflow.DispatchResult, error) {
+	if err := authorize(s.user, api.ActionManageNotifications); err != nil {
+		return workflow.DispatchResult{}, err
+	}
+	return s.svc.DispatchOutbox()
+}
+
+// RunDispatcher runs the outbox dispatcher without a check. It is started
+// by the application, not by users.
+func (s *workflowService) RunDispatcher(ctx context.Context) error {
+	return s.svc.RunDispatcher(ctx)
+}
+
+func (s *workflowService) Suppressed() workflow.Suppressed {
+	return s.svc.Suppressed()
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnknownPermission is returned for a permission that is not one of
+	// Permissions.
+	ErrUnknownPermission = errors.New("unknown permission")
+	// ErrMissingPermissions is returned for an API key without permissions.
+	ErrMissingPermissions = errors.New("at least one permission is required")
+)
+
+// Permission is an operation an API key is allowed to perform.
+type Permission string
+
+const (
+	// PermissionSubmitInvoices allows submitting invoices for approval.
+	PermissionSubmitInvoices Permission = "submit_invoices"
+	// PermissionRead allows reading the company, its departments, workflow
+	// rules, approvers and notifications.
+	PermissionRead Permission = "read"
+	// PermissionManageRules allows changing the company's departments and
+	// workflow rules.
+	PermissionManageRules Permission = "manage_rules"
+	// PermissionManageApprovers allows creating, updating and deleting
+	// approvers.
+	PermissionManageApprovers Permission = "manage_approvers"
+	// PermissionManageCompanies allows updating and deleting the company.
+	PermissionManageCompanies Permission = "manage_companies"
+	// PermissionManageNotifications allows replaying dead letters.
+	PermissionManageNotifications Permission = "manage_notifications"
+)
+
+// Permissions are the permissions an API key can be granted.
+var Permissions = []Permission{
+	PermissionSubmitInvoices, PermissionRead, PermissionManageRules, PermissionManageApprovers,
+	PermissionManageCompanies, PermissionManageNotifications,
+}
+
+// ParsePermissions parses a comma separated list of permissions. The
+// permission "all" grants every permission.
+func ParsePermissions(s string) ([]Permission, error) {
+	var permissions []Permission
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+		case name == "all":
+			permissions = append(permissions, Permissions...)
+		default:
+			permissions = append(permissions, Permission(name))
+		}
+	}
+	if err := ValidatePermissions(permissions); err != nil {
+		return nil, err
+	}
+	return NormalizePermissions(permissions), nil
+}
+
+// ValidatePermissions validates that there is at least one permission and
+// that each is one of Permissions.
+func ValidatePermissions(permissions []Permission) error {
+	if len(permissions) == 0 {
+		return ErrMissingPermissions
+	}
+	for _, permission := range permissions {
+		if !slices.Contains(Permissions, permission) {
+			return fmt.Errorf("%w: %q (must be one of: %s)", ErrUnknownPermission, permission, JoinPermissions(Permissions))
+		}
+	}
+	return nil
+}
+
+// NormalizePermissions returns the permissions without duplicates, in the
+// order of Permissions.
+func NormalizePermissions(permissions []Permission) []Permission {
+	var normalized []Permission
+	for _, permission := range Permissions {
+		if slices.Contains(permissions, permission) {
+			normalized = append(normalized, permission)
+		}
+	}
+	return normalized
+}
+
+// JoinPermissions returns the permissions as a comma separated list.
+func JoinPermissions(permissions []Permission) string {
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = string(permission)
+	}
+	return strings.Join(names, ", ")
+}
+
+// APIKey is an API key of a company. Only a hash of the key is stored, so
+// the key itself is shown once, when it is created. Prefix is the start of
+// the key, which identifies it in listings. A revoked key is no longer
+// accepted. A key with a UserID acts as that user, and is only allowed what
+// both its permissions and the user's role allow.
+type APIKey struct {
+	ID          int          `json:"id"`
+	CompanyID   int          `json:"company_id,omitempty"`
+	Name        string       `json:"name"`
+	Prefix      string       `json:"prefix"`
+	Permissions []Permission `json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	RevokedAt   *time.Time   `json:"revoked_at,omitempty"`
+	UserID      int          `json:"user_id,omitempty"`
+}
+
+// Revoked reports whether the API key has been revoked.
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// Can reports whether the API key grants the permission.
+func (k *APIKey) Can(permission Permission) bool {
+	return slices.Contains(k.Permissions, permission)
+}
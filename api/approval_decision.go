@@ -0,0 +1,48 @@
+package api
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrMissingApprovalRequestID = errors.New("approval request id is missing")
+	ErrInvalidApprovalStatus    = errors.New("approval status must be approved or rejected")
+	ErrMissingDecidedBy         = errors.New("decided_by is missing")
+)
+
+// ApprovalStatus is the status of an approval request.
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending  ApprovalStatus = "pending"
+	ApprovalStatusApproved ApprovalStatus = "approved"
+	ApprovalStatusRejected ApprovalStatus = "rejected"
+)
+
+// ApprovalDecision represents an approver's decision on an approval request.
+// DecidedBy is the approver's contact ID on Channel, e.g. their Slack ID.
+type ApprovalDecision struct {
+	ApprovalRequestID int            `json:"approval_request_id"`
+	Status            ApprovalStatus `json:"status"`
+	Channel           string         `json:"channel"`
+	DecidedBy         string         `json:"decided_by"`
+	DecidedAt         time.Time      `json:"decided_at"`
+	// User is the email of the user who records the decision, if any. It
+	// is set by the service acting as the user, never by clients.
+	User string `json:"-"`
+}
+
+func (d *ApprovalDecision) Validate() error {
+	if d.ApprovalRequestID <= 0 {
+		return ErrMissingApprovalRequestID
+	}
+	if d.Status != ApprovalStatusApproved && d.Status != ApprovalStatusRejected {
+		return ErrInvalidApprovalStatus
+	}
+	if d.DecidedBy == "" {
+		return ErrMissingDecidedBy
+	}
+
+	return nil
+}
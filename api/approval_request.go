@@ -2,6 +2,9 @@ package api
 
 // ApprovalRequest represents an approval request to be sent.
 type ApprovalRequest struct {
+	ID int `json:"id,omitempty"`
+	// Company is the name of the company the invoice belongs to.
+	Company  string         `json:"company,omitempty"`
 	Approver Approver       `json:"approver"`
 	Invoice  InvoiceDetails `json:"invoice"`
 }
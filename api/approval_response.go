@@ -1,9 +1,27 @@
 package api
 
+import "time"
+
 // ApprovalResponse represents a response to an approval request.
 type ApprovalResponse struct {
+	ApprovalRequestID int    `json:"approval_request_id,omitempty"`
 	ApproverName      string `json:"approver_name"`
 	ApproverRole      string `json:"approver_role"`
 	ApproverChannel   string `json:"approver_channel"`
 	ApproverContactID string `json:"approver_contact_id"`
+	// ConversationID is the channel-specific conversation the request was
+	// delivered to, such as the Slack direct message channel.
+	ConversationID string `json:"conversation_id,omitempty"`
+	// MessageID identifies the delivered message, such as the Slack message ts.
+	MessageID string `json:"message_id,omitempty"`
+	// Queued is true when the request could not be delivered yet and was
+	// left in the notification outbox to be retried.
+	Queued bool `json:"queued,omitempty"`
+	// Duplicate is true when the invoice was already sent to the approver
+	// within the dedup window. ApprovalRequestID is then the ID of that
+	// approval request, and nothing was sent.
+	Duplicate bool `json:"duplicate,omitempty"`
+	// DigestAt is set when the approver receives approval requests in
+	// digests, to when the digest with the request is sent.
+	DigestAt *time.Time `json:"digest_at,omitempty"`
 }
@@ -0,0 +1,21 @@
+package api
+
+import "time"
+
+// ApprovalRequestStatus is the status of an approval request.
+type ApprovalRequestStatus struct {
+	ApprovalRequestID int            `json:"approval_request_id"`
+	Status            ApprovalStatus `json:"status"`
+	// DeliveredChannel is the channel the request was delivered over, empty
+	// until it is delivered.
+	DeliveredChannel string `json:"delivered_channel,omitempty"`
+	// DecidedBy is the approver's contact ID on the channel they decided
+	// over.
+	DecidedBy string     `json:"decided_by,omitempty"`
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+}
+
+// Decided reports whether the approval request was approved or rejected.
+func (s ApprovalRequestStatus) Decided() bool {
+	return s.Status == ApprovalStatusApproved || s.Status == ApprovalStatusRejected
+}
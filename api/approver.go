@@ -1,12 +1,45 @@
 package api
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
 
 var (
-	ErrMissingEmail   = errors.New("email is missing")
-	ErrMissingSlackID = errors.New("slack_id is missing")
+	ErrMissingContact    = errors.New("approver has no email, slack_id or teams_id")
+	ErrMissingEmail      = errors.New("email is missing")
+	ErrMissingSlackID    = errors.New("slack_id is missing")
+	ErrMissingTeamsID    = errors.New("teams_id is missing")
+	ErrUnsupportedLocale = errors.New("unsupported locale")
+	// ErrUnsupportedNotificationPreference is returned when an approver's
+	// notification preference is not one of NotificationPreferences.
+	ErrUnsupportedNotificationPreference = errors.New("unsupported notification preference")
+)
+
+// DefaultLocale is the locale of approvers without one.
+const DefaultLocale = "en"
+
+// Locales are the locales notifications can be sent in.
+var Locales = []string{"en", "sv", "de"}
+
+const (
+	// NotificationImmediate sends every approval request as soon as it is
+	// created. It is the default notification preference.
+	NotificationImmediate = "immediate"
+	// NotificationHourlyDigest batches the approval requests of an hour
+	// into one message, sent at the top of the next hour.
+	NotificationHourlyDigest = "hourly_digest"
+	// NotificationDailyDigest batches the approval requests of a day into
+	// one message, sent once a day.
+	NotificationDailyDigest = "daily_digest"
 )
 
+// NotificationPreferences are the ways approvers can receive approval
+// requests.
+var NotificationPreferences = []string{NotificationImmediate, NotificationHourlyDigest, NotificationDailyDigest}
+
 // Approver represents a person who can approve invoices.
 type Approver struct {
 	ID        int    `json:"id,omitempty"`
@@ -15,14 +48,43 @@ type Approver struct {
 	Role      string `json:"role"`
 	Email     string `json:"email"`
 	SlackID   string `json:"slack_id"`
+	// TeamsID is the approver's Microsoft Teams user ID: their Microsoft
+	// Entra object ID or user principal name. It is optional.
+	TeamsID string `json:"teams_id,omitempty"`
+	// Locale is the locale the approver receives notifications in, one of
+	// Locales. It defaults to DefaultLocale.
+	Locale string `json:"locale,omitempty"`
+	// NotificationPreference is how the approver receives approval
+	// requests, one of NotificationPreferences. It defaults to
+	// NotificationImmediate.
+	NotificationPreference string `json:"notification_preference,omitempty"`
+	// AlternateApproverID is the ID of the approver that invoices are
+	// routed to instead when this approver submitted them. It is optional.
+	AlternateApproverID int `json:"alternate_approver_id,omitempty"`
+	// ManagerID is the ID of the approver this approver reports to. It is
+	// optional. Approvers without a manager are at the top of the org chart.
+	ManagerID int `json:"manager_id,omitempty"`
+}
+
+// IsDigest reports whether the approver receives approval requests in
+// digests.
+func (a *Approver) IsDigest() bool {
+	return a.NotificationPreference == NotificationHourlyDigest || a.NotificationPreference == NotificationDailyDigest
 }
 
+// Validate validates the approver. An approver needs a contact on at least
+// one channel. Which contacts are required depends on the channels of the
+// workflow rules the approver is assigned to, and is checked against them
+// by the management service.
 func (a *Approver) Validate() error {
-	if a.Email == "" {
-		return ErrMissingEmail
+	if a.Email == "" && a.SlackID == "" && a.TeamsID == "" {
+		return ErrMissingContact
+	}
+	if a.Locale != "" && !slices.Contains(Locales, a.Locale) {
+		return fmt.Errorf("%w: %q (must be one of: %s)", ErrUnsupportedLocale, a.Locale, strings.Join(Locales, ", "))
 	}
-	if a.SlackID == "" {
-		return ErrMissingSlackID
+	if a.NotificationPreference != "" && !slices.Contains(NotificationPreferences, a.NotificationPreference) {
+		return fmt.Errorf("%w: %q (must be one of: %s)", ErrUnsupportedNotificationPreference, a.NotificationPreference, strings.Join(NotificationPreferences, ", "))
 	}
 
 	return nil
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrMissingCompanyName    = errors.New("company name is missing")
+	ErrMissingDepartmentName = errors.New("department name is missing")
+)
+
+// Company represents a company and the departments it owns.
+type Company struct {
+	ID          int      `json:"id,omitempty"`
+	Name        string   `json:"name"`
+	Departments []string `json:"departments,omitempty"`
+	// FallbackChannels are the default fallback channels of the company's
+	// workflow rules, tried in order when a rule's approval channel fails
+	// and the rule defines no fallback channels of its own.
+	FallbackChannels []string `json:"fallback_channels,omitempty"`
+}
+
+// Validate validates the company.
+func (c *Company) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return ErrMissingCompanyName
+	}
+	for _, department := range c.Departments {
+		if strings.TrimSpace(department) == "" {
+			return ErrMissingDepartmentName
+		}
+	}
+	if err := validateFallbackChannels("", c.FallbackChannels); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package api
+
+import "time"
+
+// DeliveryAttempt represents one attempt to deliver an approval request over
+// a notification channel. Attempts of the same delivery share the
+// idempotency key. StatusCode is zero when no response was received.
+type DeliveryAttempt struct {
+	CompanyID         int       `json:"company_id,omitempty"`
+	ApprovalRequestID int       `json:"approval_request_id"`
+	Channel           string    `json:"channel"`
+	IdempotencyKey    string    `json:"idempotency_key"`
+	Attempt           int       `json:"attempt"`
+	StatusCode        int       `json:"status_code,omitempty"`
+	Error             string    `json:"error,omitempty"`
+	Succeeded         bool      `json:"succeeded"`
+	AttemptedAt       time.Time `json:"attempted_at"`
+}
@@ -0,0 +1,10 @@
+package api
+
+// Digest is a batch of pending approval requests sent to an approver as
+// one message.
+type Digest struct {
+	// Company is the name of the company the invoices belong to.
+	Company  string            `json:"company,omitempty"`
+	Approver Approver          `json:"approver"`
+	Requests []ApprovalRequest `json:"requests"`
+}
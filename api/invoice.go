@@ -1,13 +1,77 @@
 package api
 
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/money"
+)
+
+// DueDateLayout is the format of invoice due dates.
+const DueDateLayout = "2006-01-02"
+
+// ErrInvalidDueDate is returned when an invoice due date is not a date in
+// the DueDateLayout format.
+var ErrInvalidDueDate = errors.New("invalid due date")
+
 // InvoiceRequest represents an invoice that needs approval.
 type InvoiceRequest struct {
-	CompanyName               string  `json:"company_name"`
-	Amount                    float64 `json:"amount"`
-	Department                string  `json:"department,omitempty"`
-	IsManagerApprovalRequired bool    `json:"is_manager_approval_required,omitempty"`
+	CompanyName               string      `json:"company_name"`
+	Amount                    money.Money `json:"amount"`
+	Department                string      `json:"department,omitempty"`
+	IsManagerApprovalRequired bool        `json:"is_manager_approval_required,omitempty"`
+	Vendor                    string      `json:"vendor,omitempty"`
+	Description               string      `json:"description,omitempty"`
+	// DueDate is the date the invoice is due, in the DueDateLayout format.
+	DueDate string `json:"due_date,omitempty"`
+	// SubmittedBy is the email of the user who submits the invoice. It is
+	// never sent to an approver with the same email. Invoices submitted
+	// by a user are always recorded as submitted by them, and API keys
+	// without a user cannot set it.
+	SubmittedBy string `json:"submitted_by,omitempty"`
 }
 
+// InvoiceDetails represents the invoice details sent to an approver.
 type InvoiceDetails struct {
-	Amount float64 `json:"amount"`
+	Amount                    money.Money `json:"amount"`
+	Department                string      `json:"department,omitempty"`
+	IsManagerApprovalRequired bool        `json:"is_manager_approval_required,omitempty"`
+	Vendor                    string      `json:"vendor,omitempty"`
+	Description               string      `json:"description,omitempty"`
+	// DueDate is the date the invoice is due, in the DueDateLayout format.
+	DueDate string `json:"due_date,omitempty"`
+	// SubmittedBy is the email of the user who submitted the invoice, if
+	// known.
+	SubmittedBy string `json:"submitted_by,omitempty"`
+}
+
+// Validate checks that the invoice amount is positive and in a valid
+// currency and that the due date, if set, is in the DueDateLayout format.
+// It returns an *ValidationError listing every invalid field.
+func (i *InvoiceRequest) Validate() error {
+	verr := &ValidationError{}
+
+	if _, err := money.ParseCurrency(string(i.Amount.Currency)); err != nil {
+		verr.Add("amount", err)
+	} else if !i.Amount.IsPositive() {
+		verr.Add("amount", fmt.Errorf("%w: must be greater than 0", money.ErrInvalidAmount))
+	}
+
+	if i.DueDate != "" {
+		if _, err := ParseDueDate(i.DueDate); err != nil {
+			verr.Add("due_date", err)
+		}
+	}
+
+	return verr.ErrOrNil()
+}
+
+// ParseDueDate parses an invoice due date in the DueDateLayout format.
+func ParseDueDate(value string) (time.Time, error) {
+	date, err := time.Parse(DueDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q (expected YYYY-MM-DD)", ErrInvalidDueDate, value)
+	}
+	return date, nil
 }
@@ -0,0 +1,8 @@
+package api
+
+// OrgChartNode is an approver in the company's reporting hierarchy, with the
+// approvers who report to them.
+type OrgChartNode struct {
+	Approver Approver       `json:"approver"`
+	Reports  []OrgChartNode `json:"reports,omitempty"`
+}
@@ -0,0 +1,30 @@
+package api
+
+import "time"
+
+// OutboxStatus is the delivery status of an outbox message.
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"
+	OutboxStatusDelivered OutboxStatus = "delivered"
+	OutboxStatusDead      OutboxStatus = "dead"
+)
+
+// OutboxMessage represents the notification of an approval request waiting
+// in the outbox to be delivered over Channel. Attempts counts the failed
+// delivery attempts and LastError describes the last failure. A dead
+// message, also called a dead letter, failed every attempt and is only
+// retried when it is replayed.
+type OutboxMessage struct {
+	ID                int          `json:"id"`
+	CompanyID         int          `json:"company_id,omitempty"`
+	ApprovalRequestID int          `json:"approval_request_id"`
+	Channel           string       `json:"channel"`
+	Status            OutboxStatus `json:"status"`
+	Attempts          int          `json:"attempts"`
+	NextAttemptAt     time.Time    `json:"next_attempt_at"`
+	LastError         string       `json:"last_error,omitempty"`
+	CreatedAt         time.Time    `json:"created_at"`
+	DeliveredAt       *time.Time   `json:"delivered_at,omitempty"`
+}
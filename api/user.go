@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrUnknownRole is returned for a role that is not one of Roles.
+	ErrUnknownRole = errors.New("unknown role")
+	// ErrPermissionDenied is matched by every PermissionDeniedError with
+	// errors.Is.
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// Role is the role of a user, which determines the actions they are allowed
+// to perform.
+type Role string
+
+const (
+	// RoleAdmin is allowed to perform every action.
+	RoleAdmin Role = "admin"
+	// RoleRuleEditor is allowed to read and to change departments and
+	// workflow rules.
+	RoleRuleEditor Role = "rule-editor"
+	// RoleApprover is allowed to read and to decide approval requests.
+	RoleApprover Role = "approver"
+	// RoleSubmitter is allowed to read and to submit invoices.
+	RoleSubmitter Role = "submitter"
+	// RoleViewer is only allowed to read.
+	RoleViewer Role = "viewer"
+)
+
+// Roles are the roles a user can have.
+var Roles = []Role{RoleAdmin, RoleRuleEditor, RoleApprover, RoleSubmitter, RoleViewer}
+
+// Action is an operation of the management and workflow services that
+// requires a role allowing it.
+type Action string
+
+const (
+	// ActionRead is reading the company, its departments, workflow rules,
+	// approvers, notifications and approval requests.
+	ActionRead Action = "read"
+	// ActionSubmitInvoices is submitting invoices for approval.
+	ActionSubmitInvoices Action = "submit_invoices"
+	// ActionDecide is approving or rejecting approval requests.
+	ActionDecide Action = "decide"
+	// ActionManageRules is changing departments and workflow rules.
+	ActionManageRules Action = "manage_rules"
+	// ActionManageApprovers is creating, updating and deleting approvers.
+	ActionManageApprovers Action = "manage_approvers"
+	// ActionManageCompanies is creating, updating and deleting companies.
+	ActionManageCompanies Action = "manage_companies"
+	// ActionManageNotifications is dispatching the outbox and replaying
+	// dead letters.
+	ActionManageNotifications Action = "manage_notifications"
+	// ActionManageAccess is managing users and API keys.
+	ActionManageAccess Action = "manage_access"
+)
+
+// roleActions are the actions each role allows, besides RoleAdmin, which
+// allows every action.
+var roleActions = map[Role][]Action{
+	RoleRuleEditor: {ActionRead, ActionManageRules},
+	RoleApprover:   {ActionRead, ActionDecide},
+	RoleSubmitter:  {ActionRead, ActionSubmitInvoices},
+	RoleViewer:     {ActionRead},
+}
+
+// Allows reports whether the role allows the action.
+func (r Role) Allows(action Action) bool {
+	if r == RoleAdmin {
+		return true
+	}
+	return slices.Contains(roleActions[r], action)
+}
+
+// ValidateRole validates that the role is one of Roles.
+func ValidateRole(role Role) error {
+	if !slices.Contains(Roles, role) {
+		names := make([]string, len(Roles))
+		for i, r := range Roles {
+			names[i] = string(r)
+		}
+		return fmt.Errorf("%w: %q (must be one of: %s)", ErrUnknownRole, role, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// User is a person who uses the management and workflow services of a
+// company, with the role that determines what they are allowed to do.
+type User struct {
+	ID        int    `json:"id,omitempty"`
+	CompanyID int    `json:"company_id,omitempty"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Role      Role   `json:"role"`
+}
+
+// Allows reports whether the user's role allows the action.
+func (u *User) Allows(action Action) bool {
+	return u.Role.Allows(action)
+}
+
+// PermissionDeniedError is returned when a user's role does not allow an
+// action.
+type PermissionDeniedError struct {
+	User   string
+	Role   Role
+	Action Action
+}
+
+// Error describes the user, their role and the action they are not allowed
+// to perform.
+func (e *PermissionDeniedError) Error() string {
+	return fmt.Sprintf("%v: %s has the role %s, which does not allow %s", ErrPermissionDenied, e.User, e.Role, e.Action)
+}
+
+// Is reports whether target is ErrPermissionDenied.
+func (e *PermissionDeniedError) Is(target error) bool {
+	return target == ErrPermissionDenied
+}
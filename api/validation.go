@@ -0,0 +1,83 @@
+package api
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrValidation is matched by every ValidationError with errors.Is.
+	ErrValidation = errors.New("validation failed")
+	// ErrMissingField is returned for a required field that is not set.
+	ErrMissingField = errors.New("is required")
+)
+
+// FieldError describes why a single field is invalid. Field is the JSON
+// name of the field, e.g. "approver_id".
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// Error returns the field name followed by the reason it is invalid.
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError collects the field errors of an invalid request so that
+// callers can report all of them at once.
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+// Add records an error for the given field.
+func (e *ValidationError) Add(field string, err error) {
+	e.Fields = append(e.Fields, &FieldError{Field: field, Err: err})
+}
+
+// Merge adds the field errors of err if it is a ValidationError. Any
+// other error is not tied to a field and is returned unchanged.
+func (e *ValidationError) Merge(err error) error {
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+	e.Fields = append(e.Fields, verr.Fields...)
+	return nil
+}
+
+// ErrOrNil returns the validation error if it has any field errors.
+func (e *ValidationError) ErrOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error returns all field errors separated by semicolons.
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether target is ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Unwrap returns the field errors so that errors.Is matches their causes.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, field := range e.Fields {
+		errs[i] = field
+	}
+	return errs
+}
@@ -3,51 +3,225 @@ package api
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/KatrinSalt/backend-challenge-go/money"
 )
 
 var (
 	ErrInvalidApprovalChannel = errors.New("invalid approval channel")
 	ErrInvalidAmountRange     = errors.New("invalid amount range")
+	ErrInvalidBound           = errors.New("invalid bound")
+)
+
+// Bound describes whether an amount range bound includes its endpoint.
+type Bound string
+
+const (
+	BoundInclusive Bound = "inclusive"
+	BoundExclusive Bound = "exclusive"
 )
 
+const (
+	// DefaultMinBound is the default lower bound semantics of a rule.
+	DefaultMinBound = BoundInclusive
+	// DefaultMaxBound is the default upper bound semantics of a rule.
+	DefaultMaxBound = BoundExclusive
+)
+
+// ParseBound parses a bound from its string representation. An empty
+// string is returned as an empty bound so that defaults can apply.
+func ParseBound(s string) (Bound, error) {
+	b := Bound(strings.ToLower(strings.TrimSpace(s)))
+	if err := b.validate(); err != nil {
+		return "", err
+	}
+	return b, nil
+}
+
+// validate checks that the bound is empty, inclusive or exclusive.
+func (b Bound) validate() error {
+	switch b {
+	case "", BoundInclusive, BoundExclusive:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q (must be %s or %s)", ErrInvalidBound, string(b), BoundInclusive, BoundExclusive)
+	}
+}
+
 // WorkflowRule represents a rule that determines how invoices are approved.
 type WorkflowRule struct {
-	ID                        int      `json:"id,omitempty"`
-	CompanyID                 int      `json:"company_id,omitempty"`
-	MinAmount                 *float64 `json:"min_amount,omitempty"`
-	MaxAmount                 *float64 `json:"max_amount,omitempty"`
-	Department                *string  `json:"department,omitempty"`
-	IsManagerApprovalRequired int      `json:"is_manager_approval_required,omitempty"`
-	ApproverID                int      `json:"approver_id"`
-	ApprovalChannel           int      `json:"approval_channel"`
+	ID                        int          `json:"id,omitempty"`
+	CompanyID                 int          `json:"company_id,omitempty"`
+	MinAmount                 *money.Money `json:"min_amount,omitempty"`
+	MaxAmount                 *money.Money `json:"max_amount,omitempty"`
+	MinBound                  Bound        `json:"min_bound,omitempty"`
+	MaxBound                  Bound        `json:"max_bound,omitempty"`
+	Department                *string      `json:"department,omitempty"`
+	IsManagerApprovalRequired int          `json:"is_manager_approval_required,omitempty"`
+	ApproverID                int          `json:"approver_id"`
+	ApprovalChannel           string       `json:"approval_channel"`
+	// FallbackChannels are tried in order when the approval request cannot
+	// be delivered over ApprovalChannel. If empty, the company's fallback
+	// channels are used.
+	FallbackChannels []string `json:"fallback_channels,omitempty"`
+	// ManagerLevels routes approval requests to the submitter's manager
+	// that many levels up the reporting hierarchy instead of ApproverID: 1
+	// is the submitter's manager, 2 their manager's manager, and so on.
+	// ApproverID still receives them if the submitter is not an approver or
+	// has no manager that far up. Zero, the default, always routes to
+	// ApproverID.
+	ManagerLevels int `json:"manager_levels,omitempty"`
 }
 
-// Validate validates the workflow rule.
+// Validate validates the workflow rule. It returns a *ValidationError
+// listing every invalid field.
 func (w *WorkflowRule) Validate() error {
-	// Validate approval channel (0 = slack, 1 = email)
-	if w.ApprovalChannel < 0 || w.ApprovalChannel > 1 {
-		return ErrInvalidApprovalChannel
+	verr := &ValidationError{}
+
+	// Validate approval channel. Whether a channel of that name is
+	// registered is checked by the management service.
+	if strings.TrimSpace(w.ApprovalChannel) == "" {
+		verr.Add("approval_channel", fmt.Errorf("%w: name is required", ErrInvalidApprovalChannel))
+	}
+
+	// Validate fallback channels
+	if err := validateFallbackChannels(w.ApprovalChannel, w.FallbackChannels); err != nil {
+		verr.Add("fallback_channels", err)
 	}
 
 	// Validate manager approval required (0 = no, 1 = yes)
 	if w.IsManagerApprovalRequired < 0 || w.IsManagerApprovalRequired > 1 {
-		return fmt.Errorf("invalid manager approval required value: %d (must be 0 or 1)", w.IsManagerApprovalRequired)
+		verr.Add("is_manager_approval_required", fmt.Errorf("invalid manager approval required value: %d (must be 0 or 1)", w.IsManagerApprovalRequired))
+	}
+
+	// Validate manager levels
+	if w.ManagerLevels < 0 {
+		verr.Add("manager_levels", fmt.Errorf("invalid manager levels: %d (must be 0 or more)", w.ManagerLevels))
+	}
+
+	// Validate bound semantics
+	if err := w.MinBound.validate(); err != nil {
+		verr.Add("min_bound", err)
+	}
+	if err := w.MaxBound.validate(); err != nil {
+		verr.Add("max_bound", err)
 	}
 
 	// Validate amount range if both are provided
 	if w.MinAmount != nil && w.MaxAmount != nil {
-		if *w.MinAmount > *w.MaxAmount {
-			return ErrInvalidAmountRange
+		cmp, err := w.MinAmount.Compare(*w.MaxAmount)
+		switch {
+		case err != nil:
+			verr.Add("max_amount", err)
+		case cmp > 0:
+			verr.Add("max_amount", ErrInvalidAmountRange)
+		// A range with equal endpoints is only non-empty if both bounds include them.
+		case cmp == 0 && (!w.MinInclusive() || !w.MaxInclusive()):
+			verr.Add("max_amount", fmt.Errorf("%w: [%s, %s] must include both bounds when min equals max", ErrInvalidAmountRange, w.MinAmount.Decimal(), w.MaxAmount.Decimal()))
 		}
 	}
 
 	// Validate required fields
 	if w.CompanyID <= 0 {
-		return errors.New("company_id is required")
+		verr.Add("company_id", ErrMissingField)
 	}
 	if w.ApproverID <= 0 {
-		return errors.New("approver_id is required")
+		verr.Add("approver_id", ErrMissingField)
+	}
+
+	return verr.ErrOrNil()
+}
+
+// Channels returns the approval channel followed by the fallback channels,
+// in the order they are tried. companyFallbacks are used if the rule has no
+// fallback channels of its own.
+func (w *WorkflowRule) Channels(companyFallbacks []string) []string {
+	fallbacks := w.FallbackChannels
+	if len(fallbacks) == 0 {
+		fallbacks = companyFallbacks
 	}
+	return ChannelChain(w.ApprovalChannel, fallbacks)
+}
 
+// ChannelChain returns the primary channel followed by the fallback
+// channels, skipping fallbacks that repeat an earlier channel.
+func ChannelChain(primary string, fallbacks []string) []string {
+	chain := []string{primary}
+	for _, fallback := range fallbacks {
+		if !slices.Contains(chain, fallback) {
+			chain = append(chain, fallback)
+		}
+	}
+	return chain
+}
+
+// validateFallbackChannels checks that the fallback channels are named and
+// that none repeats the primary channel or another fallback.
+func validateFallbackChannels(primary string, fallbacks []string) error {
+	seen := map[string]bool{primary: primary != ""}
+	for _, fallback := range fallbacks {
+		switch {
+		case strings.TrimSpace(fallback) == "":
+			return fmt.Errorf("%w: name is required", ErrInvalidApprovalChannel)
+		case seen[fallback]:
+			return fmt.Errorf("%w: %q is listed more than once", ErrInvalidApprovalChannel, fallback)
+		}
+		seen[fallback] = true
+	}
 	return nil
 }
+
+// MinInclusive reports whether the lower amount bound includes its endpoint.
+func (w *WorkflowRule) MinInclusive() bool {
+	if w.MinBound == "" {
+		return DefaultMinBound == BoundInclusive
+	}
+	return w.MinBound == BoundInclusive
+}
+
+// MaxInclusive reports whether the upper amount bound includes its endpoint.
+func (w *WorkflowRule) MaxInclusive() bool {
+	if w.MaxBound == "" {
+		return DefaultMaxBound == BoundInclusive
+	}
+	return w.MaxBound == BoundInclusive
+}
+
+// AmountRange returns the amount range in interval notation, e.g.
+// "[1000, 5000)". Unbounded ends are shown as -∞ and ∞, and the currency
+// is appended only when it is not the default currency.
+func (w *WorkflowRule) AmountRange() string {
+	if w.MinAmount == nil && w.MaxAmount == nil {
+		return "Any"
+	}
+
+	lower, upper := "(-∞", "∞)"
+	var currency money.Currency
+	if w.MinAmount != nil {
+		lower = "(" + rangeEndpoint(*w.MinAmount)
+		if w.MinInclusive() {
+			lower = "[" + rangeEndpoint(*w.MinAmount)
+		}
+		currency = w.MinAmount.Currency
+	}
+	if w.MaxAmount != nil {
+		upper = rangeEndpoint(*w.MaxAmount) + ")"
+		if w.MaxInclusive() {
+			upper = rangeEndpoint(*w.MaxAmount) + "]"
+		}
+		currency = w.MaxAmount.Currency
+	}
+
+	if currency == "" || currency == money.DefaultCurrency {
+		return fmt.Sprintf("%s, %s", lower, upper)
+	}
+	return fmt.Sprintf("%s, %s %s", lower, upper, currency)
+}
+
+// rangeEndpoint formats an amount range endpoint, leaving out the minor
+// units of whole amounts.
+func rangeEndpoint(m money.Money) string {
+	return strings.TrimSuffix(m.Decimal(), ".00")
+}
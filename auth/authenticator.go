@@ -0,0 +1,138 @@
+// Package auth authenticates the API keys of companies and carries the
+// company, permissions and user of a key through request contexts.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+)
+
+var (
+	// ErrInvalidAPIKey is returned for a missing, unknown or revoked API key.
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrUnboundSubmitter is returned when an invoice names its submitter
+	// but the API key does not act as a user.
+	ErrUnboundSubmitter = errors.New("can only be set by API keys that act as a user")
+)
+
+// databaseService defines the database operations needed to authenticate
+// API keys.
+type databaseService interface {
+	GetAPIKeyByHash(hash string) (db.APIKey, error)
+	GetCompanyByID(id int) (db.Company, error)
+	GetUserByID(companyID, id int) (db.User, error)
+}
+
+// Principal is the company an API key belongs to and the permissions it
+// grants. User is the user the key acts as, if any.
+type Principal struct {
+	KeyID       int
+	CompanyID   int
+	Company     string
+	Permissions []api.Permission
+	User        *api.User
+}
+
+// Can reports whether the principal has the permission.
+func (p Principal) Can(permission api.Permission) bool {
+	return slices.Contains(p.Permissions, permission)
+}
+
+// CheckSubmitter returns a validation error if the invoice names its
+// submitter and the principal has no user. Keys without a user cannot
+// vouch for who submitted an invoice, and keys with one always submit
+// invoices as their user.
+func (p Principal) CheckSubmitter(invoice api.InvoiceRequest) error {
+	if p.User != nil || invoice.SubmittedBy == "" {
+		return nil
+	}
+	verr := &api.ValidationError{}
+	verr.Add("submitted_by", ErrUnboundSubmitter)
+	return verr
+}
+
+// Authenticator resolves API keys to the company they belong to.
+type Authenticator struct {
+	dbService databaseService
+}
+
+// NewAuthenticator returns an authenticator for the API keys stored in the
+// database.
+func NewAuthenticator(dbService databaseService) (*Authenticator, error) {
+	if dbService == nil {
+		return nil, errors.New("database service is required to authenticate api keys")
+	}
+	return &Authenticator{dbService: dbService}, nil
+}
+
+// Authenticate returns the principal of an API key. It returns
+// ErrInvalidAPIKey if the key is empty, unknown or revoked, or if the user
+// it acts as was deleted.
+func (a *Authenticator) Authenticate(key string) (Principal, error) {
+	if key == "" {
+		return Principal{}, fmt.Errorf("%w: no api key", ErrInvalidAPIKey)
+	}
+
+	dbKey, err := a.dbService.GetAPIKeyByHash(HashKey(key))
+	if errors.Is(err, db.ErrAPIKeyNotFound) {
+		return Principal{}, fmt.Errorf("%w: unknown api key %s", ErrInvalidAPIKey, Prefix(key))
+	}
+	if err != nil {
+		return Principal{}, fmt.Errorf("failed to get api key: %w", err)
+	}
+	if dbKey.RevokedAt != nil {
+		return Principal{}, fmt.Errorf("%w: api key %s was revoked", ErrInvalidAPIKey, dbKey.Prefix)
+	}
+
+	company, err := a.dbService.GetCompanyByID(dbKey.CompanyID)
+	if err != nil {
+		return Principal{}, fmt.Errorf("failed to get company of api key %s: %w", dbKey.Prefix, err)
+	}
+	permissions, err := api.ParsePermissions(dbKey.Permissions)
+	if err != nil {
+		return Principal{}, fmt.Errorf("invalid permissions of api key %s: %w", dbKey.Prefix, err)
+	}
+
+	principal := Principal{
+		KeyID:       dbKey.ID,
+		CompanyID:   company.ID,
+		Company:     company.Name,
+		Permissions: permissions,
+	}
+	if dbKey.UserID != nil {
+		user, err := a.dbService.GetUserByID(dbKey.CompanyID, *dbKey.UserID)
+		if errors.Is(err, db.ErrUserNotFound) {
+			return Principal{}, fmt.Errorf("%w: the user of api key %s was deleted", ErrInvalidAPIKey, dbKey.Prefix)
+		}
+		if err != nil {
+			return Principal{}, fmt.Errorf("failed to get user of api key %s: %w", dbKey.Prefix, err)
+		}
+		principal.User = &api.User{
+			ID:        user.ID,
+			CompanyID: user.CompanyID,
+			Name:      user.Name,
+			Email:     user.Email,
+			Role:      api.Role(user.Role),
+		}
+	}
+	return principal, nil
+}
+
+// principalKey is the context key of the principal.
+type principalKey struct{}
+
+// NewContext returns a copy of ctx that carries the principal.
+func NewContext(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal carried by ctx, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	revokedAt := "2026-01-03T09:00:00Z"
+	keys := map[string]db.APIKey{
+		HashKey("ak_valid"):   {ID: 1, CompanyID: 2, Prefix: "ak_valid", Permissions: "read,submit_invoices"},
+		HashKey("ak_revoked"): {ID: 2, CompanyID: 2, Prefix: "ak_revoked", Permissions: "read", RevokedAt: &revokedAt},
+		HashKey("ak_bad"):     {ID: 3, CompanyID: 2, Prefix: "ak_bad", Permissions: "root"},
+		HashKey("ak_user"):    {ID: 4, CompanyID: 2, Prefix: "ak_user", Permissions: "read", UserID: intPtr(5)},
+		HashKey("ak_deleted"): {ID: 5, CompanyID: 2, Prefix: "ak_deleted", Permissions: "read", UserID: intPtr(6)},
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		want    Principal
+		wantErr error
+	}{
+		{
+			name: "valid key",
+			key:  "ak_valid",
+			want: Principal{
+				KeyID:       1,
+				CompanyID:   2,
+				Company:     "Acme",
+				Permissions: []api.Permission{api.PermissionSubmitInvoices, api.PermissionRead},
+			},
+		},
+		{
+			name: "key acting as a user",
+			key:  "ak_user",
+			want: Principal{
+				KeyID:       4,
+				CompanyID:   2,
+				Company:     "Acme",
+				Permissions: []api.Permission{api.PermissionRead},
+				User:        &api.User{ID: 5, CompanyID: 2, Name: "Auditor", Email: "auditor@acme.com", Role: api.RoleViewer},
+			},
+		},
+		{name: "no key", key: "", wantErr: ErrInvalidAPIKey},
+		{name: "unknown key", key: "ak_unknown", wantErr: ErrInvalidAPIKey},
+		{name: "revoked key", key: "ak_revoked", wantErr: ErrInvalidAPIKey},
+		{name: "unknown permission", key: "ak_bad", wantErr: api.ErrUnknownPermission},
+		{name: "deleted user", key: "ak_deleted", wantErr: ErrInvalidAPIKey},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, err := NewAuthenticator(&mockDBService{
+				keys:    keys,
+				company: db.Company{ID: 2, Name: "Acme"},
+				users:   []db.User{{ID: 5, CompanyID: 2, Name: "Auditor", Email: "auditor@acme.com", Role: "viewer"}},
+			})
+			if err != nil {
+				t.Fatalf("NewAuthenticator() unexpected error: %v", err)
+			}
+
+			got, err := a.Authenticate(test.key)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("Authenticate() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Authenticate() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Errorf("FromContext() found a principal in an empty context")
+	}
+
+	want := Principal{KeyID: 1, CompanyID: 2, Company: "Acme", Permissions: []api.Permission{api.PermissionRead}}
+	got, ok := FromContext(NewContext(context.Background(), want))
+	if !ok {
+		t.Fatalf("FromContext() found no principal")
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("FromContext() mismatch (-want +got)\n%s", diff)
+	}
+	if !got.Can(api.PermissionRead) || got.Can(api.PermissionManageRules) {
+		t.Errorf("Can() does not match the permissions %v", got.Permissions)
+	}
+}
+
+// mockDBService stores API keys by hash and users for one company.
+type mockDBService struct {
+	keys    map[string]db.APIKey
+	company db.Company
+	users   []db.User
+}
+
+func (m *mockDBService) GetAPIKeyByHash(hash string) (db.APIKey, error) {
+	key, ok := m.keys[hash]
+	if !ok {
+		return db.APIKey{}, db.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func (m *mockDBService) GetCompanyByID(id int) (db.Company, error) {
+	if id != m.company.ID {
+		return db.Company{}, db.ErrCompanyNotFound
+	}
+	return m.company, nil
+}
+
+func (m *mockDBService) GetUserByID(companyID, id int) (db.User, error) {
+	for _, user := range m.users {
+		if user.ID == id && user.CompanyID == companyID {
+			return user, nil
+		}
+	}
+	return db.User{}, db.ErrUserNotFound
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	// KeyPrefix starts every API key, so that keys are recognizable, for
+	// example by secret scanners.
+	KeyPrefix = "ak_"
+	// keyBytes is the number of random bytes of an API key.
+	keyBytes = 32
+	// prefixLength is the length of the start of a key that identifies it
+	// in listings.
+	prefixLength = len(KeyPrefix) + 8
+)
+
+// GenerateKey returns a new random API key.
+func GenerateKey() (string, error) {
+	b := make([]byte, keyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return KeyPrefix + hex.EncodeToString(b), nil
+}
+
+// HashKey returns the hex encoded SHA-256 hash of an API key, which is
+// stored instead of the key. API keys are random, so a fast hash is enough
+// to keep them from being recovered from the database.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Prefix returns the start of an API key, which identifies it in listings
+// without revealing it.
+func Prefix(key string) string {
+	if len(key) < prefixLength {
+		return key
+	}
+	return key[:prefixLength]
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KatrinSalt/backend-challenge-go/db"
+)
+
+func TestGenerateKey(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(key, KeyPrefix) || len(key) != len(KeyPrefix)+2*keyBytes {
+		t.Errorf("GenerateKey() = %q, want %s followed by %d hex digits", key, KeyPrefix, 2*keyBytes)
+	}
+
+	other, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	if key == other {
+		t.Errorf("GenerateKey() returned %q twice", key)
+	}
+}
+
+func TestHashKey(t *testing.T) {
+	// The sample key is stored with the hash of the key.
+	sample := db.NewSampleAPIKey()
+	if got := HashKey(db.SampleAPIKey); got != sample.Hash {
+		t.Errorf("HashKey(SampleAPIKey) = %q, want the sample hash %q", got, sample.Hash)
+	}
+	if got := Prefix(db.SampleAPIKey); got != sample.Prefix {
+		t.Errorf("Prefix(SampleAPIKey) = %q, want the sample prefix %q", got, sample.Prefix)
+	}
+}
@@ -0,0 +1,210 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/cmd/cli/output"
+	"github.com/urfave/cli/v2"
+)
+
+// apiKeyFlags maps API key fields to the flags that set them.
+var apiKeyFlags = map[string]string{
+	"name":        "name",
+	"permissions": "permissions",
+	"user_id":     "user",
+}
+
+func CreateAPIKey() *cli.Command {
+	return &cli.Command{
+		Name:    "create-api-key",
+		Aliases: []string{"cak"},
+		Usage:   "Create an API key for the company to authenticate requests to the API",
+		UsageText: ` 
+		    backend-challenge-cli create-api-key --name "ERP" --permissions "submit_invoices,read"
+		    backend-challenge-cli --company "Acme" create-api-key --name "Admin" --permissions all
+		    backend-challenge-cli cak -n "Reporting" -p read
+		    backend-challenge-cli cak -n "Auditor" -p read --user "auditor@light.com"`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "name",
+				Aliases:  []string{"n"},
+				Usage:    "Name of the API key, which describes what it is used for, required",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "permissions",
+				Aliases:  []string{"p"},
+				Usage:    fmt.Sprintf("Comma-separated permissions of the API key (%s), or 'all', required", api.JoinPermissions(api.Permissions)),
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "user",
+				Aliases: []string{"u"},
+				Usage:   "Email of the user the API key acts as, whose role limits it further, optional",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			permissions, err := api.ParsePermissions(c.String("permissions"))
+			if err != nil {
+				return fmt.Errorf("invalid --permissions: %w", err)
+			}
+
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			newKey := api.APIKey{
+				Name:        c.String("name"),
+				Permissions: permissions,
+			}
+
+			// Resolve the user the API key acts as
+			if email := c.String("user"); email != "" {
+				user, err := services.Management.GetUserByEmail(email)
+				if err != nil {
+					return fmt.Errorf("invalid --user: %w", err)
+				}
+				newKey.UserID = user.ID
+			}
+
+			// Create API key
+			apiKey, key, err := services.Management.CreateAPIKey(newKey)
+			if err != nil {
+				return formatValidationError("failed to create api key", err, apiKeyFlags)
+			}
+
+			message := fmt.Sprintf("✅ API key created successfully!\n"+
+				"ID: %d\n"+
+				"Name: %s\n"+
+				"Permissions: %s\n"+
+				"Key: %s\n\n"+
+				"⚠️  Store the key now, it is not shown again. Send it as 'Authorization: Bearer <key>'.",
+				apiKey.ID, apiKey.Name, api.JoinPermissions(apiKey.Permissions), key)
+			output.Println(message)
+			return nil
+		},
+	}
+}
+
+func ListAPIKeys() *cli.Command {
+	return &cli.Command{
+		Name:    "list-api-keys",
+		Aliases: []string{"lak"},
+		Usage:   "List the API keys of the company, including revoked keys",
+		UsageText: ` 
+		    backend-challenge-cli list-api-keys
+		    backend-challenge-cli --company "Acme" lak`,
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// List API keys
+			apiKeys, err := services.Management.ListAPIKeys()
+			if err != nil {
+				return fmt.Errorf("failed to list api keys: %w", err)
+			}
+
+			if len(apiKeys) == 0 {
+				output.Println("No API keys found for this company.")
+			} else {
+				output.Println(fmt.Sprintf("Found %d API key(s):", len(apiKeys)))
+				for _, apiKey := range apiKeys {
+					revoked := "Not revoked"
+					if apiKey.Revoked() {
+						revoked = apiKey.RevokedAt.Format(time.RFC3339)
+					}
+					user := "-"
+					if apiKey.UserID != 0 {
+						user = fmt.Sprintf("%d", apiKey.UserID)
+					}
+					output.Println(fmt.Sprintf("ID: %d | Name: %s | Prefix: %s... | Permissions: %s | User: %s | Created: %s | Revoked: %s",
+						apiKey.ID,
+						apiKey.Name,
+						apiKey.Prefix,
+						api.JoinPermissions(apiKey.Permissions),
+						user,
+						apiKey.CreatedAt.Format(time.RFC3339),
+						revoked))
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func RevokeAPIKey() *cli.Command {
+	return &cli.Command{
+		Name:    "revoke-api-key",
+		Aliases: []string{"rak"},
+		Usage:   "Revoke an API key of the company, so it is no longer accepted",
+		UsageText: ` 
+		    backend-challenge-cli revoke-api-key --id 1
+		    backend-challenge-cli rak -i 1`,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:     "id",
+				Aliases:  []string{"i"},
+				Usage:    "ID of the API key to revoke, required",
+				Required: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Revoke API key
+			if err := services.Management.RevokeAPIKey(c.Int("id")); err != nil {
+				return fmt.Errorf("failed to revoke api key: %w", err)
+			}
+
+			output.Println(fmt.Sprintf("✅ API key %d revoked successfully!", c.Int("id")))
+			return nil
+		},
+	}
+}
@@ -2,6 +2,8 @@ package commands
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/KatrinSalt/backend-challenge-go/api"
 	"github.com/KatrinSalt/backend-challenge-go/cmd/cli/output"
@@ -14,8 +16,9 @@ func CreateApprover() *cli.Command {
 		Aliases: []string{"ca"},
 		Usage:   "Create a new approver",
 		UsageText: ` 
-		    backend-challenge-cli create-approver --name "John Doe" --role "Manager" --email "john@example.com" --slack-id "U123456"
-		    backend-challenge-cli ca -n "Jane Smith" -r "Director" -e "jane@example.com" -s "U789012"`,
+		    backend-challenge-cli create-approver --name "John Doe" --role "Manager" --email "john@example.com" --slack-id "U123456" --teams-id "john@example.com" --locale sv --notification-preference daily_digest
+		    backend-challenge-cli ca -n "Jane Smith" -r "Director" -e "jane@example.com" -s "U789012"
+		    backend-challenge-cli ca -n "Max Berg" -r "Controller" -e "max@example.com" -m 2`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:     "name",
@@ -30,40 +33,73 @@ func CreateApprover() *cli.Command {
 				Required: true,
 			},
 			&cli.StringFlag{
-				Name:     "email",
-				Aliases:  []string{"e"},
-				Usage:    "Email address of the approver, required",
-				Required: true,
+				Name:    "email",
+				Aliases: []string{"e"},
+				Usage:   "Email address of the approver, optional if the approver has another contact",
 			},
 			&cli.StringFlag{
-				Name:     "slack-id",
-				Aliases:  []string{"s"},
-				Usage:    "Slack ID of the approver, required",
-				Required: true,
+				Name:    "slack-id",
+				Aliases: []string{"s"},
+				Usage:   "Slack ID of the approver, optional if the approver has another contact",
+			},
+			&cli.StringFlag{
+				Name:    "teams-id",
+				Aliases: []string{"t"},
+				Usage:   "Microsoft Teams user ID (Entra object ID or user principal name) of the approver, optional",
+			},
+			&cli.StringFlag{
+				Name:    "locale",
+				Aliases: []string{"l"},
+				Usage:   fmt.Sprintf("Locale of the approver's notifications (%s)", strings.Join(api.Locales, ", ")),
+				Value:   api.DefaultLocale,
+			},
+			&cli.StringFlag{
+				Name:    "notification-preference",
+				Aliases: []string{"np"},
+				Usage:   fmt.Sprintf("When the approver receives approval requests (%s)", strings.Join(api.NotificationPreferences, ", ")),
+				Value:   api.NotificationImmediate,
+			},
+			&cli.IntFlag{
+				Name:    "alternate-approver-id",
+				Aliases: []string{"a"},
+				Usage:   "ID of the approver who receives the invoices this approver submitted, optional; defaults to their manager",
+			},
+			&cli.IntFlag{
+				Name:    "manager-id",
+				Aliases: []string{"m"},
+				Usage:   "ID of the approver this approver reports to, optional",
 			},
 		},
 		Action: func(c *cli.Context) error {
 			// Get CLI config from global flags
 			cliConfig := &Config{
-				Company:     c.String("company"),
-				Departments: c.String("departments"),
-				SlackConn:   c.String("slack-connection-string"),
-				EmailConn:   c.String("email-connection-string"),
-				Verbose:     c.Bool("verbose"),
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
 			}
 
 			// Setup services
-			services, err := setupServicesWithConfig(cliConfig)
+			services, err := setupServicesAsUser(cliConfig)
 			if err != nil {
 				return fmt.Errorf("failed to setup services: %w", err)
 			}
 
 			// Create approver
 			approver := api.Approver{
-				Name:    c.String("name"),
-				Role:    c.String("role"),
-				Email:   c.String("email"),
-				SlackID: c.String("slack-id"),
+				Name:                   c.String("name"),
+				Role:                   c.String("role"),
+				Email:                  c.String("email"),
+				SlackID:                c.String("slack-id"),
+				TeamsID:                c.String("teams-id"),
+				Locale:                 c.String("locale"),
+				NotificationPreference: c.String("notification-preference"),
+				AlternateApproverID:    c.Int("alternate-approver-id"),
+				ManagerID:              c.Int("manager-id"),
 			}
 
 			createdApprover, err := services.Management.CreateApprover(approver)
@@ -76,9 +112,14 @@ func CreateApprover() *cli.Command {
 				"Name: %s\n"+
 				"Role: %s\n"+
 				"Email: %s\n"+
-				"Slack ID: %s",
+				"Slack ID: %s\n"+
+				"Teams ID: %s\n"+
+				"Locale: %s\n"+
+				"Notification preference: %s\n"+
+				"Alternate approver ID: %s\n"+
+				"Manager ID: %s",
 				createdApprover.ID, createdApprover.Name, createdApprover.Role,
-				createdApprover.Email, createdApprover.SlackID)
+				formatOptional(createdApprover.Email), formatOptional(createdApprover.SlackID), formatOptional(createdApprover.TeamsID), createdApprover.Locale, createdApprover.NotificationPreference, formatOptionalID(createdApprover.AlternateApproverID), formatOptionalID(createdApprover.ManagerID))
 			output.Println(message)
 			return nil
 		},
@@ -92,7 +133,8 @@ func UpdateApprover() *cli.Command {
 		Usage:   "Update an existing approver",
 		UsageText: ` 
 		    backend-challenge-cli update-approver --id 1 --name "John Doe Updated" --role "Senior Manager" --email "john.updated@example.com" --slack-id "U123456"
-		    backend-challenge-cli ua -i 1 -n "Jane Smith Updated" -r "VP" -e "jane.updated@example.com" -s "U789012"`,
+		    backend-challenge-cli ua -i 1 -n "Jane Smith Updated" -r "VP" -e "jane.updated@example.com" -s "U789012" -np hourly_digest
+		    backend-challenge-cli ua -i 2 -n "Vera Sander" -r "Finance Department Manager" -e "vera_sander@light.com" -a 3`,
 		Flags: []cli.Flag{
 			&cli.IntFlag{
 				Name:     "id",
@@ -113,41 +155,74 @@ func UpdateApprover() *cli.Command {
 				Required: true,
 			},
 			&cli.StringFlag{
-				Name:     "email",
-				Aliases:  []string{"e"},
-				Usage:    "Email address of the approver, required",
-				Required: true,
+				Name:    "email",
+				Aliases: []string{"e"},
+				Usage:   "Email address of the approver, optional if the approver has another contact",
 			},
 			&cli.StringFlag{
-				Name:     "slack-id",
-				Aliases:  []string{"s"},
-				Usage:    "Slack ID of the approver, required",
-				Required: true,
+				Name:    "slack-id",
+				Aliases: []string{"s"},
+				Usage:   "Slack ID of the approver, optional if the approver has another contact",
+			},
+			&cli.StringFlag{
+				Name:    "teams-id",
+				Aliases: []string{"t"},
+				Usage:   "Microsoft Teams user ID (Entra object ID or user principal name) of the approver, optional",
+			},
+			&cli.StringFlag{
+				Name:    "locale",
+				Aliases: []string{"l"},
+				Usage:   fmt.Sprintf("Locale of the approver's notifications (%s)", strings.Join(api.Locales, ", ")),
+				Value:   api.DefaultLocale,
+			},
+			&cli.StringFlag{
+				Name:    "notification-preference",
+				Aliases: []string{"np"},
+				Usage:   fmt.Sprintf("When the approver receives approval requests (%s)", strings.Join(api.NotificationPreferences, ", ")),
+				Value:   api.NotificationImmediate,
+			},
+			&cli.IntFlag{
+				Name:    "alternate-approver-id",
+				Aliases: []string{"a"},
+				Usage:   "ID of the approver who receives the invoices this approver submitted, optional; defaults to their manager",
+			},
+			&cli.IntFlag{
+				Name:    "manager-id",
+				Aliases: []string{"m"},
+				Usage:   "ID of the approver this approver reports to, optional",
 			},
 		},
 		Action: func(c *cli.Context) error {
 			// Get CLI config from global flags
 			cliConfig := &Config{
-				Company:     c.String("company"),
-				Departments: c.String("departments"),
-				SlackConn:   c.String("slack-connection-string"),
-				EmailConn:   c.String("email-connection-string"),
-				Verbose:     c.Bool("verbose"),
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
 			}
 
 			// Setup services
-			services, err := setupServicesWithConfig(cliConfig)
+			services, err := setupServicesAsUser(cliConfig)
 			if err != nil {
 				return fmt.Errorf("failed to setup services: %w", err)
 			}
 
 			// Update approver
 			approver := api.Approver{
-				ID:      c.Int("id"),
-				Name:    c.String("name"),
-				Role:    c.String("role"),
-				Email:   c.String("email"),
-				SlackID: c.String("slack-id"),
+				ID:                     c.Int("id"),
+				Name:                   c.String("name"),
+				Role:                   c.String("role"),
+				Email:                  c.String("email"),
+				SlackID:                c.String("slack-id"),
+				TeamsID:                c.String("teams-id"),
+				Locale:                 c.String("locale"),
+				NotificationPreference: c.String("notification-preference"),
+				AlternateApproverID:    c.Int("alternate-approver-id"),
+				ManagerID:              c.Int("manager-id"),
 			}
 
 			err = services.Management.UpdateApprover(approver)
@@ -160,9 +235,14 @@ func UpdateApprover() *cli.Command {
 				"Name: %s\n"+
 				"Role: %s\n"+
 				"Email: %s\n"+
-				"Slack ID: %s",
+				"Slack ID: %s\n"+
+				"Teams ID: %s\n"+
+				"Locale: %s\n"+
+				"Notification preference: %s\n"+
+				"Alternate approver ID: %s\n"+
+				"Manager ID: %s",
 				approver.ID, approver.Name, approver.Role,
-				approver.Email, approver.SlackID)
+				formatOptional(approver.Email), formatOptional(approver.SlackID), formatOptional(approver.TeamsID), approver.Locale, approver.NotificationPreference, formatOptionalID(approver.AlternateApproverID), formatOptionalID(approver.ManagerID))
 			output.Println(message)
 			return nil
 		},
@@ -188,15 +268,18 @@ func DeleteApprover() *cli.Command {
 		Action: func(c *cli.Context) error {
 			// Get CLI config from global flags
 			cliConfig := &Config{
-				Company:     c.String("company"),
-				Departments: c.String("departments"),
-				SlackConn:   c.String("slack-connection-string"),
-				EmailConn:   c.String("email-connection-string"),
-				Verbose:     c.Bool("verbose"),
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
 			}
 
 			// Setup services
-			services, err := setupServicesWithConfig(cliConfig)
+			services, err := setupServicesAsUser(cliConfig)
 			if err != nil {
 				return fmt.Errorf("failed to setup services: %w", err)
 			}
@@ -233,15 +316,18 @@ func GetApproverByID() *cli.Command {
 		Action: func(c *cli.Context) error {
 			// Get CLI config from global flags
 			cliConfig := &Config{
-				Company:     c.String("company"),
-				Departments: c.String("departments"),
-				SlackConn:   c.String("slack-connection-string"),
-				EmailConn:   c.String("email-connection-string"),
-				Verbose:     c.Bool("verbose"),
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
 			}
 
 			// Setup services
-			services, err := setupServicesWithConfig(cliConfig)
+			services, err := setupServicesAsUser(cliConfig)
 			if err != nil {
 				return fmt.Errorf("failed to setup services: %w", err)
 			}
@@ -258,9 +344,14 @@ func GetApproverByID() *cli.Command {
 				"Name: %s\n"+
 				"Role: %s\n"+
 				"Email: %s\n"+
-				"Slack ID: %s",
+				"Slack ID: %s\n"+
+				"Teams ID: %s\n"+
+				"Locale: %s\n"+
+				"Notification preference: %s\n"+
+				"Alternate approver ID: %s\n"+
+				"Manager ID: %s",
 				approver.ID, approver.Name, approver.Role,
-				approver.Email, approver.SlackID)
+				formatOptional(approver.Email), formatOptional(approver.SlackID), formatOptional(approver.TeamsID), approver.Locale, approver.NotificationPreference, formatOptionalID(approver.AlternateApproverID), formatOptionalID(approver.ManagerID))
 			output.Println(message)
 			return nil
 		},
@@ -278,15 +369,18 @@ func ListApprovers() *cli.Command {
 		Action: func(c *cli.Context) error {
 			// Get CLI config from global flags
 			cliConfig := &Config{
-				Company:     c.String("company"),
-				Departments: c.String("departments"),
-				SlackConn:   c.String("slack-connection-string"),
-				EmailConn:   c.String("email-connection-string"),
-				Verbose:     c.Bool("verbose"),
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
 			}
 
 			// Setup services
-			services, err := setupServicesWithConfig(cliConfig)
+			services, err := setupServicesAsUser(cliConfig)
 			if err != nil {
 				return fmt.Errorf("failed to setup services: %w", err)
 			}
@@ -302,8 +396,8 @@ func ListApprovers() *cli.Command {
 			} else {
 				output.Println(fmt.Sprintf("Found %d approver(s):", len(approvers)))
 				for _, approver := range approvers {
-					message := fmt.Sprintf("ID: %d | Name: %s | Role: %s | Email: %s | Slack ID: %s",
-						approver.ID, approver.Name, approver.Role, approver.Email, approver.SlackID)
+					message := fmt.Sprintf("ID: %d | Name: %s | Role: %s | Email: %s | Slack ID: %s | Teams ID: %s | Locale: %s | Notifications: %s | Alternate: %s | Manager: %s",
+						approver.ID, approver.Name, approver.Role, formatOptional(approver.Email), formatOptional(approver.SlackID), formatOptional(approver.TeamsID), approver.Locale, approver.NotificationPreference, formatOptionalID(approver.AlternateApproverID), formatOptionalID(approver.ManagerID))
 					output.Println(message)
 				}
 			}
@@ -311,3 +405,85 @@ func ListApprovers() *cli.Command {
 		},
 	}
 }
+
+func OrgChart() *cli.Command {
+	return &cli.Command{
+		Name:    "org-chart",
+		Aliases: []string{"oc"},
+		Usage:   "Show the reporting hierarchy of the company's approvers",
+		UsageText: ` 
+		    backend-challenge-cli org-chart
+		    backend-challenge-cli oc --id 2`,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:    "id",
+				Aliases: []string{"i"},
+				Usage:   "ID of the approver to show the reports of, optional; defaults to the whole company",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Get org chart
+			nodes, err := services.Management.OrgChart(c.Int("id"))
+			if err != nil {
+				return fmt.Errorf("failed to get org chart: %w", err)
+			}
+
+			if len(nodes) == 0 {
+				output.Println("No approvers found for this company.")
+				return nil
+			}
+			for _, node := range nodes {
+				printOrgChartNode(node, "", "")
+			}
+			return nil
+		},
+	}
+}
+
+// printOrgChartNode prints the approver of the node and, below them, the
+// approvers reporting to them as a tree. prefix is printed before the
+// approver, and indent before their reports.
+func printOrgChartNode(node api.OrgChartNode, prefix, indent string) {
+	output.Println(fmt.Sprintf("%s%s (%s, ID %d)", prefix, node.Approver.Name, node.Approver.Role, node.Approver.ID))
+	for i, report := range node.Reports {
+		if i == len(node.Reports)-1 {
+			printOrgChartNode(report, indent+"└── ", indent+"    ")
+		} else {
+			printOrgChartNode(report, indent+"├── ", indent+"│   ")
+		}
+	}
+}
+
+// formatOptional formats an optional approver field.
+func formatOptional(s string) string {
+	if s == "" {
+		return "Not set"
+	}
+	return s
+}
+
+// formatOptionalID formats an optional ID field.
+func formatOptionalID(id int) string {
+	if id == 0 {
+		return "Not set"
+	}
+	return strconv.Itoa(id)
+}
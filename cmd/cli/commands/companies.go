@@ -0,0 +1,435 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/cmd/cli/output"
+	"github.com/KatrinSalt/backend-challenge-go/config"
+	"github.com/urfave/cli/v2"
+)
+
+func CreateCompany() *cli.Command {
+	return &cli.Command{
+		Name:    "create-company",
+		Aliases: []string{"cc"},
+		Usage:   "Create a new company with its departments",
+		UsageText: ` 
+		    backend-challenge-cli create-company --name "Acme" --departments "Finance,Marketing"
+		    backend-challenge-cli create-company --name "Acme" --fallback-channels "email"
+		    backend-challenge-cli cc -n "Acme" -d "Engineering"`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "name",
+				Aliases:  []string{"n"},
+				Usage:    "Name of the company, required",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "departments",
+				Aliases: []string{"d"},
+				Usage:   "Comma-separated list of departments (e.g., 'Finance,Marketing'), optional",
+			},
+			&cli.StringFlag{
+				Name:    "fallback-channels",
+				Aliases: []string{"fc"},
+				Usage:   fmt.Sprintf("Comma-separated default fallback channels for the company's workflow rules (%s), optional", strings.Join(config.ChannelNames(), ", ")),
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Create company
+			company := api.Company{
+				Name:             c.String("name"),
+				Departments:      parseDepartmentsFlag(c.String("departments")),
+				FallbackChannels: parseChannelsFlag(c.String("fallback-channels")),
+			}
+
+			createdCompany, err := services.Management.CreateCompany(company)
+			if err != nil {
+				return fmt.Errorf("failed to create company: %w", err)
+			}
+
+			message := fmt.Sprintf("✅ Company created successfully!\n"+
+				"ID: %d\n"+
+				"Name: %s\n"+
+				"Departments: %s\n"+
+				"Fallback Channels: %s",
+				createdCompany.ID, createdCompany.Name, formatDepartments(createdCompany.Departments),
+				formatChannels(createdCompany.FallbackChannels))
+			output.Println(message)
+			return nil
+		},
+	}
+}
+
+func UpdateCompany() *cli.Command {
+	return &cli.Command{
+		Name:    "update-company",
+		Aliases: []string{"uc"},
+		Usage:   "Rename an existing company or change its fallback channels",
+		UsageText: ` 
+		    backend-challenge-cli update-company --id 2 --name "Acme Inc"
+		    backend-challenge-cli update-company --id 2 --name "Acme Inc" --fallback-channels "teams,email"
+		    backend-challenge-cli uc -i 2 -n "Acme Inc"`,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:     "id",
+				Aliases:  []string{"i"},
+				Usage:    "ID of the company to update, required",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "name",
+				Aliases:  []string{"n"},
+				Usage:    "New name of the company, required",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "fallback-channels",
+				Aliases: []string{"fc"},
+				Usage:   fmt.Sprintf("Comma-separated default fallback channels (%s), optional; an empty value clears them", strings.Join(config.ChannelNames(), ", ")),
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Update company
+			company := api.Company{
+				ID:   c.Int("id"),
+				Name: c.String("name"),
+			}
+			if c.IsSet("fallback-channels") {
+				company.FallbackChannels = parseChannelsFlag(c.String("fallback-channels"))
+			} else {
+				// Keep the company's current fallback channels
+				current, err := services.Management.GetCompanyByID(company.ID)
+				if err != nil {
+					return fmt.Errorf("failed to update company: %w", err)
+				}
+				company.FallbackChannels = current.FallbackChannels
+			}
+
+			if err := services.Management.UpdateCompany(company); err != nil {
+				return fmt.Errorf("failed to update company: %w", err)
+			}
+
+			output.Println(fmt.Sprintf("✅ Company with ID %d updated successfully!", company.ID))
+			return nil
+		},
+	}
+}
+
+func DeleteCompany() *cli.Command {
+	return &cli.Command{
+		Name:    "delete-company",
+		Aliases: []string{"dc"},
+		Usage:   "Delete a company and its departments",
+		UsageText: ` 
+		    backend-challenge-cli delete-company --id 2
+		    backend-challenge-cli dc -i 2`,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:     "id",
+				Aliases:  []string{"i"},
+				Usage:    "ID of the company to delete, required",
+				Required: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Delete company
+			id := c.Int("id")
+			if err := services.Management.DeleteCompany(id); err != nil {
+				return fmt.Errorf("failed to delete company: %w", err)
+			}
+
+			output.Println(fmt.Sprintf("✅ Company with ID %d deleted successfully!", id))
+			return nil
+		},
+	}
+}
+
+func GetCompanyByID() *cli.Command {
+	return &cli.Command{
+		Name:    "get-company",
+		Aliases: []string{"gc"},
+		Usage:   "Get a company by ID",
+		UsageText: ` 
+		    backend-challenge-cli get-company --id 1
+		    backend-challenge-cli gc -i 1`,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:     "id",
+				Aliases:  []string{"i"},
+				Usage:    "ID of the company to fetch, required",
+				Required: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Get company
+			company, err := services.Management.GetCompanyByID(c.Int("id"))
+			if err != nil {
+				return fmt.Errorf("failed to get company: %w", err)
+			}
+
+			message := fmt.Sprintf("✅ Company found!\n"+
+				"ID: %d\n"+
+				"Name: %s\n"+
+				"Departments: %s\n"+
+				"Fallback Channels: %s",
+				company.ID, company.Name, formatDepartments(company.Departments), formatChannels(company.FallbackChannels))
+			output.Println(message)
+			return nil
+		},
+	}
+}
+
+func ListCompanies() *cli.Command {
+	return &cli.Command{
+		Name:    "list-companies",
+		Aliases: []string{"lc"},
+		Usage:   "List all companies",
+		UsageText: ` 
+		    backend-challenge-cli list-companies
+		    backend-challenge-cli lc`,
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// List companies
+			companies, err := services.Management.ListCompanies()
+			if err != nil {
+				return fmt.Errorf("failed to list companies: %w", err)
+			}
+
+			if len(companies) == 0 {
+				output.Println("No companies found.")
+			} else {
+				output.Println(fmt.Sprintf("Found %d company(ies):", len(companies)))
+				for _, company := range companies {
+					message := fmt.Sprintf("ID: %d | Name: %s | Departments: %s | Fallback Channels: %s",
+						company.ID, company.Name, formatDepartments(company.Departments), formatChannels(company.FallbackChannels))
+					output.Println(message)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func AddDepartment() *cli.Command {
+	return &cli.Command{
+		Name:    "add-department",
+		Aliases: []string{"ad"},
+		Usage:   "Add a department to the company",
+		UsageText: ` 
+		    backend-challenge-cli add-department --name "Engineering"
+		    backend-challenge-cli --company "Light" ad -n "Sales"`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "name",
+				Aliases:  []string{"n"},
+				Usage:    "Name of the department, required",
+				Required: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Add department
+			department, err := services.Management.AddDepartment(c.String("name"))
+			if err != nil {
+				return fmt.Errorf("failed to add department: %w", err)
+			}
+
+			output.Println(fmt.Sprintf("✅ Department %s added to company %s successfully!", department, cliConfig.Company))
+			return nil
+		},
+	}
+}
+
+func RemoveDepartment() *cli.Command {
+	return &cli.Command{
+		Name:    "remove-department",
+		Aliases: []string{"rd"},
+		Usage:   "Remove a department from the company",
+		UsageText: ` 
+		    backend-challenge-cli remove-department --name "Marketing"
+		    backend-challenge-cli --company "Light" rd -n "Finance"`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "name",
+				Aliases:  []string{"n"},
+				Usage:    "Name of the department, required",
+				Required: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Remove department
+			name := c.String("name")
+			if err := services.Management.RemoveDepartment(name); err != nil {
+				return fmt.Errorf("failed to remove department: %w", err)
+			}
+
+			output.Println(fmt.Sprintf("✅ Department %s removed from company %s successfully!", name, cliConfig.Company))
+			return nil
+		},
+	}
+}
+
+// parseDepartmentsFlag splits a comma-separated list of departments.
+func parseDepartmentsFlag(value string) []string {
+	var departments []string
+	for _, department := range strings.Split(value, ",") {
+		if department = strings.TrimSpace(department); department != "" {
+			departments = append(departments, department)
+		}
+	}
+	return departments
+}
+
+// formatDepartments formats a list of departments for display.
+func formatDepartments(departments []string) string {
+	if len(departments) == 0 {
+		return "None"
+	}
+	return strings.Join(departments, ", ")
+}
+
+// parseChannelsFlag splits a comma-separated list of channel names.
+func parseChannelsFlag(value string) []string {
+	var channels []string
+	for _, channel := range strings.Split(value, ",") {
+		if channel = strings.TrimSpace(channel); channel != "" {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// formatChannels formats a list of channel names for display.
+func formatChannels(channels []string) string {
+	if len(channels) == 0 {
+		return "None"
+	}
+	return strings.Join(channels, ", ")
+}
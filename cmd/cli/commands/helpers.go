@@ -1,7 +1,11 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
 
 	"github.com/KatrinSalt/backend-challenge-go/common"
 	"github.com/KatrinSalt/backend-challenge-go/config"
@@ -9,11 +13,20 @@ import (
 
 // Config holds the CLI configuration
 type Config struct {
-	Company     string
-	Departments string
-	SlackConn   string
-	EmailConn   string
-	Verbose     bool
+	Company            string
+	AsUser             string
+	DatabasePath       string
+	DevSampleAPIKey    bool
+	SlackConn          string
+	SlackSigningSecret string
+	EmailConn          string
+	EmailLinkSecret    string
+	EmailLinkBaseURL   string
+	TeamsConn          string
+	WebhookConn        string
+	WebhookSecret      string
+	TemplatesDir       string
+	Verbose            bool
 }
 
 // setupServicesWithConfig loads configuration using CLI config and sets up all required services
@@ -24,15 +37,39 @@ func setupServicesWithConfig(cliConfig *Config) (*config.Services, error) {
 	}
 
 	// Add optional flags if they are set
-	if cliConfig.Departments != "" {
-		flags = append(flags, "--departments", cliConfig.Departments)
-	}
 	if cliConfig.SlackConn != "" {
 		flags = append(flags, "--slack-connection-string", cliConfig.SlackConn)
 	}
+	if cliConfig.SlackSigningSecret != "" {
+		flags = append(flags, "--slack-signing-secret", cliConfig.SlackSigningSecret)
+	}
 	if cliConfig.EmailConn != "" {
 		flags = append(flags, "--email-connection-string", cliConfig.EmailConn)
 	}
+	if cliConfig.EmailLinkSecret != "" {
+		flags = append(flags, "--email-link-secret", cliConfig.EmailLinkSecret)
+	}
+	if cliConfig.EmailLinkBaseURL != "" {
+		flags = append(flags, "--email-link-base-url", cliConfig.EmailLinkBaseURL)
+	}
+	if cliConfig.TeamsConn != "" {
+		flags = append(flags, "--teams-connection-string", cliConfig.TeamsConn)
+	}
+	if cliConfig.WebhookConn != "" {
+		flags = append(flags, "--webhook-connection-string", cliConfig.WebhookConn)
+	}
+	if cliConfig.WebhookSecret != "" {
+		flags = append(flags, "--webhook-secret", cliConfig.WebhookSecret)
+	}
+	if cliConfig.TemplatesDir != "" {
+		flags = append(flags, "--templates-dir", cliConfig.TemplatesDir)
+	}
+	if cliConfig.DatabasePath != "" {
+		flags = append(flags, "--database-path", cliConfig.DatabasePath)
+	}
+	if cliConfig.DevSampleAPIKey {
+		flags = append(flags, "--dev-sample-api-key")
+	}
 
 	// Parse flags
 	parsedFlags, err := config.ParseFlags(flags)
@@ -57,3 +94,59 @@ func setupServicesWithConfig(cliConfig *Config) (*config.Services, error) {
 
 	return services, nil
 }
+
+// setupServicesAsUser sets up the services like setupServicesWithConfig and
+// makes them act as the user of --as-user, so that only the operations
+// their role allows are performed. It fails without a user, so management
+// and workflow commands never run unchecked.
+func setupServicesAsUser(cliConfig *Config) (*config.Services, error) {
+	if cliConfig.AsUser == "" {
+		return nil, errors.New("--as-user or AS_USER is required: set the email of the user to act as")
+	}
+
+	services, err := setupServicesWithConfig(cliConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := services.ActAs(cliConfig.AsUser); err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+// workflowRuleFlags maps workflow rule fields to the flags that set them.
+var workflowRuleFlags = map[string]string{
+	"min_amount":                   "min-amount",
+	"max_amount":                   "max-amount",
+	"min_bound":                    "min-bound",
+	"max_bound":                    "max-bound",
+	"department":                   "department",
+	"approver_id":                  "approver-id",
+	"approval_channel":             "approval-channel",
+	"fallback_channels":            "fallback-channels",
+	"is_manager_approval_required": "manager-approval",
+	"manager_levels":               "manager-levels",
+}
+
+// formatValidationError renders the field errors of an *api.ValidationError
+// one per line, keyed by the flag that sets each field. Other errors are
+// returned unchanged.
+func formatValidationError(msg string, err error, flags map[string]string) error {
+	var verr *api.ValidationError
+	if !errors.As(err, &verr) {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	var b strings.Builder
+	b.WriteString(msg + ":")
+	for _, field := range verr.Fields {
+		name := field.Field
+		if flag, ok := flags[field.Field]; ok {
+			name = "--" + flag
+		}
+		fmt.Fprintf(&b, "\n  %s: %v", name, field.Err)
+	}
+
+	return errors.New(b.String())
+}
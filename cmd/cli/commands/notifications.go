@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/cmd/cli/output"
+	"github.com/KatrinSalt/backend-challenge-go/notification/email"
+	"github.com/KatrinSalt/backend-challenge-go/notification/templates"
+	"github.com/urfave/cli/v2"
+)
+
+func PreviewNotification() *cli.Command {
+	return &cli.Command{
+		Name:    "preview-notification",
+		Aliases: []string{"pn"},
+		Usage:   "Render the approval request notification of a channel for a sample invoice",
+		UsageText: ` 
+		    backend-challenge-cli preview-notification
+		    backend-challenge-cli preview-notification --channel slack --locale sv
+		    backend-challenge-cli pn -ch teams -l de --templates-dir ./templates
+		    backend-challenge-cli pn -ch slack --digest`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "channel",
+				Aliases: []string{"ch"},
+				Usage:   "Channel to render the notification for (email, slack, teams)",
+				Value:   email.ChannelName,
+			},
+			&cli.StringFlag{
+				Name:    "locale",
+				Aliases: []string{"l"},
+				Usage:   fmt.Sprintf("Locale to render the notification in (%s)", strings.Join(api.Locales, ", ")),
+				Value:   api.DefaultLocale,
+			},
+			&cli.BoolFlag{
+				Name:    "digest",
+				Aliases: []string{"d"},
+				Usage:   "Render the digest of several sample invoices instead",
+			},
+			&cli.StringFlag{
+				Name:    "templates-dir",
+				Usage:   "Directory of per-company notification template overrides",
+				EnvVars: []string{"NOTIFICATION_TEMPLATES_DIR"},
+			},
+		},
+		Action: func(c *cli.Context) error {
+			locale := c.String("locale")
+			if !slices.Contains(api.Locales, locale) {
+				return fmt.Errorf("%w: %q (must be one of: %s)", api.ErrUnsupportedLocale, locale, strings.Join(api.Locales, ", "))
+			}
+
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				TemplatesDir: c.String("templates-dir"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesWithConfig(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Render the notification for the sample invoice, or the digest
+			// of the sample invoices.
+			var content templates.Content
+			if c.Bool("digest") {
+				data := templates.SampleDigestData()
+				data.Company = cliConfig.Company
+				data.Locale = locale
+				content, err = services.Templates.RenderDigest(c.String("channel"), data)
+			} else {
+				data := templates.SampleData()
+				data.Company = cliConfig.Company
+				data.Locale = locale
+				content, err = services.Templates.Render(c.String("channel"), data)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to render notification: %w", err)
+			}
+
+			message := fmt.Sprintf("✅ Notification preview (%s, %s)\n"+
+				"Subject: %s\n\n"+
+				"%s",
+				c.String("channel"), locale, content.Subject, strings.TrimSpace(content.Text))
+			if content.HTML != "" {
+				message += "\n\nHTML:\n" + strings.TrimSpace(content.HTML)
+			}
+			output.Println(message)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/cmd/cli/output"
+	"github.com/urfave/cli/v2"
+)
+
+func ListDeadLetters() *cli.Command {
+	return &cli.Command{
+		Name:    "list-dead-letters",
+		Aliases: []string{"ldl"},
+		Usage:   "List the approval notifications that failed all their delivery attempts",
+		UsageText: ` 
+		    backend-challenge-cli list-dead-letters
+		    backend-challenge-cli ldl`,
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// List dead letters
+			messages, err := services.Management.ListOutboxMessages(api.OutboxStatusDead)
+			if err != nil {
+				return fmt.Errorf("failed to list dead letters: %w", err)
+			}
+
+			if len(messages) == 0 {
+				output.Println("No dead letters found for this company.")
+			} else {
+				output.Println(fmt.Sprintf("Found %d dead letter(s):", len(messages)))
+				for _, message := range messages {
+					output.Println(fmt.Sprintf("ID: %d | Approval Request: %d | Channel: %s | Attempts: %d | Created: %s | Last Error: %s",
+						message.ID,
+						message.ApprovalRequestID,
+						message.Channel,
+						message.Attempts,
+						message.CreatedAt.Format(time.RFC3339),
+						formatOptional(message.LastError)))
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func ReplayDeadLetter() *cli.Command {
+	return &cli.Command{
+		Name:    "replay-dead-letter",
+		Aliases: []string{"rdl"},
+		Usage:   "Move a dead letter back to the outbox and retry its delivery",
+		UsageText: ` 
+		    backend-challenge-cli replay-dead-letter --id 1
+		    backend-challenge-cli rdl -i 1`,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:     "id",
+				Aliases:  []string{"i"},
+				Usage:    "ID of the dead letter to replay, required",
+				Required: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Move the dead letter back to the outbox and dispatch it
+			message, err := services.Management.ReplayDeadLetter(c.Int("id"))
+			if err != nil {
+				return fmt.Errorf("failed to replay dead letter: %w", err)
+			}
+			if _, err := services.Workflow.DispatchOutbox(); err != nil {
+				return fmt.Errorf("failed to dispatch notifications: %w", err)
+			}
+
+			// The dispatch pass may deliver other notifications too, so
+			// report the replayed notification's own outcome.
+			message, err = services.Management.GetOutboxMessageByID(message.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get notification: %w", err)
+			}
+
+			switch message.Status {
+			case api.OutboxStatusDelivered:
+				output.Println(fmt.Sprintf("✅ Notification %d for approval request %d delivered via %s!", message.ID, message.ApprovalRequestID, message.Channel))
+			case api.OutboxStatusDead:
+				output.Println(fmt.Sprintf("❌ Notification %d failed again and is back in the dead letters.", message.ID))
+			default:
+				output.Println(fmt.Sprintf("⏳ Notification %d is queued for another delivery attempt.", message.ID))
+			}
+			return nil
+		},
+	}
+}
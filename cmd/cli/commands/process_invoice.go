@@ -1,9 +1,16 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/KatrinSalt/backend-challenge-go/cmd/cli/output"
+	"github.com/KatrinSalt/backend-challenge-go/notification/email"
 	"github.com/urfave/cli/v2"
 )
 
@@ -15,19 +22,80 @@ func ProcessInvoice() *cli.Command {
 		UsageText: ` 
 		    backend-challenge-cli process-invoice
 		    backend-challenge-cli invoice
-		    backend-challenge-cli i`,
+		    backend-challenge-cli i
+		    backend-challenge-cli process-invoice --slack-interaction-addr :3000 --slack-signing-secret "secret"
+		    backend-challenge-cli --webhook-connection-string https://tools.example.com/approvals process-invoice --webhook-secret "secret"
+		    backend-challenge-cli process-invoice --templates-dir ./templates
+		    backend-challenge-cli process-invoice --email-link-addr :3000 --email-link-secret "secret" --email-link-base-url https://approvals.example.com
+		    backend-challenge-cli process-invoice --email-replies-dir ~/Maildir/approvals`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "slack-interaction-addr",
+				Usage: "Address to listen on for Slack Approve/Reject button clicks while processing invoices, e.g. ':3000'",
+			},
+			&cli.StringFlag{
+				Name:    "slack-signing-secret",
+				Usage:   "Signing secret of the Slack app, required with --slack-interaction-addr",
+				EnvVars: []string{"SLACK_SIGNING_SECRET"},
+			},
+			&cli.StringFlag{
+				Name:  "email-link-addr",
+				Usage: "Address to listen on for approve/reject links clicked in approval emails while processing invoices, e.g. ':3000'",
+			},
+			&cli.StringFlag{
+				Name:    "email-link-secret",
+				Usage:   "Secret for signing the approve/reject links in approval emails, required with --email-link-addr",
+				EnvVars: []string{"EMAIL_LINK_SECRET"},
+			},
+			&cli.StringFlag{
+				Name:    "email-link-base-url",
+				Usage:   "Public base URL of the approve/reject links in approval emails (default: http://localhost:<port> of --email-link-addr)",
+				EnvVars: []string{"EMAIL_LINK_BASE_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "email-replies-dir",
+				Usage:   "Maildir or directory of .eml files to read replies to approval emails from while processing invoices",
+				EnvVars: []string{"EMAIL_REPLIES_DIR"},
+			},
+			&cli.DurationFlag{
+				Name:  "email-replies-interval",
+				Usage: "How often to check --email-replies-dir for new replies",
+				Value: 10 * time.Second,
+			},
+			&cli.StringFlag{
+				Name:    "webhook-secret",
+				Usage:   "Secret for signing the approval requests posted to the webhook channel",
+				EnvVars: []string{"WEBHOOK_SECRET"},
+			},
+			&cli.StringFlag{
+				Name:    "templates-dir",
+				Usage:   "Directory of per-company notification template overrides, laid out as <company>/<channel>/<locale>/",
+				EnvVars: []string{"NOTIFICATION_TEMPLATES_DIR"},
+			},
+		},
 		Action: func(c *cli.Context) error {
 			// Get CLI config from global flags
 			cliConfig := &Config{
-				Company:     c.String("company"),
-				Departments: c.String("departments"),
-				SlackConn:   c.String("slack-connection-string"),
-				EmailConn:   c.String("email-connection-string"),
-				Verbose:     c.Bool("verbose"),
+				Company:            c.String("company"),
+				DatabasePath:       c.String("database-path"),
+				AsUser:             c.String("as-user"),
+				SlackConn:          c.String("slack-connection-string"),
+				SlackSigningSecret: c.String("slack-signing-secret"),
+				EmailConn:          c.String("email-connection-string"),
+				TeamsConn:          c.String("teams-connection-string"),
+				WebhookConn:        c.String("webhook-connection-string"),
+				WebhookSecret:      c.String("webhook-secret"),
+				TemplatesDir:       c.String("templates-dir"),
+				EmailLinkSecret:    c.String("email-link-secret"),
+				EmailLinkBaseURL:   c.String("email-link-base-url"),
+				Verbose:            c.Bool("verbose"),
+			}
+			if cliConfig.EmailLinkBaseURL == "" && c.String("email-link-addr") != "" {
+				cliConfig.EmailLinkBaseURL = localBaseURL(c.String("email-link-addr"))
 			}
 
 			// Setup workflow services using CLI config
-			services, err := setupServicesWithConfig(cliConfig)
+			services, err := setupServicesAsUser(cliConfig)
 			if err != nil {
 				return fmt.Errorf("failed to setup services: %w", err)
 			}
@@ -36,7 +104,11 @@ func ProcessInvoice() *cli.Command {
 			if cliConfig.Verbose {
 				output.Printf("🔧 Configuration:\n")
 				output.Printf("   Company: %s\n", cliConfig.Company)
-				output.Printf("   Departments: %s\n", cliConfig.Departments)
+				departments, err := services.Management.ListDepartments()
+				if err != nil {
+					return fmt.Errorf("failed to list departments: %w", err)
+				}
+				output.Printf("   Departments: %s\n", strings.Join(departments, ","))
 				if cliConfig.SlackConn != "" {
 					output.Printf("   Slack Connection: %s\n", cliConfig.SlackConn[:8]+"...")
 				}
@@ -46,8 +118,43 @@ func ProcessInvoice() *cli.Command {
 				output.Println("")
 			}
 
+			// Listen for Slack interactions and email decision links while
+			// invoices are processed
+			routes := map[string]map[string]http.Handler{}
+			if addr := c.String("slack-interaction-addr"); addr != "" {
+				if services.SlackInteractions == nil {
+					return errors.New("--slack-signing-secret is required to listen for slack interactions")
+				}
+				addRoute(routes, addr, slackInteractionPath, services.SlackInteractions)
+			}
+			if addr := c.String("email-link-addr"); addr != "" {
+				if services.EmailDecisions == nil {
+					return errors.New("--email-link-secret is required to listen for email decision links")
+				}
+				addRoute(routes, addr, email.DecisionPath, services.EmailDecisions)
+			}
+			for addr, handlers := range routes {
+				stop, err := startDecisionServer(addr, handlers)
+				if err != nil {
+					return fmt.Errorf("failed to listen for approval decisions: %w", err)
+				}
+				defer stop()
+			}
+
+			// Retry failed notifications in the background while invoices
+			// are processed
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go services.Workflow.RunDispatcher(ctx)
+
+			// Record the decisions in replies to approval emails
+			if dir := c.String("email-replies-dir"); dir != "" {
+				go services.EmailReplies.Watch(ctx, dir, c.Duration("email-replies-interval"))
+				output.Printf("📥 Reading replies to approval emails from %s\n", dir)
+			}
+
 			// Run the interactive workflow
-			err = services.Workflow.Run()
+			err = services.Workflow.Run(cliConfig.AsUser)
 			if err != nil {
 				return fmt.Errorf("failed to process invoice: %w", err)
 			}
@@ -57,3 +164,60 @@ func ProcessInvoice() *cli.Command {
 		},
 	}
 }
+
+// slackInteractionPath is the path of the Slack interactivity request URL.
+const slackInteractionPath = "/slack/interactions"
+
+// addRoute registers handler for path on the server listening on addr.
+func addRoute(routes map[string]map[string]http.Handler, addr, path string, handler http.Handler) {
+	if routes[addr] == nil {
+		routes[addr] = map[string]http.Handler{}
+	}
+	routes[addr][path] = handler
+}
+
+// localBaseURL returns the base URL of a server listening on addr on this
+// machine.
+func localBaseURL(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "http://" + addr
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// startDecisionServer serves the handlers, keyed by path, on addr in the
+// background. The returned function shuts the server down.
+func startDecisionServer(addr string, handlers map[string]http.Handler) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	for path, handler := range handlers {
+		mux.Handle(path, handler)
+	}
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			output.PrintlnErr(fmt.Errorf("approval decision server stopped: %w", err))
+		}
+	}()
+	for path := range handlers {
+		output.Printf("🔔 Listening for approval decisions on http://%s%s\n", listener.Addr(), path)
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}, nil
+}
@@ -0,0 +1,226 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/cmd/cli/output"
+	"github.com/KatrinSalt/backend-challenge-go/common"
+	grpcserver "github.com/KatrinSalt/backend-challenge-go/grpc"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/notification/email"
+	"github.com/KatrinSalt/backend-challenge-go/server"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+	"github.com/urfave/cli/v2"
+)
+
+func Serve() *cli.Command {
+	return &cli.Command{
+		Name:    "serve",
+		Aliases: []string{"server", "s"},
+		Usage:   "Serve invoices, workflow rules and approvers as a JSON REST API and over gRPC",
+		UsageText: ` 
+		    backend-challenge-cli serve
+		    backend-challenge-cli serve --addr :9000
+		    backend-challenge-cli serve --grpc-addr :9090
+		    backend-challenge-cli serve --grpc-addr :9090 --http=false
+		    backend-challenge-cli --company "Acme" serve --slack-signing-secret "secret" --email-link-secret "secret" --email-link-base-url https://approvals.example.com`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "addr",
+				Usage:   "Address to listen on",
+				Value:   ":8080",
+				EnvVars: []string{"SERVER_ADDR"},
+			},
+			&cli.BoolFlag{
+				Name:  "http",
+				Usage: "Serve the JSON REST API on --addr. Set --http=false to serve only gRPC",
+				Value: true,
+			},
+			&cli.StringFlag{
+				Name:    "grpc-addr",
+				Usage:   "Address to serve the gRPC services on, with health checking and reflection (default: gRPC is not served)",
+				EnvVars: []string{"GRPC_ADDR"},
+			},
+			&cli.DurationFlag{
+				Name:  "shutdown-timeout",
+				Usage: "How long in-flight requests are given to complete on shutdown",
+				Value: 10 * time.Second,
+			},
+			&cli.StringFlag{
+				Name:    "slack-signing-secret",
+				Usage:   "Signing secret of the Slack app, to serve Slack Approve/Reject button clicks at /slack/interactions",
+				EnvVars: []string{"SLACK_SIGNING_SECRET"},
+			},
+			&cli.StringFlag{
+				Name:    "email-link-secret",
+				Usage:   "Secret for signing the approve/reject links in approval emails, to serve them at /email/decisions",
+				EnvVars: []string{"EMAIL_LINK_SECRET"},
+			},
+			&cli.StringFlag{
+				Name:    "email-link-base-url",
+				Usage:   "Public base URL of the approve/reject links in approval emails (default: http://localhost:<port> of --addr)",
+				EnvVars: []string{"EMAIL_LINK_BASE_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "email-replies-dir",
+				Usage:   "Maildir or directory of .eml files to read replies to approval emails from",
+				EnvVars: []string{"EMAIL_REPLIES_DIR"},
+			},
+			&cli.StringFlag{
+				Name:    "webhook-secret",
+				Usage:   "Secret for signing the approval requests posted to the webhook channel",
+				EnvVars: []string{"WEBHOOK_SECRET"},
+			},
+			&cli.BoolFlag{
+				Name:    "dev-sample-api-key",
+				Usage:   "Seed the public sample API key with every permission, for local testing only",
+				EnvVars: []string{"DEV_SAMPLE_API_KEY"},
+			},
+			&cli.StringFlag{
+				Name:    "templates-dir",
+				Usage:   "Directory of per-company notification template overrides, laid out as <company>/<channel>/<locale>/",
+				EnvVars: []string{"NOTIFICATION_TEMPLATES_DIR"},
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if !c.Bool("http") && c.String("grpc-addr") == "" {
+				return fmt.Errorf("nothing to serve: set --grpc-addr to serve gRPC when --http=false")
+			}
+
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:            c.String("company"),
+				DatabasePath:       c.String("database-path"),
+				SlackConn:          c.String("slack-connection-string"),
+				SlackSigningSecret: c.String("slack-signing-secret"),
+				EmailConn:          c.String("email-connection-string"),
+				TeamsConn:          c.String("teams-connection-string"),
+				WebhookConn:        c.String("webhook-connection-string"),
+				WebhookSecret:      c.String("webhook-secret"),
+				TemplatesDir:       c.String("templates-dir"),
+				EmailLinkSecret:    c.String("email-link-secret"),
+				EmailLinkBaseURL:   c.String("email-link-base-url"),
+				DevSampleAPIKey:    c.Bool("dev-sample-api-key"),
+				Verbose:            c.Bool("verbose"),
+			}
+			if cliConfig.EmailLinkBaseURL == "" {
+				cliConfig.EmailLinkBaseURL = localBaseURL(c.String("addr"))
+			}
+
+			services, err := setupServicesWithConfig(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Shut down gracefully on interrupt
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			// Serve each request for the company of its API key
+			companyServices := func(company string) (management.Service, workflow.Service, error) {
+				return services.ForCompany(ctx, company)
+			}
+
+			// Serve the decision handlers that are configured next to the
+			// API
+			handlers := map[string]http.Handler{}
+			if services.SlackInteractions != nil {
+				handlers[slackInteractionPath] = services.SlackInteractions
+			}
+			if services.EmailDecisions != nil {
+				handlers[email.DecisionPath] = services.EmailDecisions
+			}
+
+			var servers []func(ctx context.Context) error
+			if c.Bool("http") {
+				srv, err := server.NewServer(services.Management, services.Workflow, server.WithOptions(server.Options{
+					Logger:          common.NewLogger(),
+					Addr:            c.String("addr"),
+					ShutdownTimeout: c.Duration("shutdown-timeout"),
+					Handlers:        handlers,
+				}), server.WithAuthentication(services.Authenticator, companyServices))
+				if err != nil {
+					return fmt.Errorf("failed to create server: %w", err)
+				}
+				servers = append(servers, func(ctx context.Context) error {
+					err := srv.ListenAndServe(ctx, func(addr net.Addr) {
+						output.Printf("🌐 Serving the %s API on http://%s\n", cliConfig.Company, addr)
+						for path := range handlers {
+							output.Printf("🔔 Listening for approval decisions on http://%s%s\n", addr, path)
+						}
+					})
+					if err != nil {
+						return fmt.Errorf("failed to serve API: %w", err)
+					}
+					return nil
+				})
+			}
+			if addr := c.String("grpc-addr"); addr != "" {
+				srv, err := grpcserver.NewServer(services.Management, services.Workflow, grpcserver.WithOptions(grpcserver.Options{
+					Logger:          common.NewLogger(),
+					Addr:            addr,
+					ShutdownTimeout: c.Duration("shutdown-timeout"),
+				}), grpcserver.WithAuthentication(services.Authenticator, companyServices))
+				if err != nil {
+					return fmt.Errorf("failed to create gRPC server: %w", err)
+				}
+				servers = append(servers, func(ctx context.Context) error {
+					err := srv.ListenAndServe(ctx, func(addr net.Addr) {
+						output.Printf("📡 Serving the %s gRPC services on %s\n", cliConfig.Company, addr)
+					})
+					if err != nil {
+						return fmt.Errorf("failed to serve gRPC: %w", err)
+					}
+					return nil
+				})
+			}
+
+			// Retry failed notifications and record email replies in the
+			// background while serving
+			go services.Workflow.RunDispatcher(ctx)
+			if dir := c.String("email-replies-dir"); dir != "" {
+				go services.EmailReplies.Watch(ctx, dir, 0)
+			}
+
+			if err := serveAll(ctx, servers); err != nil {
+				return err
+			}
+
+			output.Println("👋 Server stopped")
+			return nil
+		},
+	}
+}
+
+// serveAll runs the servers until the context is cancelled or one of them
+// fails, which stops the others. It returns the first error.
+func serveAll(ctx context.Context, servers []func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(servers))
+	for _, serve := range servers {
+		go func() {
+			err := serve(ctx)
+			if err != nil {
+				cancel()
+			}
+			errs <- err
+		}()
+	}
+
+	var first error
+	for range servers {
+		if err := <-errs; err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
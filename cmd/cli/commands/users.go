@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/cmd/cli/output"
+	"github.com/urfave/cli/v2"
+)
+
+// userFlags maps user fields to the flags that set them.
+var userFlags = map[string]string{
+	"name":  "name",
+	"email": "email",
+	"role":  "role",
+}
+
+func CreateUser() *cli.Command {
+	roles := make([]string, len(api.Roles))
+	for i, role := range api.Roles {
+		roles[i] = string(role)
+	}
+
+	return &cli.Command{
+		Name:    "create-user",
+		Aliases: []string{"cu"},
+		Usage:   "Create a user of the company with a role that determines what they are allowed to do",
+		UsageText: ` 
+		    backend-challenge-cli create-user --name "Erik Lind" --email "erik@light.com" --role approver
+		    backend-challenge-cli cu -n "Sara Ek" -e "sara@light.com" -r viewer`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "name",
+				Aliases:  []string{"n"},
+				Usage:    "Name of the user, required",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "email",
+				Aliases:  []string{"e"},
+				Usage:    "Email address of the user, unique within the company, required",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "role",
+				Aliases:  []string{"r"},
+				Usage:    fmt.Sprintf("Role of the user (%s), required", strings.Join(roles, ", ")),
+				Required: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Create user
+			user, err := services.Management.CreateUser(api.User{
+				Name:  c.String("name"),
+				Email: c.String("email"),
+				Role:  api.Role(c.String("role")),
+			})
+			if err != nil {
+				return formatValidationError("failed to create user", err, userFlags)
+			}
+
+			message := fmt.Sprintf("✅ User created successfully!\n"+
+				"ID: %d\n"+
+				"Name: %s\n"+
+				"Email: %s\n"+
+				"Role: %s",
+				user.ID, user.Name, user.Email, user.Role)
+			output.Println(message)
+			return nil
+		},
+	}
+}
+
+func DeleteUser() *cli.Command {
+	return &cli.Command{
+		Name:    "delete-user",
+		Aliases: []string{"du"},
+		Usage:   "Delete a user of the company by ID, which also rejects the API keys acting as them",
+		UsageText: ` 
+		    backend-challenge-cli delete-user --id 1
+		    backend-challenge-cli du -i 1`,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:     "id",
+				Aliases:  []string{"i"},
+				Usage:    "ID of the user to delete, required",
+				Required: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// Delete user
+			id := c.Int("id")
+			if err := services.Management.DeleteUser(id); err != nil {
+				return fmt.Errorf("failed to delete user: %w", err)
+			}
+
+			output.Println(fmt.Sprintf("✅ User with ID %d deleted successfully!", id))
+			return nil
+		},
+	}
+}
+
+func ListUsers() *cli.Command {
+	return &cli.Command{
+		Name:    "list-users",
+		Aliases: []string{"lu"},
+		Usage:   "List the users of the company and their roles",
+		UsageText: ` 
+		    backend-challenge-cli list-users
+		    backend-challenge-cli --company "Acme" lu`,
+		Action: func(c *cli.Context) error {
+			// Get CLI config from global flags
+			cliConfig := &Config{
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
+			}
+
+			// Setup services
+			services, err := setupServicesAsUser(cliConfig)
+			if err != nil {
+				return fmt.Errorf("failed to setup services: %w", err)
+			}
+
+			// List users
+			users, err := services.Management.ListUsers()
+			if err != nil {
+				return fmt.Errorf("failed to list users: %w", err)
+			}
+
+			if len(users) == 0 {
+				output.Println("No users found for this company.")
+			} else {
+				output.Println(fmt.Sprintf("Found %d user(s):", len(users)))
+				for _, user := range users {
+					output.Println(fmt.Sprintf("ID: %d | Name: %s | Email: %s | Role: %s",
+						user.ID, user.Name, user.Email, user.Role))
+				}
+			}
+			return nil
+		},
+	}
+}
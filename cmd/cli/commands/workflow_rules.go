@@ -2,9 +2,13 @@ package commands
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/KatrinSalt/backend-challenge-go/api"
 	"github.com/KatrinSalt/backend-challenge-go/cmd/cli/output"
+	"github.com/KatrinSalt/backend-challenge-go/config"
+	"github.com/KatrinSalt/backend-challenge-go/money"
 	"github.com/urfave/cli/v2"
 )
 
@@ -14,18 +18,37 @@ func CreateWorkflowRule() *cli.Command {
 		Aliases: []string{"cwr"},
 		Usage:   "Create a new workflow rule",
 		UsageText: ` 
-		    backend-challenge-cli create-workflow-rule --min-amount 100 --max-amount 500 --department "Finance" --approver-id 1 --approval-channel 0 --manager-approval 1
-		    backend-challenge-cli cwr -min 100 -max 500 -d "Marketing" -aid 1 -ac 0 -ma 0`,
+		    backend-challenge-cli create-workflow-rule --min-amount 100 --max-amount 500 --department "Finance" --approver-id 1 --approval-channel slack --manager-approval 1
+		    backend-challenge-cli create-workflow-rule --min-amount 100 --approver-id 1 --approval-channel slack --fallback-channels email
+		    backend-challenge-cli create-workflow-rule --min-amount 1000 --max-amount 5000 --max-bound inclusive --approver-id 1 --approval-channel slack
+		    backend-challenge-cli create-workflow-rule --min-amount 20000 --approver-id 3 --manager-levels 2 --approval-channel email
+		    backend-challenge-cli cwr -min 100 -max 500 -d "Marketing" -aid 1 -ac slack -ma 0`,
 		Flags: []cli.Flag{
-			&cli.Float64Flag{
+			&cli.StringFlag{
 				Name:    "min-amount",
 				Aliases: []string{"min"},
-				Usage:   "Minimum amount for the rule (optional)",
+				Usage:   "Minimum amount for the rule, at most 2 decimal places (optional)",
 			},
-			&cli.Float64Flag{
+			&cli.StringFlag{
 				Name:    "max-amount",
 				Aliases: []string{"max"},
-				Usage:   "Maximum amount for the rule (optional)",
+				Usage:   "Maximum amount for the rule, at most 2 decimal places (optional)",
+			},
+			&cli.StringFlag{
+				Name:    "min-bound",
+				Aliases: []string{"minb"},
+				Usage:   "Whether the minimum amount is inclusive or exclusive (optional, default: inclusive)",
+			},
+			&cli.StringFlag{
+				Name:    "max-bound",
+				Aliases: []string{"maxb"},
+				Usage:   "Whether the maximum amount is inclusive or exclusive (optional, default: exclusive)",
+			},
+			&cli.StringFlag{
+				Name:    "currency",
+				Aliases: []string{"cur"},
+				Usage:   "Currency of the rule amounts, requires --min-amount or --max-amount (optional)",
+				Value:   string(money.DefaultCurrency),
 			},
 			&cli.StringFlag{
 				Name:    "department",
@@ -38,49 +61,70 @@ func CreateWorkflowRule() *cli.Command {
 				Usage:    "ID of the approver for this rule, required",
 				Required: true,
 			},
-			&cli.IntFlag{
+			&cli.StringFlag{
 				Name:     "approval-channel",
 				Aliases:  []string{"ac"},
-				Usage:    "Approval channel (0=Slack, 1=Email), required",
+				Usage:    fmt.Sprintf("Name of the approval channel (%s), required", strings.Join(config.ChannelNames(), ", ")),
 				Required: true,
 			},
+			&cli.StringFlag{
+				Name:    "fallback-channels",
+				Aliases: []string{"fc"},
+				Usage:   "Comma-separated channels to try in order when the approval channel fails, optional; defaults to the company's fallback channels",
+			},
 			&cli.IntFlag{
 				Name:    "manager-approval",
 				Aliases: []string{"ma"},
 				Usage:   "Whether manager approval is required (0=No, 1=Yes, optional)",
 			},
+			&cli.IntFlag{
+				Name:    "manager-levels",
+				Aliases: []string{"ml"},
+				Usage:   "Route to the submitter's manager this many levels up instead of the approver, who still gets invoices without such a manager (optional, default: 0)",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			// Get CLI config from global flags
 			cliConfig := &Config{
-				Company:     c.String("company"),
-				Departments: c.String("departments"),
-				SlackConn:   c.String("slack-connection-string"),
-				EmailConn:   c.String("email-connection-string"),
-				Verbose:     c.Bool("verbose"),
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
 			}
 
 			// Setup services
-			services, err := setupServicesWithConfig(cliConfig)
+			services, err := setupServicesAsUser(cliConfig)
 			if err != nil {
 				return fmt.Errorf("failed to setup services: %w", err)
 			}
 
 			// Create workflow rule
 			rule := api.WorkflowRule{
-				CompanyID:       1, // Light company ID from sample data
-				ApproverID:      c.Int("approver-id"),
-				ApprovalChannel: c.Int("approval-channel"),
+				ApproverID:       c.Int("approver-id"),
+				ApprovalChannel:  c.String("approval-channel"),
+				FallbackChannels: parseChannelsFlag(c.String("fallback-channels")),
+				ManagerLevels:    c.Int("manager-levels"),
 			}
 
 			// Set optional fields
-			if c.IsSet("min-amount") {
-				minAmount := c.Float64("min-amount")
-				rule.MinAmount = &minAmount
+			if rule.MinAmount, err = parseAmountFlag(c, "min-amount"); err != nil {
+				return err
+			}
+			if rule.MaxAmount, err = parseAmountFlag(c, "max-amount"); err != nil {
+				return err
+			}
+			if err := checkCurrencyFlag(c, rule); err != nil {
+				return err
+			}
+			if rule.MinBound, err = parseBoundFlag(c, "min-bound"); err != nil {
+				return err
 			}
-			if c.IsSet("max-amount") {
-				maxAmount := c.Float64("max-amount")
-				rule.MaxAmount = &maxAmount
+			if rule.MaxBound, err = parseBoundFlag(c, "max-bound"); err != nil {
+				return err
 			}
 			if c.IsSet("department") {
 				department := c.String("department")
@@ -95,24 +139,24 @@ func CreateWorkflowRule() *cli.Command {
 
 			createdRule, err := services.Management.CreateWorkflowRule(rule)
 			if err != nil {
-				return fmt.Errorf("failed to create workflow rule: %w", err)
+				return formatValidationError("failed to create workflow rule", err, workflowRuleFlags)
 			}
 
 			message := fmt.Sprintf("✅ Workflow rule created successfully!\n"+
 				"ID: %d\n"+
-				"Min Amount: %s\n"+
-				"Max Amount: %s\n"+
+				"Amount Range: %s\n"+
 				"Department: %s\n"+
 				"Manager Approval Required: %s\n"+
-				"Approver ID: %d\n"+
-				"Approval Channel: %s",
+				"Approver: %s\n"+
+				"Approval Channel: %s\n"+
+				"Fallback Channels: %s",
 				createdRule.ID,
-				formatFloatPtr(createdRule.MinAmount),
-				formatFloatPtr(createdRule.MaxAmount),
+				createdRule.AmountRange(),
 				formatStringPtr(createdRule.Department),
 				formatManagerApproval(createdRule.IsManagerApprovalRequired),
-				createdRule.ApproverID,
-				formatApprovalChannel(createdRule.ApprovalChannel))
+				formatRuleApprover(createdRule),
+				createdRule.ApprovalChannel,
+				formatChannels(createdRule.FallbackChannels))
 			output.Println(message)
 			return nil
 		},
@@ -125,8 +169,8 @@ func UpdateWorkflowRule() *cli.Command {
 		Aliases: []string{"uwr"},
 		Usage:   "Update an existing workflow rule",
 		UsageText: ` 
-		    backend-challenge-cli update-workflow-rule --id 1 --min-amount 200 --max-amount 600 --department "Finance" --approver-id 2 --approval-channel 1 --manager-approval 0
-		    backend-challenge-cli uwr -i 1 -min 200 -max 600 -d "Marketing" -aid 2 -ac 1 -ma 1`,
+		    backend-challenge-cli update-workflow-rule --id 1 --min-amount 200 --max-amount 600 --department "Finance" --approver-id 2 --approval-channel email --manager-approval 0
+		    backend-challenge-cli uwr -i 1 -min 200 -max 600 -d "Marketing" -aid 2 -ac email -ma 1`,
 		Flags: []cli.Flag{
 			&cli.IntFlag{
 				Name:     "id",
@@ -134,15 +178,31 @@ func UpdateWorkflowRule() *cli.Command {
 				Usage:    "ID of the workflow rule to update, required",
 				Required: true,
 			},
-			&cli.Float64Flag{
+			&cli.StringFlag{
 				Name:    "min-amount",
 				Aliases: []string{"min"},
-				Usage:   "Minimum amount for the rule (optional)",
+				Usage:   "Minimum amount for the rule, at most 2 decimal places (optional)",
 			},
-			&cli.Float64Flag{
+			&cli.StringFlag{
 				Name:    "max-amount",
 				Aliases: []string{"max"},
-				Usage:   "Maximum amount for the rule (optional)",
+				Usage:   "Maximum amount for the rule, at most 2 decimal places (optional)",
+			},
+			&cli.StringFlag{
+				Name:    "min-bound",
+				Aliases: []string{"minb"},
+				Usage:   "Whether the minimum amount is inclusive or exclusive (optional, default: inclusive)",
+			},
+			&cli.StringFlag{
+				Name:    "max-bound",
+				Aliases: []string{"maxb"},
+				Usage:   "Whether the maximum amount is inclusive or exclusive (optional, default: exclusive)",
+			},
+			&cli.StringFlag{
+				Name:    "currency",
+				Aliases: []string{"cur"},
+				Usage:   "Currency of the rule amounts, requires --min-amount or --max-amount (optional)",
+				Value:   string(money.DefaultCurrency),
 			},
 			&cli.StringFlag{
 				Name:    "department",
@@ -155,49 +215,71 @@ func UpdateWorkflowRule() *cli.Command {
 				Usage:    "ID of the approver for this rule, required",
 				Required: true,
 			},
-			&cli.IntFlag{
+			&cli.StringFlag{
 				Name:     "approval-channel",
 				Aliases:  []string{"ac"},
-				Usage:    "Approval channel (0=Slack, 1=Email), required",
+				Usage:    fmt.Sprintf("Name of the approval channel (%s), required", strings.Join(config.ChannelNames(), ", ")),
 				Required: true,
 			},
+			&cli.StringFlag{
+				Name:    "fallback-channels",
+				Aliases: []string{"fc"},
+				Usage:   "Comma-separated channels to try in order when the approval channel fails, optional; defaults to the company's fallback channels",
+			},
 			&cli.IntFlag{
 				Name:    "manager-approval",
 				Aliases: []string{"ma"},
 				Usage:   "Whether manager approval is required (0=No, 1=Yes, optional)",
 			},
+			&cli.IntFlag{
+				Name:    "manager-levels",
+				Aliases: []string{"ml"},
+				Usage:   "Route to the submitter's manager this many levels up instead of the approver, who still gets invoices without such a manager (optional, default: 0)",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			// Get CLI config from global flags
 			cliConfig := &Config{
-				Company:     c.String("company"),
-				Departments: c.String("departments"),
-				SlackConn:   c.String("slack-connection-string"),
-				EmailConn:   c.String("email-connection-string"),
-				Verbose:     c.Bool("verbose"),
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
 			}
 
 			// Setup services
-			services, err := setupServicesWithConfig(cliConfig)
+			services, err := setupServicesAsUser(cliConfig)
 			if err != nil {
 				return fmt.Errorf("failed to setup services: %w", err)
 			}
 
 			// Update workflow rule
 			rule := api.WorkflowRule{
-				ID:              c.Int("id"),
-				ApproverID:      c.Int("approver-id"),
-				ApprovalChannel: c.Int("approval-channel"),
+				ID:               c.Int("id"),
+				ApproverID:       c.Int("approver-id"),
+				ApprovalChannel:  c.String("approval-channel"),
+				FallbackChannels: parseChannelsFlag(c.String("fallback-channels")),
+				ManagerLevels:    c.Int("manager-levels"),
 			}
 
 			// Set optional fields
-			if c.IsSet("min-amount") {
-				minAmount := c.Float64("min-amount")
-				rule.MinAmount = &minAmount
+			if rule.MinAmount, err = parseAmountFlag(c, "min-amount"); err != nil {
+				return err
+			}
+			if rule.MaxAmount, err = parseAmountFlag(c, "max-amount"); err != nil {
+				return err
+			}
+			if err := checkCurrencyFlag(c, rule); err != nil {
+				return err
 			}
-			if c.IsSet("max-amount") {
-				maxAmount := c.Float64("max-amount")
-				rule.MaxAmount = &maxAmount
+			if rule.MinBound, err = parseBoundFlag(c, "min-bound"); err != nil {
+				return err
+			}
+			if rule.MaxBound, err = parseBoundFlag(c, "max-bound"); err != nil {
+				return err
 			}
 			if c.IsSet("department") {
 				department := c.String("department")
@@ -212,24 +294,24 @@ func UpdateWorkflowRule() *cli.Command {
 
 			err = services.Management.UpdateWorkflowRule(rule)
 			if err != nil {
-				return fmt.Errorf("failed to update workflow rule: %w", err)
+				return formatValidationError("failed to update workflow rule", err, workflowRuleFlags)
 			}
 
 			message := fmt.Sprintf("✅ Workflow rule updated successfully!\n"+
 				"ID: %d\n"+
-				"Min Amount: %s\n"+
-				"Max Amount: %s\n"+
+				"Amount Range: %s\n"+
 				"Department: %s\n"+
 				"Manager Approval Required: %s\n"+
-				"Approver ID: %d\n"+
-				"Approval Channel: %s",
+				"Approver: %s\n"+
+				"Approval Channel: %s\n"+
+				"Fallback Channels: %s",
 				rule.ID,
-				formatFloatPtr(rule.MinAmount),
-				formatFloatPtr(rule.MaxAmount),
+				rule.AmountRange(),
 				formatStringPtr(rule.Department),
 				formatManagerApproval(rule.IsManagerApprovalRequired),
-				rule.ApproverID,
-				formatApprovalChannel(rule.ApprovalChannel))
+				formatRuleApprover(rule),
+				rule.ApprovalChannel,
+				formatChannels(rule.FallbackChannels))
 			output.Println(message)
 			return nil
 		},
@@ -255,15 +337,18 @@ func DeleteWorkflowRule() *cli.Command {
 		Action: func(c *cli.Context) error {
 			// Get CLI config from global flags
 			cliConfig := &Config{
-				Company:     c.String("company"),
-				Departments: c.String("departments"),
-				SlackConn:   c.String("slack-connection-string"),
-				EmailConn:   c.String("email-connection-string"),
-				Verbose:     c.Bool("verbose"),
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
 			}
 
 			// Setup services
-			services, err := setupServicesWithConfig(cliConfig)
+			services, err := setupServicesAsUser(cliConfig)
 			if err != nil {
 				return fmt.Errorf("failed to setup services: %w", err)
 			}
@@ -300,15 +385,18 @@ func GetWorkflowRuleByID() *cli.Command {
 		Action: func(c *cli.Context) error {
 			// Get CLI config from global flags
 			cliConfig := &Config{
-				Company:     c.String("company"),
-				Departments: c.String("departments"),
-				SlackConn:   c.String("slack-connection-string"),
-				EmailConn:   c.String("email-connection-string"),
-				Verbose:     c.Bool("verbose"),
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
 			}
 
 			// Setup services
-			services, err := setupServicesWithConfig(cliConfig)
+			services, err := setupServicesAsUser(cliConfig)
 			if err != nil {
 				return fmt.Errorf("failed to setup services: %w", err)
 			}
@@ -322,19 +410,19 @@ func GetWorkflowRuleByID() *cli.Command {
 
 			message := fmt.Sprintf("✅ Workflow rule found!\n"+
 				"ID: %d\n"+
-				"Min Amount: %s\n"+
-				"Max Amount: %s\n"+
+				"Amount Range: %s\n"+
 				"Department: %s\n"+
 				"Manager Approval Required: %s\n"+
-				"Approver ID: %d\n"+
-				"Approval Channel: %s",
+				"Approver: %s\n"+
+				"Approval Channel: %s\n"+
+				"Fallback Channels: %s",
 				rule.ID,
-				formatFloatPtr(rule.MinAmount),
-				formatFloatPtr(rule.MaxAmount),
+				rule.AmountRange(),
 				formatStringPtr(rule.Department),
 				formatManagerApproval(rule.IsManagerApprovalRequired),
-				rule.ApproverID,
-				formatApprovalChannel(rule.ApprovalChannel))
+				formatRuleApprover(rule),
+				rule.ApprovalChannel,
+				formatChannels(rule.FallbackChannels))
 			output.Println(message)
 			return nil
 		},
@@ -352,15 +440,18 @@ func ListWorkflowRules() *cli.Command {
 		Action: func(c *cli.Context) error {
 			// Get CLI config from global flags
 			cliConfig := &Config{
-				Company:     c.String("company"),
-				Departments: c.String("departments"),
-				SlackConn:   c.String("slack-connection-string"),
-				EmailConn:   c.String("email-connection-string"),
-				Verbose:     c.Bool("verbose"),
+				Company:      c.String("company"),
+				DatabasePath: c.String("database-path"),
+				AsUser:       c.String("as-user"),
+				SlackConn:    c.String("slack-connection-string"),
+				EmailConn:    c.String("email-connection-string"),
+				TeamsConn:    c.String("teams-connection-string"),
+				WebhookConn:  c.String("webhook-connection-string"),
+				Verbose:      c.Bool("verbose"),
 			}
 
 			// Setup services
-			services, err := setupServicesWithConfig(cliConfig)
+			services, err := setupServicesAsUser(cliConfig)
 			if err != nil {
 				return fmt.Errorf("failed to setup services: %w", err)
 			}
@@ -376,14 +467,14 @@ func ListWorkflowRules() *cli.Command {
 			} else {
 				output.Println(fmt.Sprintf("Found %d workflow rule(s):", len(rules)))
 				for _, rule := range rules {
-					message := fmt.Sprintf("ID: %d | Min: %s | Max: %s | Dept: %s | Manager: %s | Approver: %d | Channel: %s",
+					message := fmt.Sprintf("ID: %d | Range: %s | Dept: %s | Manager: %s | Approver: %s | Channel: %s | Fallbacks: %s",
 						rule.ID,
-						formatFloatPtr(rule.MinAmount),
-						formatFloatPtr(rule.MaxAmount),
+						rule.AmountRange(),
 						formatStringPtr(rule.Department),
 						formatManagerApproval(rule.IsManagerApprovalRequired),
-						rule.ApproverID,
-						formatApprovalChannel(rule.ApprovalChannel))
+						formatRuleApprover(rule),
+						rule.ApprovalChannel,
+						formatChannels(rule.FallbackChannels))
 					output.Println(message)
 				}
 			}
@@ -392,12 +483,35 @@ func ListWorkflowRules() *cli.Command {
 	}
 }
 
-// Helper functions for formatting optional fields
-func formatFloatPtr(f *float64) string {
-	if f == nil {
-		return "Any"
+// parseAmountFlag parses an optional amount flag in the currency given by the currency flag.
+func parseAmountFlag(c *cli.Context, name string) (*money.Money, error) {
+	if !c.IsSet(name) {
+		return nil, nil
+	}
+	amount, err := money.Parse(c.String(name), money.Currency(c.String("currency")))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s: %w", name, err)
+	}
+	return &amount, nil
+}
+
+// checkCurrencyFlag rejects a currency flag on a rule without amount
+// bounds. Such a rule matches invoices in any currency, so the currency
+// would be silently ignored.
+func checkCurrencyFlag(c *cli.Context, rule api.WorkflowRule) error {
+	if c.IsSet("currency") && rule.MinAmount == nil && rule.MaxAmount == nil {
+		return fmt.Errorf("invalid --currency: only applies to the amount bounds, set --min-amount or --max-amount")
 	}
-	return fmt.Sprintf("%.2f", *f)
+	return nil
+}
+
+// parseBoundFlag parses an optional inclusive/exclusive bound flag.
+func parseBoundFlag(c *cli.Context, name string) (api.Bound, error) {
+	bound, err := api.ParseBound(c.String(name))
+	if err != nil {
+		return "", fmt.Errorf("invalid --%s: %w", name, err)
+	}
+	return bound, nil
 }
 
 func formatStringPtr(s *string) string {
@@ -407,24 +521,25 @@ func formatStringPtr(s *string) string {
 	return *s
 }
 
-func formatManagerApproval(approval int) string {
-	switch approval {
+// formatRuleApprover formats who a rule routes approval requests to.
+func formatRuleApprover(rule api.WorkflowRule) string {
+	switch rule.ManagerLevels {
 	case 0:
-		return "No"
+		return strconv.Itoa(rule.ApproverID)
 	case 1:
-		return "Yes"
+		return fmt.Sprintf("submitter's manager, or %d", rule.ApproverID)
 	default:
-		return "Any"
+		return fmt.Sprintf("submitter's manager %d levels up, or %d", rule.ManagerLevels, rule.ApproverID)
 	}
 }
 
-func formatApprovalChannel(channel int) string {
-	switch channel {
+func formatManagerApproval(approval int) string {
+	switch approval {
 	case 0:
-		return "Slack"
+		return "No"
 	case 1:
-		return "Email"
+		return "Yes"
 	default:
-		return fmt.Sprintf("Unknown (%d)", channel)
+		return "Any"
 	}
 }
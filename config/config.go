@@ -2,7 +2,7 @@ package config
 
 import (
 	"context"
-	"strings"
+	"time"
 
 	"github.com/KatrinSalt/backend-challenge-go/db"
 	"github.com/sethvargo/go-envconfig"
@@ -13,14 +13,11 @@ const (
 	defaultCompanyName = "Light"
 )
 
-var (
-	// Default company departments.
-	defaultCompanyDepartments = []string{"Marketing", "Finance"}
-)
-
 const (
-	defaultSlackConnectionString = "slack"
-	defaultEmailConnectionString = "email"
+	defaultSlackConnectionString   = "slack"
+	defaultEmailConnectionString   = "smtp://localhost:25"
+	defaultTeamsConnectionString   = "http://localhost/teams"
+	defaultWebhookConnectionString = "http://localhost/webhook"
 )
 
 // Configuration contains the configuration for the application.
@@ -33,24 +30,75 @@ type ServicesConfig struct {
 	Database Database
 	Slack    Slack
 	Email    Email
+	Teams    Teams
+	Webhook  Webhook
+	// Templates configures the notification content.
+	Templates Templates
+	// Notifications configures the deduplication and rate limiting of
+	// notifications.
+	Notifications Notifications
 }
 type Company struct {
-	Name        string   `env:"COMPANY_NAME"`
-	Departments []string `env:"COMPANY_DEPARTMENTS"`
+	Name string `env:"COMPANY_NAME"`
 }
 
-// Placeholder for database configuration.
 type Database struct {
+	// Path is the path of the database file. The database is kept in
+	// memory if it is not set.
+	Path       string `env:"DATABASE_PATH"`
 	Schema     []string
 	SampleData *db.SampleData
+	// SampleAPIKey seeds db.SampleAPIKey, which has every permission and is
+	// public. It is only meant for local testing.
+	SampleAPIKey bool `env:"DEV_SAMPLE_API_KEY"`
 }
 
 type Slack struct {
 	ConnectionString string `env:"SLACK_CONNECTION_STRING"`
+	// BaseURL overrides the Slack Web API base URL, e.g. for a local stub.
+	BaseURL string `env:"SLACK_BASE_URL"`
+	// SigningSecret verifies interaction requests from the Slack app.
+	SigningSecret string `env:"SLACK_SIGNING_SECRET"`
 }
 
 type Email struct {
 	ConnectionString string `env:"EMAIL_CONNECTION_STRING"`
+	// LinkSecret signs the one-click decision links in approval emails.
+	LinkSecret string `env:"EMAIL_LINK_SECRET"`
+	// LinkBaseURL is the public base URL the decision links point to.
+	LinkBaseURL string `env:"EMAIL_LINK_BASE_URL"`
+	// LinkTTL is how long decision links stay valid.
+	LinkTTL time.Duration `env:"EMAIL_LINK_TTL"`
+}
+
+type Teams struct {
+	// ConnectionString is the URL of the incoming webhook approval
+	// requests are posted to.
+	ConnectionString string `env:"TEAMS_CONNECTION_STRING"`
+}
+
+type Webhook struct {
+	// ConnectionString is the URL approval requests are posted to.
+	ConnectionString string `env:"WEBHOOK_CONNECTION_STRING"`
+	// Secret signs the webhook requests.
+	Secret string `env:"WEBHOOK_SECRET"`
+}
+
+type Templates struct {
+	// Dir is the directory of the per-company template overrides.
+	Dir string `env:"NOTIFICATION_TEMPLATES_DIR"`
+}
+
+type Notifications struct {
+	// DedupWindow is how long an invoice is not sent to the same approver
+	// again.
+	DedupWindow time.Duration `env:"NOTIFICATION_DEDUP_WINDOW"`
+	// RateLimitBurst is the number of notifications an approver can
+	// receive on a channel at once.
+	RateLimitBurst int `env:"NOTIFICATION_RATE_LIMIT_BURST"`
+	// RateLimitInterval is how long it takes until an approver can receive
+	// one more notification on a channel.
+	RateLimitInterval time.Duration `env:"NOTIFICATION_RATE_LIMIT_INTERVAL"`
 }
 
 // Options contains options for creating new configurations.
@@ -71,8 +119,7 @@ func New(options ...Option) (Configuration, error) {
 	cfg := Configuration{
 		Services: ServicesConfig{
 			Company: Company{
-				Name:        defaultCompanyName,
-				Departments: defaultCompanyDepartments,
+				Name: defaultCompanyName,
 			},
 			Database: Database{
 				// Placeholder for database schema.
@@ -85,6 +132,12 @@ func New(options ...Option) (Configuration, error) {
 			Email: Email{
 				ConnectionString: defaultEmailConnectionString,
 			},
+			Teams: Teams{
+				ConnectionString: defaultTeamsConnectionString,
+			},
+			Webhook: Webhook{
+				ConnectionString: defaultWebhookConnectionString,
+			},
 		},
 	}
 
@@ -93,19 +146,39 @@ func New(options ...Option) (Configuration, error) {
 		if opts.Flags.companyName != "" {
 			cfg.Services.Company.Name = opts.Flags.companyName
 		}
-		if opts.Flags.departments != "" {
-			departments := strings.Split(opts.Flags.departments, ",")
-			for i, dept := range departments {
-				departments[i] = strings.TrimSpace(dept)
-			}
-			cfg.Services.Company.Departments = departments
-		}
 		if opts.Flags.slack != "" {
 			cfg.Services.Slack.ConnectionString = opts.Flags.slack
 		}
+		if opts.Flags.slackSigningSecret != "" {
+			cfg.Services.Slack.SigningSecret = opts.Flags.slackSigningSecret
+		}
 		if opts.Flags.email != "" {
 			cfg.Services.Email.ConnectionString = opts.Flags.email
 		}
+		if opts.Flags.emailLinkSecret != "" {
+			cfg.Services.Email.LinkSecret = opts.Flags.emailLinkSecret
+		}
+		if opts.Flags.emailLinkBaseURL != "" {
+			cfg.Services.Email.LinkBaseURL = opts.Flags.emailLinkBaseURL
+		}
+		if opts.Flags.teams != "" {
+			cfg.Services.Teams.ConnectionString = opts.Flags.teams
+		}
+		if opts.Flags.webhook != "" {
+			cfg.Services.Webhook.ConnectionString = opts.Flags.webhook
+		}
+		if opts.Flags.webhookSecret != "" {
+			cfg.Services.Webhook.Secret = opts.Flags.webhookSecret
+		}
+		if opts.Flags.templatesDir != "" {
+			cfg.Services.Templates.Dir = opts.Flags.templatesDir
+		}
+		if opts.Flags.databasePath != "" {
+			cfg.Services.Database.Path = opts.Flags.databasePath
+		}
+		if opts.Flags.devSampleAPIKey {
+			cfg.Services.Database.SampleAPIKey = true
+		}
 	}
 
 	if err := envconfig.Process(context.Background(), &cfg); err != nil {
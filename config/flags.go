@@ -8,10 +8,18 @@ import (
 
 // flags holds the command line flags for the config package.
 type flags struct {
-	companyName string
-	departments string
-	slack       string
-	email       string
+	companyName        string
+	slack              string
+	slackSigningSecret string
+	email              string
+	emailLinkSecret    string
+	emailLinkBaseURL   string
+	teams              string
+	webhook            string
+	webhookSecret      string
+	templatesDir       string
+	databasePath       string
+	devSampleAPIKey    bool
 }
 
 // ParseFlags parses the command line flags and returns a flags struct.
@@ -27,9 +35,17 @@ func ParseFlags(args []string) (*flags, error) {
 	}
 
 	fs.StringVar(&f.companyName, "company", "", "A company name for which the workflow service will be configured.")
-	fs.StringVar(&f.departments, "departments", "", "Comma-separated list of departments (e.g., 'Finance,Marketing').")
 	fs.StringVar(&f.slack, "slack-connection-string", "", "A connection string for the slack service.")
+	fs.StringVar(&f.slackSigningSecret, "slack-signing-secret", "", "A signing secret for verifying slack interaction requests.")
 	fs.StringVar(&f.email, "email-connection-string", "", "A connection string for the email service.")
+	fs.StringVar(&f.emailLinkSecret, "email-link-secret", "", "A secret for signing the decision links in approval emails.")
+	fs.StringVar(&f.emailLinkBaseURL, "email-link-base-url", "", "A public base URL for the decision links in approval emails.")
+	fs.StringVar(&f.teams, "teams-connection-string", "", "A webhook URL for the teams service.")
+	fs.StringVar(&f.webhook, "webhook-connection-string", "", "A URL for the webhook service.")
+	fs.StringVar(&f.webhookSecret, "webhook-secret", "", "A secret for signing webhook requests.")
+	fs.StringVar(&f.templatesDir, "templates-dir", "", "A directory of per-company notification template overrides.")
+	fs.StringVar(&f.databasePath, "database-path", "", "A path of the database file, which is kept in memory if not set.")
+	fs.BoolVar(&f.devSampleAPIKey, "dev-sample-api-key", false, "Seed the public sample API key, for local testing only.")
 
 	if err := fs.Parse(args); err != nil {
 		return &f, err
@@ -1,14 +1,25 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"sync"
 
+	"github.com/KatrinSalt/backend-challenge-go/access"
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/auth"
 	"github.com/KatrinSalt/backend-challenge-go/common"
 	"github.com/KatrinSalt/backend-challenge-go/db"
 	"github.com/KatrinSalt/backend-challenge-go/db/sqlite"
 	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
 	"github.com/KatrinSalt/backend-challenge-go/notification/email"
 	"github.com/KatrinSalt/backend-challenge-go/notification/slack"
+	"github.com/KatrinSalt/backend-challenge-go/notification/teams"
+	"github.com/KatrinSalt/backend-challenge-go/notification/templates"
+	"github.com/KatrinSalt/backend-challenge-go/notification/webhook"
 	"github.com/KatrinSalt/backend-challenge-go/workflow"
 )
 
@@ -16,6 +27,99 @@ import (
 type Services struct {
 	Workflow   workflow.Service
 	Management management.Service
+	// SlackInteractions handles Slack interaction requests. It is nil unless
+	// a Slack signing secret is configured. Like EmailDecisions and
+	// EmailReplies, it records each decision for the company of its
+	// approval request.
+	SlackInteractions http.Handler
+	// EmailDecisions handles the decision links in approval emails. It is
+	// nil unless an email link secret is configured.
+	EmailDecisions http.Handler
+	// EmailReplies records the decisions in replies to approval emails.
+	EmailReplies *email.ReplyProcessor
+	// Templates renders the content of the approval request notifications.
+	Templates *templates.Renderer
+	// Authenticator resolves API keys to the company they belong to.
+	Authenticator *auth.Authenticator
+
+	log       common.Logger
+	cfg       Configuration
+	dbSvc     db.Service
+	mu        sync.Mutex
+	companies map[string]companyServices
+}
+
+// companyServices holds the services of a company.
+type companyServices struct {
+	management management.Service
+	workflow   workflow.Service
+}
+
+// ForCompany returns the management and workflow services of the company
+// with the name, creating them on first use. They share the database of the
+// services of the configured company. The outbox dispatcher of services it
+// creates runs until ctx is cancelled.
+func (s *Services) ForCompany(ctx context.Context, name string) (management.Service, workflow.Service, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if services, ok := s.companies[name]; ok {
+		return services.management, services.workflow, nil
+	}
+
+	cfg := s.cfg
+	cfg.Services.Company.Name = name
+	managementSvc, err := setUpManagementService(s.log, s.dbSvc, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create management service for company %s: %v", name, err)
+	}
+	workflowSvc, _, err := setUpWorkflowService(s.log, s.dbSvc, managementSvc, s.Templates, nil, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create workflow service for company %s: %v", name, err)
+	}
+	go workflowSvc.RunDispatcher(ctx)
+
+	s.companies[name] = companyServices{management: managementSvc, workflow: workflowSvc}
+	return managementSvc, workflowSvc, nil
+}
+
+// ActAs makes Management and Workflow act as the user of the configured
+// company with the email, so that they only perform the operations the
+// user's role allows. The services returned by ForCompany are not affected.
+func (s *Services) ActAs(email string) error {
+	user, err := s.Management.GetUserByEmail(email)
+	if err != nil {
+		return fmt.Errorf("failed to act as user: %w", err)
+	}
+	s.Management = access.Management(s.Management, user)
+	s.Workflow = access.Workflow(s.Workflow, user)
+	return nil
+}
+
+// channelNames are the names of the notification channels registered by
+// SetUpServices, in alphabetical order.
+var channelNames = []string{email.ChannelName, slack.ChannelName, teams.ChannelName, webhook.ChannelName}
+
+// channelContacts holds, per channel, the approver contact the channel
+// delivers to.
+var channelContacts = map[string]management.ContactFunc{
+	email.ChannelName:   email.ContactID,
+	slack.ChannelName:   slack.ContactID,
+	teams.ChannelName:   teams.ContactID,
+	webhook.ChannelName: webhook.ContactID,
+}
+
+// ChannelNames returns the names of the notification channels workflow
+// rules can use.
+func ChannelNames() []string {
+	return append([]string(nil), channelNames...)
+}
+
+// decisionHandlers holds the handlers that record approval decisions.
+type decisionHandlers struct {
+	slack        http.Handler
+	email        http.Handler
+	emailReplies *email.ReplyProcessor
 }
 
 func SetUpServices(log common.Logger, cfg Configuration) (*Services, error) {
@@ -23,59 +127,230 @@ func SetUpServices(log common.Logger, cfg Configuration) (*Services, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create database service: %v", err)
 	}
-	// Create workflow service.
-	workflowSvc, err := setUpWorkflowService(log, dbSvc, cfg)
+	// Load the notification templates, with the company overrides.
+	renderer, err := templates.NewRenderer(templates.WithDir(cfg.Services.Templates.Dir))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create workflow service: %v", err)
+		return nil, fmt.Errorf("failed to load notification templates: %v", err)
+	}
+	services := &Services{
+		Templates: renderer,
+		log:       log,
+		cfg:       cfg,
+		dbSvc:     dbSvc,
+		companies: make(map[string]companyServices),
 	}
-	// Create management service.
+	// Create management service. It is created first as it records the
+	// webhook delivery attempts.
 	managementSvc, err := setUpManagementService(log, dbSvc, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create management service: %v", err)
 	}
+	// Create workflow service, and the decision handlers that route each
+	// decision to the company of its approval request.
+	workflowSvc, handlers, err := setUpWorkflowService(log, dbSvc, managementSvc, renderer, decisionRouter{services: services}, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workflow service: %v", err)
+	}
+	// Create the authenticator of the API keys.
+	authenticator, err := auth.NewAuthenticator(dbSvc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authenticator: %v", err)
+	}
 
-	return &Services{
-		Workflow:   workflowSvc,
-		Management: managementSvc,
-	}, nil
+	services.Workflow = workflowSvc
+	services.Management = managementSvc
+	services.SlackInteractions = handlers.slack
+	services.EmailDecisions = handlers.email
+	services.EmailReplies = handlers.emailReplies
+	services.Authenticator = authenticator
+	services.companies[cfg.Services.Company.Name] = companyServices{management: managementSvc, workflow: workflowSvc}
+	return services, nil
+}
 
+// decisionRouter records the decisions of the decision handlers with the
+// workflow service of the company of their approval request, so that the
+// approvers of every company can decide over Slack and email, not only
+// those of the configured company.
+type decisionRouter struct {
+	services *Services
+}
+
+// RecordDecision records the decision with the workflow service of the
+// company of its approval request.
+func (r decisionRouter) RecordDecision(decision api.ApprovalDecision) (api.ApprovalRequest, error) {
+	if err := decision.Validate(); err != nil {
+		return api.ApprovalRequest{}, err
+	}
+
+	companyID, err := r.services.dbSvc.GetApprovalRequestCompanyID(decision.ApprovalRequestID)
+	if err != nil {
+		return api.ApprovalRequest{}, err
+	}
+	company, err := r.services.dbSvc.GetCompanyByID(companyID)
+	if err != nil {
+		return api.ApprovalRequest{}, err
+	}
+
+	// The outbox dispatcher of services created for a decision runs until
+	// the process exits.
+	_, workflowSvc, err := r.services.ForCompany(context.Background(), company.Name)
+	if err != nil {
+		return api.ApprovalRequest{}, err
+	}
+	return workflowSvc.RecordDecision(decision)
+}
+
+// ApprovalRequestsByMessageID returns the IDs of the approval requests of
+// every company delivered over the channel in the message with the ID.
+func (r decisionRouter) ApprovalRequestsByMessageID(channel, messageID string) ([]int, error) {
+	companies, err := r.services.dbSvc.ListCompanies()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, company := range companies {
+		requests, err := r.services.dbSvc.ListApprovalRequestsByMessageID(company.ID, channel, messageID)
+		if err != nil {
+			return nil, err
+		}
+		for _, request := range requests {
+			ids = append(ids, request.ID)
+		}
+	}
+	return ids, nil
 }
 
 // setUpManagementService creates and configures a management service.
 func setUpManagementService(log common.Logger, dbSvc db.Service, cfg Configuration) (management.Service, error) {
 	// Create management service with company name.
-	return management.NewService(log, dbSvc, cfg.Services.Company.Name)
+	return management.NewService(log, dbSvc, cfg.Services.Company.Name,
+		management.WithChannels(ChannelNames()...),
+		management.WithContacts(channelContacts),
+	)
 }
 
-func setUpWorkflowService(log common.Logger, dbSvc db.Service, cfg Configuration) (workflow.Service, error) {
+// setUpWorkflowService creates and configures a workflow service. Webhook
+// delivery attempts are recorded with the recorder, and notifications are
+// rendered with the renderer. Unless decisions is nil, it also creates the
+// handlers that record decisions with decisions: the email reply
+// processor, and the handlers for Slack interactions and email decision
+// links, for the channels whose secrets are configured.
+func setUpWorkflowService(log common.Logger, dbSvc db.Service, recorder webhook.AttemptRecorder, renderer *templates.Renderer, decisions email.ReplyRecorder, cfg Configuration) (workflow.Service, decisionHandlers, error) {
 	// Create slack notification service.
-	slackSvc, err := slack.NewService(cfg.Services.Slack.ConnectionString, slack.WithLogger(log))
+	slackSvc, err := slack.NewService(
+		cfg.Services.Slack.ConnectionString,
+		slack.WithLogger(log),
+		slack.WithBaseURL(cfg.Services.Slack.BaseURL),
+		slack.WithSigningSecret(cfg.Services.Slack.SigningSecret),
+		slack.WithTemplates(renderer),
+	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create slack notification service: %v", err)
+		return nil, decisionHandlers{}, fmt.Errorf("failed to create slack notification service: %v", err)
 	}
 
 	// Create email notification service.
-	emailSvc, err := email.NewService(cfg.Services.Email.ConnectionString, email.WithLogger(log))
+	emailSvc, err := email.NewService(
+		cfg.Services.Email.ConnectionString,
+		email.WithLogger(log),
+		email.WithLinkSecret(cfg.Services.Email.LinkSecret),
+		email.WithLinkBaseURL(cfg.Services.Email.LinkBaseURL),
+		email.WithLinkTTL(cfg.Services.Email.LinkTTL),
+		email.WithTemplates(renderer),
+	)
+	if err != nil {
+		return nil, decisionHandlers{}, fmt.Errorf("failed to create email notification service: %v", err)
+	}
+
+	// Create teams notification service.
+	teamsSvc, err := teams.NewService(cfg.Services.Teams.ConnectionString, teams.WithLogger(log), teams.WithTemplates(renderer))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create email notification service: %v", err)
+		return nil, decisionHandlers{}, fmt.Errorf("failed to create teams notification service: %v", err)
+	}
+
+	// Create webhook notification service.
+	webhookSvc, err := webhook.NewService(
+		cfg.Services.Webhook.ConnectionString,
+		webhook.WithLogger(log),
+		webhook.WithSecret(cfg.Services.Webhook.Secret),
+		webhook.WithRecorder(recorder),
+	)
+	if err != nil {
+		return nil, decisionHandlers{}, fmt.Errorf("failed to create webhook notification service: %v", err)
+	}
+
+	// Register the channels workflow rules can send approval requests over,
+	// each behind the rate limiter.
+	limiter := notification.NewRateLimiter(notification.WithRateLimiterOptions(notification.RateLimiterOptions{
+		Burst:    cfg.Services.Notifications.RateLimitBurst,
+		Interval: cfg.Services.Notifications.RateLimitInterval,
+	}))
+	channels, err := notification.NewRegistry(
+		limiter.Limit(slackSvc.Channel()),
+		limiter.Limit(emailSvc.Channel()),
+		limiter.Limit(teamsSvc.Channel()),
+		limiter.Limit(webhookSvc.Channel()),
+	)
+	if err != nil {
+		return nil, decisionHandlers{}, fmt.Errorf("failed to register notification channels: %v", err)
 	}
 
 	// Create workflow service.
-	workflowSvc, err := workflow.NewService(cfg.Services.Company.Name, cfg.Services.Company.Departments, dbSvc, slackSvc, emailSvc, workflow.WithLogger(log))
+	workflowSvc, err := workflow.NewService(
+		cfg.Services.Company.Name,
+		dbSvc,
+		channels,
+		workflow.WithLogger(log),
+		workflow.WithOptions(workflow.Options{DedupWindow: cfg.Services.Notifications.DedupWindow}),
+	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create workflow service: %v", err)
+		return nil, decisionHandlers{}, fmt.Errorf("failed to create workflow service: %v", err)
 	}
 
 	if err := workflowSvc.ValidateCompany(); err != nil {
-		return nil, fmt.Errorf("failed to start workflow service for company %s: %v", cfg.Services.Company.Name, err)
+		return nil, decisionHandlers{}, fmt.Errorf("failed to start workflow service for company %s: %v", cfg.Services.Company.Name, err)
+	}
+
+	if decisions == nil {
+		return workflowSvc, decisionHandlers{}, nil
+	}
+
+	// The decision handlers use the workflow services without the access
+	// checks. Their decisions are not made by a user but by the approver
+	// the request was sent to, proven by the signed Slack interaction, the
+	// signed decision link or the reply's sender, and RecordDecision only
+	// accepts them from that approver and never from the submitter.
+	var handlers decisionHandlers
+	if cfg.Services.Slack.SigningSecret != "" {
+		handlers.slack, err = slackSvc.InteractionHandler(decisions)
+		if err != nil {
+			return nil, decisionHandlers{}, fmt.Errorf("failed to create slack interaction handler: %v", err)
+		}
+	}
+	if cfg.Services.Email.LinkSecret != "" {
+		handlers.email, err = emailSvc.DecisionHandler(decisions)
+		if err != nil {
+			return nil, decisionHandlers{}, fmt.Errorf("failed to create email decision handler: %v", err)
+		}
+	}
+	handlers.emailReplies, err = emailSvc.ReplyProcessor(decisions, workflow.DecisionRefused)
+	if err != nil {
+		return nil, decisionHandlers{}, fmt.Errorf("failed to create email reply processor: %v", err)
 	}
 
-	return workflowSvc, nil
+	return workflowSvc, handlers, nil
 }
 
+// setUpDatabaseService creates the database service. A new database is
+// seeded with the sample data, and the sample API key is only seeded when
+// the configuration asks for it.
 func setUpDatabaseService(cfg Database) (db.Service, error) {
 	// Create SQL client.
-	client, err := sqlite.NewClient()
+	var clientOptions []sqlite.ClientOption
+	if cfg.Path != "" {
+		clientOptions = append(clientOptions, sqlite.WithDataSource(cfg.Path))
+	}
+	client, err := sqlite.NewClient(clientOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create sql client: %v", err)
 	}
@@ -87,7 +362,7 @@ func setUpDatabaseService(cfg Database) (db.Service, error) {
 
 	// Set defaults if sample data is nil or has no data
 	if cfg.SampleData == nil ||
-		(cfg.SampleData.Companies == nil && cfg.SampleData.Approvers == nil && cfg.SampleData.WorkflowRules == nil) {
+		(cfg.SampleData.Companies == nil && cfg.SampleData.Departments == nil && cfg.SampleData.Approvers == nil && cfg.SampleData.WorkflowRules == nil && cfg.SampleData.APIKeys == nil) {
 		cfg.SampleData = db.NewSampleData()
 	}
 
@@ -101,10 +376,34 @@ func setUpDatabaseService(cfg Database) (db.Service, error) {
 		return nil, fmt.Errorf("failed to initialize database schema: %v", err)
 	}
 
-	// Seed with sample data
-	if err := svc.SeedSampleData(); err != nil {
-		return nil, fmt.Errorf("failed to seed sample data: %v", err)
+	// Seed with sample data, unless a database file was seeded before
+	companies, err := svc.ListCompanies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies: %v", err)
+	}
+	if len(companies) == 0 {
+		if err := svc.SeedSampleData(); err != nil {
+			return nil, fmt.Errorf("failed to seed sample data: %v", err)
+		}
+	}
+
+	if cfg.SampleAPIKey {
+		if err := seedSampleAPIKey(svc); err != nil {
+			return nil, fmt.Errorf("failed to seed sample api key: %v", err)
+		}
 	}
 
 	return svc, nil
 }
+
+// seedSampleAPIKey adds db.SampleAPIKey if the database does not have it
+// yet.
+func seedSampleAPIKey(svc db.Service) error {
+	key := db.NewSampleAPIKey()
+	_, err := svc.GetAPIKeyByHash(key.Hash)
+	if !errors.Is(err, db.ErrAPIKeyNotFound) {
+		return err
+	}
+	_, err = svc.CreateAPIKey(key)
+	return err
+}
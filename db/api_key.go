@@ -0,0 +1,18 @@
+package db
+
+// APIKey is an API key of a company. Hash is the hex encoded SHA-256 hash
+// of the key, which itself is not stored, and Prefix is the start of the
+// key, which identifies it in listings. Permissions is a comma separated
+// list. Timestamps are RFC 3339 strings in UTC, and RevokedAt is set once
+// the key is revoked. UserID is set for a key that acts as a user.
+type APIKey struct {
+	ID          int     `db:"id"`
+	CompanyID   int     `db:"company_id"`
+	Name        string  `db:"name"`
+	Prefix      string  `db:"prefix"`
+	Hash        string  `db:"hash"`
+	Permissions string  `db:"permissions"`
+	CreatedAt   string  `db:"created_at"`
+	RevokedAt   *string `db:"revoked_at"`
+	UserID      *int    `db:"user_id"`
+}
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/KatrinSalt/backend-challenge-go/db/sql"
+)
+
+var (
+	ErrAPIKeyNotFound       = errors.New("api key not found")
+	ErrAPIKeyAlreadyRevoked = errors.New("api key already revoked")
+)
+
+// apiKeyColumns lists the columns read by the API key store in the order
+// expected by scanAPIKey.
+const apiKeyColumns = "id, company_id, name, prefix, hash, permissions, created_at, revoked_at, user_id"
+
+// APIKeyStore defines the interface for API key operations
+type APIKeyStore interface {
+	Create(key APIKey) (APIKey, error)
+	GetByHash(hash string) (APIKey, error)
+	List(companyID int) ([]APIKey, error)
+	Revoke(companyID, id int, revokedAt string) error
+}
+
+// apiKeyStore implements APIKeyStore
+type apiKeyStore struct {
+	client sql.Client
+	table  string
+}
+
+// APIKeyStoreOptions contains options for the API key store.
+type APIKeyStoreOptions struct {
+	Table string
+}
+
+// APIKeyStoreOption is a function that sets options on the API key store.
+type APIKeyStoreOption func(o *APIKeyStoreOptions)
+
+// NewAPIKeyStore creates a new API key store
+func NewAPIKeyStore(client sql.Client, options ...APIKeyStoreOption) (*apiKeyStore, error) {
+	if client == nil {
+		return nil, errors.New("nil sql client")
+	}
+
+	opts := APIKeyStoreOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+	if len(opts.Table) == 0 {
+		opts.Table = defaultAPIKeyTable
+	}
+
+	return &apiKeyStore{
+		client: client,
+		table:  opts.Table,
+	}, nil
+}
+
+// Create stores an API key.
+func (s *apiKeyStore) Create(key APIKey) (APIKey, error) {
+	insert := fmt.Sprintf(`
+		INSERT INTO %s (company_id, name, prefix, hash, permissions, created_at, user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING %s`, s.table, apiKeyColumns)
+
+	outKey, err := scanAPIKey(s.client.QueryRow(insert,
+		key.CompanyID,
+		key.Name,
+		key.Prefix,
+		key.Hash,
+		key.Permissions,
+		key.CreatedAt,
+		key.UserID,
+	))
+	if err != nil {
+		return APIKey{}, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return outKey, nil
+}
+
+// GetByHash retrieves the API key with the hash, of any company, including
+// a revoked key.
+func (s *apiKeyStore) GetByHash(hash string) (APIKey, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE hash = $1", apiKeyColumns, s.table)
+	key, err := scanAPIKey(s.client.QueryRow(query, hash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return APIKey{}, ErrAPIKeyNotFound
+		}
+		return APIKey{}, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return key, nil
+}
+
+// List retrieves a company's API keys, including the revoked keys, in the
+// order they were created.
+func (s *apiKeyStore) List(companyID int) ([]APIKey, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE company_id = $1 ORDER BY id", apiKeyColumns, s.table)
+
+	rows, err := s.client.Query(query, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over api key rows: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke revokes a company's API key. A key can only be revoked once.
+func (s *apiKeyStore) Revoke(companyID, id int, revokedAt string) error {
+	tx, err := s.client.Transaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkCompanyScope(tx, s.table, "api key", id, companyID, ErrAPIKeyNotFound); err != nil {
+		return err
+	}
+
+	// Only a key that is not revoked is updated, so a key that has already
+	// been revoked affects no rows.
+	update := fmt.Sprintf(`
+		UPDATE %s
+		SET revoked_at = $1
+		WHERE id = $2 AND company_id = $3 AND revoked_at IS NULL`, s.table)
+
+	result, err := tx.Exec(update, revokedAt, id, companyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrAPIKeyAlreadyRevoked
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// scanAPIKey scans a row of apiKeyColumns.
+func scanAPIKey(row sql.Row) (APIKey, error) {
+	var key APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.CompanyID,
+		&key.Name,
+		&key.Prefix,
+		&key.Hash,
+		&key.Permissions,
+		&key.CreatedAt,
+		&key.RevokedAt,
+		&key.UserID,
+	)
+	if err != nil {
+		return APIKey{}, err
+	}
+	return key, nil
+}
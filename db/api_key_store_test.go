@@ -0,0 +1,305 @@
+package db
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	sqlpkg "github.com/KatrinSalt/backend-challenge-go/db/sql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAPIKeyStore_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *apiKeyStore
+		key     APIKey
+		want    APIKey
+		wantErr bool
+	}{
+		{
+			name: "successful api key creation",
+			store: &apiKeyStore{
+				client: &mockSQLClient{
+					queryRowResult: &mockSQLRow{values: []interface{}{
+						1, 1, "ERP", "ak_1a2b3c4d", "hash", "submit_invoices,read", "2026-01-02T15:04:05Z", (*string)(nil), (*int)(nil),
+					}},
+				},
+				table: "api_keys",
+			},
+			key: APIKey{
+				CompanyID:   1,
+				Name:        "ERP",
+				Prefix:      "ak_1a2b3c4d",
+				Hash:        "hash",
+				Permissions: "submit_invoices,read",
+				CreatedAt:   "2026-01-02T15:04:05Z",
+			},
+			want: APIKey{
+				ID:          1,
+				CompanyID:   1,
+				Name:        "ERP",
+				Prefix:      "ak_1a2b3c4d",
+				Hash:        "hash",
+				Permissions: "submit_invoices,read",
+				CreatedAt:   "2026-01-02T15:04:05Z",
+			},
+		},
+		{
+			name: "insert fails",
+			store: &apiKeyStore{
+				client: &mockSQLClient{
+					queryRowResult: &mockSQLRow{scanErr: errors.New("insert failed")},
+				},
+				table: "api_keys",
+			},
+			key:     APIKey{CompanyID: 1, Name: "ERP"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.store.Create(test.key)
+
+			if test.wantErr {
+				if gotErr == nil {
+					t.Errorf("Create() expected error but got none")
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("Create() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Create() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAPIKeyStore_GetByHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *apiKeyStore
+		want    APIKey
+		wantErr error
+	}{
+		{
+			name: "revoked api key",
+			store: &apiKeyStore{
+				client: &mockSQLClient{
+					queryRowResult: &mockSQLRow{values: []interface{}{
+						2, 3, "ERP", "ak_1a2b3c4d", "hash", "read", "2026-01-02T15:04:05Z", stringPtr("2026-01-03T09:00:00Z"), intPtr(4),
+					}},
+				},
+				table: "api_keys",
+			},
+			want: APIKey{
+				ID:          2,
+				CompanyID:   3,
+				Name:        "ERP",
+				Prefix:      "ak_1a2b3c4d",
+				Hash:        "hash",
+				Permissions: "read",
+				CreatedAt:   "2026-01-02T15:04:05Z",
+				RevokedAt:   stringPtr("2026-01-03T09:00:00Z"),
+				UserID:      intPtr(4),
+			},
+		},
+		{
+			name: "unknown hash",
+			store: &apiKeyStore{
+				client: &mockSQLClient{
+					queryRowResult: &mockSQLRow{scanErr: sqlpkg.ErrNoRows},
+				},
+				table: "api_keys",
+			},
+			wantErr: ErrAPIKeyNotFound,
+		},
+		{
+			name: "query fails",
+			store: &apiKeyStore{
+				client: &mockSQLClient{
+					queryRowResult: &mockSQLRow{scanErr: errors.New("query failed")},
+				},
+				table: "api_keys",
+			},
+			wantErr: errors.New("query failed"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.store.GetByHash("hash")
+
+			if test.wantErr != nil {
+				if gotErr == nil || (!errors.Is(gotErr, test.wantErr) && !strings.Contains(gotErr.Error(), test.wantErr.Error())) {
+					t.Errorf("GetByHash() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("GetByHash() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("GetByHash() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAPIKeyStore_List(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *apiKeyStore
+		want    []APIKey
+		wantErr bool
+	}{
+		{
+			name: "successful list",
+			store: &apiKeyStore{
+				client: &mockSQLClient{
+					queryResult: &mockSQLRows{
+						rows: [][]interface{}{
+							{1, 1, "ERP", "ak_1a2b3c4d", "hash-1", "submit_invoices", "2026-01-02T15:04:05Z", stringPtr("2026-01-03T09:00:00Z"), (*int)(nil)},
+							{2, 1, "Admin", "ak_5e6f7a8b", "hash-2", "read,manage_rules", "2026-01-02T15:04:06Z", (*string)(nil), intPtr(2)},
+						},
+					},
+				},
+				table: "api_keys",
+			},
+			want: []APIKey{
+				{ID: 1, CompanyID: 1, Name: "ERP", Prefix: "ak_1a2b3c4d", Hash: "hash-1", Permissions: "submit_invoices", CreatedAt: "2026-01-02T15:04:05Z", RevokedAt: stringPtr("2026-01-03T09:00:00Z")},
+				{ID: 2, CompanyID: 1, Name: "Admin", Prefix: "ak_5e6f7a8b", Hash: "hash-2", Permissions: "read,manage_rules", CreatedAt: "2026-01-02T15:04:06Z", UserID: intPtr(2)},
+			},
+		},
+		{
+			name: "query fails",
+			store: &apiKeyStore{
+				client: &mockSQLClient{
+					queryErr: errors.New("query failed"),
+				},
+				table: "api_keys",
+			},
+			wantErr: true,
+		},
+		{
+			name: "scan fails",
+			store: &apiKeyStore{
+				client: &mockSQLClient{
+					queryResult: &mockSQLRows{
+						rows:    [][]interface{}{{1}},
+						scanErr: errors.New("scan failed"),
+					},
+				},
+				table: "api_keys",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.store.List(1)
+
+			if test.wantErr {
+				if gotErr == nil {
+					t.Errorf("List() expected error but got none")
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("List() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("List() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAPIKeyStore_Revoke(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *apiKeyStore
+		wantErr error
+	}{
+		{
+			name: "successful revocation",
+			store: &apiKeyStore{
+				client: &mockSQLClient{
+					tx: &mockSQLTx{
+						execResult:     &mockSQLResult{},
+						queryRowResult: &mockSQLRow{values: []interface{}{1}},
+					},
+				},
+				table: "api_keys",
+			},
+		},
+		{
+			name: "api key of another company",
+			store: &apiKeyStore{
+				client: &mockSQLClient{
+					tx: &mockSQLTx{
+						queryRowResult: &mockSQLRow{values: []interface{}{2}},
+					},
+				},
+				table: "api_keys",
+			},
+			wantErr: ErrCrossCompanyAccess,
+		},
+		{
+			name: "unknown api key",
+			store: &apiKeyStore{
+				client: &mockSQLClient{
+					tx: &mockSQLTx{
+						queryRowResult: &mockSQLRow{scanErr: sqlpkg.ErrNoRows},
+					},
+				},
+				table: "api_keys",
+			},
+			wantErr: ErrAPIKeyNotFound,
+		},
+		{
+			name: "commit fails",
+			store: &apiKeyStore{
+				client: &mockSQLClient{
+					tx: &mockSQLTx{
+						execResult:     &mockSQLResult{},
+						queryRowResult: &mockSQLRow{values: []interface{}{1}},
+						commitErr:      errors.New("commit failed"),
+					},
+				},
+				table: "api_keys",
+			},
+			wantErr: errors.New("commit failed"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotErr := test.store.Revoke(1, 1, "2026-01-03T09:00:00Z")
+
+			if test.wantErr != nil {
+				if gotErr == nil || (!errors.Is(gotErr, test.wantErr) && !strings.Contains(gotErr.Error(), test.wantErr.Error())) {
+					t.Errorf("Revoke() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("Revoke() unexpected error: %v", gotErr)
+			}
+		})
+	}
+}
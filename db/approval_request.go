@@ -0,0 +1,38 @@
+package db
+
+const (
+	// ApprovalStatusPending is the status of an approval request awaiting a decision.
+	ApprovalStatusPending = "pending"
+	// ApprovalStatusApproved is the status of an approved approval request.
+	ApprovalStatusApproved = "approved"
+	// ApprovalStatusRejected is the status of a rejected approval request.
+	ApprovalStatusRejected = "rejected"
+)
+
+// ApprovalRequest represents an approval request sent to an approver for an
+// invoice. The amount is stored in minor units (cents) of Currency, and
+// timestamps are RFC 3339 strings in UTC.
+type ApprovalRequest struct {
+	ID                        int     `db:"id"`
+	CompanyID                 int     `db:"company_id"`
+	WorkflowRuleID            int     `db:"workflow_rule_id"`
+	ApproverID                int     `db:"approver_id"`
+	Amount                    int64   `db:"amount"`
+	Currency                  string  `db:"currency"`
+	Department                *string `db:"department"`
+	Vendor                    *string `db:"vendor"`
+	Description               *string `db:"description"`
+	DueDate                   *string `db:"due_date"`
+	IsManagerApprovalRequired bool    `db:"is_manager_approval_required"`
+	ApprovalChannel           string  `db:"approval_channel"`
+	DeliveredChannel          *string `db:"delivered_channel"`
+	ConversationID            *string `db:"conversation_id"`
+	MessageID                 *string `db:"message_id"`
+	Status                    string  `db:"status"`
+	DecidedBy                 *string `db:"decided_by"`
+	DecidedAt                 *string `db:"decided_at"`
+	CreatedAt                 string  `db:"created_at"`
+	// SubmittedBy is the email of the user who submitted the invoice, if
+	// known.
+	SubmittedBy *string `db:"submitted_by"`
+}
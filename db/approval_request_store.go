@@ -0,0 +1,307 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/KatrinSalt/backend-challenge-go/db/sql"
+)
+
+var (
+	ErrApprovalRequestNotFound       = errors.New("approval request not found")
+	ErrApprovalRequestAlreadyDecided = errors.New("approval request already decided")
+	ErrInvalidApprovalStatus         = errors.New("invalid approval status")
+)
+
+// approvalRequestColumns lists the columns read by the approval request store
+// in the order expected by scanApprovalRequest.
+const approvalRequestColumns = `id, company_id, workflow_rule_id, approver_id, amount, currency, department,
+	vendor, description, due_date, is_manager_approval_required, approval_channel, delivered_channel, conversation_id, message_id, status, decided_by, decided_at, created_at, submitted_by`
+
+// ApprovalRequestStore defines the interface for approval request operations
+type ApprovalRequestStore interface {
+	Create(request ApprovalRequest) (ApprovalRequest, error)
+	GetByID(companyID, id int) (ApprovalRequest, error)
+	GetCompanyID(id int) (int, error)
+	FindDuplicate(request ApprovalRequest, since string) (ApprovalRequest, error)
+	ListByMessageID(companyID int, channel, messageID string) ([]ApprovalRequest, error)
+	SetDelivery(companyID, id int, channel, conversationID, messageID string) error
+	Decide(companyID, id int, status, decidedBy, decidedAt string) error
+}
+
+// approvalRequestStore implements ApprovalRequestStore
+type approvalRequestStore struct {
+	client sql.Client
+	table  string
+}
+
+// ApprovalRequestStoreOptions contains options for the approval request store.
+type ApprovalRequestStoreOptions struct {
+	Table string
+}
+
+// ApprovalRequestStoreOption is a function that sets options on the approval request store.
+type ApprovalRequestStoreOption func(o *ApprovalRequestStoreOptions)
+
+// NewApprovalRequestStore creates a new approval request store
+func NewApprovalRequestStore(client sql.Client, options ...ApprovalRequestStoreOption) (*approvalRequestStore, error) {
+	if client == nil {
+		return nil, errors.New("nil sql client")
+	}
+
+	opts := ApprovalRequestStoreOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+	if len(opts.Table) == 0 {
+		opts.Table = defaultApprovalRequestTable
+	}
+
+	return &approvalRequestStore{
+		client: client,
+		table:  opts.Table,
+	}, nil
+}
+
+// Create creates a new pending approval request.
+func (s *approvalRequestStore) Create(request ApprovalRequest) (ApprovalRequest, error) {
+	return insertApprovalRequest(s.client, s.table, request)
+}
+
+// GetByID retrieves a company's approval request by its ID.
+func (s *approvalRequestStore) GetByID(companyID, id int) (ApprovalRequest, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", approvalRequestColumns, s.table)
+	request, err := scanApprovalRequest(s.client.QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ApprovalRequest{}, ErrApprovalRequestNotFound
+		}
+		return ApprovalRequest{}, err
+	}
+
+	if request.CompanyID != companyID {
+		return ApprovalRequest{}, &CrossCompanyError{Entity: "approval request", ID: id, CompanyID: companyID}
+	}
+	return request, nil
+}
+
+// GetCompanyID returns the ID of the company that owns the approval request
+// with the ID.
+func (s *approvalRequestStore) GetCompanyID(id int) (int, error) {
+	var companyID int
+	query := fmt.Sprintf("SELECT company_id FROM %s WHERE id = $1", s.table)
+	if err := s.client.QueryRow(query, id).Scan(&companyID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrApprovalRequestNotFound
+		}
+		return 0, err
+	}
+	return companyID, nil
+}
+
+// FindDuplicate returns the latest approval request of the company for the
+// same invoice and approver as request that was created at or after since,
+// whatever its status. The invoice is identified by its amount, currency,
+// department, vendor, description, due date and whether manager approval
+// is required.
+func (s *approvalRequestStore) FindDuplicate(request ApprovalRequest, since string) (ApprovalRequest, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE company_id = $1 AND approver_id = $2 AND amount = $3 AND currency = $4
+			AND COALESCE(department, '') = COALESCE($5, '')
+			AND COALESCE(vendor, '') = COALESCE($6, '')
+			AND COALESCE(description, '') = COALESCE($7, '')
+			AND COALESCE(due_date, '') = COALESCE($8, '')
+			AND is_manager_approval_required = $9 AND created_at >= $10
+		ORDER BY id DESC
+		LIMIT 1`, approvalRequestColumns, s.table)
+
+	duplicate, err := scanApprovalRequest(s.client.QueryRow(query,
+		request.CompanyID,
+		request.ApproverID,
+		request.Amount,
+		request.Currency,
+		request.Department,
+		request.Vendor,
+		request.Description,
+		request.DueDate,
+		request.IsManagerApprovalRequired,
+		since,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ApprovalRequest{}, ErrApprovalRequestNotFound
+		}
+		return ApprovalRequest{}, fmt.Errorf("failed to find duplicate approval request: %w", err)
+	}
+	return duplicate, nil
+}
+
+// ListByMessageID returns the company's approval requests delivered over
+// the channel in the message with the ID, ordered by ID. A digest delivers
+// several approval requests in one message.
+func (s *approvalRequestStore) ListByMessageID(companyID int, channel, messageID string) ([]ApprovalRequest, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE company_id = $1 AND delivered_channel = $2 AND message_id = $3
+		ORDER BY id`, approvalRequestColumns, s.table)
+
+	rows, err := s.client.Query(query, companyID, channel, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query approval requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []ApprovalRequest
+	for rows.Next() {
+		request, err := scanApprovalRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan approval request: %w", err)
+		}
+		requests = append(requests, request)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over approval request rows: %w", err)
+	}
+
+	return requests, nil
+}
+
+// SetDelivery records the channel the approval request was delivered over,
+// which is a fallback channel if the approval channel failed, and where it
+// was delivered, so that the message can be found and updated once a
+// decision is made.
+func (s *approvalRequestStore) SetDelivery(companyID, id int, channel, conversationID, messageID string) error {
+	tx, err := s.client.Transaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkCompanyScope(tx, s.table, "approval request", id, companyID, ErrApprovalRequestNotFound); err != nil {
+		return err
+	}
+
+	update := fmt.Sprintf(`
+		UPDATE %s
+		SET delivered_channel = $1, conversation_id = NULLIF($2, ''), message_id = NULLIF($3, '')
+		WHERE id = $4 AND company_id = $5`, s.table)
+	if _, err := tx.Exec(update, channel, conversationID, messageID, id, companyID); err != nil {
+		return fmt.Errorf("failed to update approval request delivery: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Decide records the decision on a pending approval request. A request can
+// only be decided once.
+func (s *approvalRequestStore) Decide(companyID, id int, status, decidedBy, decidedAt string) error {
+	if status != ApprovalStatusApproved && status != ApprovalStatusRejected {
+		return fmt.Errorf("%w: %q", ErrInvalidApprovalStatus, status)
+	}
+
+	tx, err := s.client.Transaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkCompanyScope(tx, s.table, "approval request", id, companyID, ErrApprovalRequestNotFound); err != nil {
+		return err
+	}
+
+	// Only a pending request is updated, so a request that has already been
+	// decided affects no rows.
+	update := fmt.Sprintf(`
+		UPDATE %s
+		SET status = $1, decided_by = $2, decided_at = $3
+		WHERE id = $4 AND company_id = $5 AND status = $6`, s.table)
+
+	result, err := tx.Exec(update, status, decidedBy, decidedAt, id, companyID, ApprovalStatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to update approval request: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrApprovalRequestAlreadyDecided
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// insertApprovalRequest inserts a pending approval request into table with
+// q, which is either the client or a transaction.
+func insertApprovalRequest(q rowQuerier, table string, request ApprovalRequest) (ApprovalRequest, error) {
+	insert := fmt.Sprintf(`
+		INSERT INTO %s (company_id, workflow_rule_id, approver_id, amount, currency, department,
+			vendor, description, due_date, is_manager_approval_required, approval_channel, status, created_at, submitted_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING %s`, table, approvalRequestColumns)
+
+	outRequest, err := scanApprovalRequest(q.QueryRow(insert,
+		request.CompanyID,
+		request.WorkflowRuleID,
+		request.ApproverID,
+		request.Amount,
+		request.Currency,
+		request.Department,
+		request.Vendor,
+		request.Description,
+		request.DueDate,
+		request.IsManagerApprovalRequired,
+		request.ApprovalChannel,
+		ApprovalStatusPending,
+		request.CreatedAt,
+		request.SubmittedBy,
+	))
+	if err != nil {
+		return ApprovalRequest{}, fmt.Errorf("failed to create approval request: %w", err)
+	}
+
+	return outRequest, nil
+}
+
+// scanApprovalRequest scans a row of approvalRequestColumns.
+func scanApprovalRequest(row sql.Row) (ApprovalRequest, error) {
+	var request ApprovalRequest
+	err := row.Scan(
+		&request.ID,
+		&request.CompanyID,
+		&request.WorkflowRuleID,
+		&request.ApproverID,
+		&request.Amount,
+		&request.Currency,
+		&request.Department,
+		&request.Vendor,
+		&request.Description,
+		&request.DueDate,
+		&request.IsManagerApprovalRequired,
+		&request.ApprovalChannel,
+		&request.DeliveredChannel,
+		&request.ConversationID,
+		&request.MessageID,
+		&request.Status,
+		&request.DecidedBy,
+		&request.DecidedAt,
+		&request.CreatedAt,
+		&request.SubmittedBy,
+	)
+	if err != nil {
+		return ApprovalRequest{}, err
+	}
+	return request, nil
+}
@@ -0,0 +1,538 @@
+package db
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	sqlpkg "github.com/KatrinSalt/backend-challenge-go/db/sql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApprovalRequestStore_Create(t *testing.T) {
+	tests := []struct {
+		name  string
+		input struct {
+			store   *approvalRequestStore
+			request ApprovalRequest
+		}
+		want    ApprovalRequest
+		wantErr bool
+	}{
+		{
+			name: "successful approval request creation",
+			input: struct {
+				store   *approvalRequestStore
+				request ApprovalRequest
+			}{
+				store: &approvalRequestStore{
+					client: &mockSQLClient{
+						queryRowResult: &mockSQLRow{values: []interface{}{
+							1, 1, 2, 3, int64(750000), "USD", stringPtr("Finance"), (*string)(nil), (*string)(nil), (*string)(nil), true, "email",
+							(*string)(nil), (*string)(nil), (*string)(nil), "pending", (*string)(nil), (*string)(nil), "2026-01-02T15:04:05Z", stringPtr("finance_team@light.com"),
+						}},
+					},
+					table: "approval_requests",
+				},
+				request: ApprovalRequest{
+					CompanyID:                 1,
+					WorkflowRuleID:            2,
+					ApproverID:                3,
+					Amount:                    750000,
+					Currency:                  "USD",
+					Department:                stringPtr("Finance"),
+					IsManagerApprovalRequired: true,
+					ApprovalChannel:           "email",
+					CreatedAt:                 "2026-01-02T15:04:05Z",
+					SubmittedBy:               stringPtr("finance_team@light.com"),
+				},
+			},
+			want: ApprovalRequest{
+				ID:                        1,
+				CompanyID:                 1,
+				WorkflowRuleID:            2,
+				ApproverID:                3,
+				Amount:                    750000,
+				Currency:                  "USD",
+				Department:                stringPtr("Finance"),
+				IsManagerApprovalRequired: true,
+				ApprovalChannel:           "email",
+				Status:                    ApprovalStatusPending,
+				CreatedAt:                 "2026-01-02T15:04:05Z",
+				SubmittedBy:               stringPtr("finance_team@light.com"),
+			},
+		},
+		{
+			name: "insert fails",
+			input: struct {
+				store   *approvalRequestStore
+				request ApprovalRequest
+			}{
+				store: &approvalRequestStore{
+					client: &mockSQLClient{
+						queryRowResult: &mockSQLRow{scanErr: errors.New("insert failed")},
+					},
+					table: "approval_requests",
+				},
+				request: ApprovalRequest{CompanyID: 1},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.input.store.Create(test.input.request)
+
+			if test.wantErr {
+				if gotErr == nil {
+					t.Errorf("Create() expected error but got none")
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("Create() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Create() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApprovalRequestStore_FindDuplicate(t *testing.T) {
+	request := ApprovalRequest{
+		CompanyID:  1,
+		ApproverID: 3,
+		Amount:     300000,
+		Currency:   "USD",
+		Vendor:     stringPtr("Northwind"),
+	}
+
+	tests := []struct {
+		name    string
+		row     *mockSQLRow
+		want    ApprovalRequest
+		wantErr error
+	}{
+		{
+			name: "duplicate found",
+			row: &mockSQLRow{values: []interface{}{
+				7, 1, 2, 3, int64(300000), "USD", (*string)(nil), stringPtr("Northwind"), (*string)(nil), (*string)(nil), false, "slack",
+				stringPtr("slack"), (*string)(nil), (*string)(nil), "pending", (*string)(nil), (*string)(nil), "2026-01-02T15:04:05Z", (*string)(nil),
+			}},
+			want: ApprovalRequest{
+				ID:               7,
+				CompanyID:        1,
+				WorkflowRuleID:   2,
+				ApproverID:       3,
+				Amount:           300000,
+				Currency:         "USD",
+				Vendor:           stringPtr("Northwind"),
+				ApprovalChannel:  "slack",
+				DeliveredChannel: stringPtr("slack"),
+				Status:           ApprovalStatusPending,
+				CreatedAt:        "2026-01-02T15:04:05Z",
+			},
+		},
+		{
+			name:    "no duplicate",
+			row:     &mockSQLRow{scanErr: sqlpkg.ErrNoRows},
+			wantErr: ErrApprovalRequestNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &approvalRequestStore{client: &mockSQLClient{queryRowResult: test.row}, table: "approval_requests"}
+
+			got, gotErr := store.FindDuplicate(request, "2026-01-02T00:00:00Z")
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("FindDuplicate() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+			if gotErr != nil {
+				t.Fatalf("FindDuplicate() unexpected error: %v", gotErr)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("FindDuplicate() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApprovalRequestStore_ListByMessageID(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  *mockSQLClient
+		want    []ApprovalRequest
+		wantErr bool
+	}{
+		{
+			name: "requests of a digest",
+			client: &mockSQLClient{
+				queryResult: &mockSQLRows{
+					rows: [][]interface{}{
+						{
+							7, 1, 2, 3, int64(300000), "USD", (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), false, "email",
+							stringPtr("email"), (*string)(nil), stringPtr("<1.a@light.com>"), "pending", (*string)(nil), (*string)(nil), "2026-01-02T15:04:05Z", (*string)(nil),
+						},
+						{
+							8, 1, 2, 3, int64(450000), "USD", (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), false, "email",
+							stringPtr("email"), (*string)(nil), stringPtr("<1.a@light.com>"), "pending", (*string)(nil), (*string)(nil), "2026-01-02T15:05:05Z", (*string)(nil),
+						},
+					},
+				},
+			},
+			want: []ApprovalRequest{
+				{ID: 7, CompanyID: 1, WorkflowRuleID: 2, ApproverID: 3, Amount: 300000, Currency: "USD", ApprovalChannel: "email", DeliveredChannel: stringPtr("email"), MessageID: stringPtr("<1.a@light.com>"), Status: ApprovalStatusPending, CreatedAt: "2026-01-02T15:04:05Z"},
+				{ID: 8, CompanyID: 1, WorkflowRuleID: 2, ApproverID: 3, Amount: 450000, Currency: "USD", ApprovalChannel: "email", DeliveredChannel: stringPtr("email"), MessageID: stringPtr("<1.a@light.com>"), Status: ApprovalStatusPending, CreatedAt: "2026-01-02T15:05:05Z"},
+			},
+		},
+		{
+			name:   "no requests",
+			client: &mockSQLClient{queryResult: &mockSQLRows{}},
+		},
+		{
+			name:    "query fails",
+			client:  &mockSQLClient{queryErr: errors.New("query failed")},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &approvalRequestStore{client: test.client, table: "approval_requests"}
+
+			got, gotErr := store.ListByMessageID(1, "email", "<1.a@light.com>")
+			if test.wantErr {
+				if gotErr == nil {
+					t.Errorf("ListByMessageID() expected error but got none")
+				}
+				return
+			}
+			if gotErr != nil {
+				t.Fatalf("ListByMessageID() unexpected error: %v", gotErr)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ListByMessageID() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApprovalRequestStore_GetByID(t *testing.T) {
+	tests := []struct {
+		name  string
+		input struct {
+			store     *approvalRequestStore
+			companyID int
+			id        int
+		}
+		want    ApprovalRequest
+		wantErr error
+	}{
+		{
+			name: "approval request found",
+			input: struct {
+				store     *approvalRequestStore
+				companyID int
+				id        int
+			}{
+				store: &approvalRequestStore{
+					client: &mockSQLClient{
+						queryRowResult: &mockSQLRow{values: []interface{}{
+							1, 1, 2, 3, int64(300000), "USD", (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), false, "slack",
+							stringPtr("slack"), stringPtr("D123456"), stringPtr("1700000000.000100"), "approved", stringPtr("U123456"), stringPtr("2026-01-02T16:00:00Z"), "2026-01-02T15:04:05Z", (*string)(nil),
+						}},
+					},
+					table: "approval_requests",
+				},
+				companyID: 1,
+				id:        1,
+			},
+			want: ApprovalRequest{
+				ID:               1,
+				CompanyID:        1,
+				WorkflowRuleID:   2,
+				ApproverID:       3,
+				Amount:           300000,
+				Currency:         "USD",
+				ApprovalChannel:  "slack",
+				DeliveredChannel: stringPtr("slack"),
+				ConversationID:   stringPtr("D123456"),
+				MessageID:        stringPtr("1700000000.000100"),
+				Status:           ApprovalStatusApproved,
+				DecidedBy:        stringPtr("U123456"),
+				DecidedAt:        stringPtr("2026-01-02T16:00:00Z"),
+				CreatedAt:        "2026-01-02T15:04:05Z",
+			},
+		},
+		{
+			name: "approval request not found",
+			input: struct {
+				store     *approvalRequestStore
+				companyID int
+				id        int
+			}{
+				store: &approvalRequestStore{
+					client: &mockSQLClient{
+						queryRowResult: &mockSQLRow{scanErr: sqlpkg.ErrNoRows},
+					},
+					table: "approval_requests",
+				},
+				companyID: 1,
+				id:        99,
+			},
+			wantErr: ErrApprovalRequestNotFound,
+		},
+		{
+			name: "approval request of another company",
+			input: struct {
+				store     *approvalRequestStore
+				companyID int
+				id        int
+			}{
+				store: &approvalRequestStore{
+					client: &mockSQLClient{
+						queryRowResult: &mockSQLRow{values: []interface{}{
+							1, 2, 2, 3, int64(300000), "USD", (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), false, "slack",
+							(*string)(nil), (*string)(nil), (*string)(nil), "pending", (*string)(nil), (*string)(nil), "2026-01-02T15:04:05Z", (*string)(nil),
+						}},
+					},
+					table: "approval_requests",
+				},
+				companyID: 1,
+				id:        1,
+			},
+			wantErr: ErrCrossCompanyAccess,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.input.store.GetByID(test.input.companyID, test.input.id)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("GetByID() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("GetByID() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("GetByID() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApprovalRequestStore_GetCompanyID(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  *mockSQLClient
+		want    int
+		wantErr error
+	}{
+		{
+			name:   "company found",
+			client: &mockSQLClient{queryRowResult: &mockSQLRow{values: []interface{}{2}}},
+			want:   2,
+		},
+		{
+			name:    "approval request not found",
+			client:  &mockSQLClient{queryRowResult: &mockSQLRow{scanErr: sqlpkg.ErrNoRows}},
+			wantErr: ErrApprovalRequestNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &approvalRequestStore{client: test.client, table: "approval_requests"}
+
+			got, gotErr := store.GetCompanyID(7)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("GetCompanyID() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Fatalf("GetCompanyID() unexpected error: %v", gotErr)
+			}
+			if got != test.want {
+				t.Errorf("GetCompanyID() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestApprovalRequestStore_SetDelivery(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   *approvalRequestStore
+		wantErr error
+	}{
+		{
+			name: "successful delivery update",
+			input: &approvalRequestStore{
+				client: &mockSQLClient{
+					tx: &mockSQLTx{
+						execResult:     &mockSQLResult{},
+						queryRowResult: &mockSQLRow{values: []interface{}{1}},
+					},
+				},
+				table: "approval_requests",
+			},
+		},
+		{
+			name: "approval request not found",
+			input: &approvalRequestStore{
+				client: &mockSQLClient{
+					tx: &mockSQLTx{
+						queryRowResult: &mockSQLRow{scanErr: sqlpkg.ErrNoRows},
+					},
+				},
+				table: "approval_requests",
+			},
+			wantErr: ErrApprovalRequestNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotErr := test.input.SetDelivery(1, 1, "slack", "D123456", "1700000000.000100")
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("SetDelivery() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("SetDelivery() unexpected error: %v", gotErr)
+			}
+		})
+	}
+}
+
+func TestApprovalRequestStore_Decide(t *testing.T) {
+	tests := []struct {
+		name  string
+		input struct {
+			store     *approvalRequestStore
+			companyID int
+			status    string
+		}
+		wantErr error
+	}{
+		{
+			name: "successful decision",
+			input: struct {
+				store     *approvalRequestStore
+				companyID int
+				status    string
+			}{
+				store: &approvalRequestStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							execResult:     &mockSQLResult{},
+							queryRowResult: &mockSQLRow{values: []interface{}{1}},
+						},
+					},
+					table: "approval_requests",
+				},
+				companyID: 1,
+				status:    ApprovalStatusApproved,
+			},
+		},
+		{
+			name: "invalid status",
+			input: struct {
+				store     *approvalRequestStore
+				companyID int
+				status    string
+			}{
+				store: &approvalRequestStore{
+					client: &mockSQLClient{},
+					table:  "approval_requests",
+				},
+				companyID: 1,
+				status:    ApprovalStatusPending,
+			},
+			wantErr: ErrInvalidApprovalStatus,
+		},
+		{
+			name: "approval request of another company",
+			input: struct {
+				store     *approvalRequestStore
+				companyID int
+				status    string
+			}{
+				store: &approvalRequestStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							queryRowResult: &mockSQLRow{values: []interface{}{2}},
+						},
+					},
+					table: "approval_requests",
+				},
+				companyID: 1,
+				status:    ApprovalStatusRejected,
+			},
+			wantErr: ErrCrossCompanyAccess,
+		},
+		{
+			name: "commit fails",
+			input: struct {
+				store     *approvalRequestStore
+				companyID int
+				status    string
+			}{
+				store: &approvalRequestStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							execResult:     &mockSQLResult{},
+							queryRowResult: &mockSQLRow{values: []interface{}{1}},
+							commitErr:      errors.New("commit failed"),
+						},
+					},
+					table: "approval_requests",
+				},
+				companyID: 1,
+				status:    ApprovalStatusApproved,
+			},
+			wantErr: errors.New("commit failed"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotErr := test.input.store.Decide(test.input.companyID, 1, test.input.status, "U123456", "2026-01-02T16:00:00Z")
+
+			if test.wantErr != nil {
+				if gotErr == nil || (!errors.Is(gotErr, test.wantErr) && !strings.Contains(gotErr.Error(), test.wantErr.Error())) {
+					t.Errorf("Decide() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("Decide() unexpected error: %v", gotErr)
+			}
+		})
+	}
+}
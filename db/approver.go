@@ -8,4 +8,13 @@ type Approver struct {
 	Role      string `db:"role"`
 	Email     string `db:"email"`
 	SlackID   string `db:"slack_id"`
+	TeamsID   string `db:"teams_id"`
+	Locale    string `db:"locale"`
+	// NotificationPreference is immediate, hourly_digest or daily_digest.
+	NotificationPreference string `db:"notification_preference"`
+	// AlternateApproverID is the approver that invoices are routed to
+	// instead when this approver submitted them, if any.
+	AlternateApproverID *int `db:"alternate_approver_id"`
+	// ManagerID is the approver this approver reports to, if any.
+	ManagerID *int `db:"manager_id"`
 }
@@ -13,12 +13,17 @@ var (
 	ErrApproverAlreadyExists = errors.New("approver already exists")
 )
 
+// approverColumns lists the columns read by the approver store in the order
+// expected by scanApprover. Missing emails and Slack IDs are read as empty
+// strings.
+const approverColumns = "id, company_id, name, role, COALESCE(email, ''), COALESCE(slack_id, ''), teams_id, locale, notification_preference, alternate_approver_id, manager_id"
+
 // ApproverStore defines the interface for approver operations
 type ApproverStore interface {
 	Create(approver Approver) (Approver, error)
-	GetByID(id int) (Approver, error)
+	GetByID(companyID, id int) (Approver, error)
 	Update(approver Approver) error
-	Delete(id int) error
+	Delete(companyID, id int) error
 	List(companyID int) ([]Approver, error)
 }
 
@@ -64,21 +69,21 @@ func (s *approverStore) Create(approver Approver) (Approver, error) {
 	}
 	defer tx.Rollback()
 
-	insert := fmt.Sprintf("INSERT INTO %s (company_id, name, role, email, slack_id) VALUES ($1, $2, $3, $4, $5)", s.table)
-	if _, err := tx.Exec(insert, approver.CompanyID, approver.Name, approver.Role, approver.Email, approver.SlackID); err != nil {
+	// Email and Slack ID are optional. Missing ones are stored as NULL, so
+	// that they do not collide with the unique constraints.
+	insert := fmt.Sprintf(`
+		INSERT INTO %s (company_id, name, role, email, slack_id, teams_id, locale, notification_preference, alternate_approver_id, manager_id)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, COALESCE(NULLIF($8, ''), 'immediate'), $9, $10)
+		RETURNING %s`, s.table, approverColumns)
+
+	outApprover, err := scanApprover(tx.QueryRow(insert, approver.CompanyID, approver.Name, approver.Role, approver.Email, approver.SlackID, approver.TeamsID, approver.Locale, approver.NotificationPreference, approver.AlternateApproverID, approver.ManagerID))
+	if err != nil {
 		if strings.Contains(err.Error(), sql.SQLStateDuplicateKey) {
 			return Approver{}, ErrApproverAlreadyExists
 		}
 		return Approver{}, err
 	}
 
-	// Get the created approver with its generated ID.
-	var outApprover Approver
-	query := fmt.Sprintf("SELECT id, company_id, name, role, email, slack_id FROM %s WHERE company_id = $1 AND email = $2", s.table)
-	if err := tx.QueryRow(query, approver.CompanyID, approver.Email).Scan(&outApprover.ID, &outApprover.CompanyID, &outApprover.Name, &outApprover.Role, &outApprover.Email, &outApprover.SlackID); err != nil {
-		return Approver{}, err
-	}
-
 	if err := tx.Commit(); err != nil {
 		return Approver{}, err
 	}
@@ -86,17 +91,20 @@ func (s *approverStore) Create(approver Approver) (Approver, error) {
 	return outApprover, nil
 }
 
-// GetByID retrieves an approver by their ID.
-func (s *approverStore) GetByID(id int) (Approver, error) {
-	var approver Approver
-	query := fmt.Sprintf("SELECT id, company_id, name, role, email, slack_id FROM %s WHERE id = $1", s.table)
-	err := s.client.QueryRow(query, id).Scan(&approver.ID, &approver.CompanyID, &approver.Name, &approver.Role, &approver.Email, &approver.SlackID)
+// GetByID retrieves a company's approver by their ID.
+func (s *approverStore) GetByID(companyID, id int) (Approver, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", approverColumns, s.table)
+	approver, err := scanApprover(s.client.QueryRow(query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return Approver{}, ErrApproverNotFound
 		}
 		return Approver{}, err
 	}
+
+	if approver.CompanyID != companyID {
+		return Approver{}, &CrossCompanyError{Entity: "approver", ID: id, CompanyID: companyID}
+	}
 	return approver, nil
 }
 
@@ -108,30 +116,29 @@ func (s *approverStore) Update(approver Approver) error {
 	}
 	defer tx.Rollback()
 
-	// Check if the approver exists
-	var exists bool
-	checkQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1)", s.table)
-	if err := tx.QueryRow(checkQuery, approver.ID).Scan(&exists); err != nil {
-		return fmt.Errorf("failed to check if approver exists: %w", err)
-	}
-
-	if !exists {
-		return ErrApproverNotFound
+	// Check if the approver exists and belongs to the company
+	if err := checkCompanyScope(tx, s.table, "approver", approver.ID, approver.CompanyID, ErrApproverNotFound); err != nil {
+		return err
 	}
 
 	// Update the approver
 	updateQuery := fmt.Sprintf(`
 		UPDATE %s 
-		SET company_id = $2, name = $3, role = $4, email = $5, slack_id = $6 
-		WHERE id = $1`, s.table)
+		SET name = $1, role = $2, email = NULLIF($3, ''), slack_id = NULLIF($4, ''), teams_id = $5, locale = $6, notification_preference = COALESCE(NULLIF($7, ''), 'immediate'), alternate_approver_id = $8, manager_id = $9
+		WHERE id = $10 AND company_id = $11`, s.table)
 
 	result, err := tx.Exec(updateQuery,
-		approver.ID,
-		approver.CompanyID,
 		approver.Name,
 		approver.Role,
 		approver.Email,
-		approver.SlackID)
+		approver.SlackID,
+		approver.TeamsID,
+		approver.Locale,
+		approver.NotificationPreference,
+		approver.AlternateApproverID,
+		approver.ManagerID,
+		approver.ID,
+		approver.CompanyID)
 
 	if err != nil {
 		return fmt.Errorf("failed to update approver: %w", err)
@@ -155,7 +162,7 @@ func (s *approverStore) Update(approver Approver) error {
 
 // List retrieves all approvers for a specific company.
 func (s *approverStore) List(companyID int) ([]Approver, error) {
-	query := fmt.Sprintf("SELECT id, company_id, name, role, email, slack_id FROM %s WHERE company_id = $1", s.table)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE company_id = $1 ORDER BY id", approverColumns, s.table)
 
 	rows, err := s.client.Query(query, companyID)
 	if err != nil {
@@ -165,8 +172,7 @@ func (s *approverStore) List(companyID int) ([]Approver, error) {
 
 	var approvers []Approver
 	for rows.Next() {
-		var approver Approver
-		err := rows.Scan(&approver.ID, &approver.CompanyID, &approver.Name, &approver.Role, &approver.Email, &approver.SlackID)
+		approver, err := scanApprover(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan approver: %w", err)
 		}
@@ -180,28 +186,30 @@ func (s *approverStore) List(companyID int) ([]Approver, error) {
 	return approvers, nil
 }
 
-// Delete deletes an approver by their ID.
-func (s *approverStore) Delete(id int) error {
+// Delete deletes a company's approver by their ID. Approvers whose alternate
+// they were are left without one, and the approvers reporting to them
+// report to their manager instead.
+func (s *approverStore) Delete(companyID, id int) error {
 	tx, err := s.client.Transaction()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// Check if the approver exists
-	var exists bool
-	checkQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1)", s.table)
-	if err := tx.QueryRow(checkQuery, id).Scan(&exists); err != nil {
-		return fmt.Errorf("failed to check if approver exists: %w", err)
+	// Check if the approver exists and belongs to the company
+	if err := checkCompanyScope(tx, s.table, "approver", id, companyID, ErrApproverNotFound); err != nil {
+		return err
 	}
 
-	if !exists {
-		return ErrApproverNotFound
+	// Move the approver's reports up to the approver's manager
+	moveReports := fmt.Sprintf("UPDATE %s SET manager_id = (SELECT manager_id FROM %s WHERE id = $2) WHERE company_id = $1 AND manager_id = $2", s.table, s.table)
+	if _, err := tx.Exec(moveReports, companyID, id); err != nil {
+		return fmt.Errorf("failed to move reports of approver: %w", err)
 	}
 
 	// Delete the approver
-	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1", s.table)
-	result, err := tx.Exec(deleteQuery, id)
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND company_id = $2", s.table)
+	result, err := tx.Exec(deleteQuery, id, companyID)
 
 	if err != nil {
 		return fmt.Errorf("failed to delete approver: %w", err)
@@ -216,9 +224,36 @@ func (s *approverStore) Delete(id int) error {
 		return ErrApproverNotFound
 	}
 
+	clearAlternate := fmt.Sprintf("UPDATE %s SET alternate_approver_id = NULL WHERE company_id = $1 AND alternate_approver_id = $2", s.table)
+	if _, err := tx.Exec(clearAlternate, companyID, id); err != nil {
+		return fmt.Errorf("failed to clear alternate approver: %w", err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
+
+// scanApprover scans a row of approverColumns.
+func scanApprover(row sql.Row) (Approver, error) {
+	var approver Approver
+	err := row.Scan(
+		&approver.ID,
+		&approver.CompanyID,
+		&approver.Name,
+		&approver.Role,
+		&approver.Email,
+		&approver.SlackID,
+		&approver.TeamsID,
+		&approver.Locale,
+		&approver.NotificationPreference,
+		&approver.AlternateApproverID,
+		&approver.ManagerID,
+	)
+	if err != nil {
+		return Approver{}, err
+	}
+	return approver, nil
+}
@@ -127,27 +127,33 @@ func TestApproverStore_Create(t *testing.T) {
 						tx: &mockSQLTx{
 							execResult: &mockSQLResult{},
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{1, 1, "John Doe", "Manager", "john@example.com", "U123456"},
+								values: []interface{}{1, 1, "John Doe", "Manager", "john@example.com", "U123456", "john@example.com", "sv", "daily_digest", (*int)(nil), (*int)(nil)},
 							},
 						},
 					},
 					table: "approvers",
 				},
 				approver: Approver{
-					CompanyID: 1,
-					Name:      "John Doe",
-					Role:      "Manager",
-					Email:     "john@example.com",
-					SlackID:   "U123456",
+					CompanyID:              1,
+					Name:                   "John Doe",
+					Role:                   "Manager",
+					Email:                  "john@example.com",
+					SlackID:                "U123456",
+					TeamsID:                "john@example.com",
+					Locale:                 "sv",
+					NotificationPreference: "daily_digest",
 				},
 			},
 			want: Approver{
-				ID:        1,
-				CompanyID: 1,
-				Name:      "John Doe",
-				Role:      "Manager",
-				Email:     "john@example.com",
-				SlackID:   "U123456",
+				ID:                     1,
+				CompanyID:              1,
+				Name:                   "John Doe",
+				Role:                   "Manager",
+				Email:                  "john@example.com",
+				SlackID:                "U123456",
+				TeamsID:                "john@example.com",
+				Locale:                 "sv",
+				NotificationPreference: "daily_digest",
 			},
 			wantErr: false,
 		},
@@ -160,7 +166,9 @@ func TestApproverStore_Create(t *testing.T) {
 				store: &approverStore{
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
-							execErr: errors.New("duplicate key value violates unique constraint"),
+							queryRowResult: &mockSQLRow{
+								scanErr: errors.New("duplicate key value violates unique constraint"),
+							},
 						},
 					},
 					table: "approvers",
@@ -241,7 +249,7 @@ func TestApproverStore_Create(t *testing.T) {
 						tx: &mockSQLTx{
 							execResult: &mockSQLResult{},
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{1, 1, "John Doe", "Manager", "john@example.com", "U123456"},
+								values: []interface{}{1, 1, "John Doe", "Manager", "john@example.com", "U123456", "", "en", "immediate", (*int)(nil), (*int)(nil)},
 							},
 							commitErr: errors.New("commit failed"),
 						},
@@ -309,7 +317,7 @@ func TestApproverStore_GetByID(t *testing.T) {
 				store: &approverStore{
 					client: &mockSQLClient{
 						queryRowResult: &mockSQLRow{
-							values: []interface{}{1, 1, "John Doe", "Manager", "john@example.com", "U123456"},
+							values: []interface{}{1, 1, "John Doe", "Manager", "john@example.com", "U123456", "", "en", "immediate", (*int)(nil), (*int)(nil)},
 						},
 					},
 					table: "approvers",
@@ -317,15 +325,37 @@ func TestApproverStore_GetByID(t *testing.T) {
 				id: 1,
 			},
 			want: Approver{
-				ID:        1,
-				CompanyID: 1,
-				Name:      "John Doe",
-				Role:      "Manager",
-				Email:     "john@example.com",
-				SlackID:   "U123456",
+				ID:                     1,
+				CompanyID:              1,
+				Name:                   "John Doe",
+				Role:                   "Manager",
+				Email:                  "john@example.com",
+				SlackID:                "U123456",
+				Locale:                 "en",
+				NotificationPreference: "immediate",
 			},
 			wantErr: false,
 		},
+		{
+			name: "approver of another company",
+			input: struct {
+				store *approverStore
+				id    int
+			}{
+				store: &approverStore{
+					client: &mockSQLClient{
+						queryRowResult: &mockSQLRow{
+							values: []interface{}{3, 2, "Jane Doe", "CFO", "jane@example.com", "U654321", "", "en", "immediate", (*int)(nil), (*int)(nil)},
+						},
+					},
+					table: "approvers",
+				},
+				id: 3,
+			},
+			want:    Approver{},
+			wantErr: true,
+			errMsg:  "cross-company access: approver 3 does not belong to company 1",
+		},
 		{
 			name: "approver not found",
 			input: struct {
@@ -370,7 +400,7 @@ func TestApproverStore_GetByID(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got, gotErr := test.input.store.GetByID(test.input.id)
+			got, gotErr := test.input.store.GetByID(1, test.input.id)
 
 			if test.wantErr {
 				if gotErr == nil {
@@ -415,7 +445,7 @@ func TestApproverStore_Update(t *testing.T) {
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{true}, // Approver exists
+								values: []interface{}{1}, // Approver exists in company 1
 							},
 							execResult: &mockSQLResult{},
 						},
@@ -433,6 +463,30 @@ func TestApproverStore_Update(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "approver of another company",
+			input: struct {
+				store    *approverStore
+				approver Approver
+			}{
+				store: &approverStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							queryRowResult: &mockSQLRow{
+								values: []interface{}{2}, // Approver exists in company 2
+							},
+						},
+					},
+					table: "approvers",
+				},
+				approver: Approver{
+					ID:        3,
+					CompanyID: 1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "cross-company access: approver 3 does not belong to company 1",
+		},
 		{
 			name: "approver not found for update",
 			input: struct {
@@ -443,7 +497,7 @@ func TestApproverStore_Update(t *testing.T) {
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{false}, // Approver doesn't exist
+								scanErr: sqlpkg.ErrNoRows, // Approver doesn't exist
 							},
 						},
 					},
@@ -485,7 +539,7 @@ func TestApproverStore_Update(t *testing.T) {
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{true}, // Approver exists
+								values: []interface{}{1}, // Approver exists in company 1
 							},
 							execResult: &mockSQLResult{},
 							commitErr:  errors.New("commit failed"),
@@ -494,7 +548,8 @@ func TestApproverStore_Update(t *testing.T) {
 					table: "approvers",
 				},
 				approver: Approver{
-					ID: 1,
+					ID:        1,
+					CompanyID: 1,
 				},
 			},
 			wantErr: true,
@@ -544,7 +599,7 @@ func TestApproverStore_Delete(t *testing.T) {
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{true}, // Approver exists
+								values: []interface{}{1}, // Approver exists in company 1
 							},
 							execResult: &mockSQLResult{},
 						},
@@ -555,6 +610,27 @@ func TestApproverStore_Delete(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "approver of another company",
+			input: struct {
+				store *approverStore
+				id    int
+			}{
+				store: &approverStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							queryRowResult: &mockSQLRow{
+								values: []interface{}{2}, // Approver exists in company 2
+							},
+						},
+					},
+					table: "approvers",
+				},
+				id: 3,
+			},
+			wantErr: true,
+			errMsg:  "cross-company access: approver 3 does not belong to company 1",
+		},
 		{
 			name: "approver not found for deletion",
 			input: struct {
@@ -565,7 +641,7 @@ func TestApproverStore_Delete(t *testing.T) {
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{false}, // Approver doesn't exist
+								scanErr: sqlpkg.ErrNoRows, // Approver doesn't exist
 							},
 						},
 					},
@@ -603,7 +679,7 @@ func TestApproverStore_Delete(t *testing.T) {
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{true}, // Approver exists
+								values: []interface{}{1}, // Approver exists in company 1
 							},
 							execResult: &mockSQLResult{},
 							commitErr:  errors.New("commit failed"),
@@ -620,7 +696,7 @@ func TestApproverStore_Delete(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			gotErr := test.input.store.Delete(test.input.id)
+			gotErr := test.input.store.Delete(1, test.input.id)
 
 			if test.wantErr {
 				if gotErr == nil {
@@ -661,8 +737,8 @@ func TestApproverStore_List(t *testing.T) {
 					client: &mockSQLClient{
 						queryResult: &mockSQLRows{
 							rows: [][]interface{}{
-								{1, 1, "John Doe", "Manager", "john@example.com", "U123456"},
-								{2, 1, "Jane Smith", "Director", "jane@example.com", "U789012"},
+								{1, 1, "John Doe", "Manager", "john@example.com", "U123456", "", "en", "immediate", (*int)(nil), (*int)(nil)},
+								{2, 1, "Jane Smith", "Director", "jane@example.com", "U789012", "", "en", "immediate", intPtr(1), intPtr(1)},
 							},
 						},
 					},
@@ -672,20 +748,26 @@ func TestApproverStore_List(t *testing.T) {
 			},
 			want: []Approver{
 				{
-					ID:        1,
-					CompanyID: 1,
-					Name:      "John Doe",
-					Role:      "Manager",
-					Email:     "john@example.com",
-					SlackID:   "U123456",
+					ID:                     1,
+					CompanyID:              1,
+					Name:                   "John Doe",
+					Role:                   "Manager",
+					Email:                  "john@example.com",
+					SlackID:                "U123456",
+					Locale:                 "en",
+					NotificationPreference: "immediate",
 				},
 				{
-					ID:        2,
-					CompanyID: 1,
-					Name:      "Jane Smith",
-					Role:      "Director",
-					Email:     "jane@example.com",
-					SlackID:   "U789012",
+					ID:                     2,
+					CompanyID:              1,
+					Name:                   "Jane Smith",
+					Role:                   "Director",
+					Email:                  "jane@example.com",
+					SlackID:                "U789012",
+					Locale:                 "en",
+					NotificationPreference: "immediate",
+					AlternateApproverID:    intPtr(1),
+					ManagerID:              intPtr(1),
 				},
 			},
 			wantErr: false,
@@ -737,7 +819,7 @@ func TestApproverStore_List(t *testing.T) {
 					client: &mockSQLClient{
 						queryResult: &mockSQLRows{
 							rows: [][]interface{}{
-								{1, 1, "John Doe", "Manager", "john@example.com", "U123456"},
+								{1, 1, "John Doe", "Manager", "john@example.com", "U123456", "", "en", "immediate", (*int)(nil), (*int)(nil)},
 							},
 							scanErr: errors.New("scan failed"),
 						},
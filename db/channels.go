@@ -0,0 +1,21 @@
+package db
+
+import "strings"
+
+// SplitChannels splits a comma separated list of channel names, as stored
+// in the fallback_channels columns, into its names.
+func SplitChannels(channels string) []string {
+	var names []string
+	for _, name := range strings.Split(channels, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// JoinChannels joins channel names into a comma separated list for the
+// fallback_channels columns.
+func JoinChannels(names []string) string {
+	return strings.Join(names, ",")
+}
@@ -1,7 +1,10 @@
 package db
 
-// Company represents a company in the system.
+// Company represents a company in the system. FallbackChannels holds the
+// company's default fallback channels for workflow rules that define none,
+// as a comma separated list in the order they are tried.
 type Company struct {
-	ID   int    `db:"id"`
-	Name string `db:"name"`
+	ID               int    `db:"id"`
+	Name             string `db:"name"`
+	FallbackChannels string `db:"fallback_channels"`
 }
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/KatrinSalt/backend-challenge-go/db/sql"
+)
+
+// ErrCrossCompanyAccess is matched by every CrossCompanyError with errors.Is.
+var ErrCrossCompanyAccess = errors.New("cross-company access")
+
+// CrossCompanyError is returned when an operation on behalf of one company
+// targets an entity owned by another company.
+type CrossCompanyError struct {
+	Entity    string
+	ID        int
+	CompanyID int
+}
+
+// Error returns a description of the rejected access.
+func (e *CrossCompanyError) Error() string {
+	return fmt.Sprintf("%s: %s %d does not belong to company %d", ErrCrossCompanyAccess, e.Entity, e.ID, e.CompanyID)
+}
+
+// Is reports whether target is ErrCrossCompanyAccess.
+func (e *CrossCompanyError) Is(target error) bool {
+	return target == ErrCrossCompanyAccess
+}
+
+// rowQuerier is implemented by both sql.Client and sql.Tx.
+type rowQuerier interface {
+	QueryRow(query string, args ...any) sql.Row
+}
+
+// checkCompanyScope checks that the entity with the given ID exists in table
+// and is owned by the company. It returns notFound if the entity does not
+// exist and a *CrossCompanyError if another company owns it.
+func checkCompanyScope(q rowQuerier, table, entity string, id, companyID int, notFound error) error {
+	var ownerID int
+	query := fmt.Sprintf("SELECT company_id FROM %s WHERE id = $1", table)
+	if err := q.QueryRow(query, id).Scan(&ownerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFound
+		}
+		return fmt.Errorf("failed to check if %s exists: %w", entity, err)
+	}
+
+	if ownerID != companyID {
+		return &CrossCompanyError{Entity: entity, ID: id, CompanyID: companyID}
+	}
+
+	return nil
+}
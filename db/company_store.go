@@ -11,6 +11,7 @@ import (
 var (
 	ErrCompanyNotFound      = errors.New("company not found")
 	ErrCompanyAlreadyExists = errors.New("company already exists")
+	ErrCompanyNotEmpty      = errors.New("company still has approvers or workflow rules")
 )
 
 // CompanyStore defines the interface for company operations
@@ -18,6 +19,9 @@ type CompanyStore interface {
 	Create(company Company) (Company, error)
 	GetByID(id int) (Company, error)
 	GetByName(name string) (Company, error)
+	Update(company Company) error
+	Delete(id int) error
+	List() ([]Company, error)
 }
 
 // companyStore implements CompanyStore
@@ -62,8 +66,8 @@ func (s *companyStore) Create(company Company) (Company, error) {
 	}
 	defer tx.Rollback()
 
-	insert := fmt.Sprintf("INSERT INTO %s (name) VALUES ($1)", s.table)
-	if _, err := tx.Exec(insert, company.Name); err != nil {
+	insert := fmt.Sprintf("INSERT INTO %s (name, fallback_channels) VALUES ($1, $2)", s.table)
+	if _, err := tx.Exec(insert, company.Name, company.FallbackChannels); err != nil {
 		if strings.Contains(err.Error(), sql.SQLStateDuplicateKey) {
 			return Company{}, ErrCompanyAlreadyExists
 		}
@@ -72,8 +76,8 @@ func (s *companyStore) Create(company Company) (Company, error) {
 
 	// Get the created company with its generated ID.
 	var outCompany Company
-	query := fmt.Sprintf("SELECT id, name FROM %s WHERE name = $1", s.table)
-	if err := tx.QueryRow(query, company.Name).Scan(&outCompany.ID, &outCompany.Name); err != nil {
+	query := fmt.Sprintf("SELECT id, name, fallback_channels FROM %s WHERE name = $1", s.table)
+	if err := tx.QueryRow(query, company.Name).Scan(&outCompany.ID, &outCompany.Name, &outCompany.FallbackChannels); err != nil {
 		return Company{}, err
 	}
 
@@ -87,8 +91,8 @@ func (s *companyStore) Create(company Company) (Company, error) {
 // GetByID retrieves a company by its ID.
 func (s *companyStore) GetByID(id int) (Company, error) {
 	var company Company
-	query := fmt.Sprintf("SELECT id, name FROM %s WHERE id = $1", s.table)
-	err := s.client.QueryRow(query, id).Scan(&company.ID, &company.Name)
+	query := fmt.Sprintf("SELECT id, name, fallback_channels FROM %s WHERE id = $1", s.table)
+	err := s.client.QueryRow(query, id).Scan(&company.ID, &company.Name, &company.FallbackChannels)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return Company{}, ErrCompanyNotFound
@@ -101,8 +105,8 @@ func (s *companyStore) GetByID(id int) (Company, error) {
 // GetByName retrieves a company by its name.
 func (s *companyStore) GetByName(name string) (Company, error) {
 	var company Company
-	query := fmt.Sprintf("SELECT id, name FROM %s WHERE name = $1", s.table)
-	err := s.client.QueryRow(query, name).Scan(&company.ID, &company.Name)
+	query := fmt.Sprintf("SELECT id, name, fallback_channels FROM %s WHERE name = $1", s.table)
+	err := s.client.QueryRow(query, name).Scan(&company.ID, &company.Name, &company.FallbackChannels)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return Company{}, ErrCompanyNotFound
@@ -111,3 +115,114 @@ func (s *companyStore) GetByName(name string) (Company, error) {
 	}
 	return company, nil
 }
+
+// Update updates an existing company.
+func (s *companyStore) Update(company Company) error {
+	tx, err := s.client.Transaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Check if the company exists
+	var exists bool
+	checkQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1)", s.table)
+	if err := tx.QueryRow(checkQuery, company.ID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check if company exists: %w", err)
+	}
+
+	if !exists {
+		return ErrCompanyNotFound
+	}
+
+	// Check that the new name is not taken by another company
+	var taken bool
+	nameQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE name = $1 AND id != $2)", s.table)
+	if err := tx.QueryRow(nameQuery, company.Name, company.ID).Scan(&taken); err != nil {
+		return fmt.Errorf("failed to check company name: %w", err)
+	}
+
+	if taken {
+		return ErrCompanyAlreadyExists
+	}
+
+	// Update the company
+	updateQuery := fmt.Sprintf("UPDATE %s SET name = $1, fallback_channels = $2 WHERE id = $3", s.table)
+	if _, err := tx.Exec(updateQuery, company.Name, company.FallbackChannels, company.ID); err != nil {
+		return fmt.Errorf("failed to update company: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a company by its ID.
+func (s *companyStore) Delete(id int) error {
+	tx, err := s.client.Transaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Check if the company exists
+	var exists bool
+	checkQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1)", s.table)
+	if err := tx.QueryRow(checkQuery, id).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check if company exists: %w", err)
+	}
+
+	if !exists {
+		return ErrCompanyNotFound
+	}
+
+	// Delete the company
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1", s.table)
+	result, err := tx.Exec(deleteQuery, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete company: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrCompanyNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves all companies.
+func (s *companyStore) List() ([]Company, error) {
+	query := fmt.Sprintf("SELECT id, name, fallback_channels FROM %s ORDER BY id", s.table)
+
+	rows, err := s.client.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query companies: %w", err)
+	}
+	defer rows.Close()
+
+	var companies []Company
+	for rows.Next() {
+		var company Company
+		if err := rows.Scan(&company.ID, &company.Name, &company.FallbackChannels); err != nil {
+			return nil, fmt.Errorf("failed to scan company: %w", err)
+		}
+		companies = append(companies, company)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over company rows: %w", err)
+	}
+
+	return companies, nil
+}
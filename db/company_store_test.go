@@ -126,7 +126,7 @@ func TestCompanyStore_Create(t *testing.T) {
 						tx: &mockSQLTx{
 							execResult: &mockSQLResult{},
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{1, "Light"},
+								values: []interface{}{1, "Light", ""},
 							},
 						},
 					},
@@ -212,7 +212,7 @@ func TestCompanyStore_Create(t *testing.T) {
 						tx: &mockSQLTx{
 							execResult: &mockSQLResult{},
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{1, "Light"},
+								values: []interface{}{1, "Light", ""},
 							},
 							commitErr: errors.New("commit failed"),
 						},
@@ -274,7 +274,7 @@ func TestCompanyStore_GetByID(t *testing.T) {
 				store: &companyStore{
 					client: &mockSQLClient{
 						queryRowResult: &mockSQLRow{
-							values: []interface{}{1, "Light"},
+							values: []interface{}{1, "Light", ""},
 						},
 					},
 					table: "companies",
@@ -376,7 +376,7 @@ func TestCompanyStore_GetByName(t *testing.T) {
 				store: &companyStore{
 					client: &mockSQLClient{
 						queryRowResult: &mockSQLRow{
-							values: []interface{}{1, "Light"},
+							values: []interface{}{1, "Light", ""},
 						},
 					},
 					table: "companies",
@@ -457,3 +457,113 @@ func TestCompanyStore_GetByName(t *testing.T) {
 		})
 	}
 }
+
+func TestCompanyStore_Update(t *testing.T) {
+	tests := []struct {
+		name  string
+		input struct {
+			store   *companyStore
+			company Company
+		}
+		wantErr error
+	}{
+		{
+			name: "successful company update",
+			input: struct {
+				store   *companyStore
+				company Company
+			}{
+				store: &companyStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							execResult: &mockSQLResult{},
+							queryRowResults: []sqlpkg.Row{
+								&mockSQLRow{values: []interface{}{true}},
+								&mockSQLRow{values: []interface{}{false}},
+							},
+						},
+					},
+					table: "companies",
+				},
+				company: Company{ID: 1, Name: "Light AB"},
+			},
+		},
+		{
+			name: "company not found",
+			input: struct {
+				store   *companyStore
+				company Company
+			}{
+				store: &companyStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							queryRowResult: &mockSQLRow{values: []interface{}{false}},
+						},
+					},
+					table: "companies",
+				},
+				company: Company{ID: 99, Name: "Light AB"},
+			},
+			wantErr: ErrCompanyNotFound,
+		},
+		{
+			name: "name taken by another company",
+			input: struct {
+				store   *companyStore
+				company Company
+			}{
+				store: &companyStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							queryRowResult: &mockSQLRow{values: []interface{}{true}},
+						},
+					},
+					table: "companies",
+				},
+				company: Company{ID: 2, Name: "Light"},
+			},
+			wantErr: ErrCompanyAlreadyExists,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotErr := test.input.store.Update(test.input.company)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("Update() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("Update() unexpected error: %v", gotErr)
+			}
+		})
+	}
+}
+
+func TestCompanyStore_List(t *testing.T) {
+	store := &companyStore{
+		client: &mockSQLClient{
+			queryResult: &mockSQLRows{
+				rows: [][]interface{}{
+					{1, "Light", ""},
+					{2, "Acme", "email"},
+				},
+			},
+		},
+		table: "companies",
+	}
+
+	got, err := store.List()
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+
+	want := []Company{{ID: 1, Name: "Light"}, {ID: 2, Name: "Acme", FallbackChannels: "email"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("List() mismatch (-want +got)\n%s", diff)
+	}
+}
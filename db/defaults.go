@@ -1,7 +1,13 @@
 package db
 
 const (
-	defaultCompanyTable      = "companies"
-	defaultApproverTable     = "approvers"
-	defaultWorkflowRuleTable = "workflow_rules"
+	defaultCompanyTable         = "companies"
+	defaultDepartmentTable      = "departments"
+	defaultApproverTable        = "approvers"
+	defaultWorkflowRuleTable    = "workflow_rules"
+	defaultApprovalRequestTable = "approval_requests"
+	defaultDeliveryAttemptTable = "delivery_attempts"
+	defaultOutboxTable          = "notification_outbox"
+	defaultAPIKeyTable          = "api_keys"
+	defaultUserTable            = "users"
 )
@@ -0,0 +1,18 @@
+package db
+
+// DeliveryAttempt records one attempt to deliver an approval request over
+// a notification channel. StatusCode is the HTTP status returned by the
+// receiver, if any, and Error describes why the attempt failed. AttemptedAt
+// is an RFC 3339 string in UTC.
+type DeliveryAttempt struct {
+	ID                int     `db:"id"`
+	CompanyID         int     `db:"company_id"`
+	ApprovalRequestID int     `db:"approval_request_id"`
+	Channel           string  `db:"channel"`
+	IdempotencyKey    string  `db:"idempotency_key"`
+	Attempt           int     `db:"attempt"`
+	StatusCode        *int    `db:"status_code"`
+	Error             *string `db:"error"`
+	Succeeded         bool    `db:"succeeded"`
+	AttemptedAt       string  `db:"attempted_at"`
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/KatrinSalt/backend-challenge-go/db/sql"
+)
+
+// deliveryAttemptColumns lists the columns read by the delivery attempt
+// store in the order expected by scanDeliveryAttempt.
+const deliveryAttemptColumns = `id, company_id, approval_request_id, channel, idempotency_key, attempt,
+	status_code, error, succeeded, attempted_at`
+
+// DeliveryAttemptStore defines the interface for delivery attempt operations
+type DeliveryAttemptStore interface {
+	Create(attempt DeliveryAttempt) (DeliveryAttempt, error)
+	ListByApprovalRequest(companyID, approvalRequestID int) ([]DeliveryAttempt, error)
+}
+
+// deliveryAttemptStore implements DeliveryAttemptStore
+type deliveryAttemptStore struct {
+	client sql.Client
+	table  string
+}
+
+// DeliveryAttemptStoreOptions contains options for the delivery attempt store.
+type DeliveryAttemptStoreOptions struct {
+	Table string
+}
+
+// DeliveryAttemptStoreOption is a function that sets options on the delivery attempt store.
+type DeliveryAttemptStoreOption func(o *DeliveryAttemptStoreOptions)
+
+// NewDeliveryAttemptStore creates a new delivery attempt store
+func NewDeliveryAttemptStore(client sql.Client, options ...DeliveryAttemptStoreOption) (*deliveryAttemptStore, error) {
+	if client == nil {
+		return nil, errors.New("nil sql client")
+	}
+
+	opts := DeliveryAttemptStoreOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+	if len(opts.Table) == 0 {
+		opts.Table = defaultDeliveryAttemptTable
+	}
+
+	return &deliveryAttemptStore{
+		client: client,
+		table:  opts.Table,
+	}, nil
+}
+
+// Create records a delivery attempt.
+func (s *deliveryAttemptStore) Create(attempt DeliveryAttempt) (DeliveryAttempt, error) {
+	insert := fmt.Sprintf(`
+		INSERT INTO %s (company_id, approval_request_id, channel, idempotency_key, attempt,
+			status_code, error, succeeded, attempted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING %s`, s.table, deliveryAttemptColumns)
+
+	outAttempt, err := scanDeliveryAttempt(s.client.QueryRow(insert,
+		attempt.CompanyID,
+		attempt.ApprovalRequestID,
+		attempt.Channel,
+		attempt.IdempotencyKey,
+		attempt.Attempt,
+		attempt.StatusCode,
+		attempt.Error,
+		attempt.Succeeded,
+		attempt.AttemptedAt,
+	))
+	if err != nil {
+		return DeliveryAttempt{}, fmt.Errorf("failed to create delivery attempt: %w", err)
+	}
+
+	return outAttempt, nil
+}
+
+// ListByApprovalRequest retrieves the delivery attempts of a company's
+// approval request in the order they were made.
+func (s *deliveryAttemptStore) ListByApprovalRequest(companyID, approvalRequestID int) ([]DeliveryAttempt, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE company_id = $1 AND approval_request_id = $2 ORDER BY id", deliveryAttemptColumns, s.table)
+
+	rows, err := s.client.Query(query, companyID, approvalRequestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query delivery attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []DeliveryAttempt
+	for rows.Next() {
+		attempt, err := scanDeliveryAttempt(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery attempt: %w", err)
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over delivery attempt rows: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// scanDeliveryAttempt scans a row of deliveryAttemptColumns.
+func scanDeliveryAttempt(row sql.Row) (DeliveryAttempt, error) {
+	var attempt DeliveryAttempt
+	err := row.Scan(
+		&attempt.ID,
+		&attempt.CompanyID,
+		&attempt.ApprovalRequestID,
+		&attempt.Channel,
+		&attempt.IdempotencyKey,
+		&attempt.Attempt,
+		&attempt.StatusCode,
+		&attempt.Error,
+		&attempt.Succeeded,
+		&attempt.AttemptedAt,
+	)
+	if err != nil {
+		return DeliveryAttempt{}, err
+	}
+	return attempt, nil
+}
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDeliveryAttemptStore_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *deliveryAttemptStore
+		attempt DeliveryAttempt
+		want    DeliveryAttempt
+		wantErr bool
+	}{
+		{
+			name: "successful attempt creation",
+			store: &deliveryAttemptStore{
+				client: &mockSQLClient{
+					queryRowResult: &mockSQLRow{values: []interface{}{
+						1, 1, 7, "webhook", "key-1", 1, intPtr(200), (*string)(nil), true, "2026-01-02T15:04:05Z",
+					}},
+				},
+				table: "delivery_attempts",
+			},
+			attempt: DeliveryAttempt{
+				CompanyID:         1,
+				ApprovalRequestID: 7,
+				Channel:           "webhook",
+				IdempotencyKey:    "key-1",
+				Attempt:           1,
+				StatusCode:        intPtr(200),
+				Succeeded:         true,
+				AttemptedAt:       "2026-01-02T15:04:05Z",
+			},
+			want: DeliveryAttempt{
+				ID:                1,
+				CompanyID:         1,
+				ApprovalRequestID: 7,
+				Channel:           "webhook",
+				IdempotencyKey:    "key-1",
+				Attempt:           1,
+				StatusCode:        intPtr(200),
+				Succeeded:         true,
+				AttemptedAt:       "2026-01-02T15:04:05Z",
+			},
+		},
+		{
+			name: "insert fails",
+			store: &deliveryAttemptStore{
+				client: &mockSQLClient{
+					queryRowResult: &mockSQLRow{scanErr: errors.New("insert failed")},
+				},
+				table: "delivery_attempts",
+			},
+			attempt: DeliveryAttempt{CompanyID: 1, ApprovalRequestID: 7, Channel: "webhook"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.store.Create(test.attempt)
+
+			if test.wantErr {
+				if gotErr == nil {
+					t.Errorf("Create() expected error but got none")
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("Create() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Create() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeliveryAttemptStore_ListByApprovalRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *deliveryAttemptStore
+		want    []DeliveryAttempt
+		wantErr bool
+	}{
+		{
+			name: "successful list",
+			store: &deliveryAttemptStore{
+				client: &mockSQLClient{
+					queryResult: &mockSQLRows{
+						rows: [][]interface{}{
+							{1, 1, 7, "webhook", "key-1", 1, (*int)(nil), stringPtr("connection refused"), false, "2026-01-02T15:04:05Z"},
+							{2, 1, 7, "webhook", "key-1", 2, intPtr(200), (*string)(nil), true, "2026-01-02T15:04:06Z"},
+						},
+					},
+				},
+				table: "delivery_attempts",
+			},
+			want: []DeliveryAttempt{
+				{ID: 1, CompanyID: 1, ApprovalRequestID: 7, Channel: "webhook", IdempotencyKey: "key-1", Attempt: 1, Error: stringPtr("connection refused"), AttemptedAt: "2026-01-02T15:04:05Z"},
+				{ID: 2, CompanyID: 1, ApprovalRequestID: 7, Channel: "webhook", IdempotencyKey: "key-1", Attempt: 2, StatusCode: intPtr(200), Succeeded: true, AttemptedAt: "2026-01-02T15:04:06Z"},
+			},
+		},
+		{
+			name: "query fails",
+			store: &deliveryAttemptStore{
+				client: &mockSQLClient{
+					queryErr: errors.New("query failed"),
+				},
+				table: "delivery_attempts",
+			},
+			wantErr: true,
+		},
+		{
+			name: "scan fails",
+			store: &deliveryAttemptStore{
+				client: &mockSQLClient{
+					queryResult: &mockSQLRows{
+						rows:    [][]interface{}{{1}},
+						scanErr: errors.New("scan failed"),
+					},
+				},
+				table: "delivery_attempts",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.store.ListByApprovalRequest(1, 7)
+
+			if test.wantErr {
+				if gotErr == nil {
+					t.Errorf("ListByApprovalRequest() expected error but got none")
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("ListByApprovalRequest() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ListByApprovalRequest() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package db
+
+// Department represents a department owned by a company.
+type Department struct {
+	ID        int    `db:"id"`
+	CompanyID int    `db:"company_id"`
+	Name      string `db:"name"`
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/KatrinSalt/backend-challenge-go/db/sql"
+)
+
+var (
+	ErrDepartmentNotFound      = errors.New("department not found")
+	ErrDepartmentAlreadyExists = errors.New("department already exists")
+)
+
+// DepartmentStore defines the interface for department operations
+type DepartmentStore interface {
+	Create(department Department) (Department, error)
+	Delete(companyID int, name string) error
+	DeleteByCompany(companyID int) error
+	List(companyID int) ([]Department, error)
+}
+
+// departmentStore implements DepartmentStore
+type departmentStore struct {
+	client sql.Client
+	table  string
+}
+
+// DepartmentStoreOptions contains options for the department store.
+type DepartmentStoreOptions struct {
+	Table string
+}
+
+// DepartmentStoreOption is a function that sets options on the department store.
+type DepartmentStoreOption func(o *DepartmentStoreOptions)
+
+// NewDepartmentStore creates a new department store
+func NewDepartmentStore(client sql.Client, options ...DepartmentStoreOption) (*departmentStore, error) {
+	if client == nil {
+		return nil, errors.New("nil sql client")
+	}
+
+	opts := DepartmentStoreOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+	if len(opts.Table) == 0 {
+		opts.Table = defaultDepartmentTable
+	}
+
+	return &departmentStore{
+		client: client,
+		table:  opts.Table,
+	}, nil
+}
+
+// Create creates a new department for a company. Department names are
+// unique per company, ignoring case.
+func (s *departmentStore) Create(department Department) (Department, error) {
+	tx, err := s.client.Transaction()
+	if err != nil {
+		return Department{}, err
+	}
+	defer tx.Rollback()
+
+	// Check if the department already exists
+	var exists bool
+	checkQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE company_id = $1 AND LOWER(name) = LOWER($2))", s.table)
+	if err := tx.QueryRow(checkQuery, department.CompanyID, department.Name).Scan(&exists); err != nil {
+		return Department{}, fmt.Errorf("failed to check if department exists: %w", err)
+	}
+
+	if exists {
+		return Department{}, ErrDepartmentAlreadyExists
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (company_id, name) VALUES ($1, $2)", s.table)
+	if _, err := tx.Exec(insert, department.CompanyID, department.Name); err != nil {
+		return Department{}, err
+	}
+
+	// Get the created department with its generated ID.
+	var outDepartment Department
+	query := fmt.Sprintf("SELECT id, company_id, name FROM %s WHERE company_id = $1 AND name = $2", s.table)
+	if err := tx.QueryRow(query, department.CompanyID, department.Name).Scan(&outDepartment.ID, &outDepartment.CompanyID, &outDepartment.Name); err != nil {
+		return Department{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Department{}, err
+	}
+
+	return outDepartment, nil
+}
+
+// Delete deletes a company's department by its name, ignoring case.
+func (s *departmentStore) Delete(companyID int, name string) error {
+	tx, err := s.client.Transaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Check if the department exists
+	var exists bool
+	checkQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE company_id = $1 AND LOWER(name) = LOWER($2))", s.table)
+	if err := tx.QueryRow(checkQuery, companyID, name).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check if department exists: %w", err)
+	}
+
+	if !exists {
+		return ErrDepartmentNotFound
+	}
+
+	// Delete the department
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE company_id = $1 AND LOWER(name) = LOWER($2)", s.table)
+	if _, err := tx.Exec(deleteQuery, companyID, name); err != nil {
+		return fmt.Errorf("failed to delete department: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByCompany deletes all departments of a company.
+func (s *departmentStore) DeleteByCompany(companyID int) error {
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE company_id = $1", s.table)
+	if _, err := s.client.Exec(deleteQuery, companyID); err != nil {
+		return fmt.Errorf("failed to delete company departments: %w", err)
+	}
+	return nil
+}
+
+// List retrieves all departments for a specific company.
+func (s *departmentStore) List(companyID int) ([]Department, error) {
+	query := fmt.Sprintf("SELECT id, company_id, name FROM %s WHERE company_id = $1 ORDER BY id", s.table)
+
+	rows, err := s.client.Query(query, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query departments by company ID: %w", err)
+	}
+	defer rows.Close()
+
+	var departments []Department
+	for rows.Next() {
+		var department Department
+		if err := rows.Scan(&department.ID, &department.CompanyID, &department.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan department: %w", err)
+		}
+		departments = append(departments, department)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over department rows: %w", err)
+	}
+
+	return departments, nil
+}
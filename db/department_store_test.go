@@ -0,0 +1,214 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	sqlpkg "github.com/KatrinSalt/backend-challenge-go/db/sql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDepartmentStore_Create(t *testing.T) {
+	tests := []struct {
+		name  string
+		input struct {
+			store      *departmentStore
+			department Department
+		}
+		want    Department
+		wantErr error
+	}{
+		{
+			name: "successful department creation",
+			input: struct {
+				store      *departmentStore
+				department Department
+			}{
+				store: &departmentStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							execResult: &mockSQLResult{},
+							queryRowResults: []sqlpkg.Row{
+								&mockSQLRow{values: []interface{}{false}},
+								&mockSQLRow{values: []interface{}{1, 1, "Finance"}},
+							},
+						},
+					},
+					table: "departments",
+				},
+				department: Department{CompanyID: 1, Name: "Finance"},
+			},
+			want: Department{ID: 1, CompanyID: 1, Name: "Finance"},
+		},
+		{
+			name: "department already exists",
+			input: struct {
+				store      *departmentStore
+				department Department
+			}{
+				store: &departmentStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							queryRowResult: &mockSQLRow{values: []interface{}{true}},
+						},
+					},
+					table: "departments",
+				},
+				department: Department{CompanyID: 1, Name: "finance"},
+			},
+			wantErr: ErrDepartmentAlreadyExists,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.input.store.Create(test.input.department)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("Create() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("Create() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Create() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDepartmentStore_Delete(t *testing.T) {
+	tests := []struct {
+		name  string
+		input struct {
+			store     *departmentStore
+			companyID int
+			name      string
+		}
+		wantErr error
+	}{
+		{
+			name: "successful department deletion",
+			input: struct {
+				store     *departmentStore
+				companyID int
+				name      string
+			}{
+				store: &departmentStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							execResult:     &mockSQLResult{},
+							queryRowResult: &mockSQLRow{values: []interface{}{true}},
+						},
+					},
+					table: "departments",
+				},
+				companyID: 1,
+				name:      "Finance",
+			},
+		},
+		{
+			name: "department not found",
+			input: struct {
+				store     *departmentStore
+				companyID int
+				name      string
+			}{
+				store: &departmentStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							queryRowResult: &mockSQLRow{values: []interface{}{false}},
+						},
+					},
+					table: "departments",
+				},
+				companyID: 1,
+				name:      "Engineering",
+			},
+			wantErr: ErrDepartmentNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotErr := test.input.store.Delete(test.input.companyID, test.input.name)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("Delete() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("Delete() unexpected error: %v", gotErr)
+			}
+		})
+	}
+}
+
+func TestDepartmentStore_List(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   *departmentStore
+		want    []Department
+		wantErr bool
+	}{
+		{
+			name: "successful list",
+			input: &departmentStore{
+				client: &mockSQLClient{
+					queryResult: &mockSQLRows{
+						rows: [][]interface{}{
+							{1, 1, "Marketing"},
+							{2, 1, "Finance"},
+						},
+					},
+				},
+				table: "departments",
+			},
+			want: []Department{
+				{ID: 1, CompanyID: 1, Name: "Marketing"},
+				{ID: 2, CompanyID: 1, Name: "Finance"},
+			},
+		},
+		{
+			name: "query fails",
+			input: &departmentStore{
+				client: &mockSQLClient{
+					queryErr: errors.New("query failed"),
+				},
+				table: "departments",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.input.List(1)
+
+			if test.wantErr {
+				if gotErr == nil {
+					t.Errorf("List() expected error but got none")
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("List() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("List() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -43,13 +43,19 @@ func (m *mockSQLClient) Exec(query string, args ...any) (sqlpkg.Result, error) {
 }
 
 type mockSQLTx struct {
-	execResult     sqlpkg.Result
-	execErr        error
-	queryRowResult sqlpkg.Row
-	commitErr      error
+	execResult      sqlpkg.Result
+	execErr         error
+	queryRowResult  sqlpkg.Row
+	queryRowResults []sqlpkg.Row // Returned in order, one per QueryRow call, before queryRowResult
+	commitErr       error
 }
 
 func (m *mockSQLTx) QueryRow(query string, args ...any) sqlpkg.Row {
+	if len(m.queryRowResults) > 0 {
+		row := m.queryRowResults[0]
+		m.queryRowResults = m.queryRowResults[1:]
+		return row
+	}
 	return m.queryRowResult
 }
 
@@ -104,8 +110,12 @@ func (m *mockSQLRow) Scan(dest ...interface{}) error {
 			if v, ok := val.(string); ok {
 				*d = v
 			}
-		case **float64:
-			if v, ok := val.(*float64); ok {
+		case *int64:
+			if v, ok := val.(int64); ok {
+				*d = v
+			}
+		case **int64:
+			if v, ok := val.(*int64); ok {
 				*d = v
 			}
 		case **string:
@@ -164,8 +174,12 @@ func (m *mockSQLRows) Scan(dest ...interface{}) error {
 			if v, ok := val.(string); ok {
 				*d = v
 			}
-		case **float64:
-			if v, ok := val.(*float64); ok {
+		case *int64:
+			if v, ok := val.(int64); ok {
+				*d = v
+			}
+		case **int64:
+			if v, ok := val.(*int64); ok {
 				*d = v
 			}
 		case **string:
@@ -0,0 +1,39 @@
+package db
+
+const (
+	// OutboxStatusPending is the status of an outbox message waiting to be
+	// delivered.
+	OutboxStatusPending = "pending"
+	// OutboxStatusDelivered is the status of a delivered outbox message.
+	OutboxStatusDelivered = "delivered"
+	// OutboxStatusDead is the status of an outbox message that failed every
+	// delivery attempt. Dead messages are not retried until replayed.
+	OutboxStatusDead = "dead"
+)
+
+// OutboxMessage is a notification waiting in the outbox to be delivered
+// over a channel, or over its fallback channels, a comma separated list
+// tried in order if the channel fails. Digest is the notification
+// preference of the approver, hourly_digest or daily_digest, if the
+// message is sent in a digest, and empty otherwise. Payload is the JSON
+// encoded notification. Attempts counts the failed delivery attempts, and
+// NextAttemptAt is when the message is due. ClaimedUntil is set while a
+// delivery attempt is in progress, until the claim expires, so that the
+// message is not sent again by another dispatch pass, in this or another
+// process sharing the database. Timestamps are RFC 3339 strings in UTC.
+type OutboxMessage struct {
+	ID                int     `db:"id"`
+	CompanyID         int     `db:"company_id"`
+	ApprovalRequestID int     `db:"approval_request_id"`
+	Channel           string  `db:"channel"`
+	FallbackChannels  string  `db:"fallback_channels"`
+	Digest            string  `db:"digest"`
+	Payload           string  `db:"payload"`
+	Status            string  `db:"status"`
+	Attempts          int     `db:"attempts"`
+	NextAttemptAt     string  `db:"next_attempt_at"`
+	LastError         *string `db:"last_error"`
+	CreatedAt         string  `db:"created_at"`
+	DeliveredAt       *string `db:"delivered_at"`
+	ClaimedUntil      *string `db:"claimed_until"`
+}
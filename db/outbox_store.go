@@ -0,0 +1,281 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/KatrinSalt/backend-challenge-go/db/sql"
+)
+
+var (
+	ErrOutboxMessageNotFound = errors.New("outbox message not found")
+	ErrInvalidOutboxStatus   = errors.New("invalid outbox status")
+)
+
+// outboxColumns lists the columns read by the outbox store in the order
+// expected by scanOutboxMessage.
+const outboxColumns = `id, company_id, approval_request_id, channel, fallback_channels, digest, payload, status, attempts,
+	next_attempt_at, last_error, created_at, delivered_at, claimed_until`
+
+// OutboxStore defines the interface for notification outbox operations
+type OutboxStore interface {
+	CreateWithApprovalRequest(request ApprovalRequest, message OutboxMessage) (ApprovalRequest, OutboxMessage, error)
+	GetByID(companyID, id int) (OutboxMessage, error)
+	ClaimDue(companyID int, now, claimedUntil string, limit int) ([]OutboxMessage, error)
+	ListByStatus(companyID int, status string) ([]OutboxMessage, error)
+	Update(message OutboxMessage) error
+}
+
+// outboxStore implements OutboxStore
+type outboxStore struct {
+	client               sql.Client
+	table                string
+	approvalRequestTable string
+}
+
+// OutboxStoreOptions contains options for the outbox store.
+type OutboxStoreOptions struct {
+	Table                string
+	ApprovalRequestTable string
+}
+
+// OutboxStoreOption is a function that sets options on the outbox store.
+type OutboxStoreOption func(o *OutboxStoreOptions)
+
+// NewOutboxStore creates a new outbox store
+func NewOutboxStore(client sql.Client, options ...OutboxStoreOption) (*outboxStore, error) {
+	if client == nil {
+		return nil, errors.New("nil sql client")
+	}
+
+	opts := OutboxStoreOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+	if len(opts.Table) == 0 {
+		opts.Table = defaultOutboxTable
+	}
+	if len(opts.ApprovalRequestTable) == 0 {
+		opts.ApprovalRequestTable = defaultApprovalRequestTable
+	}
+
+	return &outboxStore{
+		client:               client,
+		table:                opts.Table,
+		approvalRequestTable: opts.ApprovalRequestTable,
+	}, nil
+}
+
+// CreateWithApprovalRequest creates a pending approval request and the
+// outbox message that notifies its approver in one transaction, so that
+// either both are recorded or neither is. The message is linked to the
+// created request, and claimed if its ClaimedUntil is set.
+func (s *outboxStore) CreateWithApprovalRequest(request ApprovalRequest, message OutboxMessage) (ApprovalRequest, OutboxMessage, error) {
+	tx, err := s.client.Transaction()
+	if err != nil {
+		return ApprovalRequest{}, OutboxMessage{}, err
+	}
+	defer tx.Rollback()
+
+	outRequest, err := insertApprovalRequest(tx, s.approvalRequestTable, request)
+	if err != nil {
+		return ApprovalRequest{}, OutboxMessage{}, err
+	}
+
+	insert := fmt.Sprintf(`
+		INSERT INTO %s (company_id, approval_request_id, channel, fallback_channels, digest, payload, status, attempts,
+			next_attempt_at, created_at, claimed_until)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING %s`, s.table, outboxColumns)
+
+	outMessage, err := scanOutboxMessage(tx.QueryRow(insert,
+		outRequest.CompanyID,
+		outRequest.ID,
+		message.Channel,
+		message.FallbackChannels,
+		message.Digest,
+		message.Payload,
+		OutboxStatusPending,
+		0,
+		message.NextAttemptAt,
+		message.CreatedAt,
+		message.ClaimedUntil,
+	))
+	if err != nil {
+		return ApprovalRequest{}, OutboxMessage{}, fmt.Errorf("failed to create outbox message: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ApprovalRequest{}, OutboxMessage{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return outRequest, outMessage, nil
+}
+
+// GetByID retrieves a company's outbox message by its ID.
+func (s *outboxStore) GetByID(companyID, id int) (OutboxMessage, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", outboxColumns, s.table)
+	message, err := scanOutboxMessage(s.client.QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OutboxMessage{}, ErrOutboxMessageNotFound
+		}
+		return OutboxMessage{}, err
+	}
+
+	if message.CompanyID != companyID {
+		return OutboxMessage{}, &CrossCompanyError{Entity: "outbox message", ID: id, CompanyID: companyID}
+	}
+	return message, nil
+}
+
+// ClaimDue claims up to limit of a company's pending outbox messages that
+// are due at now and not claimed, oldest first, until claimedUntil. Each
+// message is claimed with a conditional update, so a message listed by
+// concurrent callers, in this or another process, is claimed by only one
+// of them. The claimed messages are returned as they are stored once
+// claimed.
+func (s *outboxStore) ClaimDue(companyID int, now, claimedUntil string, limit int) ([]OutboxMessage, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE company_id = $1 AND status = $2 AND next_attempt_at <= $3 AND (claimed_until IS NULL OR claimed_until <= $3)
+		ORDER BY next_attempt_at, id
+		LIMIT $4`, outboxColumns, s.table)
+
+	due, err := s.list(query, companyID, OutboxStatusPending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := fmt.Sprintf(`
+		UPDATE %s
+		SET claimed_until = $1
+		WHERE id = $2 AND status = $3 AND next_attempt_at <= $4 AND (claimed_until IS NULL OR claimed_until <= $4)
+		RETURNING %s`, s.table, outboxColumns)
+
+	var claimed []OutboxMessage
+	for _, candidate := range due {
+		message, err := scanOutboxMessage(s.client.QueryRow(claim, claimedUntil, candidate.ID, OutboxStatusPending, now))
+		if err != nil {
+			// Another caller claimed the message, or recorded a delivery
+			// attempt, since it was listed.
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to claim outbox message: %w", err)
+		}
+		claimed = append(claimed, message)
+	}
+
+	return claimed, nil
+}
+
+// ListByStatus retrieves a company's outbox messages with the given status.
+func (s *outboxStore) ListByStatus(companyID int, status string) ([]OutboxMessage, error) {
+	if !validOutboxStatus(status) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidOutboxStatus, status)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE company_id = $1 AND status = $2 ORDER BY id", outboxColumns, s.table)
+	return s.list(query, companyID, status)
+}
+
+// Update updates the delivery state of a company's outbox message: its
+// status, attempts, next attempt, last error and delivery time. The claim
+// on the message is released.
+func (s *outboxStore) Update(message OutboxMessage) error {
+	if !validOutboxStatus(message.Status) {
+		return fmt.Errorf("%w: %q", ErrInvalidOutboxStatus, message.Status)
+	}
+
+	tx, err := s.client.Transaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkCompanyScope(tx, s.table, "outbox message", message.ID, message.CompanyID, ErrOutboxMessageNotFound); err != nil {
+		return err
+	}
+
+	update := fmt.Sprintf(`
+		UPDATE %s
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4, delivered_at = $5, claimed_until = NULL
+		WHERE id = $6 AND company_id = $7`, s.table)
+
+	if _, err := tx.Exec(update,
+		message.Status,
+		message.Attempts,
+		message.NextAttemptAt,
+		message.LastError,
+		message.DeliveredAt,
+		message.ID,
+		message.CompanyID,
+	); err != nil {
+		return fmt.Errorf("failed to update outbox message: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// list runs a query for outbox messages.
+func (s *outboxStore) list(query string, args ...any) ([]OutboxMessage, error) {
+	rows, err := s.client.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []OutboxMessage
+	for rows.Next() {
+		message, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over outbox message rows: %w", err)
+	}
+
+	return messages, nil
+}
+
+// validOutboxStatus reports whether status is a known outbox status.
+func validOutboxStatus(status string) bool {
+	switch status {
+	case OutboxStatusPending, OutboxStatusDelivered, OutboxStatusDead:
+		return true
+	}
+	return false
+}
+
+// scanOutboxMessage scans a row of outboxColumns.
+func scanOutboxMessage(row sql.Row) (OutboxMessage, error) {
+	var message OutboxMessage
+	err := row.Scan(
+		&message.ID,
+		&message.CompanyID,
+		&message.ApprovalRequestID,
+		&message.Channel,
+		&message.FallbackChannels,
+		&message.Digest,
+		&message.Payload,
+		&message.Status,
+		&message.Attempts,
+		&message.NextAttemptAt,
+		&message.LastError,
+		&message.CreatedAt,
+		&message.DeliveredAt,
+		&message.ClaimedUntil,
+	)
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+	return message, nil
+}
@@ -0,0 +1,303 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	sqlpkg "github.com/KatrinSalt/backend-challenge-go/db/sql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOutboxStore_CreateWithApprovalRequest(t *testing.T) {
+	approvalRequestRow := func() *mockSQLRow {
+		return &mockSQLRow{values: []interface{}{
+			7, 1, 2, 3, int64(1500000), "USD", (*string)(nil), (*string)(nil), (*string)(nil), (*string)(nil), false, "slack",
+			(*string)(nil), (*string)(nil), (*string)(nil), "pending", (*string)(nil), (*string)(nil), "2026-01-02T15:00:00Z", (*string)(nil),
+		}}
+	}
+
+	tests := []struct {
+		name        string
+		tx          *mockSQLTx
+		wantRequest ApprovalRequest
+		wantMessage OutboxMessage
+		wantErr     bool
+	}{
+		{
+			name: "request and message created",
+			tx: &mockSQLTx{
+				queryRowResults: []sqlpkg.Row{
+					approvalRequestRow(),
+					&mockSQLRow{values: []interface{}{
+						1, 1, 7, "slack", "email", "hourly_digest", `{"approver":{}}`, "pending", 0,
+						"2026-01-02T15:00:00Z", (*string)(nil), "2026-01-02T15:00:00Z", (*string)(nil), (*string)(nil),
+					}},
+				},
+			},
+			wantRequest: ApprovalRequest{
+				ID:              7,
+				CompanyID:       1,
+				WorkflowRuleID:  2,
+				ApproverID:      3,
+				Amount:          1500000,
+				Currency:        "USD",
+				ApprovalChannel: "slack",
+				Status:          ApprovalStatusPending,
+				CreatedAt:       "2026-01-02T15:00:00Z",
+			},
+			wantMessage: OutboxMessage{
+				ID:                1,
+				CompanyID:         1,
+				ApprovalRequestID: 7,
+				Channel:           "slack",
+				FallbackChannels:  "email",
+				Digest:            "hourly_digest",
+				Payload:           `{"approver":{}}`,
+				Status:            OutboxStatusPending,
+				NextAttemptAt:     "2026-01-02T15:00:00Z",
+				CreatedAt:         "2026-01-02T15:00:00Z",
+			},
+		},
+		{
+			name: "approval request insert fails",
+			tx: &mockSQLTx{
+				queryRowResult: &mockSQLRow{scanErr: errors.New("insert failed")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "outbox message insert fails",
+			tx: &mockSQLTx{
+				queryRowResults: []sqlpkg.Row{approvalRequestRow()},
+				queryRowResult:  &mockSQLRow{scanErr: errors.New("insert failed")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "commit fails",
+			tx: &mockSQLTx{
+				queryRowResults: []sqlpkg.Row{
+					approvalRequestRow(),
+					&mockSQLRow{values: []interface{}{
+						1, 1, 7, "slack", "", `{}`, "pending", 0,
+						"2026-01-02T15:00:00Z", (*string)(nil), "2026-01-02T15:00:00Z", (*string)(nil),
+					}},
+				},
+				commitErr: errors.New("commit failed"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &outboxStore{
+				client:               &mockSQLClient{tx: test.tx},
+				table:                "notification_outbox",
+				approvalRequestTable: "approval_requests",
+			}
+
+			gotRequest, gotMessage, gotErr := store.CreateWithApprovalRequest(
+				ApprovalRequest{CompanyID: 1, WorkflowRuleID: 2, ApproverID: 3, Amount: 1500000, Currency: "USD", ApprovalChannel: "slack"},
+				OutboxMessage{Channel: "slack", FallbackChannels: "email", Payload: `{"approver":{}}`, NextAttemptAt: "2026-01-02T15:00:00Z", CreatedAt: "2026-01-02T15:00:00Z"},
+			)
+
+			if test.wantErr {
+				if gotErr == nil {
+					t.Errorf("CreateWithApprovalRequest() expected error but got none")
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Fatalf("CreateWithApprovalRequest() unexpected error: %v", gotErr)
+			}
+			if diff := cmp.Diff(test.wantRequest, gotRequest); diff != "" {
+				t.Errorf("CreateWithApprovalRequest() request mismatch (-want +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantMessage, gotMessage); diff != "" {
+				t.Errorf("CreateWithApprovalRequest() message mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOutboxStore_ClaimDue(t *testing.T) {
+	dueRows := func() *mockSQLRows {
+		return &mockSQLRows{rows: [][]interface{}{
+			{1, 1, 7, "slack", "", "", `{}`, "pending", 1, "2026-01-02T15:00:01Z", stringPtr("slack is down"), "2026-01-02T15:00:00Z", (*string)(nil), (*string)(nil)},
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		client  *mockSQLClient
+		want    []OutboxMessage
+		wantErr bool
+	}{
+		{
+			name: "due message claimed",
+			client: &mockSQLClient{
+				queryResult: dueRows(),
+				queryRowResult: &mockSQLRow{values: []interface{}{
+					1, 1, 7, "slack", "", "", `{}`, "pending", 1, "2026-01-02T15:00:01Z", stringPtr("slack is down"), "2026-01-02T15:00:00Z", (*string)(nil), stringPtr("2026-01-02T15:16:00Z"),
+				}},
+			},
+			want: []OutboxMessage{
+				{ID: 1, CompanyID: 1, ApprovalRequestID: 7, Channel: "slack", Payload: `{}`, Status: OutboxStatusPending, Attempts: 1,
+					NextAttemptAt: "2026-01-02T15:00:01Z", LastError: stringPtr("slack is down"), CreatedAt: "2026-01-02T15:00:00Z",
+					ClaimedUntil: stringPtr("2026-01-02T15:16:00Z")},
+			},
+		},
+		{
+			name: "message claimed by another caller since it was listed",
+			client: &mockSQLClient{
+				queryResult:    dueRows(),
+				queryRowResult: &mockSQLRow{scanErr: sqlpkg.ErrNoRows},
+			},
+		},
+		{
+			name: "claim fails",
+			client: &mockSQLClient{
+				queryResult:    dueRows(),
+				queryRowResult: &mockSQLRow{scanErr: errors.New("database is locked")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &outboxStore{client: test.client, table: "notification_outbox"}
+
+			got, gotErr := store.ClaimDue(1, "2026-01-02T15:01:00Z", "2026-01-02T15:16:00Z", 50)
+
+			if test.wantErr {
+				if gotErr == nil {
+					t.Errorf("ClaimDue() expected error but got none")
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Fatalf("ClaimDue() unexpected error: %v", gotErr)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ClaimDue() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOutboxStore_ListByStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  *mockSQLClient
+		status  string
+		want    []OutboxMessage
+		wantErr error
+	}{
+		{
+			name: "dead letters listed",
+			client: &mockSQLClient{
+				queryResult: &mockSQLRows{
+					rows: [][]interface{}{
+						{3, 1, 9, "email", "", "", `{}`, "dead", 5, "2026-01-02T15:05:00Z", stringPtr("smtp: connection refused"), "2026-01-02T15:00:00Z", (*string)(nil), (*string)(nil)},
+					},
+				},
+			},
+			status: OutboxStatusDead,
+			want: []OutboxMessage{
+				{ID: 3, CompanyID: 1, ApprovalRequestID: 9, Channel: "email", Payload: `{}`, Status: OutboxStatusDead, Attempts: 5,
+					NextAttemptAt: "2026-01-02T15:05:00Z", LastError: stringPtr("smtp: connection refused"), CreatedAt: "2026-01-02T15:00:00Z"},
+			},
+		},
+		{
+			name:    "invalid status",
+			client:  &mockSQLClient{},
+			status:  "failed",
+			wantErr: ErrInvalidOutboxStatus,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &outboxStore{client: test.client, table: "notification_outbox"}
+
+			got, gotErr := store.ListByStatus(1, test.status)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("ListByStatus() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Fatalf("ListByStatus() unexpected error: %v", gotErr)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ListByStatus() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOutboxStore_Update(t *testing.T) {
+	tests := []struct {
+		name    string
+		tx      *mockSQLTx
+		message OutboxMessage
+		wantErr error
+	}{
+		{
+			name: "successful update",
+			tx: &mockSQLTx{
+				execResult:     &mockSQLResult{},
+				queryRowResult: &mockSQLRow{values: []interface{}{1}},
+			},
+			message: OutboxMessage{ID: 1, CompanyID: 1, Status: OutboxStatusDelivered, Attempts: 1, DeliveredAt: stringPtr("2026-01-02T15:00:01Z")},
+		},
+		{
+			name: "outbox message not found",
+			tx: &mockSQLTx{
+				queryRowResult: &mockSQLRow{scanErr: sqlpkg.ErrNoRows},
+			},
+			message: OutboxMessage{ID: 1, CompanyID: 1, Status: OutboxStatusPending},
+			wantErr: ErrOutboxMessageNotFound,
+		},
+		{
+			name: "another company's outbox message",
+			tx: &mockSQLTx{
+				queryRowResult: &mockSQLRow{values: []interface{}{2}},
+			},
+			message: OutboxMessage{ID: 1, CompanyID: 1, Status: OutboxStatusPending},
+			wantErr: ErrCrossCompanyAccess,
+		},
+		{
+			name:    "invalid status",
+			tx:      &mockSQLTx{},
+			message: OutboxMessage{ID: 1, CompanyID: 1, Status: "failed"},
+			wantErr: ErrInvalidOutboxStatus,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &outboxStore{client: &mockSQLClient{tx: test.tx}, table: "notification_outbox"}
+
+			gotErr := store.Update(test.message)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("Update() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("Update() unexpected error: %v", gotErr)
+			}
+		})
+	}
+}
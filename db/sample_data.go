@@ -1,18 +1,25 @@
 package db
 
+import "github.com/KatrinSalt/backend-challenge-go/money"
+
 // SampleData contains all the sample data for seeding the database.
 type SampleData struct {
 	Companies     []Company
+	Departments   []Department
 	Approvers     []Approver
 	WorkflowRules []WorkflowRule
+	APIKeys       []APIKey
+	Users         []User
 }
 
 // NewSampleData creates and returns sample data based on Figure 1 from the README.md.
 func NewSampleData() *SampleData {
 	return &SampleData{
 		Companies:     getSampleCompanies(),
+		Departments:   getSampleDepartments(),
 		Approvers:     getSampleApprovers(),
 		WorkflowRules: getSampleWorkflowRules(),
+		Users:         getSampleUsers(),
 	}
 }
 
@@ -20,6 +27,7 @@ func NewSampleData() *SampleData {
 const (
 	companyID           = 1
 	marketingDepartment = "Marketing"
+	financeDepartment   = "Finance"
 )
 
 // getSampleCompanies returns sample company data.
@@ -31,6 +39,20 @@ func getSampleCompanies() []Company {
 	}
 }
 
+// getSampleDepartments returns sample department data.
+func getSampleDepartments() []Department {
+	return []Department{
+		{
+			CompanyID: companyID,
+			Name:      marketingDepartment,
+		},
+		{
+			CompanyID: companyID,
+			Name:      financeDepartment,
+		},
+	}
+}
+
 const (
 	approverID1 = 1
 	approverID2 = 2
@@ -40,101 +62,113 @@ const (
 
 // getSampleApprovers returns sample approver data.
 func getSampleApprovers() []Approver {
+	// The finance team member reports to the finance department manager,
+	// who reports to the CFO like the CMO does. Invoices an approver
+	// submitted are routed to their alternate instead: the finance team
+	// member's go to the finance department manager, and the managers' go
+	// to the CFO.
+	financeManagerID := approverID2
+	cfoID := approverID3
+
 	return []Approver{
 		// finance team member.
 		{
-			ID:        approverID1,
-			CompanyID: companyID,
-			Name:      "System User",
-			Role:      "Finance Team Member",
-			Email:     "finance_team@light.com",
-			SlackID:   "U123456",
+			ID:                     approverID1,
+			CompanyID:              companyID,
+			Name:                   "System User",
+			Role:                   "Finance Team Member",
+			Email:                  "finance_team@light.com",
+			SlackID:                "U123456",
+			TeamsID:                "finance_team@light.com",
+			Locale:                 "en",
+			NotificationPreference: "immediate",
+			AlternateApproverID:    &financeManagerID,
+			ManagerID:              &financeManagerID,
 		},
 		// finance department manager.
 		{
-			ID:        approverID2,
-			CompanyID: companyID,
-			Name:      "Vera Sander",
-			Role:      "Finance Department Manager",
-			Email:     "vera_sander@light.com",
-			SlackID:   "U789012",
+			ID:                     approverID2,
+			CompanyID:              companyID,
+			Name:                   "Vera Sander",
+			Role:                   "Finance Department Manager",
+			Email:                  "vera_sander@light.com",
+			SlackID:                "U789012",
+			TeamsID:                "vera_sander@light.com",
+			Locale:                 "de",
+			NotificationPreference: "immediate",
+			AlternateApproverID:    &cfoID,
+			ManagerID:              &cfoID,
 		},
 		// Chief Financial Officer (CFO).
 		{
-			ID:        approverID3,
-			CompanyID: companyID,
-			Name:      "Amanda Svensson",
-			Role:      "CFO",
-			Email:     "amanda_svensson@light.com",
-			SlackID:   "U345678",
+			ID:                     approverID3,
+			CompanyID:              companyID,
+			Name:                   "Amanda Svensson",
+			Role:                   "CFO",
+			Email:                  "amanda_svensson@light.com",
+			SlackID:                "U345678",
+			TeamsID:                "amanda_svensson@light.com",
+			Locale:                 "sv",
+			NotificationPreference: "immediate",
 		},
 		// Chief Marketing Officer (CMO).
 		{
-			ID:        approverID4,
-			CompanyID: companyID,
-			Name:      "Sarah Johnson",
-			Role:      "CMO",
-			Email:     "sarah_johnson@light.com",
-			SlackID:   "U456789",
+			ID:                     approverID4,
+			CompanyID:              companyID,
+			Name:                   "Sarah Johnson",
+			Role:                   "CMO",
+			Email:                  "sarah_johnson@light.com",
+			SlackID:                "U456789",
+			TeamsID:                "sarah_johnson@light.com",
+			Locale:                 "en",
+			NotificationPreference: "immediate",
+			AlternateApproverID:    &cfoID,
+			ManagerID:              &cfoID,
 		},
 	}
 }
 
-// ApprovalChannel represents the channel for sending approval requests.
-type ApprovalChannel int
-
+// Names of the notification channels used by the sample workflow rules.
 const (
-	slack ApprovalChannel = iota // 0
-	email                        // 1
+	slackChannel = "slack"
+	emailChannel = "email"
 )
 
-// String returns the string representation of the approval channel.
-func (ac ApprovalChannel) String() string {
-	switch ac {
-	case slack:
-		return "slack"
-	case email:
-		return "email"
-	default:
-		return ""
-	}
-}
-
 // getSampleWorkflowRules returns sample workflow rules based on Figure 1
 func getSampleWorkflowRules() []WorkflowRule {
-	// Default values for workflow rules.
+	// Default values for workflow rules. Amounts are in cents.
 	// Rule 1: Send an approval request to any finance team member via Slack when invoice < $5k.
-	rule1MaxAmount := 5000.0
+	rule1MaxAmount := int64(500000)
 	rule1ApproverID := approverID1
-	rule1ApprovalChannel := int(slack)
+	rule1ApprovalChannel := slackChannel
 
 	// Rule 2: Send an approval request to any finance team member via Email when  $5k <= invoice < $10k,
 	// and if manager approval is not required.
-	rule2MinAmount := 5000.0
-	rule2MaxAmount := 10000.0
+	rule2MinAmount := int64(500000)
+	rule2MaxAmount := int64(1000000)
 	rule2ApproverID := approverID1
-	rule2ApprovalChannel := int(email)
+	rule2ApprovalChannel := emailChannel
 
 	// Rule 3: Send an approval request to finance department manager via Email when  $5k <= invoice < $10k,
 	// and if manager approval is required.
-	rule3MinAmount := 5000.0
-	rule3MaxAmount := 10000.0
+	rule3MinAmount := int64(500000)
+	rule3MaxAmount := int64(1000000)
 	rule3ApproverID := approverID2
-	rule3ApprovalChannel := int(email)
+	rule3ApprovalChannel := emailChannel
 	rule3IsManagerApprovalRequired := 1
 
 	// Rule 4: Send an approval request to CFO via Slack when invoice >= $10k,
 	// and if invoice is not related to marketing.
-	rule4MinAmount := 10000.0
+	rule4MinAmount := int64(1000000)
 	rule4ApproverID := approverID3
-	rule4ApprovalChannel := int(slack)
+	rule4ApprovalChannel := slackChannel
 
 	// Rule 5: Send an approval request to CMO via Email when invoice >= $10k,
 	// and if invoice is related to marketing.
-	rule5MinAmount := 10000.0
+	rule5MinAmount := int64(1000000)
 	rule5ApproverID := approverID4
 	rule5Department := marketingDepartment
-	rule5ApprovalChannel := int(email)
+	rule5ApprovalChannel := emailChannel
 
 	return []WorkflowRule{
 		// Rule 1: Send an approval request to any finance team member via Slack when invoice < $5k.
@@ -142,6 +176,9 @@ func getSampleWorkflowRules() []WorkflowRule {
 			CompanyID:                 companyID,
 			MinAmount:                 nil,
 			MaxAmount:                 &rule1MaxAmount,
+			MinInclusive:              true,  // $min <= invoice
+			MaxInclusive:              false, // invoice < $max
+			Currency:                  string(money.USD),
 			Department:                nil,
 			IsManagerApprovalRequired: nil,                  // Defaults to false
 			ApproverID:                rule1ApproverID,      // Finance team member
@@ -153,6 +190,9 @@ func getSampleWorkflowRules() []WorkflowRule {
 			CompanyID:                 companyID,
 			MinAmount:                 &rule2MinAmount,
 			MaxAmount:                 &rule2MaxAmount,
+			MinInclusive:              true,  // $min <= invoice
+			MaxInclusive:              false, // invoice < $max
+			Currency:                  string(money.USD),
 			Department:                nil,
 			IsManagerApprovalRequired: nil,                  // Defaults to false
 			ApproverID:                rule2ApproverID,      // Finance team member
@@ -164,6 +204,9 @@ func getSampleWorkflowRules() []WorkflowRule {
 			CompanyID:                 companyID,
 			MinAmount:                 &rule3MinAmount,
 			MaxAmount:                 &rule3MaxAmount,
+			MinInclusive:              true,  // $min <= invoice
+			MaxInclusive:              false, // invoice < $max
+			Currency:                  string(money.USD),
 			Department:                nil,
 			IsManagerApprovalRequired: &rule3IsManagerApprovalRequired, // true
 			ApproverID:                rule3ApproverID,                 // Finance department manager
@@ -175,6 +218,9 @@ func getSampleWorkflowRules() []WorkflowRule {
 			CompanyID:                 companyID,
 			MinAmount:                 &rule4MinAmount,
 			MaxAmount:                 nil,
+			MinInclusive:              true,  // $min <= invoice
+			MaxInclusive:              false, // invoice < $max
+			Currency:                  string(money.USD),
 			Department:                nil,
 			IsManagerApprovalRequired: nil,                  // Defaults to false
 			ApproverID:                rule4ApproverID,      // CFO
@@ -186,6 +232,9 @@ func getSampleWorkflowRules() []WorkflowRule {
 			CompanyID:                 companyID,
 			MinAmount:                 &rule5MinAmount,
 			MaxAmount:                 nil,
+			MinInclusive:              true,  // $min <= invoice
+			MaxInclusive:              false, // invoice < $max
+			Currency:                  string(money.USD),
 			Department:                &rule5Department,     // Marketing department
 			IsManagerApprovalRequired: nil,                  // Defaults to false
 			ApproverID:                rule5ApproverID,      // CMO
@@ -194,6 +243,38 @@ func getSampleWorkflowRules() []WorkflowRule {
 	}
 }
 
+// SampleAPIKey is the API key of the sample company. It has every
+// permission and is public, so it is only seeded on request, for trying
+// out the server locally.
+const SampleAPIKey = "ak_sample_light_local_testing_key"
+
+// NewSampleAPIKey returns the stored form of SampleAPIKey.
+func NewSampleAPIKey() APIKey {
+	return APIKey{
+		CompanyID: companyID,
+		Name:      "Sample key",
+		Prefix:    SampleAPIKey[:11],
+		// SHA-256 hash of SampleAPIKey.
+		Hash:        "9edd028a164f8cecdfeb9dd8dc798c8f7f58522d90b73ff918f8e0122be786ec",
+		Permissions: "submit_invoices,read,manage_rules,manage_approvers,manage_companies,manage_notifications",
+		CreatedAt:   "2026-01-01T00:00:00Z",
+	}
+}
+
+// SampleAdmin is the email of the admin of the sample company.
+const SampleAdmin = "admin@light.com"
+
+// getSampleUsers returns sample user data, one user per role.
+func getSampleUsers() []User {
+	return []User{
+		{CompanyID: companyID, Name: "Light Admin", Email: SampleAdmin, Role: "admin"},
+		{CompanyID: companyID, Name: "Vera Sander", Email: "vera_sander@light.com", Role: "rule-editor"},
+		{CompanyID: companyID, Name: "Amanda Svensson", Email: "amanda_svensson@light.com", Role: "approver"},
+		{CompanyID: companyID, Name: "Finance Team Member", Email: "finance_team@light.com", Role: "submitter"},
+		{CompanyID: companyID, Name: "Auditor", Email: "auditor@light.com", Role: "viewer"},
+	}
+}
+
 // // SeedSampleData populates the database with the provided workflow example (Fig.1).
 // func SeedSampleData(client sql.Client) error {
 // 	log.Println("Seeding database with sample data...")
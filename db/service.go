@@ -5,6 +5,7 @@ import (
 
 	"github.com/KatrinSalt/backend-challenge-go/db/sql"
 	"github.com/KatrinSalt/backend-challenge-go/db/sqlite"
+	"github.com/KatrinSalt/backend-challenge-go/money"
 )
 
 // Service interface for the database service.
@@ -12,39 +13,89 @@ type Service interface {
 	GetSampleData() *SampleData
 	Initialize() error
 	SeedSampleData() error
+	// Company Management
+	CreateCompany(company Company) (Company, error)
+	GetCompanyByID(id int) (Company, error)
 	GetCompanyByName(name string) (Company, error)
+	ListCompanies() ([]Company, error)
+	UpdateCompany(company Company) error
+	DeleteCompany(id int) error
+	// Department Management
+	CreateDepartment(department Department) (Department, error)
+	DeleteDepartment(companyID int, name string) error
+	ListDepartments(companyID int) ([]Department, error)
 	// Workflow Rule Management
 	CreateWorkflowRule(rule WorkflowRule) (WorkflowRule, error)
-	GetWorkflowRuleByID(id int) (WorkflowRule, error)
+	GetWorkflowRuleByID(companyID, id int) (WorkflowRule, error)
 	ListWorkflowRules(companyID int) ([]WorkflowRule, error)
 	UpdateWorkflowRule(rule WorkflowRule) error
-	DeleteWorkflowRule(id int) error
-	FindMatchingRule(companyID int, amount float64, department string, requiresManager bool) (WorkflowRule, error)
+	DeleteWorkflowRule(companyID, id int) error
+	FindMatchingRule(companyID int, amount money.Money, department string, requiresManager bool) (WorkflowRule, error)
 	// Approver Management
 	CreateApprover(approver Approver) (Approver, error)
-	GetApproverByID(id int) (Approver, error)
+	GetApproverByID(companyID, id int) (Approver, error)
 	ListApprovers(companyID int) ([]Approver, error)
 	UpdateApprover(approver Approver) error
-	DeleteApprover(id int) error
+	DeleteApprover(companyID, id int) error
+	// Approval Request Management
+	CreateApprovalRequest(request ApprovalRequest) (ApprovalRequest, error)
+	GetApprovalRequestByID(companyID, id int) (ApprovalRequest, error)
+	GetApprovalRequestCompanyID(id int) (int, error)
+	FindDuplicateApprovalRequest(request ApprovalRequest, since string) (ApprovalRequest, error)
+	ListApprovalRequestsByMessageID(companyID int, channel, messageID string) ([]ApprovalRequest, error)
+	SetApprovalRequestDelivery(companyID, id int, channel, conversationID, messageID string) error
+	DecideApprovalRequest(companyID, id int, status, decidedBy, decidedAt string) error
+	// Delivery Attempts
+	CreateDeliveryAttempt(attempt DeliveryAttempt) (DeliveryAttempt, error)
+	ListDeliveryAttempts(companyID, approvalRequestID int) ([]DeliveryAttempt, error)
+	// Notification Outbox
+	CreateApprovalRequestWithNotification(request ApprovalRequest, message OutboxMessage) (ApprovalRequest, OutboxMessage, error)
+	GetOutboxMessageByID(companyID, id int) (OutboxMessage, error)
+	ClaimDueOutboxMessages(companyID int, now, claimedUntil string, limit int) ([]OutboxMessage, error)
+	ListOutboxMessages(companyID int, status string) ([]OutboxMessage, error)
+	UpdateOutboxMessage(message OutboxMessage) error
+	// API Keys
+	CreateAPIKey(key APIKey) (APIKey, error)
+	GetAPIKeyByHash(hash string) (APIKey, error)
+	ListAPIKeys(companyID int) ([]APIKey, error)
+	RevokeAPIKey(companyID, id int, revokedAt string) error
+	// Users
+	CreateUser(user User) (User, error)
+	GetUserByID(companyID, id int) (User, error)
+	GetUserByEmail(companyID int, email string) (User, error)
+	DeleteUser(companyID, id int) error
+	ListUsers(companyID int) ([]User, error)
 }
 
 // Service provides a centralized interface for all database operations.
 type service struct {
-	client            sql.Client
-	schema            []string
-	sampleData        *SampleData
-	companyStore      CompanyStore
-	approverStore     ApproverStore
-	workflowRuleStore WorkflowRuleStore
+	client               sql.Client
+	schema               []string
+	sampleData           *SampleData
+	companyStore         CompanyStore
+	departmentStore      DepartmentStore
+	approverStore        ApproverStore
+	workflowRuleStore    WorkflowRuleStore
+	approvalRequestStore ApprovalRequestStore
+	deliveryAttemptStore DeliveryAttemptStore
+	outboxStore          OutboxStore
+	apiKeyStore          APIKeyStore
+	userStore            UserStore
 }
 
 // ServiceOptions contains configuration options for the database service.
 type ServiceOptions struct {
-	Schema            []string
-	SampleData        *SampleData
-	CompanyTable      string
-	ApproverTable     string
-	WorkflowRuleTable string
+	Schema               []string
+	SampleData           *SampleData
+	CompanyTable         string
+	DepartmentTable      string
+	ApproverTable        string
+	WorkflowRuleTable    string
+	ApprovalRequestTable string
+	DeliveryAttemptTable string
+	OutboxTable          string
+	APIKeyTable          string
+	UserTable            string
 }
 
 // ServiceOption is a function that sets options on the database service.
@@ -57,6 +108,13 @@ func WithCompanyTable(table string) ServiceOption {
 	}
 }
 
+// WithDepartmentTable sets the department table name.
+func WithDepartmentTable(table string) ServiceOption {
+	return func(o *ServiceOptions) {
+		o.DepartmentTable = table
+	}
+}
+
 // WithApproverTable sets the approver table name.
 func WithApproverTable(table string) ServiceOption {
 	return func(o *ServiceOptions) {
@@ -71,6 +129,41 @@ func WithWorkflowRuleTable(table string) ServiceOption {
 	}
 }
 
+// WithApprovalRequestTable sets the approval request table name.
+func WithApprovalRequestTable(table string) ServiceOption {
+	return func(o *ServiceOptions) {
+		o.ApprovalRequestTable = table
+	}
+}
+
+// WithDeliveryAttemptTable sets the delivery attempt table name.
+func WithDeliveryAttemptTable(table string) ServiceOption {
+	return func(o *ServiceOptions) {
+		o.DeliveryAttemptTable = table
+	}
+}
+
+// WithOutboxTable sets the notification outbox table name.
+func WithOutboxTable(table string) ServiceOption {
+	return func(o *ServiceOptions) {
+		o.OutboxTable = table
+	}
+}
+
+// WithAPIKeyTable sets the API key table name.
+func WithAPIKeyTable(table string) ServiceOption {
+	return func(o *ServiceOptions) {
+		o.APIKeyTable = table
+	}
+}
+
+// WithUserTable sets the user table name.
+func WithUserTable(table string) ServiceOption {
+	return func(o *ServiceOptions) {
+		o.UserTable = table
+	}
+}
+
 // WithSampleData sets the sample data.
 func WithSampleData(sampleData *SampleData) ServiceOption {
 	return func(o *ServiceOptions) {
@@ -92,9 +185,15 @@ func NewService(client sql.Client, options ...ServiceOption) (*service, error) {
 	}
 
 	opts := &ServiceOptions{
-		CompanyTable:      defaultCompanyTable,
-		ApproverTable:     defaultApproverTable,
-		WorkflowRuleTable: defaultWorkflowRuleTable,
+		CompanyTable:         defaultCompanyTable,
+		DepartmentTable:      defaultDepartmentTable,
+		ApproverTable:        defaultApproverTable,
+		WorkflowRuleTable:    defaultWorkflowRuleTable,
+		ApprovalRequestTable: defaultApprovalRequestTable,
+		DeliveryAttemptTable: defaultDeliveryAttemptTable,
+		OutboxTable:          defaultOutboxTable,
+		APIKeyTable:          defaultAPIKeyTable,
+		UserTable:            defaultUserTable,
 	}
 
 	for _, option := range options {
@@ -117,6 +216,14 @@ func NewService(client sql.Client, options ...ServiceOption) (*service, error) {
 		return nil, fmt.Errorf("failed to create company store: %w", err)
 	}
 
+	// Create department store.
+	departmentStore, err := NewDepartmentStore(client, func(o *DepartmentStoreOptions) {
+		o.Table = opts.DepartmentTable
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create department store: %w", err)
+	}
+
 	// Create approver store.
 	approverStore, err := NewApproverStore(client, func(o *ApproverStoreOptions) {
 		o.Table = opts.ApproverTable
@@ -133,13 +240,61 @@ func NewService(client sql.Client, options ...ServiceOption) (*service, error) {
 		return nil, fmt.Errorf("failed to create workflow rule store: %w", err)
 	}
 
+	// Create approval request store.
+	approvalRequestStore, err := NewApprovalRequestStore(client, func(o *ApprovalRequestStoreOptions) {
+		o.Table = opts.ApprovalRequestTable
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create approval request store: %w", err)
+	}
+
+	// Create delivery attempt store.
+	deliveryAttemptStore, err := NewDeliveryAttemptStore(client, func(o *DeliveryAttemptStoreOptions) {
+		o.Table = opts.DeliveryAttemptTable
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create delivery attempt store: %w", err)
+	}
+
+	// Create outbox store. It creates approval requests together with their
+	// notifications, so it writes to the approval request table as well.
+	outboxStore, err := NewOutboxStore(client, func(o *OutboxStoreOptions) {
+		o.Table = opts.OutboxTable
+		o.ApprovalRequestTable = opts.ApprovalRequestTable
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create outbox store: %w", err)
+	}
+
+	// Create API key store.
+	apiKeyStore, err := NewAPIKeyStore(client, func(o *APIKeyStoreOptions) {
+		o.Table = opts.APIKeyTable
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key store: %w", err)
+	}
+
+	// Create user store.
+	userStore, err := NewUserStore(client, func(o *UserStoreOptions) {
+		o.Table = opts.UserTable
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user store: %w", err)
+	}
+
 	return &service{
-		client:            client,
-		schema:            opts.Schema,
-		sampleData:        opts.SampleData,
-		companyStore:      companyStore,
-		approverStore:     approverStore,
-		workflowRuleStore: workflowRuleStore,
+		client:               client,
+		schema:               opts.Schema,
+		sampleData:           opts.SampleData,
+		companyStore:         companyStore,
+		departmentStore:      departmentStore,
+		approverStore:        approverStore,
+		workflowRuleStore:    workflowRuleStore,
+		approvalRequestStore: approvalRequestStore,
+		deliveryAttemptStore: deliveryAttemptStore,
+		outboxStore:          outboxStore,
+		apiKeyStore:          apiKeyStore,
+		userStore:            userStore,
 	}, nil
 }
 
@@ -174,6 +329,14 @@ func (s *service) SeedSampleData() error {
 		}
 	}
 
+	// Add departments.
+	for _, department := range s.sampleData.Departments {
+		_, err := s.departmentStore.Create(department)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Add approvers.
 	for _, approver := range s.sampleData.Approvers {
 		_, err := s.approverStore.Create(approver)
@@ -190,26 +353,103 @@ func (s *service) SeedSampleData() error {
 		}
 	}
 
+	// Add API keys.
+	for _, key := range s.sampleData.APIKeys {
+		_, err := s.apiKeyStore.Create(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Add users.
+	for _, user := range s.sampleData.Users {
+		_, err := s.userStore.Create(user)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// CreateCompany creates a new company.
+func (s *service) CreateCompany(company Company) (Company, error) {
+	return s.companyStore.Create(company)
+}
+
+// GetCompanyByID retrieves a company by its ID.
+func (s *service) GetCompanyByID(id int) (Company, error) {
+	return s.companyStore.GetByID(id)
+}
+
 // GetCompanyByName retrieves a company by its name.
 func (s *service) GetCompanyByName(name string) (Company, error) {
 	return s.companyStore.GetByName(name)
 }
 
+// ListCompanies retrieves all companies.
+func (s *service) ListCompanies() ([]Company, error) {
+	return s.companyStore.List()
+}
+
+// UpdateCompany updates an existing company.
+func (s *service) UpdateCompany(company Company) error {
+	return s.companyStore.Update(company)
+}
+
+// DeleteCompany deletes a company and its departments. A company that still
+// has approvers or workflow rules cannot be deleted.
+func (s *service) DeleteCompany(id int) error {
+	if _, err := s.companyStore.GetByID(id); err != nil {
+		return err
+	}
+
+	approvers, err := s.approverStore.List(id)
+	if err != nil {
+		return err
+	}
+	rules, err := s.workflowRuleStore.List(id)
+	if err != nil {
+		return err
+	}
+	if len(approvers) > 0 || len(rules) > 0 {
+		return ErrCompanyNotEmpty
+	}
+
+	if err := s.departmentStore.DeleteByCompany(id); err != nil {
+		return err
+	}
+
+	return s.companyStore.Delete(id)
+}
+
+// CreateDepartment creates a new department for a company.
+func (s *service) CreateDepartment(department Department) (Department, error) {
+	return s.departmentStore.Create(department)
+}
+
+// DeleteDepartment deletes a company's department by its name.
+func (s *service) DeleteDepartment(companyID int, name string) error {
+	return s.departmentStore.Delete(companyID, name)
+}
+
+// ListDepartments retrieves all departments for a specific company.
+func (s *service) ListDepartments(companyID int) ([]Department, error) {
+	return s.departmentStore.List(companyID)
+}
+
 // CreateApprover creates a new approver.
 func (s *service) CreateApprover(approver Approver) (Approver, error) {
 	return s.approverStore.Create(approver)
 }
 
-// GetApproverByID retrieves an approver by their ID.
-func (s *service) GetApproverByID(id int) (Approver, error) {
-	return s.approverStore.GetByID(id)
+// GetApproverByID retrieves a company's approver by their ID.
+func (s *service) GetApproverByID(companyID, id int) (Approver, error) {
+	return s.approverStore.GetByID(companyID, id)
 }
 
 // FindMatchingRule finds a workflow rule that matches the given criteria.
-func (s *service) FindMatchingRule(companyID int, amount float64, department string, requiresManager bool) (WorkflowRule, error) {
+func (s *service) FindMatchingRule(companyID int, amount money.Money, department string, requiresManager bool) (WorkflowRule, error) {
 	return s.workflowRuleStore.FindMatchingRule(companyID, amount, department, requiresManager)
 }
 
@@ -218,9 +458,9 @@ func (s *service) CreateWorkflowRule(rule WorkflowRule) (WorkflowRule, error) {
 	return s.workflowRuleStore.Create(rule)
 }
 
-// GetWorkflowRuleByID retrieves a workflow rule by its ID.
-func (s *service) GetWorkflowRuleByID(id int) (WorkflowRule, error) {
-	return s.workflowRuleStore.GetByID(id)
+// GetWorkflowRuleByID retrieves a company's workflow rule by its ID.
+func (s *service) GetWorkflowRuleByID(companyID, id int) (WorkflowRule, error) {
+	return s.workflowRuleStore.GetByID(companyID, id)
 }
 
 // UpdateWorkflowRule updates an existing workflow rule.
@@ -228,9 +468,9 @@ func (s *service) UpdateWorkflowRule(rule WorkflowRule) error {
 	return s.workflowRuleStore.Update(rule)
 }
 
-// DeleteWorkflowRule deletes a workflow rule by its ID.
-func (s *service) DeleteWorkflowRule(id int) error {
-	return s.workflowRuleStore.Delete(id)
+// DeleteWorkflowRule deletes a company's workflow rule by its ID.
+func (s *service) DeleteWorkflowRule(companyID, id int) error {
+	return s.workflowRuleStore.Delete(companyID, id)
 }
 
 // UpdateApprover updates an existing approver.
@@ -238,9 +478,9 @@ func (s *service) UpdateApprover(approver Approver) error {
 	return s.approverStore.Update(approver)
 }
 
-// DeleteApprover deletes an approver by their ID.
-func (s *service) DeleteApprover(id int) error {
-	return s.approverStore.Delete(id)
+// DeleteApprover deletes a company's approver by their ID.
+func (s *service) DeleteApprover(companyID, id int) error {
+	return s.approverStore.Delete(companyID, id)
 }
 
 // ListWorkflowRules retrieves all workflow rules for a specific company.
@@ -252,3 +492,128 @@ func (s *service) ListWorkflowRules(companyID int) ([]WorkflowRule, error) {
 func (s *service) ListApprovers(companyID int) ([]Approver, error) {
 	return s.approverStore.List(companyID)
 }
+
+// CreateApprovalRequest creates a new pending approval request.
+func (s *service) CreateApprovalRequest(request ApprovalRequest) (ApprovalRequest, error) {
+	return s.approvalRequestStore.Create(request)
+}
+
+// GetApprovalRequestByID retrieves a company's approval request by its ID.
+func (s *service) GetApprovalRequestByID(companyID, id int) (ApprovalRequest, error) {
+	return s.approvalRequestStore.GetByID(companyID, id)
+}
+
+// GetApprovalRequestCompanyID returns the ID of the company that owns an
+// approval request, for decisions that are not made on behalf of a
+// company, such as Slack interactions.
+func (s *service) GetApprovalRequestCompanyID(id int) (int, error) {
+	return s.approvalRequestStore.GetCompanyID(id)
+}
+
+// FindDuplicateApprovalRequest returns the latest approval request for the
+// same invoice and approver as request that was created at or after since.
+func (s *service) FindDuplicateApprovalRequest(request ApprovalRequest, since string) (ApprovalRequest, error) {
+	return s.approvalRequestStore.FindDuplicate(request, since)
+}
+
+// ListApprovalRequestsByMessageID returns the company's approval requests
+// delivered over the channel in the message with the ID.
+func (s *service) ListApprovalRequestsByMessageID(companyID int, channel, messageID string) ([]ApprovalRequest, error) {
+	return s.approvalRequestStore.ListByMessageID(companyID, channel, messageID)
+}
+
+// SetApprovalRequestDelivery records the channel an approval request was
+// delivered over and where it was delivered.
+func (s *service) SetApprovalRequestDelivery(companyID, id int, channel, conversationID, messageID string) error {
+	return s.approvalRequestStore.SetDelivery(companyID, id, channel, conversationID, messageID)
+}
+
+// DecideApprovalRequest records the decision on a pending approval request.
+func (s *service) DecideApprovalRequest(companyID, id int, status, decidedBy, decidedAt string) error {
+	return s.approvalRequestStore.Decide(companyID, id, status, decidedBy, decidedAt)
+}
+
+// CreateDeliveryAttempt records an attempt to deliver an approval request.
+func (s *service) CreateDeliveryAttempt(attempt DeliveryAttempt) (DeliveryAttempt, error) {
+	return s.deliveryAttemptStore.Create(attempt)
+}
+
+// ListDeliveryAttempts retrieves the delivery attempts of a company's
+// approval request.
+func (s *service) ListDeliveryAttempts(companyID, approvalRequestID int) ([]DeliveryAttempt, error) {
+	return s.deliveryAttemptStore.ListByApprovalRequest(companyID, approvalRequestID)
+}
+
+// CreateApprovalRequestWithNotification creates a pending approval request
+// and the outbox message that notifies its approver in one transaction.
+func (s *service) CreateApprovalRequestWithNotification(request ApprovalRequest, message OutboxMessage) (ApprovalRequest, OutboxMessage, error) {
+	return s.outboxStore.CreateWithApprovalRequest(request, message)
+}
+
+// GetOutboxMessageByID retrieves a company's outbox message by its ID.
+func (s *service) GetOutboxMessageByID(companyID, id int) (OutboxMessage, error) {
+	return s.outboxStore.GetByID(companyID, id)
+}
+
+// ClaimDueOutboxMessages claims up to limit of a company's pending outbox
+// messages that are due at now and not claimed, until claimedUntil.
+func (s *service) ClaimDueOutboxMessages(companyID int, now, claimedUntil string, limit int) ([]OutboxMessage, error) {
+	return s.outboxStore.ClaimDue(companyID, now, claimedUntil, limit)
+}
+
+// ListOutboxMessages retrieves a company's outbox messages with the given
+// status.
+func (s *service) ListOutboxMessages(companyID int, status string) ([]OutboxMessage, error) {
+	return s.outboxStore.ListByStatus(companyID, status)
+}
+
+// UpdateOutboxMessage updates the delivery state of a company's outbox
+// message.
+func (s *service) UpdateOutboxMessage(message OutboxMessage) error {
+	return s.outboxStore.Update(message)
+}
+
+// CreateAPIKey stores a company's API key.
+func (s *service) CreateAPIKey(key APIKey) (APIKey, error) {
+	return s.apiKeyStore.Create(key)
+}
+
+// GetAPIKeyByHash retrieves the API key with the hash.
+func (s *service) GetAPIKeyByHash(hash string) (APIKey, error) {
+	return s.apiKeyStore.GetByHash(hash)
+}
+
+// ListAPIKeys retrieves all API keys of a company.
+func (s *service) ListAPIKeys(companyID int) ([]APIKey, error) {
+	return s.apiKeyStore.List(companyID)
+}
+
+// RevokeAPIKey revokes a company's API key.
+func (s *service) RevokeAPIKey(companyID, id int, revokedAt string) error {
+	return s.apiKeyStore.Revoke(companyID, id, revokedAt)
+}
+
+// CreateUser creates a company's user.
+func (s *service) CreateUser(user User) (User, error) {
+	return s.userStore.Create(user)
+}
+
+// GetUserByID retrieves a company's user by their ID.
+func (s *service) GetUserByID(companyID, id int) (User, error) {
+	return s.userStore.GetByID(companyID, id)
+}
+
+// GetUserByEmail retrieves a company's user by their email.
+func (s *service) GetUserByEmail(companyID int, email string) (User, error) {
+	return s.userStore.GetByEmail(companyID, email)
+}
+
+// DeleteUser deletes a company's user.
+func (s *service) DeleteUser(companyID, id int) error {
+	return s.userStore.Delete(companyID, id)
+}
+
+// ListUsers retrieves all users of a company.
+func (s *service) ListUsers(companyID int) ([]User, error) {
+	return s.userStore.List(companyID)
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/KatrinSalt/backend-challenge-go/db/sql"
+	"github.com/KatrinSalt/backend-challenge-go/money"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -240,8 +241,11 @@ func TestService_SeedSampleData(t *testing.T) {
 			name: "successful seeding",
 			input: &service{
 				companyStore:      &mockCompanyStore{},
+				departmentStore:   &mockDepartmentStore{},
 				approverStore:     &mockApproverStore{},
 				workflowRuleStore: &mockWorkflowRuleStore{},
+				apiKeyStore:       &mockAPIKeyStore{},
+				userStore:         &mockUserStore{},
 				sampleData:        NewSampleData(),
 			},
 			wantErr: false,
@@ -250,8 +254,11 @@ func TestService_SeedSampleData(t *testing.T) {
 			name: "nil sample data",
 			input: &service{
 				companyStore:      &mockCompanyStore{},
+				departmentStore:   &mockDepartmentStore{},
 				approverStore:     &mockApproverStore{},
 				workflowRuleStore: &mockWorkflowRuleStore{},
+				apiKeyStore:       &mockAPIKeyStore{},
+				userStore:         &mockUserStore{},
 				sampleData:        nil,
 			},
 			wantErr: true,
@@ -263,21 +270,43 @@ func TestService_SeedSampleData(t *testing.T) {
 				companyStore: &mockCompanyStore{
 					createErr: errors.New("company creation failed"),
 				},
+				departmentStore:   &mockDepartmentStore{},
 				approverStore:     &mockApproverStore{},
 				workflowRuleStore: &mockWorkflowRuleStore{},
+				apiKeyStore:       &mockAPIKeyStore{},
+				userStore:         &mockUserStore{},
 				sampleData:        NewSampleData(),
 			},
 			wantErr: true,
 			errMsg:  "company creation failed",
 		},
 		{
-			name: "approver store creation error",
+			name: "department store creation error",
 			input: &service{
 				companyStore: &mockCompanyStore{},
+				departmentStore: &mockDepartmentStore{
+					createErr: errors.New("department creation failed"),
+				},
+				approverStore:     &mockApproverStore{},
+				workflowRuleStore: &mockWorkflowRuleStore{},
+				apiKeyStore:       &mockAPIKeyStore{},
+				userStore:         &mockUserStore{},
+				sampleData:        NewSampleData(),
+			},
+			wantErr: true,
+			errMsg:  "department creation failed",
+		},
+		{
+			name: "approver store creation error",
+			input: &service{
+				companyStore:    &mockCompanyStore{},
+				departmentStore: &mockDepartmentStore{},
 				approverStore: &mockApproverStore{
 					createErr: errors.New("approver creation failed"),
 				},
 				workflowRuleStore: &mockWorkflowRuleStore{},
+				apiKeyStore:       &mockAPIKeyStore{},
+				userStore:         &mockUserStore{},
 				sampleData:        NewSampleData(),
 			},
 			wantErr: true,
@@ -286,16 +315,51 @@ func TestService_SeedSampleData(t *testing.T) {
 		{
 			name: "workflow rule store creation error",
 			input: &service{
-				companyStore:  &mockCompanyStore{},
-				approverStore: &mockApproverStore{},
+				companyStore:    &mockCompanyStore{},
+				departmentStore: &mockDepartmentStore{},
+				approverStore:   &mockApproverStore{},
 				workflowRuleStore: &mockWorkflowRuleStore{
 					createErr: errors.New("workflow rule creation failed"),
 				},
-				sampleData: NewSampleData(),
+				apiKeyStore: &mockAPIKeyStore{},
+				userStore:   &mockUserStore{},
+				sampleData:  NewSampleData(),
 			},
 			wantErr: true,
 			errMsg:  "workflow rule creation failed",
 		},
+		{
+			name: "api key store creation error",
+			input: &service{
+				companyStore:      &mockCompanyStore{},
+				departmentStore:   &mockDepartmentStore{},
+				approverStore:     &mockApproverStore{},
+				workflowRuleStore: &mockWorkflowRuleStore{},
+				apiKeyStore: &mockAPIKeyStore{
+					createErr: errors.New("api key creation failed"),
+				},
+				userStore:  &mockUserStore{},
+				sampleData: &SampleData{APIKeys: []APIKey{NewSampleAPIKey()}},
+			},
+			wantErr: true,
+			errMsg:  "api key creation failed",
+		},
+		{
+			name: "user store creation error",
+			input: &service{
+				companyStore:      &mockCompanyStore{},
+				departmentStore:   &mockDepartmentStore{},
+				approverStore:     &mockApproverStore{},
+				workflowRuleStore: &mockWorkflowRuleStore{},
+				apiKeyStore:       &mockAPIKeyStore{},
+				userStore: &mockUserStore{
+					createErr: errors.New("user creation failed"),
+				},
+				sampleData: NewSampleData(),
+			},
+			wantErr: true,
+			errMsg:  "user creation failed",
+		},
 	}
 
 	for _, test := range tests {
@@ -510,7 +574,7 @@ func TestService_GetApproverByID(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got, gotErr := test.input.service.GetApproverByID(test.input.approverID)
+			got, gotErr := test.input.service.GetApproverByID(1, test.input.approverID)
 
 			if test.wantErr {
 				if gotErr == nil {
@@ -541,7 +605,7 @@ func TestService_FindMatchingRule(t *testing.T) {
 		input struct {
 			service         *service
 			companyID       int
-			amount          float64
+			amount          money.Money
 			department      string
 			requiresManager bool
 		}
@@ -554,7 +618,7 @@ func TestService_FindMatchingRule(t *testing.T) {
 			input: struct {
 				service         *service
 				companyID       int
-				amount          float64
+				amount          money.Money
 				department      string
 				requiresManager bool
 			}{
@@ -563,29 +627,29 @@ func TestService_FindMatchingRule(t *testing.T) {
 						rule: WorkflowRule{
 							ID:                        1,
 							CompanyID:                 1,
-							MinAmount:                 floatPtr(1000.0),
-							MaxAmount:                 floatPtr(5000.0),
+							MinAmount:                 int64Ptr(100000),
+							MaxAmount:                 int64Ptr(500000),
 							Department:                nil,
 							IsManagerApprovalRequired: intPtr(0),
 							ApproverID:                1,
-							ApprovalChannel:           0,
+							ApprovalChannel:           "slack",
 						},
 					},
 				},
 				companyID:       1,
-				amount:          2500.0,
+				amount:          money.New(250000, money.USD),
 				department:      "Finance",
 				requiresManager: false,
 			},
 			want: WorkflowRule{
 				ID:                        1,
 				CompanyID:                 1,
-				MinAmount:                 floatPtr(1000.0),
-				MaxAmount:                 floatPtr(5000.0),
+				MinAmount:                 int64Ptr(100000),
+				MaxAmount:                 int64Ptr(500000),
 				Department:                nil,
 				IsManagerApprovalRequired: intPtr(0),
 				ApproverID:                1,
-				ApprovalChannel:           0,
+				ApprovalChannel:           "slack",
 			},
 			wantErr: false,
 		},
@@ -594,7 +658,7 @@ func TestService_FindMatchingRule(t *testing.T) {
 			input: struct {
 				service         *service
 				companyID       int
-				amount          float64
+				amount          money.Money
 				department      string
 				requiresManager bool
 			}{
@@ -604,7 +668,7 @@ func TestService_FindMatchingRule(t *testing.T) {
 					},
 				},
 				companyID:       1,
-				amount:          10000.0,
+				amount:          money.New(1000000, money.USD),
 				department:      "Unknown",
 				requiresManager: false,
 			},
@@ -647,8 +711,8 @@ func TestService_FindMatchingRule(t *testing.T) {
 }
 
 // Helper functions for creating pointers
-func floatPtr(f float64) *float64 {
-	return &f
+func int64Ptr(i int64) *int64 {
+	return &i
 }
 
 func intPtr(i int) *int {
@@ -692,7 +756,9 @@ func (m *mockClient) Exec(query string, args ...any) (sql.Result, error) {
 
 type mockCompanyStore struct {
 	createErr    error
+	getByIDErr   error
 	getByNameErr error
+	deleteErr    error
 	company      Company
 }
 
@@ -704,7 +770,10 @@ func (m *mockCompanyStore) Create(company Company) (Company, error) {
 }
 
 func (m *mockCompanyStore) GetByID(id int) (Company, error) {
-	return Company{}, nil
+	if m.getByIDErr != nil {
+		return Company{}, m.getByIDErr
+	}
+	return m.company, nil
 }
 
 func (m *mockCompanyStore) GetByName(name string) (Company, error) {
@@ -714,10 +783,48 @@ func (m *mockCompanyStore) GetByName(name string) (Company, error) {
 	return m.company, nil
 }
 
+func (m *mockCompanyStore) Update(company Company) error {
+	return nil
+}
+
+func (m *mockCompanyStore) Delete(id int) error {
+	return m.deleteErr
+}
+
+func (m *mockCompanyStore) List() ([]Company, error) {
+	return []Company{m.company}, nil
+}
+
+type mockDepartmentStore struct {
+	createErr          error
+	deleteByCompanyErr error
+	department         Department
+}
+
+func (m *mockDepartmentStore) Create(department Department) (Department, error) {
+	if m.createErr != nil {
+		return Department{}, m.createErr
+	}
+	return m.department, nil
+}
+
+func (m *mockDepartmentStore) Delete(companyID int, name string) error {
+	return nil
+}
+
+func (m *mockDepartmentStore) DeleteByCompany(companyID int) error {
+	return m.deleteByCompanyErr
+}
+
+func (m *mockDepartmentStore) List(companyID int) ([]Department, error) {
+	return []Department{m.department}, nil
+}
+
 type mockApproverStore struct {
 	createErr  error
 	getByIDErr error
 	approver   Approver
+	approvers  []Approver
 }
 
 func (m *mockApproverStore) Create(approver Approver) (Approver, error) {
@@ -727,7 +834,7 @@ func (m *mockApproverStore) Create(approver Approver) (Approver, error) {
 	return m.approver, nil
 }
 
-func (m *mockApproverStore) GetByID(id int) (Approver, error) {
+func (m *mockApproverStore) GetByID(companyID, id int) (Approver, error) {
 	if m.getByIDErr != nil {
 		return Approver{}, m.getByIDErr
 	}
@@ -738,11 +845,14 @@ func (m *mockApproverStore) Update(approver Approver) error {
 	return nil
 }
 
-func (m *mockApproverStore) Delete(id int) error {
+func (m *mockApproverStore) Delete(companyID, id int) error {
 	return nil
 }
 
 func (m *mockApproverStore) List(companyID int) ([]Approver, error) {
+	if m.approvers != nil {
+		return m.approvers, nil
+	}
 	return []Approver{m.approver}, nil
 }
 
@@ -750,6 +860,7 @@ type mockWorkflowRuleStore struct {
 	createErr           error
 	findMatchingRuleErr error
 	rule                WorkflowRule
+	rules               []WorkflowRule
 }
 
 func (m *mockWorkflowRuleStore) Create(rule WorkflowRule) (WorkflowRule, error) {
@@ -760,17 +871,20 @@ func (m *mockWorkflowRuleStore) Create(rule WorkflowRule) (WorkflowRule, error)
 }
 
 func (m *mockWorkflowRuleStore) List(companyID int) ([]WorkflowRule, error) {
+	if m.rules != nil {
+		return m.rules, nil
+	}
 	return []WorkflowRule{m.rule}, nil
 }
 
-func (m *mockWorkflowRuleStore) FindMatchingRule(companyID int, amount float64, department string, requiresManager bool) (WorkflowRule, error) {
+func (m *mockWorkflowRuleStore) FindMatchingRule(companyID int, amount money.Money, department string, requiresManager bool) (WorkflowRule, error) {
 	if m.findMatchingRuleErr != nil {
 		return WorkflowRule{}, m.findMatchingRuleErr
 	}
 	return m.rule, nil
 }
 
-func (m *mockWorkflowRuleStore) GetByID(id int) (WorkflowRule, error) {
+func (m *mockWorkflowRuleStore) GetByID(companyID, id int) (WorkflowRule, error) {
 	return m.rule, nil
 }
 
@@ -778,10 +892,62 @@ func (m *mockWorkflowRuleStore) Update(rule WorkflowRule) error {
 	return nil
 }
 
-func (m *mockWorkflowRuleStore) Delete(id int) error {
+func (m *mockWorkflowRuleStore) Delete(companyID, id int) error {
+	return nil
+}
+
+type mockAPIKeyStore struct {
+	createErr error
+	key       APIKey
+}
+
+func (m *mockAPIKeyStore) Create(key APIKey) (APIKey, error) {
+	if m.createErr != nil {
+		return APIKey{}, m.createErr
+	}
+	return m.key, nil
+}
+
+func (m *mockAPIKeyStore) GetByHash(hash string) (APIKey, error) {
+	return m.key, nil
+}
+
+func (m *mockAPIKeyStore) List(companyID int) ([]APIKey, error) {
+	return []APIKey{m.key}, nil
+}
+
+func (m *mockAPIKeyStore) Revoke(companyID, id int, revokedAt string) error {
+	return nil
+}
+
+type mockUserStore struct {
+	createErr error
+	user      User
+}
+
+func (m *mockUserStore) Create(user User) (User, error) {
+	if m.createErr != nil {
+		return User{}, m.createErr
+	}
+	return m.user, nil
+}
+
+func (m *mockUserStore) GetByID(companyID, id int) (User, error) {
+	return m.user, nil
+}
+
+func (m *mockUserStore) GetByEmail(companyID int, email string) (User, error) {
+	return m.user, nil
+}
+
+func (m *mockUserStore) Delete(companyID, id int) error {
 	return nil
 }
 
+func (m *mockUserStore) List(companyID int) ([]User, error) {
+	return []User{m.user}, nil
+}
+
 func TestService_GetWorkflowRuleByID(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -804,12 +970,12 @@ func TestService_GetWorkflowRuleByID(t *testing.T) {
 						rule: WorkflowRule{
 							ID:                        1,
 							CompanyID:                 1,
-							MinAmount:                 floatPtr(100.0),
-							MaxAmount:                 floatPtr(500.0),
+							MinAmount:                 int64Ptr(10000),
+							MaxAmount:                 int64Ptr(50000),
 							Department:                stringPtr("Finance"),
 							IsManagerApprovalRequired: intPtr(1),
 							ApproverID:                1,
-							ApprovalChannel:           0,
+							ApprovalChannel:           "slack",
 						},
 					},
 				},
@@ -818,12 +984,12 @@ func TestService_GetWorkflowRuleByID(t *testing.T) {
 			want: WorkflowRule{
 				ID:                        1,
 				CompanyID:                 1,
-				MinAmount:                 floatPtr(100.0),
-				MaxAmount:                 floatPtr(500.0),
+				MinAmount:                 int64Ptr(10000),
+				MaxAmount:                 int64Ptr(50000),
 				Department:                stringPtr("Finance"),
 				IsManagerApprovalRequired: intPtr(1),
 				ApproverID:                1,
-				ApprovalChannel:           0,
+				ApprovalChannel:           "slack",
 			},
 			wantErr: false,
 		},
@@ -831,7 +997,7 @@ func TestService_GetWorkflowRuleByID(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got, gotErr := test.input.service.GetWorkflowRuleByID(test.input.id)
+			got, gotErr := test.input.service.GetWorkflowRuleByID(1, test.input.id)
 
 			if test.wantErr {
 				if gotErr == nil {
@@ -878,12 +1044,12 @@ func TestService_UpdateWorkflowRule(t *testing.T) {
 				rule: WorkflowRule{
 					ID:                        1,
 					CompanyID:                 1,
-					MinAmount:                 floatPtr(200.0),
-					MaxAmount:                 floatPtr(1000.0),
+					MinAmount:                 int64Ptr(20000),
+					MaxAmount:                 int64Ptr(100000),
 					Department:                stringPtr("IT"),
 					IsManagerApprovalRequired: intPtr(0),
 					ApproverID:                2,
-					ApprovalChannel:           1,
+					ApprovalChannel:           "email",
 				},
 			},
 			wantErr: false,
@@ -939,7 +1105,7 @@ func TestService_DeleteWorkflowRule(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			gotErr := test.input.service.DeleteWorkflowRule(test.input.id)
+			gotErr := test.input.service.DeleteWorkflowRule(1, test.input.id)
 
 			if test.wantErr {
 				if gotErr == nil {
@@ -1040,7 +1206,7 @@ func TestService_DeleteApprover(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			gotErr := test.input.service.DeleteApprover(test.input.id)
+			gotErr := test.input.service.DeleteApprover(1, test.input.id)
 
 			if test.wantErr {
 				if gotErr == nil {
@@ -1060,6 +1226,98 @@ func TestService_DeleteApprover(t *testing.T) {
 	}
 }
 
+func TestService_DeleteCompany(t *testing.T) {
+	tests := []struct {
+		name  string
+		input struct {
+			service *service
+			id      int
+		}
+		wantErr error
+	}{
+		{
+			name: "successful company deletion",
+			input: struct {
+				service *service
+				id      int
+			}{
+				service: &service{
+					companyStore:      &mockCompanyStore{company: Company{ID: 2, Name: "Acme"}},
+					departmentStore:   &mockDepartmentStore{},
+					approverStore:     &mockApproverStore{approvers: []Approver{}},
+					workflowRuleStore: &mockWorkflowRuleStore{rules: []WorkflowRule{}},
+				},
+				id: 2,
+			},
+		},
+		{
+			name: "company not found",
+			input: struct {
+				service *service
+				id      int
+			}{
+				service: &service{
+					companyStore:      &mockCompanyStore{getByIDErr: ErrCompanyNotFound},
+					departmentStore:   &mockDepartmentStore{},
+					approverStore:     &mockApproverStore{approvers: []Approver{}},
+					workflowRuleStore: &mockWorkflowRuleStore{rules: []WorkflowRule{}},
+				},
+				id: 99,
+			},
+			wantErr: ErrCompanyNotFound,
+		},
+		{
+			name: "company with approvers",
+			input: struct {
+				service *service
+				id      int
+			}{
+				service: &service{
+					companyStore:      &mockCompanyStore{company: Company{ID: 1, Name: "Light"}},
+					departmentStore:   &mockDepartmentStore{},
+					approverStore:     &mockApproverStore{approvers: []Approver{{ID: 1, CompanyID: 1}}},
+					workflowRuleStore: &mockWorkflowRuleStore{rules: []WorkflowRule{}},
+				},
+				id: 1,
+			},
+			wantErr: ErrCompanyNotEmpty,
+		},
+		{
+			name: "company with workflow rules",
+			input: struct {
+				service *service
+				id      int
+			}{
+				service: &service{
+					companyStore:      &mockCompanyStore{company: Company{ID: 1, Name: "Light"}},
+					departmentStore:   &mockDepartmentStore{},
+					approverStore:     &mockApproverStore{approvers: []Approver{}},
+					workflowRuleStore: &mockWorkflowRuleStore{rules: []WorkflowRule{{ID: 1, CompanyID: 1}}},
+				},
+				id: 1,
+			},
+			wantErr: ErrCompanyNotEmpty,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotErr := test.input.service.DeleteCompany(test.input.id)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("DeleteCompany() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("DeleteCompany() unexpected error: %v", gotErr)
+			}
+		})
+	}
+}
+
 func TestService_ListWorkflowRules(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -1082,12 +1340,12 @@ func TestService_ListWorkflowRules(t *testing.T) {
 						rule: WorkflowRule{
 							ID:                        1,
 							CompanyID:                 1,
-							MinAmount:                 floatPtr(100.0),
-							MaxAmount:                 floatPtr(500.0),
+							MinAmount:                 int64Ptr(10000),
+							MaxAmount:                 int64Ptr(50000),
 							Department:                stringPtr("Finance"),
 							IsManagerApprovalRequired: intPtr(1),
 							ApproverID:                1,
-							ApprovalChannel:           0,
+							ApprovalChannel:           "slack",
 						},
 					},
 				},
@@ -1097,12 +1355,12 @@ func TestService_ListWorkflowRules(t *testing.T) {
 				{
 					ID:                        1,
 					CompanyID:                 1,
-					MinAmount:                 floatPtr(100.0),
-					MaxAmount:                 floatPtr(500.0),
+					MinAmount:                 int64Ptr(10000),
+					MaxAmount:                 int64Ptr(50000),
 					Department:                stringPtr("Finance"),
 					IsManagerApprovalRequired: intPtr(1),
 					ApproverID:                1,
-					ApprovalChannel:           0,
+					ApprovalChannel:           "slack",
 				},
 			},
 			wantErr: false,
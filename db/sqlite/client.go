@@ -31,6 +31,14 @@ type ClientOptions struct {
 // ClientOption is a function that sets options for the client.
 type ClientOption func(o *ClientOptions)
 
+// WithDataSource sets the data source of the database, e.g. the path of a
+// database file. The default is an in-memory database.
+func WithDataSource(dataSource string) ClientOption {
+	return func(o *ClientOptions) {
+		o.DataSource = dataSource
+	}
+}
+
 // NewCLient creates a new sqlite database client.
 func NewClient(options ...ClientOption) (*client, error) {
 	opts := ClientOptions{
@@ -45,6 +53,13 @@ func NewClient(options ...ClientOption) (*client, error) {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
+	// Every connection to an in-memory database opens a database of its
+	// own, so the pool is limited to one connection to share it between
+	// goroutines, such as the outbox dispatcher.
+	if opts.DataSource == defaultDataSource {
+		db.SetMaxOpenConns(1)
+	}
+
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
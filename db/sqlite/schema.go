@@ -6,7 +6,16 @@ func NewDBSchema() []string {
 		// companies table.
 		`CREATE TABLE IF NOT EXISTS companies (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			name TEXT NOT NULL UNIQUE
+			name TEXT NOT NULL UNIQUE,
+			fallback_channels TEXT NOT NULL DEFAULT ''
+		)`,
+		// departments table.
+		`CREATE TABLE IF NOT EXISTS departments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			company_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			FOREIGN KEY (company_id) REFERENCES companies (id),
+			UNIQUE(company_id, name)
 		)`,
 		// approvers table.
 		`CREATE TABLE IF NOT EXISTS approvers (
@@ -14,9 +23,16 @@ func NewDBSchema() []string {
 			company_id INTEGER NOT NULL,
 			name TEXT NOT NULL,
 			role TEXT NOT NULL,
-			email TEXT NOT NULL,
-			slack_id TEXT NOT NULL,
+			email TEXT,
+			slack_id TEXT,
+			teams_id TEXT NOT NULL DEFAULT '',
+			locale TEXT NOT NULL DEFAULT 'en',
+			notification_preference TEXT NOT NULL DEFAULT 'immediate' CHECK (notification_preference IN ('immediate', 'hourly_digest', 'daily_digest')),
+			alternate_approver_id INTEGER,
+			manager_id INTEGER,
 			FOREIGN KEY (company_id) REFERENCES companies (id),
+			FOREIGN KEY (alternate_approver_id) REFERENCES approvers (id),
+			FOREIGN KEY (manager_id) REFERENCES approvers (id),
 			UNIQUE(company_id, email),
 			UNIQUE(company_id, slack_id)
 		)`,
@@ -24,14 +40,102 @@ func NewDBSchema() []string {
 		`CREATE TABLE IF NOT EXISTS workflow_rules (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			company_id INTEGER NOT NULL,
-			min_amount REAL,
-			max_amount REAL,
+			min_amount INTEGER,
+			max_amount INTEGER,
+			min_inclusive INTEGER NOT NULL DEFAULT 1 CHECK (min_inclusive IN (0, 1)),
+			max_inclusive INTEGER NOT NULL DEFAULT 0 CHECK (max_inclusive IN (0, 1)),
+			currency TEXT NOT NULL DEFAULT 'USD',
 			department TEXT,
 			is_manager_approval_required INTEGER DEFAULT 0 CHECK (is_manager_approval_required IN (0, 1)),
 			approver_id INTEGER NOT NULL,
-			approval_channel INTEGER NOT NULL,
+			approval_channel TEXT NOT NULL,
+			fallback_channels TEXT NOT NULL DEFAULT '',
+			manager_levels INTEGER NOT NULL DEFAULT 0 CHECK (manager_levels >= 0),
+			FOREIGN KEY (company_id) REFERENCES companies (id),
+			FOREIGN KEY (approver_id) REFERENCES approvers (id)
+		)`,
+		// approval requests table.
+		`CREATE TABLE IF NOT EXISTS approval_requests (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			company_id INTEGER NOT NULL,
+			workflow_rule_id INTEGER NOT NULL,
+			approver_id INTEGER NOT NULL,
+			amount INTEGER NOT NULL,
+			currency TEXT NOT NULL,
+			department TEXT,
+			vendor TEXT,
+			description TEXT,
+			due_date TEXT,
+			is_manager_approval_required INTEGER NOT NULL DEFAULT 0 CHECK (is_manager_approval_required IN (0, 1)),
+			approval_channel TEXT NOT NULL,
+			delivered_channel TEXT,
+			conversation_id TEXT,
+			message_id TEXT,
+			status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+			decided_by TEXT,
+			decided_at TEXT,
+			created_at TEXT NOT NULL,
+			submitted_by TEXT,
 			FOREIGN KEY (company_id) REFERENCES companies (id),
+			FOREIGN KEY (workflow_rule_id) REFERENCES workflow_rules (id),
 			FOREIGN KEY (approver_id) REFERENCES approvers (id)
 		)`,
+		// delivery attempts table.
+		`CREATE TABLE IF NOT EXISTS delivery_attempts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			company_id INTEGER NOT NULL,
+			approval_request_id INTEGER NOT NULL,
+			channel TEXT NOT NULL,
+			idempotency_key TEXT NOT NULL,
+			attempt INTEGER NOT NULL,
+			status_code INTEGER,
+			error TEXT,
+			succeeded INTEGER NOT NULL DEFAULT 0 CHECK (succeeded IN (0, 1)),
+			attempted_at TEXT NOT NULL,
+			FOREIGN KEY (company_id) REFERENCES companies (id),
+			FOREIGN KEY (approval_request_id) REFERENCES approval_requests (id)
+		)`,
+		// notification outbox table.
+		`CREATE TABLE IF NOT EXISTS notification_outbox (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			company_id INTEGER NOT NULL,
+			approval_request_id INTEGER NOT NULL,
+			channel TEXT NOT NULL,
+			fallback_channels TEXT NOT NULL DEFAULT '',
+			digest TEXT NOT NULL DEFAULT '' CHECK (digest IN ('', 'hourly_digest', 'daily_digest')),
+			payload TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TEXT NOT NULL,
+			last_error TEXT,
+			created_at TEXT NOT NULL,
+			delivered_at TEXT,
+			claimed_until TEXT,
+			FOREIGN KEY (company_id) REFERENCES companies (id),
+			FOREIGN KEY (approval_request_id) REFERENCES approval_requests (id)
+		)`,
+		// api keys table.
+		`CREATE TABLE IF NOT EXISTS api_keys (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			company_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			prefix TEXT NOT NULL,
+			hash TEXT NOT NULL UNIQUE,
+			permissions TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			revoked_at TEXT,
+			user_id INTEGER,
+			FOREIGN KEY (company_id) REFERENCES companies (id)
+		)`,
+		// users table.
+		`CREATE TABLE IF NOT EXISTS users (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			company_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			email TEXT NOT NULL,
+			role TEXT NOT NULL CHECK (role IN ('admin', 'rule-editor', 'approver', 'submitter', 'viewer')),
+			FOREIGN KEY (company_id) REFERENCES companies (id),
+			UNIQUE(company_id, email)
+		)`,
 	}
 }
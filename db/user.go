@@ -0,0 +1,11 @@
+package db
+
+// User is a user of a company. Role is one of the roles of the api
+// package.
+type User struct {
+	ID        int    `db:"id"`
+	CompanyID int    `db:"company_id"`
+	Name      string `db:"name"`
+	Email     string `db:"email"`
+	Role      string `db:"role"`
+}
@@ -0,0 +1,204 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/KatrinSalt/backend-challenge-go/db/sql"
+)
+
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrUserAlreadyExists = errors.New("user already exists")
+)
+
+// userColumns lists the columns read by the user store in the order
+// expected by scanUser.
+const userColumns = "id, company_id, name, email, role"
+
+// UserStore defines the interface for user operations
+type UserStore interface {
+	Create(user User) (User, error)
+	GetByID(companyID, id int) (User, error)
+	GetByEmail(companyID int, email string) (User, error)
+	Delete(companyID, id int) error
+	List(companyID int) ([]User, error)
+}
+
+// userStore implements UserStore
+type userStore struct {
+	client sql.Client
+	table  string
+}
+
+// UserStoreOptions contains options for the user store.
+type UserStoreOptions struct {
+	Table string
+}
+
+// UserStoreOption is a function that sets options on the user store.
+type UserStoreOption func(o *UserStoreOptions)
+
+// NewUserStore creates a new user store
+func NewUserStore(client sql.Client, options ...UserStoreOption) (*userStore, error) {
+	if client == nil {
+		return nil, errors.New("nil sql client")
+	}
+
+	opts := UserStoreOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+	if len(opts.Table) == 0 {
+		opts.Table = defaultUserTable
+	}
+
+	return &userStore{
+		client: client,
+		table:  opts.Table,
+	}, nil
+}
+
+// Create creates a new user. Emails are unique per company, ignoring
+// case.
+func (s *userStore) Create(user User) (User, error) {
+	tx, err := s.client.Transaction()
+	if err != nil {
+		return User{}, err
+	}
+	defer tx.Rollback()
+
+	// Check if a user with the email already exists
+	var exists bool
+	checkQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE company_id = $1 AND LOWER(email) = LOWER($2))", s.table)
+	if err := tx.QueryRow(checkQuery, user.CompanyID, user.Email).Scan(&exists); err != nil {
+		return User{}, fmt.Errorf("failed to check if user exists: %w", err)
+	}
+
+	if exists {
+		return User{}, ErrUserAlreadyExists
+	}
+
+	insert := fmt.Sprintf(`
+		INSERT INTO %s (company_id, name, email, role)
+		VALUES ($1, $2, $3, $4)
+		RETURNING %s`, s.table, userColumns)
+
+	outUser, err := scanUser(tx.QueryRow(insert, user.CompanyID, user.Name, user.Email, user.Role))
+	if err != nil {
+		return User{}, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return User{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return outUser, nil
+}
+
+// GetByID retrieves a company's user by their ID.
+func (s *userStore) GetByID(companyID, id int) (User, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", userColumns, s.table)
+	user, err := scanUser(s.client.QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return User{}, ErrUserNotFound
+		}
+		return User{}, err
+	}
+
+	if user.CompanyID != companyID {
+		return User{}, &CrossCompanyError{Entity: "user", ID: id, CompanyID: companyID}
+	}
+	return user, nil
+}
+
+// GetByEmail retrieves a company's user by their email, ignoring case.
+func (s *userStore) GetByEmail(companyID int, email string) (User, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE company_id = $1 AND LOWER(email) = LOWER($2)", userColumns, s.table)
+	user, err := scanUser(s.client.QueryRow(query, companyID, email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return User{}, ErrUserNotFound
+		}
+		return User{}, err
+	}
+	return user, nil
+}
+
+// List retrieves all users of a company.
+func (s *userStore) List(companyID int) ([]User, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE company_id = $1 ORDER BY id", userColumns, s.table)
+
+	rows, err := s.client.Query(query, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users by company ID: %w", err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over user rows: %w", err)
+	}
+
+	return users, nil
+}
+
+// Delete deletes a company's user by their ID.
+func (s *userStore) Delete(companyID, id int) error {
+	tx, err := s.client.Transaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Check if the user exists and belongs to the company
+	if err := checkCompanyScope(tx, s.table, "user", id, companyID, ErrUserNotFound); err != nil {
+		return err
+	}
+
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND company_id = $2", s.table)
+	result, err := tx.Exec(deleteQuery, id, companyID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// scanUser scans a row of userColumns.
+func scanUser(row sql.Row) (User, error) {
+	var user User
+	err := row.Scan(
+		&user.ID,
+		&user.CompanyID,
+		&user.Name,
+		&user.Email,
+		&user.Role,
+	)
+	if err != nil {
+		return User{}, err
+	}
+	return user, nil
+}
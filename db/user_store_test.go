@@ -0,0 +1,324 @@
+package db
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	sqlpkg "github.com/KatrinSalt/backend-challenge-go/db/sql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUserStore_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *userStore
+		user    User
+		want    User
+		wantErr error
+	}{
+		{
+			name: "successful user creation",
+			store: &userStore{
+				client: &mockSQLClient{
+					tx: &mockSQLTx{
+						queryRowResults: []sqlpkg.Row{
+							&mockSQLRow{values: []interface{}{false}},
+							&mockSQLRow{values: []interface{}{1, 1, "Vera Sander", "vera_sander@light.com", "rule-editor"}},
+						},
+					},
+				},
+				table: "users",
+			},
+			user: User{CompanyID: 1, Name: "Vera Sander", Email: "vera_sander@light.com", Role: "rule-editor"},
+			want: User{ID: 1, CompanyID: 1, Name: "Vera Sander", Email: "vera_sander@light.com", Role: "rule-editor"},
+		},
+		{
+			name: "email already taken",
+			store: &userStore{
+				client: &mockSQLClient{
+					tx: &mockSQLTx{
+						queryRowResult: &mockSQLRow{values: []interface{}{true}},
+					},
+				},
+				table: "users",
+			},
+			user:    User{CompanyID: 1, Name: "Vera Sander", Email: "VERA_SANDER@light.com", Role: "viewer"},
+			wantErr: ErrUserAlreadyExists,
+		},
+		{
+			name: "insert fails",
+			store: &userStore{
+				client: &mockSQLClient{
+					tx: &mockSQLTx{
+						queryRowResults: []sqlpkg.Row{
+							&mockSQLRow{values: []interface{}{false}},
+							&mockSQLRow{scanErr: errors.New("insert failed")},
+						},
+					},
+				},
+				table: "users",
+			},
+			user:    User{CompanyID: 1, Name: "Vera Sander", Email: "vera_sander@light.com", Role: "rule-editor"},
+			wantErr: errors.New("insert failed"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.store.Create(test.user)
+
+			if test.wantErr != nil {
+				if gotErr == nil || (!errors.Is(gotErr, test.wantErr) && !strings.Contains(gotErr.Error(), test.wantErr.Error())) {
+					t.Errorf("Create() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("Create() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Create() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUserStore_GetByID(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *userStore
+		want    User
+		wantErr error
+	}{
+		{
+			name: "successful get",
+			store: &userStore{
+				client: &mockSQLClient{
+					queryRowResult: &mockSQLRow{values: []interface{}{2, 1, "Auditor", "auditor@light.com", "viewer"}},
+				},
+				table: "users",
+			},
+			want: User{ID: 2, CompanyID: 1, Name: "Auditor", Email: "auditor@light.com", Role: "viewer"},
+		},
+		{
+			name: "user of another company",
+			store: &userStore{
+				client: &mockSQLClient{
+					queryRowResult: &mockSQLRow{values: []interface{}{2, 3, "Auditor", "auditor@acme.com", "viewer"}},
+				},
+				table: "users",
+			},
+			wantErr: ErrCrossCompanyAccess,
+		},
+		{
+			name: "unknown user",
+			store: &userStore{
+				client: &mockSQLClient{
+					queryRowResult: &mockSQLRow{scanErr: sqlpkg.ErrNoRows},
+				},
+				table: "users",
+			},
+			wantErr: ErrUserNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.store.GetByID(1, 2)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("GetByID() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("GetByID() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("GetByID() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUserStore_GetByEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *userStore
+		want    User
+		wantErr error
+	}{
+		{
+			name: "successful get",
+			store: &userStore{
+				client: &mockSQLClient{
+					queryRowResult: &mockSQLRow{values: []interface{}{1, 1, "Light Admin", "admin@light.com", "admin"}},
+				},
+				table: "users",
+			},
+			want: User{ID: 1, CompanyID: 1, Name: "Light Admin", Email: "admin@light.com", Role: "admin"},
+		},
+		{
+			name: "unknown email",
+			store: &userStore{
+				client: &mockSQLClient{
+					queryRowResult: &mockSQLRow{scanErr: sqlpkg.ErrNoRows},
+				},
+				table: "users",
+			},
+			wantErr: ErrUserNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.store.GetByEmail(1, "Admin@Light.com")
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("GetByEmail() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("GetByEmail() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("GetByEmail() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUserStore_List(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *userStore
+		want    []User
+		wantErr bool
+	}{
+		{
+			name: "successful list",
+			store: &userStore{
+				client: &mockSQLClient{
+					queryResult: &mockSQLRows{
+						rows: [][]interface{}{
+							{1, 1, "Light Admin", "admin@light.com", "admin"},
+							{2, 1, "Auditor", "auditor@light.com", "viewer"},
+						},
+					},
+				},
+				table: "users",
+			},
+			want: []User{
+				{ID: 1, CompanyID: 1, Name: "Light Admin", Email: "admin@light.com", Role: "admin"},
+				{ID: 2, CompanyID: 1, Name: "Auditor", Email: "auditor@light.com", Role: "viewer"},
+			},
+		},
+		{
+			name: "query fails",
+			store: &userStore{
+				client: &mockSQLClient{
+					queryErr: errors.New("query failed"),
+				},
+				table: "users",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.store.List(1)
+
+			if test.wantErr {
+				if gotErr == nil {
+					t.Errorf("List() expected error but got none")
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("List() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("List() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUserStore_Delete(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *userStore
+		wantErr error
+	}{
+		{
+			name: "successful deletion",
+			store: &userStore{
+				client: &mockSQLClient{
+					tx: &mockSQLTx{
+						execResult:     &mockSQLResult{},
+						queryRowResult: &mockSQLRow{values: []interface{}{1}},
+					},
+				},
+				table: "users",
+			},
+		},
+		{
+			name: "user of another company",
+			store: &userStore{
+				client: &mockSQLClient{
+					tx: &mockSQLTx{
+						queryRowResult: &mockSQLRow{values: []interface{}{2}},
+					},
+				},
+				table: "users",
+			},
+			wantErr: ErrCrossCompanyAccess,
+		},
+		{
+			name: "unknown user",
+			store: &userStore{
+				client: &mockSQLClient{
+					tx: &mockSQLTx{
+						queryRowResult: &mockSQLRow{scanErr: sqlpkg.ErrNoRows},
+					},
+				},
+				table: "users",
+			},
+			wantErr: ErrUserNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotErr := test.store.Delete(1, 1)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("Delete() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("Delete() unexpected error: %v", gotErr)
+			}
+		})
+	}
+}
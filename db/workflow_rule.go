@@ -1,13 +1,25 @@
 package db
 
 // WorkflowRule represents a rule that determines how invoices are approved.
+// Amounts are stored in minor units (cents) of Currency, and MinInclusive
+// and MaxInclusive decide whether the bounds include their endpoints.
+// FallbackChannels lists the channels tried, in order, when the approval
+// channel fails, as a comma separated list. If it is empty, the company's
+// fallback channels are used. If ManagerLevels is set, approval requests go
+// to the submitter's manager that many levels up the reporting hierarchy,
+// and to ApproverID only if there is no such manager.
 type WorkflowRule struct {
-	ID                        int      `db:"id"`
-	CompanyID                 int      `db:"company_id"`
-	MinAmount                 *float64 `db:"min_amount"`
-	MaxAmount                 *float64 `db:"max_amount"`
-	Department                *string  `db:"department"`
-	IsManagerApprovalRequired *int     `db:"is_manager_approval_required"`
-	ApproverID                int      `db:"approver_id"`
-	ApprovalChannel           int      `db:"approval_channel"`
+	ID                        int     `db:"id"`
+	CompanyID                 int     `db:"company_id"`
+	MinAmount                 *int64  `db:"min_amount"`
+	MaxAmount                 *int64  `db:"max_amount"`
+	MinInclusive              bool    `db:"min_inclusive"`
+	MaxInclusive              bool    `db:"max_inclusive"`
+	Currency                  string  `db:"currency"`
+	Department                *string `db:"department"`
+	IsManagerApprovalRequired *int    `db:"is_manager_approval_required"`
+	ApproverID                int     `db:"approver_id"`
+	ApprovalChannel           string  `db:"approval_channel"`
+	FallbackChannels          string  `db:"fallback_channels"`
+	ManagerLevels             int     `db:"manager_levels"`
 }
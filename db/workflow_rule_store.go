@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/KatrinSalt/backend-challenge-go/db/sql"
+	"github.com/KatrinSalt/backend-challenge-go/money"
 )
 
 var (
@@ -16,11 +17,11 @@ var (
 // WorkflowRuleStore defines the interface for workflow rule operations
 type WorkflowRuleStore interface {
 	Create(workflowRule WorkflowRule) (WorkflowRule, error)
-	GetByID(id int) (WorkflowRule, error)
+	GetByID(companyID, id int) (WorkflowRule, error)
 	Update(workflowRule WorkflowRule) error
-	Delete(id int) error
+	Delete(companyID, id int) error
 	List(companyID int) ([]WorkflowRule, error)
-	FindMatchingRule(companyID int, amount float64, department string, requiresManager bool) (WorkflowRule, error)
+	FindMatchingRule(companyID int, amount money.Money, department string, requiresManager bool) (WorkflowRule, error)
 }
 
 // workflowRuleStore implements WorkflowRuleStore
@@ -65,8 +66,8 @@ func (s *workflowRuleStore) Create(workflowRule WorkflowRule) (WorkflowRule, err
 	}
 	defer tx.Rollback()
 
-	insert := fmt.Sprintf("INSERT INTO %s (company_id, min_amount, max_amount, department, is_manager_approval_required, approver_id, approval_channel) VALUES ($1, $2, $3, $4, $5, $6, $7)", s.table)
-	if _, err := tx.Exec(insert, workflowRule.CompanyID, workflowRule.MinAmount, workflowRule.MaxAmount, workflowRule.Department, workflowRule.IsManagerApprovalRequired, workflowRule.ApproverID, workflowRule.ApprovalChannel); err != nil {
+	insert := fmt.Sprintf("INSERT INTO %s (company_id, min_amount, max_amount, min_inclusive, max_inclusive, currency, department, is_manager_approval_required, approver_id, approval_channel, fallback_channels, manager_levels) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)", s.table)
+	if _, err := tx.Exec(insert, workflowRule.CompanyID, workflowRule.MinAmount, workflowRule.MaxAmount, workflowRule.MinInclusive, workflowRule.MaxInclusive, currencyOrDefault(workflowRule.Currency), workflowRule.Department, workflowRule.IsManagerApprovalRequired, workflowRule.ApproverID, workflowRule.ApprovalChannel, workflowRule.FallbackChannels, workflowRule.ManagerLevels); err != nil {
 		if strings.Contains(err.Error(), sql.SQLStateDuplicateKey) {
 			return WorkflowRule{}, ErrWorkflowRuleAlreadyExists
 		}
@@ -75,8 +76,8 @@ func (s *workflowRuleStore) Create(workflowRule WorkflowRule) (WorkflowRule, err
 
 	// Get the created workflow rule with its generated ID.
 	var outWorkflowRule WorkflowRule
-	query := fmt.Sprintf("SELECT id, company_id, min_amount, max_amount, department, is_manager_approval_required, approver_id, approval_channel FROM %s WHERE company_id = $1 AND approver_id = $2 ORDER BY id DESC LIMIT 1", s.table)
-	if err := tx.QueryRow(query, workflowRule.CompanyID, workflowRule.ApproverID).Scan(&outWorkflowRule.ID, &outWorkflowRule.CompanyID, &outWorkflowRule.MinAmount, &outWorkflowRule.MaxAmount, &outWorkflowRule.Department, &outWorkflowRule.IsManagerApprovalRequired, &outWorkflowRule.ApproverID, &outWorkflowRule.ApprovalChannel); err != nil {
+	query := fmt.Sprintf("SELECT id, company_id, min_amount, max_amount, min_inclusive, max_inclusive, currency, department, is_manager_approval_required, approver_id, approval_channel, fallback_channels, manager_levels FROM %s WHERE company_id = $1 AND approver_id = $2 ORDER BY id DESC LIMIT 1", s.table)
+	if err := tx.QueryRow(query, workflowRule.CompanyID, workflowRule.ApproverID).Scan(&outWorkflowRule.ID, &outWorkflowRule.CompanyID, &outWorkflowRule.MinAmount, &outWorkflowRule.MaxAmount, &outWorkflowRule.MinInclusive, &outWorkflowRule.MaxInclusive, &outWorkflowRule.Currency, &outWorkflowRule.Department, &outWorkflowRule.IsManagerApprovalRequired, &outWorkflowRule.ApproverID, &outWorkflowRule.ApprovalChannel, &outWorkflowRule.FallbackChannels, &outWorkflowRule.ManagerLevels); err != nil {
 		return WorkflowRule{}, err
 	}
 
@@ -87,9 +88,9 @@ func (s *workflowRuleStore) Create(workflowRule WorkflowRule) (WorkflowRule, err
 	return outWorkflowRule, nil
 }
 
-// GetByID retrieves a workflow rule by its ID.
-func (s *workflowRuleStore) GetByID(id int) (WorkflowRule, error) {
-	query := fmt.Sprintf("SELECT id, company_id, min_amount, max_amount, department, is_manager_approval_required, approver_id, approval_channel FROM %s WHERE id = $1", s.table)
+// GetByID retrieves a company's workflow rule by its ID.
+func (s *workflowRuleStore) GetByID(companyID, id int) (WorkflowRule, error) {
+	query := fmt.Sprintf("SELECT id, company_id, min_amount, max_amount, min_inclusive, max_inclusive, currency, department, is_manager_approval_required, approver_id, approval_channel, fallback_channels, manager_levels FROM %s WHERE id = $1", s.table)
 
 	var rule WorkflowRule
 	err := s.client.QueryRow(query, id).Scan(
@@ -97,10 +98,15 @@ func (s *workflowRuleStore) GetByID(id int) (WorkflowRule, error) {
 		&rule.CompanyID,
 		&rule.MinAmount,
 		&rule.MaxAmount,
+		&rule.MinInclusive,
+		&rule.MaxInclusive,
+		&rule.Currency,
 		&rule.Department,
 		&rule.IsManagerApprovalRequired,
 		&rule.ApproverID,
 		&rule.ApprovalChannel,
+		&rule.FallbackChannels,
+		&rule.ManagerLevels,
 	)
 
 	if err != nil {
@@ -110,6 +116,10 @@ func (s *workflowRuleStore) GetByID(id int) (WorkflowRule, error) {
 		return WorkflowRule{}, fmt.Errorf("failed to get workflow rule by ID: %w", err)
 	}
 
+	if rule.CompanyID != companyID {
+		return WorkflowRule{}, &CrossCompanyError{Entity: "workflow rule", ID: id, CompanyID: companyID}
+	}
+
 	return rule, nil
 }
 
@@ -121,33 +131,33 @@ func (s *workflowRuleStore) Update(workflowRule WorkflowRule) error {
 	}
 	defer tx.Rollback()
 
-	// Check if the rule exists
-	var exists bool
-	checkQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1)", s.table)
-	if err := tx.QueryRow(checkQuery, workflowRule.ID).Scan(&exists); err != nil {
-		return fmt.Errorf("failed to check if workflow rule exists: %w", err)
-	}
-
-	if !exists {
-		return ErrWorkflowRuleNotFound
+	// Check if the rule exists and belongs to the company
+	if err := checkCompanyScope(tx, s.table, "workflow rule", workflowRule.ID, workflowRule.CompanyID, ErrWorkflowRuleNotFound); err != nil {
+		return err
 	}
 
 	// Update the workflow rule
 	updateQuery := fmt.Sprintf(`
 		UPDATE %s 
-		SET company_id = $2, min_amount = $3, max_amount = $4, department = $5, 
-		    is_manager_approval_required = $6, approver_id = $7, approval_channel = $8 
-		WHERE id = $1`, s.table)
+		SET min_amount = $1, max_amount = $2, min_inclusive = $3, max_inclusive = $4, 
+		    currency = $5, department = $6, is_manager_approval_required = $7, approver_id = $8, approval_channel = $9,
+		    fallback_channels = $10, manager_levels = $11
+		WHERE id = $12 AND company_id = $13`, s.table)
 
 	_, err = tx.Exec(updateQuery,
-		workflowRule.ID,
-		workflowRule.CompanyID,
 		workflowRule.MinAmount,
 		workflowRule.MaxAmount,
+		workflowRule.MinInclusive,
+		workflowRule.MaxInclusive,
+		currencyOrDefault(workflowRule.Currency),
 		workflowRule.Department,
 		workflowRule.IsManagerApprovalRequired,
 		workflowRule.ApproverID,
-		workflowRule.ApprovalChannel)
+		workflowRule.ApprovalChannel,
+		workflowRule.FallbackChannels,
+		workflowRule.ManagerLevels,
+		workflowRule.ID,
+		workflowRule.CompanyID)
 
 	if err != nil {
 		return fmt.Errorf("failed to update workflow rule: %w", err)
@@ -160,28 +170,22 @@ func (s *workflowRuleStore) Update(workflowRule WorkflowRule) error {
 	return nil
 }
 
-// Delete deletes a workflow rule by its ID.
-func (s *workflowRuleStore) Delete(id int) error {
+// Delete deletes a company's workflow rule by its ID.
+func (s *workflowRuleStore) Delete(companyID, id int) error {
 	tx, err := s.client.Transaction()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// Check if the rule exists
-	var exists bool
-	checkQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1)", s.table)
-	if err := tx.QueryRow(checkQuery, id).Scan(&exists); err != nil {
-		return fmt.Errorf("failed to check if workflow rule exists: %w", err)
-	}
-
-	if !exists {
-		return ErrWorkflowRuleNotFound
+	// Check if the rule exists and belongs to the company
+	if err := checkCompanyScope(tx, s.table, "workflow rule", id, companyID, ErrWorkflowRuleNotFound); err != nil {
+		return err
 	}
 
 	// Delete the workflow rule
-	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1", s.table)
-	result, err := tx.Exec(deleteQuery, id)
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND company_id = $2", s.table)
+	result, err := tx.Exec(deleteQuery, id, companyID)
 
 	if err != nil {
 		return fmt.Errorf("failed to delete workflow rule: %w", err)
@@ -205,7 +209,7 @@ func (s *workflowRuleStore) Delete(id int) error {
 
 // List retrieves all workflow rules for a specific company.
 func (s *workflowRuleStore) List(companyID int) ([]WorkflowRule, error) {
-	query := fmt.Sprintf("SELECT id, company_id, min_amount, max_amount, department, is_manager_approval_required, approver_id, approval_channel FROM %s WHERE company_id = $1", s.table)
+	query := fmt.Sprintf("SELECT id, company_id, min_amount, max_amount, min_inclusive, max_inclusive, currency, department, is_manager_approval_required, approver_id, approval_channel, fallback_channels, manager_levels FROM %s WHERE company_id = $1 ORDER BY id", s.table)
 
 	rows, err := s.client.Query(query, companyID)
 	if err != nil {
@@ -221,10 +225,15 @@ func (s *workflowRuleStore) List(companyID int) ([]WorkflowRule, error) {
 			&rule.CompanyID,
 			&rule.MinAmount,
 			&rule.MaxAmount,
+			&rule.MinInclusive,
+			&rule.MaxInclusive,
+			&rule.Currency,
 			&rule.Department,
 			&rule.IsManagerApprovalRequired,
 			&rule.ApproverID,
 			&rule.ApprovalChannel,
+			&rule.FallbackChannels,
+			&rule.ManagerLevels,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan workflow rule: %w", err)
@@ -239,26 +248,30 @@ func (s *workflowRuleStore) List(companyID int) ([]WorkflowRule, error) {
 	return rules, nil
 }
 
-func (s *workflowRuleStore) FindMatchingRule(companyID int, amount float64, department string, requiresManager bool) (WorkflowRule, error) {
-	query := `
-		SELECT id, company_id, min_amount, max_amount, department, 
-		       is_manager_approval_required, approver_id, approval_channel 
-		FROM workflow_rules 
+// FindMatchingRule finds the most specific workflow rule of a company that
+// matches the given invoice criteria.
+func (s *workflowRuleStore) FindMatchingRule(companyID int, amount money.Money, department string, requiresManager bool) (WorkflowRule, error) {
+	query := fmt.Sprintf(`
+		SELECT id, company_id, min_amount, max_amount, min_inclusive, max_inclusive, currency, 
+		       department, is_manager_approval_required, approver_id, approval_channel, fallback_channels, manager_levels
+		FROM %s 
 		WHERE company_id = $1 
 			AND (
-				-- Amount logic: inclusive lower bound, exclusive upper bound
-				(min_amount IS NULL OR $2 >= min_amount) AND
-				(max_amount IS NULL OR $2 < max_amount)
+				-- Amount logic: integer cents, each bound is inclusive or exclusive per rule
+				(min_amount IS NULL OR (min_inclusive = 1 AND $2 >= min_amount) OR (min_inclusive = 0 AND $2 > min_amount)) AND
+				(max_amount IS NULL OR (max_inclusive = 1 AND $2 <= max_amount) OR (max_inclusive = 0 AND $2 < max_amount))
 			)
 			AND (department IS NULL OR department = $3)
 			AND (is_manager_approval_required IS NULL OR is_manager_approval_required = $4)
+			-- Amount bounds only apply to invoices in the same currency.
+			AND ((min_amount IS NULL AND max_amount IS NULL) OR currency = $5)
 		ORDER BY 
 			(CASE WHEN min_amount IS NOT NULL THEN 1 ELSE 0 END +
 			 CASE WHEN max_amount IS NOT NULL THEN 1 ELSE 0 END +
 			 CASE WHEN department IS NOT NULL THEN 1 ELSE 0 END +
 			 CASE WHEN is_manager_approval_required IS NOT NULL THEN 1 ELSE 0 END) DESC,
 			id
-		LIMIT 1`
+		LIMIT 1`, s.table)
 
 	var rule WorkflowRule
 
@@ -268,9 +281,9 @@ func (s *workflowRuleStore) FindMatchingRule(companyID int, amount float64, depa
 		managerApprovalInt = 1
 	}
 
-	err := s.client.QueryRow(query, companyID, amount, department, managerApprovalInt).Scan(
-		&rule.ID, &rule.CompanyID, &rule.MinAmount, &rule.MaxAmount,
-		&rule.Department, &rule.IsManagerApprovalRequired, &rule.ApproverID, &rule.ApprovalChannel)
+	err := s.client.QueryRow(query, companyID, amount.Cents, department, managerApprovalInt, currencyOrDefault(string(amount.Currency))).Scan(
+		&rule.ID, &rule.CompanyID, &rule.MinAmount, &rule.MaxAmount, &rule.MinInclusive, &rule.MaxInclusive, &rule.Currency,
+		&rule.Department, &rule.IsManagerApprovalRequired, &rule.ApproverID, &rule.ApprovalChannel, &rule.FallbackChannels, &rule.ManagerLevels)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -281,3 +294,11 @@ func (s *workflowRuleStore) FindMatchingRule(companyID int, amount float64, depa
 
 	return rule, nil
 }
+
+// currencyOrDefault returns the given currency or the default currency if empty.
+func currencyOrDefault(currency string) string {
+	if currency == "" {
+		return string(money.DefaultCurrency)
+	}
+	return currency
+}
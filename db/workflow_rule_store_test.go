@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	sqlpkg "github.com/KatrinSalt/backend-challenge-go/db/sql"
+	"github.com/KatrinSalt/backend-challenge-go/money"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -127,7 +128,7 @@ func TestWorkflowRuleStore_Create(t *testing.T) {
 						tx: &mockSQLTx{
 							execResult: &mockSQLResult{},
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{1, 1, floatPtr(1000.0), floatPtr(5000.0), stringPtr("Finance"), intPtr(0), 1, 0},
+								values: []interface{}{1, 1, int64Ptr(100000), int64Ptr(500000), true, false, "USD", stringPtr("Finance"), intPtr(0), 1, "slack", "", 0},
 							},
 						},
 					},
@@ -135,23 +136,27 @@ func TestWorkflowRuleStore_Create(t *testing.T) {
 				},
 				workflowRule: WorkflowRule{
 					CompanyID:                 1,
-					MinAmount:                 floatPtr(1000.0),
-					MaxAmount:                 floatPtr(5000.0),
+					MinAmount:                 int64Ptr(100000),
+					MaxAmount:                 int64Ptr(500000),
+					MinInclusive:              true,
+					Currency:                  "USD",
 					Department:                stringPtr("Finance"),
 					IsManagerApprovalRequired: intPtr(0),
 					ApproverID:                1,
-					ApprovalChannel:           0,
+					ApprovalChannel:           "slack",
 				},
 			},
 			want: WorkflowRule{
 				ID:                        1,
 				CompanyID:                 1,
-				MinAmount:                 floatPtr(1000.0),
-				MaxAmount:                 floatPtr(5000.0),
+				MinAmount:                 int64Ptr(100000),
+				MaxAmount:                 int64Ptr(500000),
+				MinInclusive:              true,
+				Currency:                  "USD",
 				Department:                stringPtr("Finance"),
 				IsManagerApprovalRequired: intPtr(0),
 				ApproverID:                1,
-				ApprovalChannel:           0,
+				ApprovalChannel:           "slack",
 			},
 			wantErr: false,
 		},
@@ -171,12 +176,14 @@ func TestWorkflowRuleStore_Create(t *testing.T) {
 				},
 				workflowRule: WorkflowRule{
 					CompanyID:                 1,
-					MinAmount:                 floatPtr(1000.0),
-					MaxAmount:                 floatPtr(5000.0),
+					MinAmount:                 int64Ptr(100000),
+					MaxAmount:                 int64Ptr(500000),
+					MinInclusive:              true,
+					Currency:                  "USD",
 					Department:                stringPtr("Finance"),
 					IsManagerApprovalRequired: intPtr(0),
 					ApproverID:                1,
-					ApprovalChannel:           0,
+					ApprovalChannel:           "slack",
 				},
 			},
 			want:    WorkflowRule{},
@@ -197,12 +204,14 @@ func TestWorkflowRuleStore_Create(t *testing.T) {
 				},
 				workflowRule: WorkflowRule{
 					CompanyID:                 1,
-					MinAmount:                 floatPtr(1000.0),
-					MaxAmount:                 floatPtr(5000.0),
+					MinAmount:                 int64Ptr(100000),
+					MaxAmount:                 int64Ptr(500000),
+					MinInclusive:              true,
+					Currency:                  "USD",
 					Department:                stringPtr("Finance"),
 					IsManagerApprovalRequired: intPtr(0),
 					ApproverID:                1,
-					ApprovalChannel:           0,
+					ApprovalChannel:           "slack",
 				},
 			},
 			want:    WorkflowRule{},
@@ -228,12 +237,14 @@ func TestWorkflowRuleStore_Create(t *testing.T) {
 				},
 				workflowRule: WorkflowRule{
 					CompanyID:                 1,
-					MinAmount:                 floatPtr(1000.0),
-					MaxAmount:                 floatPtr(5000.0),
+					MinAmount:                 int64Ptr(100000),
+					MaxAmount:                 int64Ptr(500000),
+					MinInclusive:              true,
+					Currency:                  "USD",
 					Department:                stringPtr("Finance"),
 					IsManagerApprovalRequired: intPtr(0),
 					ApproverID:                1,
-					ApprovalChannel:           0,
+					ApprovalChannel:           "slack",
 				},
 			},
 			want:    WorkflowRule{},
@@ -251,7 +262,7 @@ func TestWorkflowRuleStore_Create(t *testing.T) {
 						tx: &mockSQLTx{
 							execResult: &mockSQLResult{},
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{1, 1, floatPtr(1000.0), floatPtr(5000.0), stringPtr("Finance"), intPtr(0), 1, 0},
+								values: []interface{}{1, 1, int64Ptr(100000), int64Ptr(500000), true, false, "USD", stringPtr("Finance"), intPtr(0), 1, "slack", "", 0},
 							},
 							commitErr: errors.New("commit failed"),
 						},
@@ -260,12 +271,14 @@ func TestWorkflowRuleStore_Create(t *testing.T) {
 				},
 				workflowRule: WorkflowRule{
 					CompanyID:                 1,
-					MinAmount:                 floatPtr(1000.0),
-					MaxAmount:                 floatPtr(5000.0),
+					MinAmount:                 int64Ptr(100000),
+					MaxAmount:                 int64Ptr(500000),
+					MinInclusive:              true,
+					Currency:                  "USD",
 					Department:                stringPtr("Finance"),
 					IsManagerApprovalRequired: intPtr(0),
 					ApproverID:                1,
-					ApprovalChannel:           0,
+					ApprovalChannel:           "slack",
 				},
 			},
 			want:    WorkflowRule{},
@@ -322,8 +335,8 @@ func TestWorkflowRuleStore_List(t *testing.T) {
 					client: &mockSQLClient{
 						queryResult: &mockSQLRows{
 							rows: [][]interface{}{
-								{1, 1, floatPtr(1000.0), floatPtr(5000.0), stringPtr("Finance"), intPtr(0), 1, 0},
-								{2, 1, floatPtr(5000.0), nil, stringPtr("IT"), intPtr(1), 2, 1},
+								{1, 1, int64Ptr(100000), int64Ptr(500000), true, false, "USD", stringPtr("Finance"), intPtr(0), 1, "slack", "", 0},
+								{2, 1, int64Ptr(500000), nil, true, false, "USD", stringPtr("IT"), intPtr(1), 2, "email", "slack,teams", 2},
 							},
 						},
 					},
@@ -335,22 +348,28 @@ func TestWorkflowRuleStore_List(t *testing.T) {
 				{
 					ID:                        1,
 					CompanyID:                 1,
-					MinAmount:                 floatPtr(1000.0),
-					MaxAmount:                 floatPtr(5000.0),
+					MinAmount:                 int64Ptr(100000),
+					MaxAmount:                 int64Ptr(500000),
+					MinInclusive:              true,
+					Currency:                  "USD",
 					Department:                stringPtr("Finance"),
 					IsManagerApprovalRequired: intPtr(0),
 					ApproverID:                1,
-					ApprovalChannel:           0,
+					ApprovalChannel:           "slack",
 				},
 				{
 					ID:                        2,
 					CompanyID:                 1,
-					MinAmount:                 floatPtr(5000.0),
+					MinAmount:                 int64Ptr(500000),
 					MaxAmount:                 nil,
+					MinInclusive:              true,
+					Currency:                  "USD",
 					Department:                stringPtr("IT"),
 					IsManagerApprovalRequired: intPtr(1),
 					ApproverID:                2,
-					ApprovalChannel:           1,
+					ApprovalChannel:           "email",
+					FallbackChannels:          "slack,teams",
+					ManagerLevels:             2,
 				},
 			},
 			wantErr: false,
@@ -402,7 +421,7 @@ func TestWorkflowRuleStore_List(t *testing.T) {
 					client: &mockSQLClient{
 						queryResult: &mockSQLRows{
 							rows: [][]interface{}{
-								{1, 1, floatPtr(1000.0), floatPtr(5000.0), stringPtr("Finance"), intPtr(0), 1, 0},
+								{1, 1, int64Ptr(100000), int64Ptr(500000), true, false, "USD", stringPtr("Finance"), intPtr(0), 1, "slack", "", 0},
 							},
 							scanErr: errors.New("scan error"),
 						},
@@ -450,7 +469,7 @@ func TestWorkflowRuleStore_FindMatchingRule(t *testing.T) {
 		input struct {
 			store           *workflowRuleStore
 			companyID       int
-			amount          float64
+			amount          money.Money
 			department      string
 			requiresManager bool
 		}
@@ -463,32 +482,34 @@ func TestWorkflowRuleStore_FindMatchingRule(t *testing.T) {
 			input: struct {
 				store           *workflowRuleStore
 				companyID       int
-				amount          float64
+				amount          money.Money
 				department      string
 				requiresManager bool
 			}{
 				store: &workflowRuleStore{
 					client: &mockSQLClient{
 						queryRowResult: &mockSQLRow{
-							values: []interface{}{1, 1, floatPtr(1000.0), floatPtr(5000.0), stringPtr("Finance"), intPtr(0), 1, 0},
+							values: []interface{}{1, 1, int64Ptr(100000), int64Ptr(500000), true, false, "USD", stringPtr("Finance"), intPtr(0), 1, "slack", "", 0},
 						},
 					},
 					table: "workflow_rules",
 				},
 				companyID:       1,
-				amount:          2500.0,
+				amount:          money.New(250000, money.USD),
 				department:      "Finance",
 				requiresManager: false,
 			},
 			want: WorkflowRule{
 				ID:                        1,
 				CompanyID:                 1,
-				MinAmount:                 floatPtr(1000.0),
-				MaxAmount:                 floatPtr(5000.0),
+				MinAmount:                 int64Ptr(100000),
+				MaxAmount:                 int64Ptr(500000),
+				MinInclusive:              true,
+				Currency:                  "USD",
 				Department:                stringPtr("Finance"),
 				IsManagerApprovalRequired: intPtr(0),
 				ApproverID:                1,
-				ApprovalChannel:           0,
+				ApprovalChannel:           "slack",
 			},
 			wantErr: false,
 		},
@@ -497,7 +518,7 @@ func TestWorkflowRuleStore_FindMatchingRule(t *testing.T) {
 			input: struct {
 				store           *workflowRuleStore
 				companyID       int
-				amount          float64
+				amount          money.Money
 				department      string
 				requiresManager bool
 			}{
@@ -510,7 +531,7 @@ func TestWorkflowRuleStore_FindMatchingRule(t *testing.T) {
 					table: "workflow_rules",
 				},
 				companyID:       1,
-				amount:          10000.0,
+				amount:          money.New(1000000, money.USD),
 				department:      "Unknown",
 				requiresManager: false,
 			},
@@ -523,7 +544,7 @@ func TestWorkflowRuleStore_FindMatchingRule(t *testing.T) {
 			input: struct {
 				store           *workflowRuleStore
 				companyID       int
-				amount          float64
+				amount          money.Money
 				department      string
 				requiresManager bool
 			}{
@@ -536,7 +557,7 @@ func TestWorkflowRuleStore_FindMatchingRule(t *testing.T) {
 					table: "workflow_rules",
 				},
 				companyID:       1,
-				amount:          2500.0,
+				amount:          money.New(250000, money.USD),
 				department:      "Finance",
 				requiresManager: false,
 			},
@@ -604,7 +625,7 @@ func TestWorkflowRuleStore_GetByID(t *testing.T) {
 					client: &mockSQLClient{
 						queryRowResult: &mockSQLRow{
 							values: []interface{}{
-								1, 1, floatPtr(100.0), floatPtr(500.0), stringPtr("Finance"), intPtr(1), 1, 0,
+								1, 1, int64Ptr(10000), int64Ptr(50000), true, false, "USD", stringPtr("Finance"), intPtr(1), 1, "slack", "", 0,
 							},
 						},
 					},
@@ -615,12 +636,14 @@ func TestWorkflowRuleStore_GetByID(t *testing.T) {
 			want: WorkflowRule{
 				ID:                        1,
 				CompanyID:                 1,
-				MinAmount:                 floatPtr(100.0),
-				MaxAmount:                 floatPtr(500.0),
+				MinAmount:                 int64Ptr(10000),
+				MaxAmount:                 int64Ptr(50000),
+				MinInclusive:              true,
+				Currency:                  "USD",
 				Department:                stringPtr("Finance"),
 				IsManagerApprovalRequired: intPtr(1),
 				ApproverID:                1,
-				ApprovalChannel:           0,
+				ApprovalChannel:           "slack",
 			},
 			wantErr: false,
 		},
@@ -668,7 +691,7 @@ func TestWorkflowRuleStore_GetByID(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got, gotErr := test.input.store.GetByID(test.input.id)
+			got, gotErr := test.input.store.GetByID(1, test.input.id)
 
 			if test.wantErr {
 				if gotErr == nil {
@@ -722,7 +745,7 @@ func TestWorkflowRuleStore_Update(t *testing.T) {
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{true}, // Rule exists
+								values: []interface{}{1}, // Rule exists in company 1
 							},
 							execResult: &mockSQLResult{},
 						},
@@ -732,12 +755,14 @@ func TestWorkflowRuleStore_Update(t *testing.T) {
 				rule: WorkflowRule{
 					ID:                        1,
 					CompanyID:                 1,
-					MinAmount:                 floatPtr(200.0),
-					MaxAmount:                 floatPtr(1000.0),
+					MinAmount:                 int64Ptr(20000),
+					MaxAmount:                 int64Ptr(100000),
+					MinInclusive:              true,
+					Currency:                  "USD",
 					Department:                stringPtr("IT"),
 					IsManagerApprovalRequired: intPtr(0),
 					ApproverID:                2,
-					ApprovalChannel:           1,
+					ApprovalChannel:           "email",
 				},
 			},
 			wantErr: false,
@@ -752,7 +777,7 @@ func TestWorkflowRuleStore_Update(t *testing.T) {
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{false}, // Rule doesn't exist
+								scanErr: sqlpkg.ErrNoRows, // Rule doesn't exist
 							},
 						},
 					},
@@ -794,7 +819,7 @@ func TestWorkflowRuleStore_Update(t *testing.T) {
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{true}, // Rule exists
+								values: []interface{}{1}, // Rule exists in company 1
 							},
 							execResult: &mockSQLResult{},
 							commitErr:  errors.New("commit failed"),
@@ -803,7 +828,8 @@ func TestWorkflowRuleStore_Update(t *testing.T) {
 					table: "workflow_rules",
 				},
 				rule: WorkflowRule{
-					ID: 1,
+					ID:        1,
+					CompanyID: 1,
 				},
 			},
 			wantErr: true,
@@ -853,7 +879,7 @@ func TestWorkflowRuleStore_Delete(t *testing.T) {
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{true}, // Rule exists
+								values: []interface{}{1}, // Rule exists in company 1
 							},
 							execResult: &mockSQLResult{},
 						},
@@ -864,6 +890,27 @@ func TestWorkflowRuleStore_Delete(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "workflow rule of another company",
+			input: struct {
+				store *workflowRuleStore
+				id    int
+			}{
+				store: &workflowRuleStore{
+					client: &mockSQLClient{
+						tx: &mockSQLTx{
+							queryRowResult: &mockSQLRow{
+								values: []interface{}{2}, // Rule exists in company 2
+							},
+						},
+					},
+					table: "workflow_rules",
+				},
+				id: 6,
+			},
+			wantErr: true,
+			errMsg:  "cross-company access: workflow rule 6 does not belong to company 1",
+		},
 		{
 			name: "workflow rule not found for deletion",
 			input: struct {
@@ -874,7 +921,7 @@ func TestWorkflowRuleStore_Delete(t *testing.T) {
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{false}, // Rule doesn't exist
+								scanErr: sqlpkg.ErrNoRows, // Rule doesn't exist
 							},
 						},
 					},
@@ -912,7 +959,7 @@ func TestWorkflowRuleStore_Delete(t *testing.T) {
 					client: &mockSQLClient{
 						tx: &mockSQLTx{
 							queryRowResult: &mockSQLRow{
-								values: []interface{}{true}, // Rule exists
+								values: []interface{}{1}, // Rule exists in company 1
 							},
 							execResult: &mockSQLResult{},
 							commitErr:  errors.New("commit failed"),
@@ -929,7 +976,7 @@ func TestWorkflowRuleStore_Delete(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			gotErr := test.input.store.Delete(test.input.id)
+			gotErr := test.input.store.Delete(1, test.input.id)
 
 			if test.wantErr {
 				if gotErr == nil {
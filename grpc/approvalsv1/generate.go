@@ -0,0 +1,5 @@
+// Package approvalsv1 contains the protobuf messages and gRPC services of
+// the approvals.v1 API, generated from approvals.proto.
+package approvalsv1
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative approvalsv1/approvals.proto
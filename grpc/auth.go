@@ -0,0 +1,184 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/KatrinSalt/backend-challenge-go/access"
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/auth"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/grpc/approvalsv1"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Authenticator resolves API keys to the company they belong to.
+type Authenticator interface {
+	Authenticate(key string) (auth.Principal, error)
+}
+
+// CompanyServicesFunc returns the management and workflow services of the
+// company with the name.
+type CompanyServicesFunc func(company string) (management.Service, workflow.Service, error)
+
+// methodPermissions are the permissions API keys need to call the methods
+// of the approvals.v1 services. The health and reflection services need no
+// API key.
+var methodPermissions = map[string]api.Permission{
+	approvalsv1.InvoiceService_SubmitInvoice_FullMethodName:       api.PermissionSubmitInvoices,
+	approvalsv1.InvoiceService_WatchApprovalStatus_FullMethodName: api.PermissionRead,
+
+	approvalsv1.ManagementService_CreateCompany_FullMethodName: api.PermissionManageCompanies,
+	approvalsv1.ManagementService_GetCompany_FullMethodName:    api.PermissionRead,
+	approvalsv1.ManagementService_UpdateCompany_FullMethodName: api.PermissionManageCompanies,
+	approvalsv1.ManagementService_DeleteCompany_FullMethodName: api.PermissionManageCompanies,
+	approvalsv1.ManagementService_ListCompanies_FullMethodName: api.PermissionRead,
+
+	approvalsv1.ManagementService_AddDepartment_FullMethodName:    api.PermissionManageRules,
+	approvalsv1.ManagementService_RemoveDepartment_FullMethodName: api.PermissionManageRules,
+	approvalsv1.ManagementService_ListDepartments_FullMethodName:  api.PermissionRead,
+
+	approvalsv1.ManagementService_CreateWorkflowRule_FullMethodName: api.PermissionManageRules,
+	approvalsv1.ManagementService_GetWorkflowRule_FullMethodName:    api.PermissionRead,
+	approvalsv1.ManagementService_UpdateWorkflowRule_FullMethodName: api.PermissionManageRules,
+	approvalsv1.ManagementService_DeleteWorkflowRule_FullMethodName: api.PermissionManageRules,
+	approvalsv1.ManagementService_ListWorkflowRules_FullMethodName:  api.PermissionRead,
+
+	approvalsv1.ManagementService_CreateApprover_FullMethodName: api.PermissionManageApprovers,
+	approvalsv1.ManagementService_GetApprover_FullMethodName:    api.PermissionRead,
+	approvalsv1.ManagementService_UpdateApprover_FullMethodName: api.PermissionManageApprovers,
+	approvalsv1.ManagementService_DeleteApprover_FullMethodName: api.PermissionManageApprovers,
+	approvalsv1.ManagementService_ListApprovers_FullMethodName:  api.PermissionRead,
+	approvalsv1.ManagementService_GetOrgChart_FullMethodName:    api.PermissionRead,
+
+	approvalsv1.ManagementService_ListDeliveryAttempts_FullMethodName: api.PermissionRead,
+	approvalsv1.ManagementService_ListOutboxMessages_FullMethodName:   api.PermissionRead,
+	approvalsv1.ManagementService_ReplayDeadLetter_FullMethodName:     api.PermissionManageNotifications,
+}
+
+// approvalsServicePrefix starts the full method names of the approvals.v1
+// services.
+const approvalsServicePrefix = "/approvals.v1."
+
+// companyServices holds the services of the company of a call.
+type companyServices struct {
+	management management.Service
+	workflow   workflow.Service
+}
+
+// companyServicesKey is the context key of the services of the company of a
+// call.
+type companyServicesKey struct{}
+
+// authorize authenticates the API key of a call of the method and checks
+// that it has the permission of the method. It returns the context of the
+// call with the principal of the key and the services of its company.
+// Calls are not authenticated if the server has no authenticator.
+func (s *Server) authorize(ctx context.Context, method string) (context.Context, error) {
+	if s.authenticator == nil || !strings.HasPrefix(method, approvalsServicePrefix) {
+		return ctx, nil
+	}
+
+	principal, err := s.authenticator.Authenticate(bearerToken(ctx))
+	if errors.Is(err, auth.ErrInvalidAPIKey) {
+		return nil, status.Errorf(codes.Unauthenticated, "a valid API key is required: %v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	permission, ok := methodPermissions[method]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "%s cannot be called with an API key", method)
+	}
+	if !principal.Can(permission) {
+		return nil, status.Errorf(codes.PermissionDenied, "the API key does not have the %s permission", permission)
+	}
+
+	managementSvc, workflowSvc, err := s.companyServices(principal.Company)
+	if err != nil {
+		return nil, err
+	}
+	// A key that acts as a user is only allowed what the user's role allows
+	if principal.User != nil {
+		managementSvc = access.Management(managementSvc, *principal.User)
+		workflowSvc = access.Workflow(workflowSvc, *principal.User)
+	}
+	ctx = auth.NewContext(ctx, principal)
+	return context.WithValue(ctx, companyServicesKey{}, companyServices{management: managementSvc, workflow: workflowSvc}), nil
+}
+
+// unaryAuth authorizes unary calls.
+func (s *Server) unaryAuth(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamAuth authorizes streaming calls.
+func (s *Server) streamAuth(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorize(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authorizedStream is a server stream with the context of an authorized
+// call.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the authorized call.
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+// bearerToken returns the bearer token of the authorization metadata of
+// the call, or an empty string if it has none.
+func bearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		scheme, token, ok := strings.Cut(value, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// managementFor returns the management service of the company of the call.
+func (s *Server) managementFor(ctx context.Context) management.Service {
+	if services, ok := ctx.Value(companyServicesKey{}).(companyServices); ok {
+		return services.management
+	}
+	return s.management
+}
+
+// workflowFor returns the workflow service of the company of the call.
+func (s *Server) workflowFor(ctx context.Context) workflow.Service {
+	if services, ok := ctx.Value(companyServicesKey{}).(companyServices); ok {
+		return services.workflow
+	}
+	return s.workflow
+}
+
+// checkCompany checks that an API key has access to the company with the
+// ID. An API key only has access to its own company, so other companies
+// are reported as not found.
+func checkCompany(ctx context.Context, id int) error {
+	if principal, ok := auth.FromContext(ctx); ok && principal.CompanyID != id {
+		return fmt.Errorf("company %d: %w", id, db.ErrCompanyNotFound)
+	}
+	return nil
+}
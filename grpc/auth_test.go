@@ -0,0 +1,175 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/auth"
+	"github.com/KatrinSalt/backend-challenge-go/grpc/approvalsv1"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestServer_Authentication(t *testing.T) {
+	principals := map[string]auth.Principal{
+		"ak_reader": {KeyID: 1, CompanyID: 2, Company: "Acme", Permissions: []api.Permission{api.PermissionRead}},
+		"ak_admin":  {KeyID: 2, CompanyID: 2, Company: "Acme", Permissions: api.Permissions},
+		"ak_rules":  {KeyID: 3, CompanyID: 2, Company: "Acme", Permissions: []api.Permission{api.PermissionRead, api.PermissionManageRules}},
+	}
+	acme := &mockManagementService{
+		company:  api.Company{ID: 2, Name: "Acme"},
+		approver: api.Approver{ID: 3, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@acme.com"},
+	}
+	companyServices := func(company string) (management.Service, workflow.Service, error) {
+		if company != "Acme" {
+			return nil, nil, errors.New("unexpected company " + company)
+		}
+		return acme, &mockWorkflowService{}, nil
+	}
+	// The services of the configured company must not be used.
+	_, conn := newTestServer(t, &mockManagementService{}, &mockWorkflowService{},
+		WithAuthentication(&mockAuthenticator{principals: principals}, companyServices))
+	client := approvalsv1.NewManagementServiceClient(conn)
+	invoices := approvalsv1.NewInvoiceServiceClient(conn)
+
+	tests := []struct {
+		name     string
+		key      string
+		call     func(ctx context.Context) (any, error)
+		want     any
+		wantCode codes.Code
+	}{
+		{
+			name: "no api key",
+			call: func(ctx context.Context) (any, error) {
+				return client.GetApprover(ctx, &approvalsv1.GetApproverRequest{Id: 3})
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "unknown api key",
+			key:  "ak_unknown",
+			call: func(ctx context.Context) (any, error) {
+				return client.GetApprover(ctx, &approvalsv1.GetApproverRequest{Id: 3})
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "served for the company of the api key",
+			key:  "ak_reader",
+			call: func(ctx context.Context) (any, error) {
+				return client.GetApprover(ctx, &approvalsv1.GetApproverRequest{Id: 3})
+			},
+			want: &approvalsv1.Approver{Id: 3, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@acme.com"},
+		},
+		{
+			name: "missing permission",
+			key:  "ak_reader",
+			call: func(ctx context.Context) (any, error) {
+				return client.DeleteApprover(ctx, &approvalsv1.DeleteApproverRequest{Id: 3})
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "rules permission does not delete companies",
+			key:  "ak_rules",
+			call: func(ctx context.Context) (any, error) {
+				return client.DeleteCompany(ctx, &approvalsv1.DeleteCompanyRequest{Id: 2})
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "rules permission does not replay dead letters",
+			key:  "ak_rules",
+			call: func(ctx context.Context) (any, error) {
+				return client.ReplayDeadLetter(ctx, &approvalsv1.ReplayDeadLetterRequest{Id: 1})
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "only the company of the api key is listed",
+			key:  "ak_reader",
+			call: func(ctx context.Context) (any, error) {
+				return client.ListCompanies(ctx, &approvalsv1.ListCompaniesRequest{})
+			},
+			want: &approvalsv1.ListCompaniesResponse{Companies: []*approvalsv1.Company{{Id: 2, Name: "Acme"}}},
+		},
+		{
+			name: "other companies are not found",
+			key:  "ak_admin",
+			call: func(ctx context.Context) (any, error) {
+				return client.GetCompany(ctx, &approvalsv1.GetCompanyRequest{Id: 1})
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "api keys without a user cannot name the submitter",
+			key:  "ak_admin",
+			call: func(ctx context.Context) (any, error) {
+				return invoices.SubmitInvoice(ctx, &approvalsv1.SubmitInvoiceRequest{Invoice: &approvalsv1.Invoice{
+					Amount:      &approvalsv1.Money{Cents: 1500000, Currency: "USD"},
+					SubmittedBy: "amanda@acme.com",
+				}})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "api keys cannot create companies",
+			key:  "ak_admin",
+			call: func(ctx context.Context) (any, error) {
+				return client.CreateCompany(ctx, &approvalsv1.CreateCompanyRequest{Company: &approvalsv1.Company{Name: "Globex"}})
+			},
+			wantCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.key != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+test.key)
+			}
+
+			got, err := test.call(ctx)
+			if test.wantCode != codes.OK {
+				if status.Code(err) != test.wantCode {
+					t.Errorf("error = %v, want code %s", err, test.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("response mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+
+	// Health checks need no API key.
+	if _, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("Check() unexpected error: %v", err)
+	}
+}
+
+// mockAuthenticator authenticates the API keys of principals.
+type mockAuthenticator struct {
+	principals map[string]auth.Principal
+}
+
+func (m *mockAuthenticator) Authenticate(key string) (auth.Principal, error) {
+	principal, ok := m.principals[key]
+	if !ok {
+		return auth.Principal{}, fmt.Errorf("%w: unknown api key", auth.ErrInvalidAPIKey)
+	}
+	return principal, nil
+}
@@ -0,0 +1,240 @@
+package grpc
+
+import (
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/grpc/approvalsv1"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// approvalStatuses are the protobuf approval statuses by api status.
+var approvalStatuses = map[api.ApprovalStatus]approvalsv1.ApprovalStatus{
+	api.ApprovalStatusPending:  approvalsv1.ApprovalStatus_APPROVAL_STATUS_PENDING,
+	api.ApprovalStatusApproved: approvalsv1.ApprovalStatus_APPROVAL_STATUS_APPROVED,
+	api.ApprovalStatusRejected: approvalsv1.ApprovalStatus_APPROVAL_STATUS_REJECTED,
+}
+
+// bounds are the protobuf bounds by api bound.
+var bounds = map[api.Bound]approvalsv1.Bound{
+	api.BoundInclusive: approvalsv1.Bound_BOUND_INCLUSIVE,
+	api.BoundExclusive: approvalsv1.Bound_BOUND_EXCLUSIVE,
+}
+
+// outboxStatuses are the protobuf outbox statuses by api status.
+var outboxStatuses = map[api.OutboxStatus]approvalsv1.OutboxStatus{
+	api.OutboxStatusPending:   approvalsv1.OutboxStatus_OUTBOX_STATUS_PENDING,
+	api.OutboxStatusDelivered: approvalsv1.OutboxStatus_OUTBOX_STATUS_DELIVERED,
+	api.OutboxStatusDead:      approvalsv1.OutboxStatus_OUTBOX_STATUS_DEAD,
+}
+
+// reverse returns the keys of m by value.
+func reverse[K comparable, V comparable](m map[K]V) map[V]K {
+	reversed := make(map[V]K, len(m))
+	for k, v := range m {
+		reversed[v] = k
+	}
+	return reversed
+}
+
+var (
+	apiBounds         = reverse(bounds)
+	apiOutboxStatuses = reverse(outboxStatuses)
+)
+
+func toMoney(m *approvalsv1.Money) money.Money {
+	return money.Money{Cents: m.GetCents(), Currency: money.Currency(m.GetCurrency())}
+}
+
+func toMoneyPtr(m *approvalsv1.Money) *money.Money {
+	if m == nil {
+		return nil
+	}
+	amount := toMoney(m)
+	return &amount
+}
+
+func fromMoney(m *money.Money) *approvalsv1.Money {
+	if m == nil {
+		return nil
+	}
+	return &approvalsv1.Money{Cents: m.Cents, Currency: string(m.Currency)}
+}
+
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func toInvoiceRequest(invoice *approvalsv1.Invoice) api.InvoiceRequest {
+	return api.InvoiceRequest{
+		CompanyName:               invoice.GetCompanyName(),
+		Amount:                    toMoney(invoice.GetAmount()),
+		Department:                invoice.GetDepartment(),
+		IsManagerApprovalRequired: invoice.GetIsManagerApprovalRequired(),
+		Vendor:                    invoice.GetVendor(),
+		Description:               invoice.GetDescription(),
+		DueDate:                   invoice.GetDueDate(),
+		SubmittedBy:               invoice.GetSubmittedBy(),
+	}
+}
+
+func fromApprovalResponse(resp api.ApprovalResponse) *approvalsv1.SubmitInvoiceResponse {
+	return &approvalsv1.SubmitInvoiceResponse{
+		ApprovalRequestId: int64(resp.ApprovalRequestID),
+		ApproverName:      resp.ApproverName,
+		ApproverRole:      resp.ApproverRole,
+		ApproverChannel:   resp.ApproverChannel,
+		ApproverContactId: resp.ApproverContactID,
+		ConversationId:    resp.ConversationID,
+		MessageId:         resp.MessageID,
+		Queued:            resp.Queued,
+		Duplicate:         resp.Duplicate,
+		DigestAt:          toTimestamp(resp.DigestAt),
+	}
+}
+
+func fromApprovalRequestStatus(status api.ApprovalRequestStatus) *approvalsv1.ApprovalStatusChange {
+	return &approvalsv1.ApprovalStatusChange{
+		ApprovalRequestId: int64(status.ApprovalRequestID),
+		Status:            approvalStatuses[status.Status],
+		DeliveredChannel:  status.DeliveredChannel,
+		DecidedBy:         status.DecidedBy,
+		DecidedAt:         toTimestamp(status.DecidedAt),
+	}
+}
+
+func toCompany(company *approvalsv1.Company) api.Company {
+	return api.Company{
+		ID:               int(company.GetId()),
+		Name:             company.GetName(),
+		Departments:      company.GetDepartments(),
+		FallbackChannels: company.GetFallbackChannels(),
+	}
+}
+
+func fromCompany(company api.Company) *approvalsv1.Company {
+	return &approvalsv1.Company{
+		Id:               int64(company.ID),
+		Name:             company.Name,
+		Departments:      company.Departments,
+		FallbackChannels: company.FallbackChannels,
+	}
+}
+
+func toWorkflowRule(rule *approvalsv1.WorkflowRule) api.WorkflowRule {
+	r := api.WorkflowRule{
+		ID:               int(rule.GetId()),
+		CompanyID:        int(rule.GetCompanyId()),
+		MinAmount:        toMoneyPtr(rule.GetMinAmount()),
+		MaxAmount:        toMoneyPtr(rule.GetMaxAmount()),
+		MinBound:         apiBounds[rule.GetMinBound()],
+		MaxBound:         apiBounds[rule.GetMaxBound()],
+		Department:       rule.Department,
+		ApproverID:       int(rule.GetApproverId()),
+		ApprovalChannel:  rule.GetApprovalChannel(),
+		FallbackChannels: rule.GetFallbackChannels(),
+		ManagerLevels:    int(rule.GetManagerLevels()),
+	}
+	if rule.GetIsManagerApprovalRequired() {
+		r.IsManagerApprovalRequired = 1
+	}
+	return r
+}
+
+func fromWorkflowRule(rule api.WorkflowRule) *approvalsv1.WorkflowRule {
+	return &approvalsv1.WorkflowRule{
+		Id:                        int64(rule.ID),
+		CompanyId:                 int64(rule.CompanyID),
+		MinAmount:                 fromMoney(rule.MinAmount),
+		MaxAmount:                 fromMoney(rule.MaxAmount),
+		MinBound:                  bounds[rule.MinBound],
+		MaxBound:                  bounds[rule.MaxBound],
+		Department:                rule.Department,
+		IsManagerApprovalRequired: rule.IsManagerApprovalRequired != 0,
+		ApproverId:                int64(rule.ApproverID),
+		ApprovalChannel:           rule.ApprovalChannel,
+		FallbackChannels:          rule.FallbackChannels,
+		ManagerLevels:             int32(rule.ManagerLevels),
+	}
+}
+
+func toApprover(approver *approvalsv1.Approver) api.Approver {
+	return api.Approver{
+		ID:                     int(approver.GetId()),
+		CompanyID:              int(approver.GetCompanyId()),
+		Name:                   approver.GetName(),
+		Role:                   approver.GetRole(),
+		Email:                  approver.GetEmail(),
+		SlackID:                approver.GetSlackId(),
+		TeamsID:                approver.GetTeamsId(),
+		Locale:                 approver.GetLocale(),
+		NotificationPreference: approver.GetNotificationPreference(),
+		AlternateApproverID:    int(approver.GetAlternateApproverId()),
+		ManagerID:              int(approver.GetManagerId()),
+	}
+}
+
+func fromApprover(approver api.Approver) *approvalsv1.Approver {
+	return &approvalsv1.Approver{
+		Id:                     int64(approver.ID),
+		CompanyId:              int64(approver.CompanyID),
+		Name:                   approver.Name,
+		Role:                   approver.Role,
+		Email:                  approver.Email,
+		SlackId:                approver.SlackID,
+		TeamsId:                approver.TeamsID,
+		Locale:                 approver.Locale,
+		NotificationPreference: approver.NotificationPreference,
+		AlternateApproverId:    int64(approver.AlternateApproverID),
+		ManagerId:              int64(approver.ManagerID),
+	}
+}
+
+func fromOrgChartNode(node api.OrgChartNode) *approvalsv1.OrgChartNode {
+	return &approvalsv1.OrgChartNode{
+		Approver: fromApprover(node.Approver),
+		Reports:  convert(node.Reports, fromOrgChartNode),
+	}
+}
+
+func fromDeliveryAttempt(attempt api.DeliveryAttempt) *approvalsv1.DeliveryAttempt {
+	return &approvalsv1.DeliveryAttempt{
+		CompanyId:         int64(attempt.CompanyID),
+		ApprovalRequestId: int64(attempt.ApprovalRequestID),
+		Channel:           attempt.Channel,
+		IdempotencyKey:    attempt.IdempotencyKey,
+		Attempt:           int32(attempt.Attempt),
+		StatusCode:        int32(attempt.StatusCode),
+		Error:             attempt.Error,
+		Succeeded:         attempt.Succeeded,
+		AttemptedAt:       toTimestamp(&attempt.AttemptedAt),
+	}
+}
+
+func fromOutboxMessage(message api.OutboxMessage) *approvalsv1.OutboxMessage {
+	return &approvalsv1.OutboxMessage{
+		Id:                int64(message.ID),
+		CompanyId:         int64(message.CompanyID),
+		ApprovalRequestId: int64(message.ApprovalRequestID),
+		Channel:           message.Channel,
+		Status:            outboxStatuses[message.Status],
+		Attempts:          int32(message.Attempts),
+		NextAttemptAt:     toTimestamp(&message.NextAttemptAt),
+		LastError:         message.LastError,
+		CreatedAt:         toTimestamp(&message.CreatedAt),
+		DeliveredAt:       toTimestamp(message.DeliveredAt),
+	}
+}
+
+// convert converts the items with the conversion function.
+func convert[T, U any](items []T, conversion func(T) U) []U {
+	converted := make([]U, len(items))
+	for i, item := range items {
+		converted[i] = conversion(item)
+	}
+	return converted
+}
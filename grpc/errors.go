@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorMapping maps the errors matching err with errors.Is to a status
+// code.
+type errorMapping struct {
+	err  error
+	code codes.Code
+}
+
+// errorMappings are the typed errors of the services and the status codes
+// they are reported with, in the order they are matched. Validation errors
+// and errors not listed are handled by toStatus.
+var errorMappings = []errorMapping{
+	// Entities of other companies are reported as not found, so that their
+	// existence is not disclosed.
+	{err: db.ErrCrossCompanyAccess, code: codes.NotFound},
+	{err: db.ErrCompanyNotFound, code: codes.NotFound},
+	{err: db.ErrDepartmentNotFound, code: codes.NotFound},
+	{err: db.ErrWorkflowRuleNotFound, code: codes.NotFound},
+	{err: db.ErrApproverNotFound, code: codes.NotFound},
+	{err: db.ErrApprovalRequestNotFound, code: codes.NotFound},
+	{err: db.ErrOutboxMessageNotFound, code: codes.NotFound},
+	{err: db.ErrCompanyAlreadyExists, code: codes.AlreadyExists},
+	{err: db.ErrDepartmentAlreadyExists, code: codes.AlreadyExists},
+	{err: db.ErrWorkflowRuleAlreadyExists, code: codes.AlreadyExists},
+	{err: db.ErrApproverAlreadyExists, code: codes.AlreadyExists},
+	{err: db.ErrCompanyNotEmpty, code: codes.FailedPrecondition},
+	{err: db.ErrApprovalRequestAlreadyDecided, code: codes.FailedPrecondition},
+	{err: management.ErrDepartmentInUse, code: codes.FailedPrecondition},
+	{err: management.ErrNotDeadLetter, code: codes.FailedPrecondition},
+	{err: api.ErrPermissionDenied, code: codes.PermissionDenied},
+	{err: api.ErrMissingField, code: codes.InvalidArgument},
+	{err: api.ErrMissingContact, code: codes.InvalidArgument},
+	{err: api.ErrUnsupportedLocale, code: codes.InvalidArgument},
+	{err: api.ErrUnsupportedNotificationPreference, code: codes.InvalidArgument},
+	{err: api.ErrMissingCompanyName, code: codes.InvalidArgument},
+	{err: api.ErrMissingDepartmentName, code: codes.InvalidArgument},
+	{err: api.ErrInvalidApprovalChannel, code: codes.InvalidArgument},
+	{err: api.ErrInvalidAmountRange, code: codes.InvalidArgument},
+	{err: api.ErrInvalidBound, code: codes.InvalidArgument},
+	{err: api.ErrInvalidDueDate, code: codes.InvalidArgument},
+	{err: db.ErrInvalidOutboxStatus, code: codes.InvalidArgument},
+	{err: money.ErrInvalidAmount, code: codes.InvalidArgument},
+	{err: money.ErrInvalidCurrency, code: codes.InvalidArgument},
+	{err: workflow.ErrUnreachableApprover, code: codes.FailedPrecondition},
+	{err: workflow.ErrUnsupportedApprovalChannel, code: codes.FailedPrecondition},
+	{err: workflow.ErrNoIndependentApprover, code: codes.FailedPrecondition},
+	{err: workflow.ErrDeadLettered, code: codes.Unavailable},
+}
+
+// toStatus returns the status an error is reported with. Validation errors
+// are reported as InvalidArgument with a BadRequest detail listing the
+// invalid fields. Errors that are not typed are reported as Internal,
+// without their message.
+func toStatus(err error) *status.Status {
+	if s, ok := status.FromError(err); ok {
+		return s
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err)
+	}
+
+	var verr *api.ValidationError
+	if errors.As(err, &verr) {
+		s := status.New(codes.InvalidArgument, api.ErrValidation.Error())
+		details := &errdetails.BadRequest{}
+		for _, field := range verr.Fields {
+			details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Description: field.Err.Error(),
+			})
+		}
+		if withDetails, err := s.WithDetails(details); err == nil {
+			return withDetails
+		}
+		return status.New(codes.InvalidArgument, verr.Error())
+	}
+
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.err) {
+			return status.New(mapping.code, err.Error())
+		}
+	}
+
+	return status.New(codes.Internal, "internal error")
+}
+
+// toError returns the status error of err, logging the errors reported as
+// internal.
+func (s *Server) toError(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	st := toStatus(err)
+	if st.Code() == codes.Internal || st.Code() == codes.Unknown {
+		s.log.Error("gRPC call failed", "method", method, "code", st.Code().String(), "error", err)
+	}
+	return st.Err()
+}
+
+// unaryErrors reports the errors of unary handlers with their status, and
+// panics as internal errors instead of crashing the server.
+func (s *Server) unaryErrors(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			resp, err = nil, fmt.Errorf("panic calling %s: %v\n%s", info.FullMethod, v, debug.Stack())
+		}
+		err = s.toError(info.FullMethod, err)
+	}()
+	return handler(ctx, req)
+}
+
+// streamErrors reports the errors of streaming handlers with their status,
+// and panics as internal errors instead of crashing the server.
+func (s *Server) streamErrors(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic calling %s: %v\n%s", info.FullMethod, v, debug.Stack())
+		}
+		err = s.toError(info.FullMethod, err)
+	}()
+	return handler(srv, stream)
+}
@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/auth"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/grpc/approvalsv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// invoiceServer implements the InvoiceService.
+type invoiceServer struct {
+	approvalsv1.UnimplementedInvoiceServiceServer
+	*Server
+}
+
+// SubmitInvoice processes an invoice through the approval workflow.
+// Invoices that no workflow rule matches are reported as
+// FailedPrecondition.
+func (s *invoiceServer) SubmitInvoice(ctx context.Context, req *approvalsv1.SubmitInvoiceRequest) (*approvalsv1.SubmitInvoiceResponse, error) {
+	if req.GetInvoice() == nil {
+		return nil, status.Error(codes.InvalidArgument, "invoice is required")
+	}
+
+	invoice := toInvoiceRequest(req.GetInvoice())
+	if principal, ok := auth.FromContext(ctx); ok {
+		if err := principal.CheckSubmitter(invoice); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.workflowFor(ctx).ProcessInvoice(invoice)
+	if errors.Is(err, db.ErrWorkflowRuleNotFound) {
+		return nil, status.Errorf(codes.FailedPrecondition, "no workflow rule matches the invoice: %v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fromApprovalResponse(resp), nil
+}
+
+// WatchApprovalStatus sends the status of the approval request, then polls
+// it and sends every change, until the request is approved or rejected or
+// the client cancels the call.
+func (s *invoiceServer) WatchApprovalStatus(req *approvalsv1.WatchApprovalStatusRequest, stream approvalsv1.InvoiceService_WatchApprovalStatusServer) error {
+	id, err := positiveID("approval_request_id", req.GetApprovalRequestId())
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.statusPollInterval)
+	defer ticker.Stop()
+
+	var last *api.ApprovalRequestStatus
+	for {
+		current, err := s.workflowFor(stream.Context()).ApprovalRequestStatus(id)
+		if err != nil {
+			return err
+		}
+		if last == nil || changed(*last, current) {
+			if err := stream.Send(fromApprovalRequestStatus(current)); err != nil {
+				return err
+			}
+			last = &current
+		}
+		if current.Decided() {
+			return nil
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// changed reports whether the status of an approval request changed.
+func changed(last, current api.ApprovalRequestStatus) bool {
+	return last.Status != current.Status ||
+		last.DeliveredChannel != current.DeliveredChannel ||
+		last.DecidedBy != current.DecidedBy
+}
+
+// positiveID converts an ID field of a request. If it is not a positive
+// integer, it returns an InvalidArgument error.
+func positiveID(field string, id int64) (int, error) {
+	if id <= 0 {
+		return 0, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid %s %d: must be a positive integer", field, id))
+	}
+	return int(id), nil
+}
@@ -0,0 +1,270 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/auth"
+	"github.com/KatrinSalt/backend-challenge-go/grpc/approvalsv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// managementServer implements the ManagementService. Updates replace the
+// entity and return it as stored.
+type managementServer struct {
+	approvalsv1.UnimplementedManagementServiceServer
+	*Server
+}
+
+// CreateCompany creates a company. API keys belong to a company, so they
+// cannot create other companies.
+func (s *managementServer) CreateCompany(ctx context.Context, req *approvalsv1.CreateCompanyRequest) (*approvalsv1.Company, error) {
+	if _, ok := auth.FromContext(ctx); ok {
+		return nil, status.Error(codes.PermissionDenied, "API keys cannot create companies")
+	}
+	if req.GetCompany() == nil {
+		return nil, status.Error(codes.InvalidArgument, "company is required")
+	}
+	created, err := s.managementFor(ctx).CreateCompany(toCompany(req.GetCompany()))
+	if err != nil {
+		return nil, err
+	}
+	return fromCompany(created), nil
+}
+
+func (s *managementServer) GetCompany(ctx context.Context, req *approvalsv1.GetCompanyRequest) (*approvalsv1.Company, error) {
+	id, err := positiveID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCompany(ctx, id); err != nil {
+		return nil, err
+	}
+	company, err := s.managementFor(ctx).GetCompanyByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return fromCompany(company), nil
+}
+
+func (s *managementServer) UpdateCompany(ctx context.Context, req *approvalsv1.UpdateCompanyRequest) (*approvalsv1.Company, error) {
+	id, err := positiveID("company.id", req.GetCompany().GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCompany(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.managementFor(ctx).UpdateCompany(toCompany(req.GetCompany())); err != nil {
+		return nil, err
+	}
+	return s.GetCompany(ctx, &approvalsv1.GetCompanyRequest{Id: int64(id)})
+}
+
+func (s *managementServer) DeleteCompany(ctx context.Context, req *approvalsv1.DeleteCompanyRequest) (*emptypb.Empty, error) {
+	id, err := positiveID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCompany(ctx, id); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, s.managementFor(ctx).DeleteCompany(id)
+}
+
+// ListCompanies lists the companies. An API key only has access to its own
+// company.
+func (s *managementServer) ListCompanies(ctx context.Context, req *approvalsv1.ListCompaniesRequest) (*approvalsv1.ListCompaniesResponse, error) {
+	if principal, ok := auth.FromContext(ctx); ok {
+		company, err := s.managementFor(ctx).GetCompanyByID(principal.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+		return &approvalsv1.ListCompaniesResponse{Companies: []*approvalsv1.Company{fromCompany(company)}}, nil
+	}
+	companies, err := s.managementFor(ctx).ListCompanies()
+	if err != nil {
+		return nil, err
+	}
+	return &approvalsv1.ListCompaniesResponse{Companies: convert(companies, fromCompany)}, nil
+}
+
+func (s *managementServer) AddDepartment(ctx context.Context, req *approvalsv1.AddDepartmentRequest) (*approvalsv1.Department, error) {
+	name, err := s.managementFor(ctx).AddDepartment(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return &approvalsv1.Department{Name: name}, nil
+}
+
+func (s *managementServer) RemoveDepartment(ctx context.Context, req *approvalsv1.RemoveDepartmentRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, s.managementFor(ctx).RemoveDepartment(req.GetName())
+}
+
+func (s *managementServer) ListDepartments(ctx context.Context, req *approvalsv1.ListDepartmentsRequest) (*approvalsv1.ListDepartmentsResponse, error) {
+	departments, err := s.managementFor(ctx).ListDepartments()
+	if err != nil {
+		return nil, err
+	}
+	return &approvalsv1.ListDepartmentsResponse{Departments: convert(departments, func(name string) *approvalsv1.Department {
+		return &approvalsv1.Department{Name: name}
+	})}, nil
+}
+
+func (s *managementServer) CreateWorkflowRule(ctx context.Context, req *approvalsv1.CreateWorkflowRuleRequest) (*approvalsv1.WorkflowRule, error) {
+	if req.GetRule() == nil {
+		return nil, status.Error(codes.InvalidArgument, "rule is required")
+	}
+	created, err := s.managementFor(ctx).CreateWorkflowRule(toWorkflowRule(req.GetRule()))
+	if err != nil {
+		return nil, err
+	}
+	return fromWorkflowRule(created), nil
+}
+
+func (s *managementServer) GetWorkflowRule(ctx context.Context, req *approvalsv1.GetWorkflowRuleRequest) (*approvalsv1.WorkflowRule, error) {
+	id, err := positiveID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	rule, err := s.managementFor(ctx).GetWorkflowRuleByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return fromWorkflowRule(rule), nil
+}
+
+func (s *managementServer) UpdateWorkflowRule(ctx context.Context, req *approvalsv1.UpdateWorkflowRuleRequest) (*approvalsv1.WorkflowRule, error) {
+	id, err := positiveID("rule.id", req.GetRule().GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.managementFor(ctx).UpdateWorkflowRule(toWorkflowRule(req.GetRule())); err != nil {
+		return nil, err
+	}
+	return s.GetWorkflowRule(ctx, &approvalsv1.GetWorkflowRuleRequest{Id: int64(id)})
+}
+
+func (s *managementServer) DeleteWorkflowRule(ctx context.Context, req *approvalsv1.DeleteWorkflowRuleRequest) (*emptypb.Empty, error) {
+	id, err := positiveID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, s.managementFor(ctx).DeleteWorkflowRule(id)
+}
+
+func (s *managementServer) ListWorkflowRules(ctx context.Context, req *approvalsv1.ListWorkflowRulesRequest) (*approvalsv1.ListWorkflowRulesResponse, error) {
+	rules, err := s.managementFor(ctx).ListWorkflowRules()
+	if err != nil {
+		return nil, err
+	}
+	return &approvalsv1.ListWorkflowRulesResponse{Rules: convert(rules, fromWorkflowRule)}, nil
+}
+
+func (s *managementServer) CreateApprover(ctx context.Context, req *approvalsv1.CreateApproverRequest) (*approvalsv1.Approver, error) {
+	if req.GetApprover() == nil {
+		return nil, status.Error(codes.InvalidArgument, "approver is required")
+	}
+	created, err := s.managementFor(ctx).CreateApprover(toApprover(req.GetApprover()))
+	if err != nil {
+		return nil, err
+	}
+	return fromApprover(created), nil
+}
+
+func (s *managementServer) GetApprover(ctx context.Context, req *approvalsv1.GetApproverRequest) (*approvalsv1.Approver, error) {
+	id, err := positiveID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	approver, err := s.managementFor(ctx).GetApproverByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return fromApprover(approver), nil
+}
+
+func (s *managementServer) UpdateApprover(ctx context.Context, req *approvalsv1.UpdateApproverRequest) (*approvalsv1.Approver, error) {
+	id, err := positiveID("approver.id", req.GetApprover().GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.managementFor(ctx).UpdateApprover(toApprover(req.GetApprover())); err != nil {
+		return nil, err
+	}
+	return s.GetApprover(ctx, &approvalsv1.GetApproverRequest{Id: int64(id)})
+}
+
+func (s *managementServer) DeleteApprover(ctx context.Context, req *approvalsv1.DeleteApproverRequest) (*emptypb.Empty, error) {
+	id, err := positiveID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, s.managementFor(ctx).DeleteApprover(id)
+}
+
+func (s *managementServer) ListApprovers(ctx context.Context, req *approvalsv1.ListApproversRequest) (*approvalsv1.ListApproversResponse, error) {
+	approvers, err := s.managementFor(ctx).ListApprovers()
+	if err != nil {
+		return nil, err
+	}
+	return &approvalsv1.ListApproversResponse{Approvers: convert(approvers, fromApprover)}, nil
+}
+
+func (s *managementServer) GetOrgChart(ctx context.Context, req *approvalsv1.GetOrgChartRequest) (*approvalsv1.GetOrgChartResponse, error) {
+	var id int
+	if req.GetApproverId() != 0 {
+		var err error
+		if id, err = positiveID("approver_id", req.GetApproverId()); err != nil {
+			return nil, err
+		}
+	}
+	nodes, err := s.managementFor(ctx).OrgChart(id)
+	if err != nil {
+		return nil, err
+	}
+	return &approvalsv1.GetOrgChartResponse{Nodes: convert(nodes, fromOrgChartNode)}, nil
+}
+
+func (s *managementServer) ListDeliveryAttempts(ctx context.Context, req *approvalsv1.ListDeliveryAttemptsRequest) (*approvalsv1.ListDeliveryAttemptsResponse, error) {
+	id, err := positiveID("approval_request_id", req.GetApprovalRequestId())
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.managementFor(ctx).ListDeliveryAttempts(id)
+	if err != nil {
+		return nil, err
+	}
+	return &approvalsv1.ListDeliveryAttemptsResponse{Attempts: convert(attempts, fromDeliveryAttempt)}, nil
+}
+
+// ListOutboxMessages lists the notifications in the outbox with the status,
+// dead letters by default.
+func (s *managementServer) ListOutboxMessages(ctx context.Context, req *approvalsv1.ListOutboxMessagesRequest) (*approvalsv1.ListOutboxMessagesResponse, error) {
+	outboxStatus := api.OutboxStatusDead
+	if req.GetStatus() != approvalsv1.OutboxStatus_OUTBOX_STATUS_UNSPECIFIED {
+		var ok bool
+		if outboxStatus, ok = apiOutboxStatuses[req.GetStatus()]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid status %d", req.GetStatus())
+		}
+	}
+	messages, err := s.managementFor(ctx).ListOutboxMessages(outboxStatus)
+	if err != nil {
+		return nil, err
+	}
+	return &approvalsv1.ListOutboxMessagesResponse{Messages: convert(messages, fromOutboxMessage)}, nil
+}
+
+func (s *managementServer) ReplayDeadLetter(ctx context.Context, req *approvalsv1.ReplayDeadLetterRequest) (*approvalsv1.OutboxMessage, error) {
+	id, err := positiveID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	message, err := s.managementFor(ctx).ReplayDeadLetter(id)
+	if err != nil {
+		return nil, err
+	}
+	return fromOutboxMessage(message), nil
+}
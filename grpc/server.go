@@ -0,0 +1,203 @@
+// Package grpc serves invoice submission and the management operations
+// over gRPC, as the approvals.v1 services of the approvalsv1 package.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/common"
+	"github.com/KatrinSalt/backend-challenge-go/grpc/approvalsv1"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+const (
+	// defaultAddr is the address the server listens on by default.
+	defaultAddr = ":9090"
+	// defaultShutdownTimeout is how long in-flight calls are given to
+	// complete when the server shuts down.
+	defaultShutdownTimeout = 10 * time.Second
+	// defaultStatusPollInterval is how often the status of a watched
+	// approval request is checked for changes.
+	defaultStatusPollInterval = time.Second
+)
+
+// Server serves the InvoiceService and ManagementService of approvals.v1,
+// with the gRPC health and reflection services.
+type Server struct {
+	log                common.Logger
+	management         management.Service
+	workflow           workflow.Service
+	addr               string
+	shutdownTimeout    time.Duration
+	statusPollInterval time.Duration
+	grpc               *grpc.Server
+	health             *health.Server
+	authenticator      Authenticator
+	companyServices    CompanyServicesFunc
+}
+
+// Options holds the configuration for the server.
+type Options struct {
+	Logger          common.Logger
+	Addr            string
+	ShutdownTimeout time.Duration
+	// StatusPollInterval is how often WatchApprovalStatus checks the
+	// approval request for changes.
+	StatusPollInterval time.Duration
+	// Authenticator, if set, authenticates the API keys calls are made
+	// with, and CompanyServices returns the services of the company of a
+	// key.
+	Authenticator   Authenticator
+	CompanyServices CompanyServicesFunc
+}
+
+// Option is a function that configures the server.
+type Option func(*Server)
+
+// NewServer returns a gRPC server for the company of the management and
+// workflow services, listening on :9090 by default. With authentication,
+// calls are served for the company of their API key instead, sent as a
+// bearer token in the authorization metadata.
+func NewServer(managementSvc management.Service, workflowSvc workflow.Service, options ...Option) (*Server, error) {
+	if managementSvc == nil {
+		return nil, errors.New("management service is required to start the gRPC server")
+	}
+	if workflowSvc == nil {
+		return nil, errors.New("workflow service is required to start the gRPC server")
+	}
+
+	s := &Server{
+		management:         managementSvc,
+		workflow:           workflowSvc,
+		addr:               defaultAddr,
+		shutdownTimeout:    defaultShutdownTimeout,
+		statusPollInterval: defaultStatusPollInterval,
+		health:             health.NewServer(),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if s.log == nil {
+		s.log = common.NewLogger()
+	}
+	if s.authenticator != nil && s.companyServices == nil {
+		return nil, errors.New("company services are required to authenticate API keys")
+	}
+
+	s.grpc = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryErrors, s.unaryAuth),
+		grpc.ChainStreamInterceptor(s.streamErrors, s.streamAuth),
+	)
+	approvalsv1.RegisterInvoiceServiceServer(s.grpc, &invoiceServer{Server: s})
+	approvalsv1.RegisterManagementServiceServer(s.grpc, &managementServer{Server: s})
+	healthpb.RegisterHealthServer(s.grpc, s.health)
+	reflection.Register(s.grpc)
+
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	for name := range s.grpc.GetServiceInfo() {
+		s.health.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	return s, nil
+}
+
+// Serve serves gRPC on the listener until Stop is called.
+func (s *Server) Serve(listener net.Listener) error {
+	return s.grpc.Serve(listener)
+}
+
+// ListenAndServe serves gRPC until the context is cancelled, then shuts the
+// server down gracefully, giving in-flight calls the shutdown timeout to
+// complete before they are cancelled. ready, if not nil, is called with the
+// address the server listens on once it accepts connections.
+func (s *Server) ListenAndServe(ctx context.Context, ready func(addr net.Addr)) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- s.Serve(listener)
+	}()
+	s.log.Info("gRPC server listening", "addr", listener.Addr().String())
+	if ready != nil {
+		ready(listener.Addr())
+	}
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.log.Info("gRPC server shutting down", "timeout", s.shutdownTimeout.String())
+	s.Stop()
+	return <-errs
+}
+
+// Stop reports the services as not serving and stops the server
+// gracefully, cancelling the calls still in flight after the shutdown
+// timeout.
+func (s *Server) Stop() {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(s.shutdownTimeout):
+		s.grpc.Stop()
+		<-stopped
+	}
+}
+
+// WithOptions configures the server with the given Options.
+func WithOptions(options Options) Option {
+	return func(s *Server) {
+		if options.Logger != nil {
+			s.log = options.Logger
+		}
+		if options.Addr != "" {
+			s.addr = options.Addr
+		}
+		if options.ShutdownTimeout > 0 {
+			s.shutdownTimeout = options.ShutdownTimeout
+		}
+		if options.StatusPollInterval > 0 {
+			s.statusPollInterval = options.StatusPollInterval
+		}
+		if options.Authenticator != nil {
+			s.authenticator = options.Authenticator
+			s.companyServices = options.CompanyServices
+		}
+	}
+}
+
+// WithAuthentication configures the server to require API keys, which are
+// authenticated with the authenticator, and to serve calls with the
+// services of the company of the key.
+func WithAuthentication(authenticator Authenticator, companyServices CompanyServicesFunc) Option {
+	return func(s *Server) {
+		s.authenticator = authenticator
+		s.companyServices = companyServices
+	}
+}
+
+// WithLogger configures the server with the given logger.
+func WithLogger(logger common.Logger) Option {
+	return func(s *Server) {
+		s.log = logger
+	}
+}
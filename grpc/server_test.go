@@ -0,0 +1,426 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/grpc/approvalsv1"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestServer_ManagementService(t *testing.T) {
+	approver := api.Approver{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com"}
+	pbApprover := &approvalsv1.Approver{Id: 3, CompanyId: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com"}
+
+	tests := []struct {
+		name       string
+		management *mockManagementService
+		call       func(ctx context.Context, client approvalsv1.ManagementServiceClient) (proto.Message, error)
+		want       proto.Message
+		wantCode   codes.Code
+		wantFields map[string]string
+	}{
+		{
+			name:       "get approver",
+			management: &mockManagementService{approver: approver},
+			call: func(ctx context.Context, client approvalsv1.ManagementServiceClient) (proto.Message, error) {
+				return client.GetApprover(ctx, &approvalsv1.GetApproverRequest{Id: 3})
+			},
+			want: pbApprover,
+		},
+		{
+			name:       "approver not found",
+			management: &mockManagementService{err: fmt.Errorf("failed to get approver: %w", db.ErrApproverNotFound)},
+			call: func(ctx context.Context, client approvalsv1.ManagementServiceClient) (proto.Message, error) {
+				return client.GetApprover(ctx, &approvalsv1.GetApproverRequest{Id: 4})
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:       "invalid id",
+			management: &mockManagementService{},
+			call: func(ctx context.Context, client approvalsv1.ManagementServiceClient) (proto.Message, error) {
+				return client.DeleteApprover(ctx, &approvalsv1.DeleteApproverRequest{})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:       "update approver",
+			management: &mockManagementService{approver: approver},
+			call: func(ctx context.Context, client approvalsv1.ManagementServiceClient) (proto.Message, error) {
+				return client.UpdateApprover(ctx, &approvalsv1.UpdateApproverRequest{Approver: pbApprover})
+			},
+			want: pbApprover,
+		},
+		{
+			name:       "empty list",
+			management: &mockManagementService{},
+			call: func(ctx context.Context, client approvalsv1.ManagementServiceClient) (proto.Message, error) {
+				return client.ListApprovers(ctx, &approvalsv1.ListApproversRequest{})
+			},
+			want: &approvalsv1.ListApproversResponse{},
+		},
+		{
+			name:       "org chart",
+			management: &mockManagementService{approver: approver},
+			call: func(ctx context.Context, client approvalsv1.ManagementServiceClient) (proto.Message, error) {
+				return client.GetOrgChart(ctx, &approvalsv1.GetOrgChartRequest{ApproverId: 3})
+			},
+			want: &approvalsv1.GetOrgChartResponse{Nodes: []*approvalsv1.OrgChartNode{{Approver: pbApprover}}},
+		},
+		{
+			name: "create workflow rule fails validation",
+			management: &mockManagementService{err: &api.ValidationError{Fields: []*api.FieldError{
+				{Field: "approver_id", Err: management.ErrUnknownApprover},
+			}}},
+			call: func(ctx context.Context, client approvalsv1.ManagementServiceClient) (proto.Message, error) {
+				return client.CreateWorkflowRule(ctx, &approvalsv1.CreateWorkflowRuleRequest{Rule: &approvalsv1.WorkflowRule{ApproverId: 9, ApprovalChannel: "slack"}})
+			},
+			wantCode:   codes.InvalidArgument,
+			wantFields: map[string]string{"approver_id": "unknown approver"},
+		},
+		{
+			name:       "department in use",
+			management: &mockManagementService{err: fmt.Errorf("failed to remove department Finance: %w", management.ErrDepartmentInUse)},
+			call: func(ctx context.Context, client approvalsv1.ManagementServiceClient) (proto.Message, error) {
+				return client.RemoveDepartment(ctx, &approvalsv1.RemoveDepartmentRequest{Name: "Finance"})
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:       "untyped error",
+			management: &mockManagementService{err: errors.New("database is locked")},
+			call: func(ctx context.Context, client approvalsv1.ManagementServiceClient) (proto.Message, error) {
+				return client.ListApprovers(ctx, &approvalsv1.ListApproversRequest{})
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := newTestConn(t, test.management, &mockWorkflowService{})
+
+			got, err := test.call(context.Background(), approvalsv1.NewManagementServiceClient(conn))
+			if test.wantCode != codes.OK {
+				st := status.Convert(err)
+				if st.Code() != test.wantCode {
+					t.Fatalf("code = %s, want %s (%v)", st.Code(), test.wantCode, err)
+				}
+				if diff := cmp.Diff(test.wantFields, fieldViolations(st)); diff != "" {
+					t.Errorf("field violations mismatch (-want +got)\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("response mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_SubmitInvoice(t *testing.T) {
+	tests := []struct {
+		name     string
+		workflow *mockWorkflowService
+		want     *approvalsv1.SubmitInvoiceResponse
+		wantCode codes.Code
+	}{
+		{
+			name:     "submitted",
+			workflow: &mockWorkflowService{resp: api.ApprovalResponse{ApprovalRequestID: 1, ApproverName: "Sarah Johnson", ApproverRole: "CMO", ApproverChannel: "email", ApproverContactID: "sarah_johnson@light.com"}},
+			want:     &approvalsv1.SubmitInvoiceResponse{ApprovalRequestId: 1, ApproverName: "Sarah Johnson", ApproverRole: "CMO", ApproverChannel: "email", ApproverContactId: "sarah_johnson@light.com"},
+		},
+		{
+			name:     "no matching rule",
+			workflow: &mockWorkflowService{err: fmt.Errorf("failed to find matching rule: %w", db.ErrWorkflowRuleNotFound)},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "invalid invoice",
+			workflow: &mockWorkflowService{err: &api.ValidationError{Fields: []*api.FieldError{{Field: "amount", Err: money.ErrInvalidAmount}}}},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := newTestConn(t, &mockManagementService{}, test.workflow)
+
+			got, err := approvalsv1.NewInvoiceServiceClient(conn).SubmitInvoice(context.Background(), &approvalsv1.SubmitInvoiceRequest{
+				Invoice: &approvalsv1.Invoice{
+					Amount:     &approvalsv1.Money{Cents: 1500000, Currency: "USD"},
+					Department: "marketing",
+					Vendor:     "Northwind",
+					DueDate:    "2026-11-01",
+				},
+			})
+			if code := status.Code(err); code != test.wantCode {
+				t.Fatalf("code = %s, want %s (%v)", code, test.wantCode, err)
+			}
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("response mismatch (-want +got)\n%s", diff)
+			}
+
+			want := []api.InvoiceRequest{{
+				Amount:     money.New(1500000, money.USD),
+				Department: "marketing",
+				Vendor:     "Northwind",
+				DueDate:    "2026-11-01",
+			}}
+			if diff := cmp.Diff(want, test.workflow.invoices); diff != "" {
+				t.Errorf("ProcessInvoice() invoices mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_WatchApprovalStatus(t *testing.T) {
+	decidedAt := time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC)
+	wf := &mockWorkflowService{statuses: []api.ApprovalRequestStatus{
+		{ApprovalRequestID: 7, Status: api.ApprovalStatusPending},
+		{ApprovalRequestID: 7, Status: api.ApprovalStatusPending},
+		{ApprovalRequestID: 7, Status: api.ApprovalStatusPending, DeliveredChannel: "email"},
+		{ApprovalRequestID: 7, Status: api.ApprovalStatusPending, DeliveredChannel: "email"},
+		{ApprovalRequestID: 7, Status: api.ApprovalStatusApproved, DeliveredChannel: "email", DecidedBy: "amanda@light.com", DecidedAt: &decidedAt},
+	}}
+	conn := newTestConn(t, &mockManagementService{}, wf)
+
+	stream, err := approvalsv1.NewInvoiceServiceClient(conn).WatchApprovalStatus(context.Background(), &approvalsv1.WatchApprovalStatusRequest{ApprovalRequestId: 7})
+	if err != nil {
+		t.Fatalf("WatchApprovalStatus() unexpected error: %v", err)
+	}
+	var got []*approvalsv1.ApprovalStatusChange
+	for {
+		change, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() unexpected error: %v", err)
+		}
+		got = append(got, change)
+	}
+
+	want := []*approvalsv1.ApprovalStatusChange{
+		{ApprovalRequestId: 7, Status: approvalsv1.ApprovalStatus_APPROVAL_STATUS_PENDING},
+		{ApprovalRequestId: 7, Status: approvalsv1.ApprovalStatus_APPROVAL_STATUS_PENDING, DeliveredChannel: "email"},
+		{ApprovalRequestId: 7, Status: approvalsv1.ApprovalStatus_APPROVAL_STATUS_APPROVED, DeliveredChannel: "email", DecidedBy: "amanda@light.com", DecidedAt: timestamppb.New(decidedAt)},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("WatchApprovalStatus() changes mismatch (-want +got)\n%s", diff)
+	}
+}
+
+func TestServer_WatchApprovalStatus_NotFound(t *testing.T) {
+	conn := newTestConn(t, &mockManagementService{}, &mockWorkflowService{err: db.ErrApprovalRequestNotFound})
+
+	stream, err := approvalsv1.NewInvoiceServiceClient(conn).WatchApprovalStatus(context.Background(), &approvalsv1.WatchApprovalStatusRequest{ApprovalRequestId: 99})
+	if err != nil {
+		t.Fatalf("WatchApprovalStatus() unexpected error: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.NotFound {
+		t.Errorf("Recv() error = %v, want code %s", err, codes.NotFound)
+	}
+}
+
+func TestServer_HealthAndReflection(t *testing.T) {
+	srv, conn := newTestServer(t, &mockManagementService{}, &mockWorkflowService{})
+
+	health := healthpb.NewHealthClient(conn)
+	for _, service := range []string{"", "approvals.v1.InvoiceService", "approvals.v1.ManagementService"} {
+		resp, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("Check(%q) unexpected error: %v", service, err)
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("Check(%q) = %s, want SERVING", service, resp.GetStatus())
+		}
+	}
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatalf("ServerReflectionInfo() unexpected error: %v", err)
+	}
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{}}); err != nil {
+		t.Fatalf("Send() unexpected error: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() unexpected error: %v", err)
+	}
+	var services []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	want := []string{"approvals.v1.InvoiceService", "approvals.v1.ManagementService", "grpc.health.v1.Health", "grpc.reflection.v1.ServerReflection", "grpc.reflection.v1alpha.ServerReflection"}
+	if diff := cmp.Diff(want, services); diff != "" {
+		t.Errorf("reflected services mismatch (-want +got)\n%s", diff)
+	}
+
+	// Stopping the server reports the services as not serving.
+	srv.health.Shutdown()
+	resp2, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Check() unexpected error: %v", err)
+	}
+	if resp2.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Check() after shutdown = %s, want NOT_SERVING", resp2.GetStatus())
+	}
+}
+
+// newTestServer serves a server for the services over an in-memory
+// connection, and returns it with a client connection to it.
+func newTestServer(t *testing.T, managementSvc management.Service, workflowSvc workflow.Service, options ...Option) (*Server, *grpc.ClientConn) {
+	t.Helper()
+	options = append([]Option{WithOptions(Options{
+		Logger:             &mockLogger{},
+		StatusPollInterval: time.Millisecond,
+	})}, options...)
+	srv, err := NewServer(managementSvc, workflowSvc, options...)
+	if err != nil {
+		t.Fatalf("NewServer() unexpected error: %v", err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return srv, conn
+}
+
+func newTestConn(t *testing.T, managementSvc management.Service, workflowSvc workflow.Service) *grpc.ClientConn {
+	t.Helper()
+	_, conn := newTestServer(t, managementSvc, workflowSvc)
+	return conn
+}
+
+// fieldViolations returns the descriptions of the field violations of the
+// status by field.
+func fieldViolations(st *status.Status) map[string]string {
+	var violations map[string]string
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, violation := range badRequest.GetFieldViolations() {
+			if violations == nil {
+				violations = map[string]string{}
+			}
+			violations[violation.GetField()] = violation.GetDescription()
+		}
+	}
+	return violations
+}
+
+type mockManagementService struct {
+	management.Service
+	company  api.Company
+	approver api.Approver
+	err      error
+}
+
+func (m *mockManagementService) GetCompanyByID(id int) (api.Company, error) {
+	return m.company, m.err
+}
+
+func (m *mockManagementService) GetApproverByID(id int) (api.Approver, error) {
+	return m.approver, m.err
+}
+
+func (m *mockManagementService) UpdateApprover(approver api.Approver) error {
+	return m.err
+}
+
+func (m *mockManagementService) DeleteApprover(id int) error {
+	return m.err
+}
+
+func (m *mockManagementService) ListApprovers() ([]api.Approver, error) {
+	return nil, m.err
+}
+
+func (m *mockManagementService) OrgChart(id int) ([]api.OrgChartNode, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []api.OrgChartNode{{Approver: m.approver}}, nil
+}
+
+func (m *mockManagementService) CreateWorkflowRule(rule api.WorkflowRule) (api.WorkflowRule, error) {
+	return rule, m.err
+}
+
+func (m *mockManagementService) RemoveDepartment(name string) error {
+	return m.err
+}
+
+type mockWorkflowService struct {
+	workflow.Service
+	resp     api.ApprovalResponse
+	err      error
+	invoices []api.InvoiceRequest
+	// statuses are returned by ApprovalRequestStatus in order, the last
+	// one repeatedly.
+	statuses []api.ApprovalRequestStatus
+	mu       sync.Mutex
+}
+
+func (m *mockWorkflowService) ProcessInvoice(invoice api.InvoiceRequest) (api.ApprovalResponse, error) {
+	m.invoices = append(m.invoices, invoice)
+	return m.resp, m.err
+}
+
+func (m *mockWorkflowService) ApprovalRequestStatus(id int) (api.ApprovalRequestStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return api.ApprovalRequestStatus{}, m.err
+	}
+	status := m.statuses[0]
+	if len(m.statuses) > 1 {
+		m.statuses = m.statuses[1:]
+	}
+	return status, nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(msg string, args ...any)  {}
+func (m *mockLogger) Error(msg string, args ...any) {}
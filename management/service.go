@@ -1,35 +1,107 @@
 package management
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/auth"
 	"github.com/KatrinSalt/backend-challenge-go/common"
 	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+)
+
+var (
+	// ErrUnknownDepartment is returned when a department is not one of the company's departments.
+	ErrUnknownDepartment = errors.New("unknown department")
+	// ErrUnknownApprover is returned when an approver is not one of the company's approvers.
+	ErrUnknownApprover = errors.New("unknown approver")
+	// ErrDepartmentInUse is returned when removing a department still referenced by workflow rules.
+	ErrDepartmentInUse = errors.New("department is used by workflow rules")
+	// ErrUnknownChannel is returned when an approval channel is not one of the registered channels.
+	ErrUnknownChannel = errors.New("unknown approval channel")
+	// ErrUnreachableApprover is returned when an approver has no contact on
+	// any of the channels of a workflow rule they are assigned to.
+	ErrUnreachableApprover = errors.New("approver has no contact on the rule's channels")
+	// ErrNotDeadLetter is returned when a notification that is not a dead
+	// letter is replayed.
+	ErrNotDeadLetter = errors.New("notification is not a dead letter")
+	// ErrUnknownUser is returned when a user is not one of the company's
+	// users.
+	ErrUnknownUser = errors.New("unknown user")
+	// ErrOwnAlternate is returned when an approver is made their own
+	// alternate approver.
+	ErrOwnAlternate = errors.New("an approver cannot be their own alternate")
+	// ErrManagerCycle is returned when an approver would report to
+	// themselves, directly or through the approvers reporting to them.
+	ErrManagerCycle = errors.New("an approver cannot report to themselves or to one of their reports")
 )
 
 // databaseService defines the interface for database operations needed by the management service.
 type databaseService interface {
 	// Company operations
+	CreateCompany(company db.Company) (db.Company, error)
+	GetCompanyByID(id int) (db.Company, error)
 	GetCompanyByName(name string) (db.Company, error)
+	ListCompanies() ([]db.Company, error)
+	UpdateCompany(company db.Company) error
+	DeleteCompany(id int) error
+
+	// Department operations
+	CreateDepartment(department db.Department) (db.Department, error)
+	DeleteDepartment(companyID int, name string) error
+	ListDepartments(companyID int) ([]db.Department, error)
 
 	// Workflow Rule operations
 	CreateWorkflowRule(rule db.WorkflowRule) (db.WorkflowRule, error)
-	GetWorkflowRuleByID(id int) (db.WorkflowRule, error)
+	GetWorkflowRuleByID(companyID, id int) (db.WorkflowRule, error)
 	UpdateWorkflowRule(rule db.WorkflowRule) error
-	DeleteWorkflowRule(id int) error
+	DeleteWorkflowRule(companyID, id int) error
 	ListWorkflowRules(companyID int) ([]db.WorkflowRule, error)
 
 	// Approver operations
 	CreateApprover(approver db.Approver) (db.Approver, error)
-	GetApproverByID(id int) (db.Approver, error)
+	GetApproverByID(companyID, id int) (db.Approver, error)
 	UpdateApprover(approver db.Approver) error
-	DeleteApprover(id int) error
+	DeleteApprover(companyID, id int) error
 	ListApprovers(companyID int) ([]db.Approver, error)
+
+	// Delivery attempt operations
+	CreateDeliveryAttempt(attempt db.DeliveryAttempt) (db.DeliveryAttempt, error)
+	ListDeliveryAttempts(companyID, approvalRequestID int) ([]db.DeliveryAttempt, error)
+	// Notification outbox
+	GetOutboxMessageByID(companyID, id int) (db.OutboxMessage, error)
+	ListOutboxMessages(companyID int, status string) ([]db.OutboxMessage, error)
+	UpdateOutboxMessage(message db.OutboxMessage) error
+	// API keys
+	CreateAPIKey(key db.APIKey) (db.APIKey, error)
+	ListAPIKeys(companyID int) ([]db.APIKey, error)
+	RevokeAPIKey(companyID, id int, revokedAt string) error
+	// Users
+	CreateUser(user db.User) (db.User, error)
+	GetUserByID(companyID, id int) (db.User, error)
+	GetUserByEmail(companyID int, email string) (db.User, error)
+	DeleteUser(companyID, id int) error
+	ListUsers(companyID int) ([]db.User, error)
 }
 
 // Service defines the interface for management operations.
 type Service interface {
+	// Company Management
+	CreateCompany(company api.Company) (api.Company, error)
+	GetCompanyByID(id int) (api.Company, error)
+	UpdateCompany(company api.Company) error
+	DeleteCompany(id int) error
+	ListCompanies() ([]api.Company, error)
+
+	// Department Management
+	AddDepartment(name string) (string, error)
+	RemoveDepartment(name string) error
+	ListDepartments() ([]string, error)
+
 	// Workflow Rule Management
 	CreateWorkflowRule(rule api.WorkflowRule) (api.WorkflowRule, error)
 	GetWorkflowRuleByID(id int) (api.WorkflowRule, error)
@@ -43,6 +115,24 @@ type Service interface {
 	UpdateApprover(approver api.Approver) error
 	DeleteApprover(id int) error
 	ListApprovers() ([]api.Approver, error)
+	OrgChart(id int) ([]api.OrgChartNode, error)
+
+	// Delivery Attempts
+	RecordDeliveryAttempt(attempt api.DeliveryAttempt) error
+	ListDeliveryAttempts(approvalRequestID int) ([]api.DeliveryAttempt, error)
+	// Notification outbox
+	ListOutboxMessages(status api.OutboxStatus) ([]api.OutboxMessage, error)
+	GetOutboxMessageByID(id int) (api.OutboxMessage, error)
+	ReplayDeadLetter(id int) (api.OutboxMessage, error)
+	// API Keys
+	CreateAPIKey(key api.APIKey) (api.APIKey, string, error)
+	ListAPIKeys() ([]api.APIKey, error)
+	RevokeAPIKey(id int) error
+	// Users
+	CreateUser(user api.User) (api.User, error)
+	GetUserByEmail(email string) (api.User, error)
+	DeleteUser(id int) error
+	ListUsers() ([]api.User, error)
 }
 
 // service implements the management service.
@@ -50,8 +140,29 @@ type service struct {
 	logger    common.Logger
 	dbService databaseService
 	company   company
+	channels  []string
+	contacts  map[string]ContactFunc
+}
+
+// ContactFunc returns an approver's contact on a notification channel, or
+// an empty string if they have none.
+type ContactFunc func(approver api.Approver) string
+
+// Options holds the configuration for the service.
+type Options struct {
+	// Channels are the names of the registered notification channels
+	// workflow rules may use. If empty, any channel name is accepted.
+	Channels []string
+	// Contacts return the approvers' contacts on the channels, by channel
+	// name. Approvers must have a contact on at least one channel of each
+	// workflow rule they are assigned to. Channels without a contact
+	// function are assumed to reach every approver.
+	Contacts map[string]ContactFunc
 }
 
+// Option is a function that configures the service.
+type Option func(*service)
+
 // Company represents a company in the management service.
 type company struct {
 	id   int
@@ -59,7 +170,7 @@ type company struct {
 }
 
 // NewService creates a new management service.
-func NewService(logger common.Logger, dbService databaseService, companyName string) (Service, error) {
+func NewService(logger common.Logger, dbService databaseService, companyName string, options ...Option) (Service, error) {
 	if companyName == "" {
 		return nil, fmt.Errorf("company name is required")
 	}
@@ -75,20 +186,221 @@ func NewService(logger common.Logger, dbService databaseService, companyName str
 		name: dbCompany.Name,
 	}
 
-	return &service{
+	s := &service{
 		logger:    logger,
 		dbService: dbService,
 		company:   company,
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	return s, nil
+}
+
+// WithOptions configures the service with the given Options.
+func WithOptions(options Options) Option {
+	return func(s *service) {
+		if len(options.Channels) > 0 {
+			s.channels = options.Channels
+		}
+		if len(options.Contacts) > 0 {
+			s.contacts = options.Contacts
+		}
+	}
+}
+
+// WithChannels configures the names of the notification channels workflow
+// rules may use.
+func WithChannels(names ...string) Option {
+	return func(s *service) {
+		s.channels = names
+	}
+}
+
+// WithContacts configures how the approvers' contacts on the notification
+// channels are found, by channel name.
+func WithContacts(contacts map[string]ContactFunc) Option {
+	return func(s *service) {
+		s.contacts = contacts
+	}
+}
+
+// CreateCompany creates a new company together with its departments.
+func (s *service) CreateCompany(company api.Company) (api.Company, error) {
+	if err := company.Validate(); err != nil {
+		return api.Company{}, fmt.Errorf("invalid company: %w", err)
+	}
+	if err := s.checkChannels(company.FallbackChannels); err != nil {
+		return api.Company{}, fmt.Errorf("invalid company: %w", err)
+	}
+
+	name := strings.TrimSpace(company.Name)
+	if _, err := s.dbService.GetCompanyByName(name); err == nil {
+		return api.Company{}, fmt.Errorf("failed to create company: %w", db.ErrCompanyAlreadyExists)
+	} else if !errors.Is(err, db.ErrCompanyNotFound) {
+		return api.Company{}, fmt.Errorf("failed to create company: %w", err)
+	}
+
+	createdCompany, err := s.dbService.CreateCompany(db.Company{
+		Name:             name,
+		FallbackChannels: db.JoinChannels(company.FallbackChannels),
+	})
+	if err != nil {
+		return api.Company{}, fmt.Errorf("failed to create company: %w", err)
+	}
+
+	var departments []string
+	for _, department := range company.Departments {
+		createdDepartment, err := s.dbService.CreateDepartment(db.Department{
+			CompanyID: createdCompany.ID,
+			Name:      strings.TrimSpace(department),
+		})
+		if err != nil {
+			return api.Company{}, fmt.Errorf("failed to create department %s: %w", department, err)
+		}
+		departments = append(departments, createdDepartment.Name)
+	}
+
+	return api.Company{
+		ID:               createdCompany.ID,
+		Name:             createdCompany.Name,
+		Departments:      departments,
+		FallbackChannels: db.SplitChannels(createdCompany.FallbackChannels),
 	}, nil
 }
 
+// GetCompanyByID retrieves a company and its departments by the company ID.
+func (s *service) GetCompanyByID(id int) (api.Company, error) {
+	if id <= 0 {
+		return api.Company{}, fmt.Errorf("invalid company ID: %d", id)
+	}
+
+	dbCompany, err := s.dbService.GetCompanyByID(id)
+	if err != nil {
+		return api.Company{}, fmt.Errorf("failed to get company: %w", err)
+	}
+
+	return s.toAPICompany(dbCompany)
+}
+
+// UpdateCompany renames an existing company and replaces its fallback
+// channels.
+func (s *service) UpdateCompany(company api.Company) error {
+	if company.ID <= 0 {
+		return fmt.Errorf("invalid company ID: %d", company.ID)
+	}
+	if err := company.Validate(); err != nil {
+		return fmt.Errorf("invalid company: %w", err)
+	}
+	if err := s.checkChannels(company.FallbackChannels); err != nil {
+		return fmt.Errorf("invalid company: %w", err)
+	}
+
+	dbCompany := db.Company{
+		ID:               company.ID,
+		Name:             strings.TrimSpace(company.Name),
+		FallbackChannels: db.JoinChannels(company.FallbackChannels),
+	}
+
+	if err := s.dbService.UpdateCompany(dbCompany); err != nil {
+		return fmt.Errorf("failed to update company: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCompany deletes a company and its departments.
+func (s *service) DeleteCompany(id int) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid company ID: %d", id)
+	}
+
+	if err := s.dbService.DeleteCompany(id); err != nil {
+		return fmt.Errorf("failed to delete company: %w", err)
+	}
+
+	return nil
+}
+
+// ListCompanies retrieves all companies with their departments.
+func (s *service) ListCompanies() ([]api.Company, error) {
+	dbCompanies, err := s.dbService.ListCompanies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies: %w", err)
+	}
+
+	apiCompanies := make([]api.Company, len(dbCompanies))
+	for i, dbCompany := range dbCompanies {
+		apiCompany, err := s.toAPICompany(dbCompany)
+		if err != nil {
+			return nil, err
+		}
+		apiCompanies[i] = apiCompany
+	}
+
+	return apiCompanies, nil
+}
+
+// AddDepartment adds a department to the company.
+func (s *service) AddDepartment(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", api.ErrMissingDepartmentName
+	}
+
+	department, err := s.dbService.CreateDepartment(db.Department{
+		CompanyID: s.company.id,
+		Name:      name,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to add department: %w", err)
+	}
+
+	return department.Name, nil
+}
+
+// RemoveDepartment removes a department from the company. A department
+// that is still used by workflow rules cannot be removed.
+func (s *service) RemoveDepartment(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return api.ErrMissingDepartmentName
+	}
+
+	rules, err := s.dbService.ListWorkflowRules(s.company.id)
+	if err != nil {
+		return fmt.Errorf("failed to remove department: %w", err)
+	}
+	for _, rule := range rules {
+		if rule.Department != nil && strings.EqualFold(*rule.Department, name) {
+			return fmt.Errorf("failed to remove department %s: %w (rule %d)", name, ErrDepartmentInUse, rule.ID)
+		}
+	}
+
+	if err := s.dbService.DeleteDepartment(s.company.id, name); err != nil {
+		return fmt.Errorf("failed to remove department: %w", err)
+	}
+
+	return nil
+}
+
+// ListDepartments retrieves the department names of the company.
+func (s *service) ListDepartments() ([]string, error) {
+	departments, err := s.listDepartments(s.company.id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list departments: %w", err)
+	}
+	return departments, nil
+}
+
 // CreateWorkflowRule creates a new workflow rule.
 func (s *service) CreateWorkflowRule(rule api.WorkflowRule) (api.WorkflowRule, error) {
 	// Set the company ID from the service
 	rule.CompanyID = s.company.id
 
 	// Validate after setting company ID
-	if err := rule.Validate(); err != nil {
+	if err := s.validateWorkflowRule(&rule); err != nil {
 		return api.WorkflowRule{}, fmt.Errorf("invalid workflow rule: %w", err)
 	}
 
@@ -111,7 +423,7 @@ func (s *service) GetWorkflowRuleByID(id int) (api.WorkflowRule, error) {
 		return api.WorkflowRule{}, fmt.Errorf("invalid workflow rule ID: %d", id)
 	}
 
-	dbRule, err := s.dbService.GetWorkflowRuleByID(id)
+	dbRule, err := s.dbService.GetWorkflowRuleByID(s.company.id, id)
 	if err != nil {
 		return api.WorkflowRule{}, err
 	}
@@ -124,15 +436,15 @@ func (s *service) UpdateWorkflowRule(rule api.WorkflowRule) error {
 	// Set the company ID from the service
 	rule.CompanyID = s.company.id
 
-	// Validate after setting company ID
-	if err := rule.Validate(); err != nil {
-		return fmt.Errorf("invalid workflow rule: %w", err)
-	}
-
 	if rule.ID <= 0 {
 		return fmt.Errorf("invalid workflow rule ID: %d", rule.ID)
 	}
 
+	// Validate after setting company ID
+	if err := s.validateWorkflowRule(&rule); err != nil {
+		return fmt.Errorf("invalid workflow rule: %w", err)
+	}
+
 	// Convert API struct to DB struct
 	dbRule := s.apiToDBWorkflowRule(rule)
 
@@ -150,7 +462,7 @@ func (s *service) DeleteWorkflowRule(id int) error {
 		return fmt.Errorf("invalid workflow rule ID: %d", id)
 	}
 
-	if err := s.dbService.DeleteWorkflowRule(id); err != nil {
+	if err := s.dbService.DeleteWorkflowRule(s.company.id, id); err != nil {
 		return err
 	}
 
@@ -173,12 +485,21 @@ func (s *service) ListWorkflowRules() ([]api.WorkflowRule, error) {
 	return apiRules, nil
 }
 
-// CreateApprover creates a new approver.
+// CreateApprover creates a new approver. The alternate approver and the
+// manager, if set, must be among the company's approvers.
 func (s *service) CreateApprover(approver api.Approver) (api.Approver, error) {
 	if err := approver.Validate(); err != nil {
 		return api.Approver{}, fmt.Errorf("invalid approver: %w", err)
 	}
 
+	if err := s.checkAlternate(approver); err != nil {
+		return api.Approver{}, fmt.Errorf("invalid approver: %w", err)
+	}
+
+	if err := s.checkManager(approver); err != nil {
+		return api.Approver{}, fmt.Errorf("invalid approver: %w", err)
+	}
+
 	// Convert API struct to DB struct
 	dbApprover := s.apiToDBApprover(approver)
 
@@ -198,7 +519,7 @@ func (s *service) GetApproverByID(id int) (api.Approver, error) {
 		return api.Approver{}, fmt.Errorf("invalid approver ID: %d", id)
 	}
 
-	dbApprover, err := s.dbService.GetApproverByID(id)
+	dbApprover, err := s.dbService.GetApproverByID(s.company.id, id)
 	if err != nil {
 		return api.Approver{}, fmt.Errorf("failed to get approver: %w", err)
 	}
@@ -206,7 +527,9 @@ func (s *service) GetApproverByID(id int) (api.Approver, error) {
 	return s.dbToAPIApprover(dbApprover), nil
 }
 
-// UpdateApprover updates an existing approver.
+// UpdateApprover updates an existing approver. The alternate approver, if
+// set, must be another of the company's approvers. The manager, if set,
+// must be an approver of the company who does not report to this approver.
 func (s *service) UpdateApprover(approver api.Approver) error {
 	if err := approver.Validate(); err != nil {
 		return fmt.Errorf("invalid approver: %w", err)
@@ -216,6 +539,19 @@ func (s *service) UpdateApprover(approver api.Approver) error {
 		return fmt.Errorf("invalid approver ID: %d", approver.ID)
 	}
 
+	// The approver must stay reachable by the rules they are assigned to.
+	if err := s.checkAssignedRules(approver); err != nil {
+		return fmt.Errorf("invalid approver: %w", err)
+	}
+
+	if err := s.checkAlternate(approver); err != nil {
+		return fmt.Errorf("invalid approver: %w", err)
+	}
+
+	if err := s.checkManager(approver); err != nil {
+		return fmt.Errorf("invalid approver: %w", err)
+	}
+
 	// Convert API struct to DB struct
 	dbApprover := s.apiToDBApprover(approver)
 
@@ -233,7 +569,7 @@ func (s *service) DeleteApprover(id int) error {
 		return fmt.Errorf("invalid approver ID: %d", id)
 	}
 
-	if err := s.dbService.DeleteApprover(id); err != nil {
+	if err := s.dbService.DeleteApprover(s.company.id, id); err != nil {
 		return fmt.Errorf("failed to delete approver: %w", err)
 	}
 
@@ -256,17 +592,571 @@ func (s *service) ListApprovers() ([]api.Approver, error) {
 	return apiApprovers, nil
 }
 
+// OrgChart returns the reporting hierarchy of the company's approvers. If id
+// is 0, it returns every approver without a manager with the approvers
+// reporting to them, directly or indirectly. Otherwise it returns the
+// approver with that ID and the approvers reporting to them.
+func (s *service) OrgChart(id int) ([]api.OrgChartNode, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("invalid approver ID: %d", id)
+	}
+
+	approvers, err := s.dbService.ListApprovers(s.company.id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvers: %w", err)
+	}
+
+	ids := make(map[int]bool, len(approvers))
+	for _, approver := range approvers {
+		ids[approver.ID] = true
+	}
+
+	// Approvers whose manager is not one of the company's approvers are at
+	// the top, like those without a manager.
+	var roots []db.Approver
+	reports := make(map[int][]db.Approver)
+	for _, approver := range approvers {
+		if approver.ManagerID != nil && ids[*approver.ManagerID] {
+			reports[*approver.ManagerID] = append(reports[*approver.ManagerID], approver)
+		} else {
+			roots = append(roots, approver)
+		}
+	}
+
+	if id != 0 {
+		i := slices.IndexFunc(approvers, func(approver db.Approver) bool { return approver.ID == id })
+		if i < 0 {
+			return nil, fmt.Errorf("failed to get approver: %w", db.ErrApproverNotFound)
+		}
+		roots = []db.Approver{approvers[i]}
+	}
+
+	nodes := make([]api.OrgChartNode, len(roots))
+	seen := make(map[int]bool)
+	for i, root := range roots {
+		nodes[i] = s.orgChartNode(root, reports, seen)
+	}
+	return nodes, nil
+}
+
+// orgChartNode returns the node of the approver with their reports. seen
+// holds the approvers already in the chart, so that a cycle in the stored
+// hierarchy does not recurse forever.
+func (s *service) orgChartNode(approver db.Approver, reports map[int][]db.Approver, seen map[int]bool) api.OrgChartNode {
+	seen[approver.ID] = true
+	node := api.OrgChartNode{Approver: s.dbToAPIApprover(approver)}
+	for _, report := range reports[approver.ID] {
+		if !seen[report.ID] {
+			node.Reports = append(node.Reports, s.orgChartNode(report, reports, seen))
+		}
+	}
+	return node
+}
+
+// RecordDeliveryAttempt records an attempt to deliver one of the company's
+// approval requests.
+func (s *service) RecordDeliveryAttempt(attempt api.DeliveryAttempt) error {
+	if _, err := s.dbService.CreateDeliveryAttempt(s.apiToDBDeliveryAttempt(attempt)); err != nil {
+		return fmt.Errorf("failed to record delivery attempt: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveryAttempts returns the delivery attempts of one of the
+// company's approval requests in the order they were made.
+func (s *service) ListDeliveryAttempts(approvalRequestID int) ([]api.DeliveryAttempt, error) {
+	dbAttempts, err := s.dbService.ListDeliveryAttempts(s.company.id, approvalRequestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delivery attempts: %w", err)
+	}
+
+	apiAttempts := make([]api.DeliveryAttempt, len(dbAttempts))
+	for i, dbAttempt := range dbAttempts {
+		apiAttempt, err := s.dbToAPIDeliveryAttempt(dbAttempt)
+		if err != nil {
+			return nil, err
+		}
+		apiAttempts[i] = apiAttempt
+	}
+
+	return apiAttempts, nil
+}
+
+// ListOutboxMessages returns the company's notifications with the given
+// status, such as the dead letters.
+func (s *service) ListOutboxMessages(status api.OutboxStatus) ([]api.OutboxMessage, error) {
+	dbMessages, err := s.dbService.ListOutboxMessages(s.company.id, string(status))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox messages: %w", err)
+	}
+
+	apiMessages := make([]api.OutboxMessage, len(dbMessages))
+	for i, dbMessage := range dbMessages {
+		apiMessage, err := s.dbToAPIOutboxMessage(dbMessage)
+		if err != nil {
+			return nil, err
+		}
+		apiMessages[i] = apiMessage
+	}
+
+	return apiMessages, nil
+}
+
+// GetOutboxMessageByID returns one of the company's notifications.
+func (s *service) GetOutboxMessageByID(id int) (api.OutboxMessage, error) {
+	message, err := s.dbService.GetOutboxMessageByID(s.company.id, id)
+	if err != nil {
+		return api.OutboxMessage{}, err
+	}
+	return s.dbToAPIOutboxMessage(message)
+}
+
+// ReplayDeadLetter moves one of the company's dead letters back to the
+// outbox with its attempts reset, so that the dispatcher delivers it on its
+// next pass. The last error is kept until the next attempt.
+func (s *service) ReplayDeadLetter(id int) (api.OutboxMessage, error) {
+	message, err := s.dbService.GetOutboxMessageByID(s.company.id, id)
+	if err != nil {
+		return api.OutboxMessage{}, err
+	}
+	if message.Status != db.OutboxStatusDead {
+		return api.OutboxMessage{}, fmt.Errorf("%w: notification %d is %s", ErrNotDeadLetter, id, message.Status)
+	}
+
+	message.Status = db.OutboxStatusPending
+	message.Attempts = 0
+	message.NextAttemptAt = time.Now().UTC().Format(time.RFC3339)
+	if err := s.dbService.UpdateOutboxMessage(message); err != nil {
+		return api.OutboxMessage{}, fmt.Errorf("failed to replay dead letter: %w", err)
+	}
+
+	return s.dbToAPIOutboxMessage(message)
+}
+
+// CreateAPIKey creates an API key of the company with the name and
+// permissions of key, acting as the user with its UserID if set. It returns
+// the key itself, which is not stored and cannot be retrieved later,
+// together with the stored key.
+func (s *service) CreateAPIKey(key api.APIKey) (api.APIKey, string, error) {
+	verr := &api.ValidationError{}
+	if strings.TrimSpace(key.Name) == "" {
+		verr.Add("name", api.ErrMissingField)
+	}
+	if err := api.ValidatePermissions(key.Permissions); err != nil {
+		verr.Add("permissions", err)
+	}
+	var userID *int
+	if key.UserID != 0 {
+		if _, err := s.dbService.GetUserByID(s.company.id, key.UserID); err != nil {
+			if !errors.Is(err, db.ErrUserNotFound) && !errors.Is(err, db.ErrCrossCompanyAccess) {
+				return api.APIKey{}, "", fmt.Errorf("failed to get user: %w", err)
+			}
+			verr.Add("user_id", fmt.Errorf("%w: %d", ErrUnknownUser, key.UserID))
+		}
+		userID = &key.UserID
+	}
+	if err := verr.ErrOrNil(); err != nil {
+		return api.APIKey{}, "", fmt.Errorf("invalid api key: %w", err)
+	}
+
+	secret, err := auth.GenerateKey()
+	if err != nil {
+		return api.APIKey{}, "", err
+	}
+
+	created, err := s.dbService.CreateAPIKey(db.APIKey{
+		CompanyID:   s.company.id,
+		Name:        strings.TrimSpace(key.Name),
+		Prefix:      auth.Prefix(secret),
+		Hash:        auth.HashKey(secret),
+		Permissions: permissionList(api.NormalizePermissions(key.Permissions)),
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		UserID:      userID,
+	})
+	if err != nil {
+		return api.APIKey{}, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	apiKey, err := s.dbToAPIAPIKey(created)
+	if err != nil {
+		return api.APIKey{}, "", err
+	}
+	return apiKey, secret, nil
+}
+
+// ListAPIKeys returns the company's API keys, including the revoked keys,
+// in the order they were created.
+func (s *service) ListAPIKeys() ([]api.APIKey, error) {
+	dbKeys, err := s.dbService.ListAPIKeys(s.company.id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	apiKeys := make([]api.APIKey, len(dbKeys))
+	for i, dbKey := range dbKeys {
+		apiKey, err := s.dbToAPIAPIKey(dbKey)
+		if err != nil {
+			return nil, err
+		}
+		apiKeys[i] = apiKey
+	}
+
+	return apiKeys, nil
+}
+
+// RevokeAPIKey revokes one of the company's API keys, which is rejected
+// from then on.
+func (s *service) RevokeAPIKey(id int) error {
+	if err := s.dbService.RevokeAPIKey(s.company.id, id, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+// CreateUser creates a user of the company. Invalid users are reported as
+// an *api.ValidationError.
+func (s *service) CreateUser(user api.User) (api.User, error) {
+	verr := &api.ValidationError{}
+	if strings.TrimSpace(user.Name) == "" {
+		verr.Add("name", api.ErrMissingField)
+	}
+	if strings.TrimSpace(user.Email) == "" {
+		verr.Add("email", api.ErrMissingField)
+	}
+	if err := api.ValidateRole(user.Role); err != nil {
+		verr.Add("role", err)
+	}
+	if err := verr.ErrOrNil(); err != nil {
+		return api.User{}, fmt.Errorf("invalid user: %w", err)
+	}
+
+	created, err := s.dbService.CreateUser(db.User{
+		CompanyID: s.company.id,
+		Name:      strings.TrimSpace(user.Name),
+		Email:     strings.TrimSpace(user.Email),
+		Role:      string(user.Role),
+	})
+	if err != nil {
+		return api.User{}, fmt.Errorf("failed to create user: %w", err)
+	}
+	return dbToAPIUser(created), nil
+}
+
+// GetUserByEmail returns the company's user with the email, ignoring case.
+func (s *service) GetUserByEmail(email string) (api.User, error) {
+	user, err := s.dbService.GetUserByEmail(s.company.id, strings.TrimSpace(email))
+	if err != nil {
+		return api.User{}, fmt.Errorf("failed to get user %s: %w", email, err)
+	}
+	return dbToAPIUser(user), nil
+}
+
+// DeleteUser deletes one of the company's users. API keys acting as the
+// user are rejected from then on.
+func (s *service) DeleteUser(id int) error {
+	if err := s.dbService.DeleteUser(s.company.id, id); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
+
+// ListUsers returns the company's users.
+func (s *service) ListUsers() ([]api.User, error) {
+	dbUsers, err := s.dbService.ListUsers(s.company.id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	users := make([]api.User, len(dbUsers))
+	for i, user := range dbUsers {
+		users[i] = dbToAPIUser(user)
+	}
+	return users, nil
+}
+
+// listDepartments returns the department names of a company.
+func (s *service) listDepartments(companyID int) ([]string, error) {
+	dbDepartments, err := s.dbService.ListDepartments(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	departments := make([]string, len(dbDepartments))
+	for i, department := range dbDepartments {
+		departments[i] = department.Name
+	}
+
+	return departments, nil
+}
+
+// validateWorkflowRule validates the rule on its own and against the
+// company's data: the department must be one of the company's departments,
+// the approver one of the company's approvers and the approval channel one
+// of the registered channels. All invalid fields are reported together as
+// an *api.ValidationError. The department is normalized to its stored case.
+func (s *service) validateWorkflowRule(rule *api.WorkflowRule) error {
+	verr := &api.ValidationError{}
+	if err := verr.Merge(rule.Validate()); err != nil {
+		return err
+	}
+
+	department, err := s.resolveDepartment(rule.Department)
+	switch {
+	case errors.Is(err, ErrUnknownDepartment):
+		verr.Add("department", err)
+	case err != nil:
+		return err
+	default:
+		rule.Department = department
+	}
+
+	if rule.ApprovalChannel != "" {
+		if err := s.checkChannel(rule.ApprovalChannel); err != nil {
+			verr.Add("approval_channel", err)
+		}
+	}
+	if err := s.checkChannels(rule.FallbackChannels); err != nil {
+		verr.Add("fallback_channels", err)
+	}
+
+	if rule.ApproverID > 0 {
+		approver, err := s.getApprover(rule.ApproverID)
+		switch {
+		case errors.Is(err, ErrUnknownApprover):
+			verr.Add("approver_id", err)
+		case err != nil:
+			return err
+		case rule.ApprovalChannel != "":
+			channels, err := s.ruleChannels(*rule)
+			if err != nil {
+				return err
+			}
+			if err := s.checkReachable(s.dbToAPIApprover(approver), channels); err != nil {
+				verr.Add("approver_id", err)
+			}
+		}
+	}
+
+	return verr.ErrOrNil()
+}
+
+// resolveDepartment checks that the department, if set, is one of the
+// company's departments and returns it in its stored case.
+func (s *service) resolveDepartment(department *string) (*string, error) {
+	if department == nil {
+		return nil, nil
+	}
+
+	departments, err := s.listDepartments(s.company.id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list departments: %w", err)
+	}
+
+	for _, d := range departments {
+		if strings.EqualFold(d, strings.TrimSpace(*department)) {
+			return &d, nil
+		}
+	}
+
+	if len(departments) == 0 {
+		return nil, fmt.Errorf("%w: %q (company %s has no departments)", ErrUnknownDepartment, *department, s.company.name)
+	}
+	return nil, fmt.Errorf("%w: %q (must be one of: %s)", ErrUnknownDepartment, *department, strings.Join(departments, ", "))
+}
+
+// checkChannel checks that the approval channel is one of the registered
+// channels.
+func (s *service) checkChannel(name string) error {
+	if len(s.channels) == 0 {
+		return nil
+	}
+	for _, channel := range s.channels {
+		if channel == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q (must be one of: %s)", ErrUnknownChannel, name, strings.Join(s.channels, ", "))
+}
+
+// checkChannels checks that the fallback channels are registered channels.
+func (s *service) checkChannels(names []string) error {
+	for _, name := range names {
+		if err := s.checkChannel(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getApprover returns the company's approver with the given ID.
+func (s *service) getApprover(id int) (db.Approver, error) {
+	approvers, err := s.dbService.ListApprovers(s.company.id)
+	if err != nil {
+		return db.Approver{}, fmt.Errorf("failed to list approvers: %w", err)
+	}
+
+	for _, approver := range approvers {
+		if approver.ID == id {
+			return approver, nil
+		}
+	}
+
+	return db.Approver{}, fmt.Errorf("%w: %d is not an approver of company %s", ErrUnknownApprover, id, s.company.name)
+}
+
+// checkAlternate checks that the approver's alternate approver, if set, is
+// another of the company's approvers. An invalid alternate is reported as
+// an *api.ValidationError.
+func (s *service) checkAlternate(approver api.Approver) error {
+	if approver.AlternateApproverID == 0 {
+		return nil
+	}
+
+	verr := &api.ValidationError{}
+	if approver.AlternateApproverID == approver.ID {
+		verr.Add("alternate_approver_id", ErrOwnAlternate)
+		return verr
+	}
+
+	_, err := s.getApprover(approver.AlternateApproverID)
+	switch {
+	case errors.Is(err, ErrUnknownApprover):
+		verr.Add("alternate_approver_id", err)
+	case err != nil:
+		return err
+	}
+	return verr.ErrOrNil()
+}
+
+// checkManager checks that the approver's manager, if set, is one of the
+// company's approvers, and that the approver is not above their manager in
+// the reporting hierarchy. An invalid manager is reported as an
+// *api.ValidationError.
+func (s *service) checkManager(approver api.Approver) error {
+	if approver.ManagerID == 0 {
+		return nil
+	}
+
+	approvers, err := s.dbService.ListApprovers(s.company.id)
+	if err != nil {
+		return fmt.Errorf("failed to list approvers: %w", err)
+	}
+	managers := make(map[int]*int, len(approvers))
+	for _, a := range approvers {
+		managers[a.ID] = a.ManagerID
+	}
+
+	verr := &api.ValidationError{}
+	if _, ok := managers[approver.ManagerID]; !ok {
+		verr.Add("manager_id", fmt.Errorf("%w: %d is not an approver of company %s", ErrUnknownApprover, approver.ManagerID, s.company.name))
+		return verr
+	}
+
+	// Walk up the hierarchy from the new manager. Reaching the approver
+	// means they would report to themselves.
+	seen := make(map[int]bool)
+	for id := approver.ManagerID; !seen[id]; {
+		if id == approver.ID {
+			verr.Add("manager_id", ErrManagerCycle)
+			break
+		}
+		seen[id] = true
+		next := managers[id]
+		if next == nil {
+			break
+		}
+		id = *next
+	}
+	return verr.ErrOrNil()
+}
+
+// ruleChannels returns the channels the rule's approval requests are sent
+// over, in the order they are tried. Rules without fallback channels fall
+// back to the company's.
+func (s *service) ruleChannels(rule api.WorkflowRule) ([]string, error) {
+	if len(rule.FallbackChannels) > 0 {
+		return rule.Channels(nil), nil
+	}
+
+	company, err := s.dbService.GetCompanyByID(s.company.id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company: %w", err)
+	}
+	return rule.Channels(db.SplitChannels(company.FallbackChannels)), nil
+}
+
+// checkReachable checks that the approver has a contact on at least one of
+// the channels.
+func (s *service) checkReachable(approver api.Approver, channels []string) error {
+	if len(s.contacts) == 0 {
+		return nil
+	}
+	for _, channel := range channels {
+		contactID, ok := s.contacts[channel]
+		if !ok || contactID(approver) != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s has no contact on %s", ErrUnreachableApprover, approver.Name, strings.Join(channels, ", "))
+}
+
+// checkAssignedRules checks that the approver has a contact on at least one
+// channel of every workflow rule they are assigned to.
+func (s *service) checkAssignedRules(approver api.Approver) error {
+	if len(s.contacts) == 0 {
+		return nil
+	}
+
+	rules, err := s.dbService.ListWorkflowRules(s.company.id)
+	if err != nil {
+		return fmt.Errorf("failed to list workflow rules: %w", err)
+	}
+	for _, dbRule := range rules {
+		if dbRule.ApproverID != approver.ID {
+			continue
+		}
+		channels, err := s.ruleChannels(s.dbToAPIWorkflowRule(dbRule))
+		if err != nil {
+			return err
+		}
+		if err := s.checkReachable(approver, channels); err != nil {
+			return fmt.Errorf("%w (rule %d)", err, dbRule.ID)
+		}
+	}
+	return nil
+}
+
 // Helper functions for converting between API and DB structs
 
+// toAPICompany converts a DB company to an API company with its departments.
+func (s *service) toAPICompany(company db.Company) (api.Company, error) {
+	departments, err := s.listDepartments(company.ID)
+	if err != nil {
+		return api.Company{}, fmt.Errorf("failed to list departments: %w", err)
+	}
+
+	return api.Company{
+		ID:               company.ID,
+		Name:             company.Name,
+		Departments:      departments,
+		FallbackChannels: db.SplitChannels(company.FallbackChannels),
+	}, nil
+}
+
 func (s *service) apiToDBWorkflowRule(rule api.WorkflowRule) db.WorkflowRule {
 	dbRule := db.WorkflowRule{
-		ID:              rule.ID,
-		CompanyID:       rule.CompanyID,
-		MinAmount:       rule.MinAmount,
-		MaxAmount:       rule.MaxAmount,
-		Department:      rule.Department,
-		ApproverID:      rule.ApproverID,
-		ApprovalChannel: rule.ApprovalChannel,
+		ID:               rule.ID,
+		CompanyID:        rule.CompanyID,
+		MinAmount:        toCents(rule.MinAmount),
+		MaxAmount:        toCents(rule.MaxAmount),
+		MinInclusive:     rule.MinInclusive(),
+		MaxInclusive:     rule.MaxInclusive(),
+		Currency:         string(ruleCurrency(rule)),
+		Department:       rule.Department,
+		ApproverID:       rule.ApproverID,
+		ApprovalChannel:  rule.ApprovalChannel,
+		FallbackChannels: db.JoinChannels(rule.FallbackChannels),
+		ManagerLevels:    rule.ManagerLevels,
 	}
 
 	// Convert int to *int for IsManagerApprovalRequired
@@ -278,13 +1168,17 @@ func (s *service) apiToDBWorkflowRule(rule api.WorkflowRule) db.WorkflowRule {
 
 func (s *service) dbToAPIWorkflowRule(rule db.WorkflowRule) api.WorkflowRule {
 	apiRule := api.WorkflowRule{
-		ID:              rule.ID,
-		CompanyID:       rule.CompanyID,
-		MinAmount:       rule.MinAmount,
-		MaxAmount:       rule.MaxAmount,
-		Department:      rule.Department,
-		ApproverID:      rule.ApproverID,
-		ApprovalChannel: rule.ApprovalChannel,
+		ID:               rule.ID,
+		CompanyID:        rule.CompanyID,
+		MinAmount:        toMoney(rule.MinAmount, rule.Currency),
+		MaxAmount:        toMoney(rule.MaxAmount, rule.Currency),
+		MinBound:         toBound(rule.MinInclusive),
+		MaxBound:         toBound(rule.MaxInclusive),
+		Department:       rule.Department,
+		ApproverID:       rule.ApproverID,
+		ApprovalChannel:  rule.ApprovalChannel,
+		FallbackChannels: db.SplitChannels(rule.FallbackChannels),
+		ManagerLevels:    rule.ManagerLevels,
 	}
 
 	// Convert *int to int for IsManagerApprovalRequired
@@ -297,23 +1191,222 @@ func (s *service) dbToAPIWorkflowRule(rule db.WorkflowRule) api.WorkflowRule {
 	return apiRule
 }
 
+// ruleCurrency returns the currency of the rule's amount bounds, or the
+// default currency if the rule has no bounds.
+func ruleCurrency(rule api.WorkflowRule) money.Currency {
+	if rule.MinAmount != nil && rule.MinAmount.Currency != "" {
+		return rule.MinAmount.Currency
+	}
+	if rule.MaxAmount != nil && rule.MaxAmount.Currency != "" {
+		return rule.MaxAmount.Currency
+	}
+	return money.DefaultCurrency
+}
+
+// toCents converts an optional amount to optional cents.
+func toCents(m *money.Money) *int64 {
+	if m == nil {
+		return nil
+	}
+	cents := m.Cents
+	return &cents
+}
+
+// toMoney converts optional cents in the given currency to an optional amount.
+func toMoney(cents *int64, currency string) *money.Money {
+	if cents == nil {
+		return nil
+	}
+	m := money.New(*cents, money.Currency(currency))
+	return &m
+}
+
+// toBound converts a stored inclusive flag to bound semantics.
+func toBound(inclusive bool) api.Bound {
+	if inclusive {
+		return api.BoundInclusive
+	}
+	return api.BoundExclusive
+}
+
 func (s *service) apiToDBApprover(approver api.Approver) db.Approver {
+	locale := approver.Locale
+	if locale == "" {
+		locale = api.DefaultLocale
+	}
+	preference := approver.NotificationPreference
+	if preference == "" {
+		preference = api.NotificationImmediate
+	}
+	var alternateID, managerID *int
+	if approver.AlternateApproverID != 0 {
+		alternateID = &approver.AlternateApproverID
+	}
+	if approver.ManagerID != 0 {
+		managerID = &approver.ManagerID
+	}
 	return db.Approver{
-		ID:        approver.ID,
-		CompanyID: s.company.id, // Use company ID from service
-		Name:      approver.Name,
-		Role:      approver.Role,
-		Email:     approver.Email,
-		SlackID:   approver.SlackID,
+		ID:                     approver.ID,
+		CompanyID:              s.company.id, // Use company ID from service
+		Name:                   approver.Name,
+		Role:                   approver.Role,
+		Email:                  approver.Email,
+		SlackID:                approver.SlackID,
+		TeamsID:                approver.TeamsID,
+		Locale:                 locale,
+		NotificationPreference: preference,
+		AlternateApproverID:    alternateID,
+		ManagerID:              managerID,
 	}
 }
 
 func (s *service) dbToAPIApprover(approver db.Approver) api.Approver {
-	return api.Approver{
-		ID:      approver.ID,
-		Name:    approver.Name,
-		Role:    approver.Role,
-		Email:   approver.Email,
-		SlackID: approver.SlackID,
+	apiApprover := api.Approver{
+		ID:                     approver.ID,
+		Name:                   approver.Name,
+		Role:                   approver.Role,
+		Email:                  approver.Email,
+		SlackID:                approver.SlackID,
+		TeamsID:                approver.TeamsID,
+		Locale:                 approver.Locale,
+		NotificationPreference: approver.NotificationPreference,
+	}
+	if approver.AlternateApproverID != nil {
+		apiApprover.AlternateApproverID = *approver.AlternateApproverID
+	}
+	if approver.ManagerID != nil {
+		apiApprover.ManagerID = *approver.ManagerID
+	}
+	return apiApprover
+}
+
+func (s *service) apiToDBDeliveryAttempt(attempt api.DeliveryAttempt) db.DeliveryAttempt {
+	var statusCode *int
+	if attempt.StatusCode != 0 {
+		statusCode = &attempt.StatusCode
+	}
+	var errMsg *string
+	if attempt.Error != "" {
+		errMsg = &attempt.Error
+	}
+
+	return db.DeliveryAttempt{
+		CompanyID:         s.company.id, // Use company ID from service
+		ApprovalRequestID: attempt.ApprovalRequestID,
+		Channel:           attempt.Channel,
+		IdempotencyKey:    attempt.IdempotencyKey,
+		Attempt:           attempt.Attempt,
+		StatusCode:        statusCode,
+		Error:             errMsg,
+		Succeeded:         attempt.Succeeded,
+		AttemptedAt:       attempt.AttemptedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func (s *service) dbToAPIDeliveryAttempt(attempt db.DeliveryAttempt) (api.DeliveryAttempt, error) {
+	attemptedAt, err := time.Parse(time.RFC3339, attempt.AttemptedAt)
+	if err != nil {
+		return api.DeliveryAttempt{}, fmt.Errorf("invalid delivery attempt time %q: %w", attempt.AttemptedAt, err)
+	}
+
+	apiAttempt := api.DeliveryAttempt{
+		CompanyID:         attempt.CompanyID,
+		ApprovalRequestID: attempt.ApprovalRequestID,
+		Channel:           attempt.Channel,
+		IdempotencyKey:    attempt.IdempotencyKey,
+		Attempt:           attempt.Attempt,
+		Succeeded:         attempt.Succeeded,
+		AttemptedAt:       attemptedAt,
+	}
+	if attempt.StatusCode != nil {
+		apiAttempt.StatusCode = *attempt.StatusCode
+	}
+	if attempt.Error != nil {
+		apiAttempt.Error = *attempt.Error
+	}
+	return apiAttempt, nil
+}
+
+func (s *service) dbToAPIOutboxMessage(message db.OutboxMessage) (api.OutboxMessage, error) {
+	nextAttemptAt, err := time.Parse(time.RFC3339, message.NextAttemptAt)
+	if err != nil {
+		return api.OutboxMessage{}, fmt.Errorf("invalid outbox message time %q: %w", message.NextAttemptAt, err)
+	}
+	createdAt, err := time.Parse(time.RFC3339, message.CreatedAt)
+	if err != nil {
+		return api.OutboxMessage{}, fmt.Errorf("invalid outbox message time %q: %w", message.CreatedAt, err)
+	}
+
+	apiMessage := api.OutboxMessage{
+		ID:                message.ID,
+		CompanyID:         message.CompanyID,
+		ApprovalRequestID: message.ApprovalRequestID,
+		Channel:           message.Channel,
+		Status:            api.OutboxStatus(message.Status),
+		Attempts:          message.Attempts,
+		NextAttemptAt:     nextAttemptAt,
+		CreatedAt:         createdAt,
+	}
+	if message.LastError != nil {
+		apiMessage.LastError = *message.LastError
+	}
+	if message.DeliveredAt != nil {
+		deliveredAt, err := time.Parse(time.RFC3339, *message.DeliveredAt)
+		if err != nil {
+			return api.OutboxMessage{}, fmt.Errorf("invalid outbox message time %q: %w", *message.DeliveredAt, err)
+		}
+		apiMessage.DeliveredAt = &deliveredAt
+	}
+	return apiMessage, nil
+}
+
+// permissionList returns the permissions as the comma separated list
+// stored in the database.
+func permissionList(permissions []api.Permission) string {
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = string(permission)
+	}
+	return strings.Join(names, ",")
+}
+
+func (s *service) dbToAPIAPIKey(key db.APIKey) (api.APIKey, error) {
+	createdAt, err := time.Parse(time.RFC3339, key.CreatedAt)
+	if err != nil {
+		return api.APIKey{}, fmt.Errorf("invalid api key time %q: %w", key.CreatedAt, err)
+	}
+	permissions, err := api.ParsePermissions(key.Permissions)
+	if err != nil {
+		return api.APIKey{}, fmt.Errorf("invalid permissions of api key %d: %w", key.ID, err)
+	}
+
+	apiKey := api.APIKey{
+		ID:          key.ID,
+		CompanyID:   key.CompanyID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Permissions: permissions,
+		CreatedAt:   createdAt,
+	}
+	if key.UserID != nil {
+		apiKey.UserID = *key.UserID
+	}
+	if key.RevokedAt != nil {
+		revokedAt, err := time.Parse(time.RFC3339, *key.RevokedAt)
+		if err != nil {
+			return api.APIKey{}, fmt.Errorf("invalid api key time %q: %w", *key.RevokedAt, err)
+		}
+		apiKey.RevokedAt = &revokedAt
+	}
+	return apiKey, nil
+}
+
+func dbToAPIUser(user db.User) api.User {
+	return api.User{
+		ID:        user.ID,
+		CompanyID: user.CompanyID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      api.Role(user.Role),
 	}
 }
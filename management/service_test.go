@@ -2,12 +2,16 @@ package management
 
 import (
 	"errors"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/auth"
 	"github.com/KatrinSalt/backend-challenge-go/common"
 	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/money"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -122,12 +126,20 @@ func TestService_CreateWorkflowRule(t *testing.T) {
 						createWorkflowRuleResult: db.WorkflowRule{
 							ID:                        1,
 							CompanyID:                 1,
-							MinAmount:                 floatPtr(100.0),
-							MaxAmount:                 floatPtr(500.0),
+							MinAmount:                 int64Ptr(10000),
+							MaxAmount:                 int64Ptr(50000),
+							MinInclusive:              true,
+							Currency:                  "USD",
 							Department:                stringPtr("Finance"),
 							IsManagerApprovalRequired: intPtr(1),
 							ApproverID:                1,
-							ApprovalChannel:           0,
+							ApprovalChannel:           "slack",
+						},
+						listDepartmentsResult: []db.Department{
+							{ID: 1, CompanyID: 1, Name: "Finance"},
+						},
+						listApproversResult: []db.Approver{
+							{ID: 1, CompanyID: 1, Name: "Finance Team Member"},
 						},
 					},
 					company: company{
@@ -137,23 +149,25 @@ func TestService_CreateWorkflowRule(t *testing.T) {
 				},
 				rule: api.WorkflowRule{
 					CompanyID:                 1,
-					MinAmount:                 floatPtr(100.0),
-					MaxAmount:                 floatPtr(500.0),
-					Department:                stringPtr("Finance"),
+					MinAmount:                 moneyPtr(10000),
+					MaxAmount:                 moneyPtr(50000),
+					Department:                stringPtr("finance"),
 					IsManagerApprovalRequired: 1,
 					ApproverID:                1,
-					ApprovalChannel:           0,
+					ApprovalChannel:           "slack",
 				},
 			},
 			want: api.WorkflowRule{
 				ID:                        1,
 				CompanyID:                 1,
-				MinAmount:                 floatPtr(100.0),
-				MaxAmount:                 floatPtr(500.0),
+				MinAmount:                 moneyPtr(10000),
+				MaxAmount:                 moneyPtr(50000),
+				MinBound:                  api.BoundInclusive,
+				MaxBound:                  api.BoundExclusive,
 				Department:                stringPtr("Finance"),
 				IsManagerApprovalRequired: 1,
 				ApproverID:                1,
-				ApprovalChannel:           0,
+				ApprovalChannel:           "slack",
 			},
 			wantErr: false,
 		},
@@ -174,7 +188,7 @@ func TestService_CreateWorkflowRule(t *testing.T) {
 				rule: api.WorkflowRule{
 					CompanyID:       1,
 					ApproverID:      1,
-					ApprovalChannel: 2, // Invalid channel
+					ApprovalChannel: "", // Missing channel
 				},
 			},
 			want:    api.WorkflowRule{},
@@ -197,16 +211,98 @@ func TestService_CreateWorkflowRule(t *testing.T) {
 				},
 				rule: api.WorkflowRule{
 					CompanyID:       1,
-					MinAmount:       floatPtr(500.0),
-					MaxAmount:       floatPtr(100.0), // Min > Max
+					MinAmount:       moneyPtr(50000),
+					MaxAmount:       moneyPtr(10000), // Min > Max
 					ApproverID:      1,
-					ApprovalChannel: 0,
+					ApprovalChannel: "slack",
 				},
 			},
 			want:    api.WorkflowRule{},
 			wantErr: true,
 			errMsg:  "invalid workflow rule",
 		},
+		{
+			name: "invalid workflow rule - empty range with exclusive bound",
+			input: struct {
+				service *service
+				rule    api.WorkflowRule
+			}{
+				service: &service{
+					logger:    &mockLogger{},
+					dbService: &mockDBService{},
+					company: company{
+						id:   1,
+						name: "Test Company",
+					},
+				},
+				rule: api.WorkflowRule{
+					CompanyID:       1,
+					MinAmount:       moneyPtr(50000),
+					MaxAmount:       moneyPtr(50000), // [500, 500) is empty
+					ApproverID:      1,
+					ApprovalChannel: "slack",
+				},
+			},
+			want:    api.WorkflowRule{},
+			wantErr: true,
+			errMsg:  "invalid amount range",
+		},
+		{
+			name: "invalid workflow rule - unknown bound",
+			input: struct {
+				service *service
+				rule    api.WorkflowRule
+			}{
+				service: &service{
+					logger:    &mockLogger{},
+					dbService: &mockDBService{},
+					company: company{
+						id:   1,
+						name: "Test Company",
+					},
+				},
+				rule: api.WorkflowRule{
+					CompanyID:       1,
+					MaxAmount:       moneyPtr(50000),
+					MaxBound:        "closed",
+					ApproverID:      1,
+					ApprovalChannel: "slack",
+				},
+			},
+			want:    api.WorkflowRule{},
+			wantErr: true,
+			errMsg:  "invalid bound",
+		},
+		{
+			name: "invalid workflow rule - unknown department",
+			input: struct {
+				service *service
+				rule    api.WorkflowRule
+			}{
+				service: &service{
+					logger: &mockLogger{},
+					dbService: &mockDBService{
+						listDepartmentsResult: []db.Department{
+							{ID: 1, CompanyID: 1, Name: "Finance"},
+							{ID: 2, CompanyID: 2, Name: "Engineering"},
+						},
+					},
+					company: company{
+						id:   1,
+						name: "Test Company",
+					},
+				},
+				rule: api.WorkflowRule{
+					CompanyID:       1,
+					Department:      stringPtr("Engineering"),
+					ApproverID:      1,
+					ApprovalChannel: "slack",
+				},
+			},
+			want:    api.WorkflowRule{},
+			wantErr: true,
+			errMsg:  "unknown department",
+		},
 		{
 			name: "database error during creation",
 			input: struct {
@@ -217,6 +313,9 @@ func TestService_CreateWorkflowRule(t *testing.T) {
 					logger: &mockLogger{},
 					dbService: &mockDBService{
 						createWorkflowRuleErr: errors.New("database error"),
+						listApproversResult: []db.Approver{
+							{ID: 1, CompanyID: 1, Name: "Finance Team Member"},
+						},
 					},
 					company: company{
 						id:   1,
@@ -226,7 +325,7 @@ func TestService_CreateWorkflowRule(t *testing.T) {
 				rule: api.WorkflowRule{
 					CompanyID:       1,
 					ApproverID:      1,
-					ApprovalChannel: 0,
+					ApprovalChannel: "slack",
 				},
 			},
 			want:    api.WorkflowRule{},
@@ -262,6 +361,327 @@ func TestService_CreateWorkflowRule(t *testing.T) {
 	}
 }
 
+func TestService_CreateWorkflowRule_FieldErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       api.WorkflowRule
+		wantFields []string
+		wantErrs   []error
+	}{
+		{
+			name: "misspelled department",
+			rule: api.WorkflowRule{
+				Department:      stringPtr("Finace"),
+				ApproverID:      1,
+				ApprovalChannel: "slack",
+			},
+			wantFields: []string{"department"},
+			wantErrs:   []error{ErrUnknownDepartment},
+		},
+		{
+			name: "approver of another company",
+			rule: api.WorkflowRule{
+				ApproverID:      7,
+				ApprovalChannel: "slack",
+			},
+			wantFields: []string{"approver_id"},
+			wantErrs:   []error{ErrUnknownApprover},
+		},
+		{
+			name: "all invalid fields are reported together",
+			rule: api.WorkflowRule{
+				MaxAmount:       moneyPtr(50000),
+				MaxBound:        "closed",
+				Department:      stringPtr("Finace"),
+				ApproverID:      7,
+				ApprovalChannel: "",
+			},
+			wantFields: []string{"approval_channel", "max_bound", "department", "approver_id"},
+			wantErrs:   []error{api.ErrInvalidApprovalChannel, api.ErrInvalidBound, ErrUnknownDepartment, ErrUnknownApprover},
+		},
+		{
+			name: "unregistered approval channel",
+			rule: api.WorkflowRule{
+				ApproverID:      1,
+				ApprovalChannel: "sms",
+			},
+			wantFields: []string{"approval_channel"},
+			wantErrs:   []error{ErrUnknownChannel},
+		},
+		{
+			name: "missing approver is not looked up",
+			rule: api.WorkflowRule{
+				ApprovalChannel: "slack",
+			},
+			wantFields: []string{"approver_id"},
+			wantErrs:   []error{api.ErrMissingField},
+		},
+		{
+			name: "fallback repeats the approval channel",
+			rule: api.WorkflowRule{
+				ApproverID:       1,
+				ApprovalChannel:  "slack",
+				FallbackChannels: []string{"email", "slack"},
+			},
+			wantFields: []string{"fallback_channels"},
+			wantErrs:   []error{api.ErrInvalidApprovalChannel},
+		},
+		{
+			name: "unregistered fallback channel",
+			rule: api.WorkflowRule{
+				ApproverID:       1,
+				ApprovalChannel:  "slack",
+				FallbackChannels: []string{"sms"},
+			},
+			wantFields: []string{"fallback_channels"},
+			wantErrs:   []error{ErrUnknownChannel},
+		},
+		{
+			name: "approver unreachable on the rule's channels",
+			rule: api.WorkflowRule{
+				ApproverID:      2,
+				ApprovalChannel: "slack",
+			},
+			wantFields: []string{"approver_id"},
+			wantErrs:   []error{ErrUnreachableApprover},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := &service{
+				logger: &mockLogger{},
+				dbService: &mockDBService{
+					listDepartmentsResult: []db.Department{
+						{ID: 1, CompanyID: 1, Name: "Marketing"},
+						{ID: 2, CompanyID: 1, Name: "Finance"},
+					},
+					listApproversResult: []db.Approver{
+						{ID: 1, CompanyID: 1, Name: "Finance Team Member", SlackID: "U1"},
+						{ID: 2, CompanyID: 1, Name: "Marketing Team Member", Email: "marketing@light.com"},
+					},
+				},
+				company:  company{id: 1, name: "Light"},
+				channels: []string{"email", "slack"},
+				contacts: testContacts,
+			}
+
+			_, gotErr := svc.CreateWorkflowRule(test.rule)
+
+			var verr *api.ValidationError
+			if !errors.As(gotErr, &verr) {
+				t.Fatalf("CreateWorkflowRule() error = %v, want *api.ValidationError", gotErr)
+			}
+			if !errors.Is(gotErr, api.ErrValidation) {
+				t.Errorf("CreateWorkflowRule() error does not match api.ErrValidation")
+			}
+
+			var gotFields []string
+			for _, field := range verr.Fields {
+				gotFields = append(gotFields, field.Field)
+			}
+			if diff := cmp.Diff(test.wantFields, gotFields); diff != "" {
+				t.Errorf("CreateWorkflowRule() fields mismatch (-want +got)\n%s", diff)
+			}
+
+			for _, wantErr := range test.wantErrs {
+				if !errors.Is(gotErr, wantErr) {
+					t.Errorf("CreateWorkflowRule() error = %v, want it to match %v", gotErr, wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestService_CreateCompany(t *testing.T) {
+	tests := []struct {
+		name  string
+		input struct {
+			dbService *mockDBService
+			company   api.Company
+		}
+		want    api.Company
+		wantErr error
+	}{
+		{
+			name: "successful company creation",
+			input: struct {
+				dbService *mockDBService
+				company   api.Company
+			}{
+				dbService: &mockDBService{
+					getCompanyByNameErr: db.ErrCompanyNotFound,
+					createCompanyResult: db.Company{ID: 2, Name: "Acme"},
+				},
+				company: api.Company{
+					Name:        " Acme ",
+					Departments: []string{"Engineering", " Sales "},
+				},
+			},
+			want: api.Company{
+				ID:          2,
+				Name:        "Acme",
+				Departments: []string{"Engineering", "Sales"},
+			},
+		},
+		{
+			name: "company already exists",
+			input: struct {
+				dbService *mockDBService
+				company   api.Company
+			}{
+				dbService: &mockDBService{
+					getCompanyByNameResult: db.Company{ID: 1, Name: "Light"},
+				},
+				company: api.Company{Name: "Light"},
+			},
+			wantErr: db.ErrCompanyAlreadyExists,
+		},
+		{
+			name: "missing company name",
+			input: struct {
+				dbService *mockDBService
+				company   api.Company
+			}{
+				dbService: &mockDBService{},
+				company:   api.Company{Name: " "},
+			},
+			wantErr: api.ErrMissingCompanyName,
+		},
+		{
+			name: "duplicate department",
+			input: struct {
+				dbService *mockDBService
+				company   api.Company
+			}{
+				dbService: &mockDBService{
+					getCompanyByNameErr: db.ErrCompanyNotFound,
+					createCompanyResult: db.Company{ID: 2, Name: "Acme"},
+					createDepartmentErr: db.ErrDepartmentAlreadyExists,
+				},
+				company: api.Company{Name: "Acme", Departments: []string{"Sales"}},
+			},
+			wantErr: db.ErrDepartmentAlreadyExists,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := &service{
+				logger:    &mockLogger{},
+				dbService: test.input.dbService,
+				company:   company{id: 1, name: "Light"},
+			}
+
+			got, gotErr := svc.CreateCompany(test.input.company)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("CreateCompany() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("CreateCompany() unexpected error: %v", gotErr)
+				return
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("CreateCompany() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_RemoveDepartment(t *testing.T) {
+	tests := []struct {
+		name  string
+		input struct {
+			dbService  *mockDBService
+			department string
+		}
+		wantErr error
+	}{
+		{
+			name: "successful department removal",
+			input: struct {
+				dbService  *mockDBService
+				department string
+			}{
+				dbService: &mockDBService{
+					listWorkflowRulesResult: []db.WorkflowRule{
+						{ID: 1, CompanyID: 1, Department: stringPtr("Marketing")},
+					},
+				},
+				department: "Finance",
+			},
+		},
+		{
+			name: "department used by workflow rule",
+			input: struct {
+				dbService  *mockDBService
+				department string
+			}{
+				dbService: &mockDBService{
+					listWorkflowRulesResult: []db.WorkflowRule{
+						{ID: 1, CompanyID: 1, Department: stringPtr("Marketing")},
+					},
+				},
+				department: "marketing",
+			},
+			wantErr: ErrDepartmentInUse,
+		},
+		{
+			name: "department not found",
+			input: struct {
+				dbService  *mockDBService
+				department string
+			}{
+				dbService: &mockDBService{
+					deleteDepartmentErr: db.ErrDepartmentNotFound,
+				},
+				department: "Engineering",
+			},
+			wantErr: db.ErrDepartmentNotFound,
+		},
+		{
+			name: "empty department name",
+			input: struct {
+				dbService  *mockDBService
+				department string
+			}{
+				dbService:  &mockDBService{},
+				department: "  ",
+			},
+			wantErr: api.ErrMissingDepartmentName,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := &service{
+				logger:    &mockLogger{},
+				dbService: test.input.dbService,
+				company:   company{id: 1, name: "Light"},
+			}
+
+			gotErr := svc.RemoveDepartment(test.input.department)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("RemoveDepartment() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("RemoveDepartment() unexpected error: %v", gotErr)
+			}
+		})
+	}
+}
+
 func TestService_CreateApprover(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -313,7 +733,7 @@ func TestService_CreateApprover(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "invalid approver - missing email",
+			name: "invalid approver - missing contact",
 			input: struct {
 				service  *service
 				approver api.Approver
@@ -327,10 +747,9 @@ func TestService_CreateApprover(t *testing.T) {
 					},
 				},
 				approver: api.Approver{
-					Name:    "John Doe",
-					Role:    "Manager",
-					SlackID: "U123456",
-					// Missing Email
+					Name: "John Doe",
+					Role: "Manager",
+					// Missing Email, SlackID and TeamsID
 				},
 			},
 			want:    api.Approver{},
@@ -353,41 +772,259 @@ func TestService_CreateApprover(t *testing.T) {
 						name: "Test Company",
 					},
 				},
-				approver: api.Approver{
-					Name:    "John Doe",
-					Role:    "Manager",
-					Email:   "john@example.com",
-					SlackID: "U123456",
-				},
+				approver: api.Approver{
+					Name:    "John Doe",
+					Role:    "Manager",
+					Email:   "john@example.com",
+					SlackID: "U123456",
+				},
+			},
+			want:    api.Approver{},
+			wantErr: true,
+			errMsg:  "failed to create approver",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.input.service.CreateApprover(test.input.approver)
+
+			if test.wantErr {
+				if gotErr == nil {
+					t.Errorf("CreateApprover() expected error but got none")
+					return
+				}
+				if test.errMsg != "" && !strings.Contains(gotErr.Error(), test.errMsg) {
+					t.Errorf("CreateApprover() expected error containing %q but got %q", test.errMsg, gotErr.Error())
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Errorf("CreateApprover() unexpected error: %v", gotErr)
+				return
+			}
+
+			if !cmp.Equal(got, test.want) {
+				t.Errorf("CreateApprover() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestService_UpdateApprover_AssignedRules(t *testing.T) {
+	rules := []db.WorkflowRule{
+		{ID: 1, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack", FallbackChannels: "email"},
+		{ID: 2, CompanyID: 1, ApproverID: 4, ApprovalChannel: "slack"},
+	}
+
+	tests := []struct {
+		name     string
+		approver api.Approver
+		wantErr  error
+	}{
+		{
+			name:     "slack ID removed but reachable over the fallback",
+			approver: api.Approver{ID: 3, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com"},
+		},
+		{
+			name:     "email removed but reachable over slack",
+			approver: api.Approver{ID: 3, Name: "Amanda Svensson", Role: "CFO", SlackID: "U345678"},
+		},
+		{
+			name:     "slack ID removed from an approver of a slack-only rule",
+			approver: api.Approver{ID: 4, Name: "John Doe", Role: "CMO", Email: "john@light.com"},
+			wantErr:  ErrUnreachableApprover,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := &service{
+				logger:    &mockLogger{},
+				dbService: &mockDBService{listWorkflowRulesResult: rules},
+				company:   company{id: 1, name: "Light"},
+				channels:  []string{"email", "slack"},
+				contacts:  testContacts,
+			}
+
+			gotErr := svc.UpdateApprover(test.approver)
+
+			if !errors.Is(gotErr, test.wantErr) {
+				t.Errorf("UpdateApprover() error = %v, want %v", gotErr, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_UpdateApprover_Alternate(t *testing.T) {
+	approvers := []db.Approver{
+		{ID: 2, CompanyID: 1, Name: "Vera Sander", Role: "Finance Manager", Email: "vera@light.com"},
+		{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com"},
+	}
+
+	tests := []struct {
+		name     string
+		approver api.Approver
+		wantErr  error
+	}{
+		{
+			name:     "alternate is another approver",
+			approver: api.Approver{ID: 2, Name: "Vera Sander", Role: "Finance Manager", Email: "vera@light.com", AlternateApproverID: 3},
+		},
+		{
+			name:     "alternate is the approver",
+			approver: api.Approver{ID: 2, Name: "Vera Sander", Role: "Finance Manager", Email: "vera@light.com", AlternateApproverID: 2},
+			wantErr:  ErrOwnAlternate,
+		},
+		{
+			name:     "alternate is not an approver of the company",
+			approver: api.Approver{ID: 2, Name: "Vera Sander", Role: "Finance Manager", Email: "vera@light.com", AlternateApproverID: 9},
+			wantErr:  ErrUnknownApprover,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := &service{
+				logger:    &mockLogger{},
+				dbService: &mockDBService{listApproversResult: approvers},
+				company:   company{id: 1, name: "Light"},
+				channels:  []string{"email", "slack"},
+				contacts:  testContacts,
+			}
+
+			gotErr := svc.UpdateApprover(test.approver)
+
+			if !errors.Is(gotErr, test.wantErr) {
+				t.Errorf("UpdateApprover() error = %v, want %v", gotErr, test.wantErr)
+			}
+			var verr *api.ValidationError
+			if test.wantErr != nil && !errors.As(gotErr, &verr) {
+				t.Errorf("UpdateApprover() error = %v, want a validation error", gotErr)
+			}
+		})
+	}
+}
+
+func TestService_UpdateApprover_Manager(t *testing.T) {
+	approvers := []db.Approver{
+		{ID: 1, CompanyID: 1, Name: "System User", Role: "Finance Team Member", Email: "finance@light.com", ManagerID: intPtr(2)},
+		{ID: 2, CompanyID: 1, Name: "Vera Sander", Role: "Finance Manager", Email: "vera@light.com", ManagerID: intPtr(3)},
+		{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com"},
+	}
+
+	tests := []struct {
+		name     string
+		approver api.Approver
+		wantErr  error
+	}{
+		{
+			name:     "manager is another approver",
+			approver: api.Approver{ID: 2, Name: "Vera Sander", Role: "Finance Manager", Email: "vera@light.com", ManagerID: 3},
+		},
+		{
+			name:     "manager is the approver",
+			approver: api.Approver{ID: 3, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", ManagerID: 3},
+			wantErr:  ErrManagerCycle,
+		},
+		{
+			name:     "manager reports to the approver",
+			approver: api.Approver{ID: 3, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", ManagerID: 1},
+			wantErr:  ErrManagerCycle,
+		},
+		{
+			name:     "manager is not an approver of the company",
+			approver: api.Approver{ID: 3, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", ManagerID: 9},
+			wantErr:  ErrUnknownApprover,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := &service{
+				logger:    &mockLogger{},
+				dbService: &mockDBService{listApproversResult: approvers},
+				company:   company{id: 1, name: "Light"},
+				channels:  []string{"email", "slack"},
+				contacts:  testContacts,
+			}
+
+			gotErr := svc.UpdateApprover(test.approver)
+
+			if !errors.Is(gotErr, test.wantErr) {
+				t.Errorf("UpdateApprover() error = %v, want %v", gotErr, test.wantErr)
+			}
+			var verr *api.ValidationError
+			if test.wantErr != nil && !errors.As(gotErr, &verr) {
+				t.Errorf("UpdateApprover() error = %v, want a validation error", gotErr)
+			}
+		})
+	}
+}
+
+func TestService_OrgChart(t *testing.T) {
+	approvers := []db.Approver{
+		{ID: 1, CompanyID: 1, Name: "System User", Role: "Finance Team Member", ManagerID: intPtr(2)},
+		{ID: 2, CompanyID: 1, Name: "Vera Sander", Role: "Finance Manager", ManagerID: intPtr(3)},
+		{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO"},
+		{ID: 4, CompanyID: 1, Name: "Sarah Johnson", Role: "CMO", ManagerID: intPtr(3)},
+		{ID: 5, CompanyID: 1, Name: "Max Berg", Role: "Controller", ManagerID: intPtr(9)},
+	}
+	system := api.Approver{ID: 1, Name: "System User", Role: "Finance Team Member", ManagerID: 2}
+	vera := api.Approver{ID: 2, Name: "Vera Sander", Role: "Finance Manager", ManagerID: 3}
+	amanda := api.Approver{ID: 3, Name: "Amanda Svensson", Role: "CFO"}
+	sarah := api.Approver{ID: 4, Name: "Sarah Johnson", Role: "CMO", ManagerID: 3}
+	max := api.Approver{ID: 5, Name: "Max Berg", Role: "Controller", ManagerID: 9}
+
+	tests := []struct {
+		name    string
+		id      int
+		want    []api.OrgChartNode
+		wantErr error
+	}{
+		{
+			name: "whole company",
+			want: []api.OrgChartNode{
+				{
+					Approver: amanda,
+					Reports: []api.OrgChartNode{
+						{Approver: vera, Reports: []api.OrgChartNode{{Approver: system}}},
+						{Approver: sarah},
+					},
+				},
+				{Approver: max},
 			},
-			want:    api.Approver{},
-			wantErr: true,
-			errMsg:  "failed to create approver",
+		},
+		{
+			name: "reports of an approver",
+			id:   2,
+			want: []api.OrgChartNode{
+				{Approver: vera, Reports: []api.OrgChartNode{{Approver: system}}},
+			},
+		},
+		{
+			name:    "unknown approver",
+			id:      7,
+			wantErr: db.ErrApproverNotFound,
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got, gotErr := test.input.service.CreateApprover(test.input.approver)
-
-			if test.wantErr {
-				if gotErr == nil {
-					t.Errorf("CreateApprover() expected error but got none")
-					return
-				}
-				if test.errMsg != "" && !strings.Contains(gotErr.Error(), test.errMsg) {
-					t.Errorf("CreateApprover() expected error containing %q but got %q", test.errMsg, gotErr.Error())
-				}
-				return
+			svc := &service{
+				logger:    &mockLogger{},
+				dbService: &mockDBService{listApproversResult: approvers},
+				company:   company{id: 1, name: "Light"},
 			}
 
-			if gotErr != nil {
-				t.Errorf("CreateApprover() unexpected error: %v", gotErr)
-				return
-			}
+			got, gotErr := svc.OrgChart(test.id)
 
-			if !cmp.Equal(got, test.want) {
-				t.Errorf("CreateApprover() = %v, want %v", got, test.want)
+			if !errors.Is(gotErr, test.wantErr) {
+				t.Errorf("OrgChart() error = %v, want %v", gotErr, test.wantErr)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("OrgChart() mismatch (-want +got)\n%s", diff)
 			}
 		})
 	}
@@ -410,12 +1047,14 @@ func TestService_ListWorkflowRules(t *testing.T) {
 						{
 							ID:                        1,
 							CompanyID:                 1,
-							MinAmount:                 floatPtr(100.0),
-							MaxAmount:                 floatPtr(500.0),
+							MinAmount:                 int64Ptr(10000),
+							MaxAmount:                 int64Ptr(50000),
+							MinInclusive:              true,
+							Currency:                  "USD",
 							Department:                stringPtr("Finance"),
 							IsManagerApprovalRequired: intPtr(1),
 							ApproverID:                1,
-							ApprovalChannel:           0,
+							ApprovalChannel:           "slack",
 						},
 					},
 				},
@@ -428,12 +1067,14 @@ func TestService_ListWorkflowRules(t *testing.T) {
 				{
 					ID:                        1,
 					CompanyID:                 1,
-					MinAmount:                 floatPtr(100.0),
-					MaxAmount:                 floatPtr(500.0),
+					MinAmount:                 moneyPtr(10000),
+					MaxAmount:                 moneyPtr(50000),
+					MinBound:                  api.BoundInclusive,
+					MaxBound:                  api.BoundExclusive,
 					Department:                stringPtr("Finance"),
 					IsManagerApprovalRequired: 1,
 					ApproverID:                1,
-					ApprovalChannel:           0,
+					ApprovalChannel:           "slack",
 				},
 			},
 			wantErr: false,
@@ -546,9 +1187,366 @@ func TestService_ListApprovers(t *testing.T) {
 	}
 }
 
+func TestService_DeliveryAttempts(t *testing.T) {
+	attemptedAt := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	dbService := &mockDBService{}
+	svc := &service{
+		logger:    &mockLogger{},
+		dbService: dbService,
+		company:   company{id: 1, name: "Test Company"},
+	}
+
+	attempts := []api.DeliveryAttempt{
+		{
+			ApprovalRequestID: 7,
+			Channel:           "webhook",
+			IdempotencyKey:    "key-1",
+			Attempt:           1,
+			Error:             "connection refused",
+			AttemptedAt:       attemptedAt,
+		},
+		{
+			ApprovalRequestID: 7,
+			Channel:           "webhook",
+			IdempotencyKey:    "key-1",
+			Attempt:           2,
+			StatusCode:        200,
+			Succeeded:         true,
+			AttemptedAt:       attemptedAt.Add(time.Second),
+		},
+	}
+	for _, attempt := range attempts {
+		if err := svc.RecordDeliveryAttempt(attempt); err != nil {
+			t.Fatalf("RecordDeliveryAttempt() unexpected error: %v", err)
+		}
+	}
+
+	wantStored := []db.DeliveryAttempt{
+		{ID: 1, CompanyID: 1, ApprovalRequestID: 7, Channel: "webhook", IdempotencyKey: "key-1", Attempt: 1, Error: stringPtr("connection refused"), AttemptedAt: "2026-01-02T15:04:05Z"},
+		{ID: 2, CompanyID: 1, ApprovalRequestID: 7, Channel: "webhook", IdempotencyKey: "key-1", Attempt: 2, StatusCode: intPtr(200), Succeeded: true, AttemptedAt: "2026-01-02T15:04:06Z"},
+	}
+	if diff := cmp.Diff(wantStored, dbService.createdDeliveryAttempts); diff != "" {
+		t.Errorf("RecordDeliveryAttempt() stored attempts mismatch (-want +got)\n%s", diff)
+	}
+
+	got, err := svc.ListDeliveryAttempts(7)
+	if err != nil {
+		t.Fatalf("ListDeliveryAttempts() unexpected error: %v", err)
+	}
+	for i := range attempts {
+		attempts[i].CompanyID = 1
+	}
+	if diff := cmp.Diff(attempts, got); diff != "" {
+		t.Errorf("ListDeliveryAttempts() mismatch (-want +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{1, 7}, dbService.listDeliveryAttemptsInput); diff != "" {
+		t.Errorf("ListDeliveryAttempts() scope mismatch (-want +got)\n%s", diff)
+	}
+
+	dbService.createDeliveryAttemptErr = errors.New("database is locked")
+	if err := svc.RecordDeliveryAttempt(attempts[0]); err == nil {
+		t.Errorf("RecordDeliveryAttempt() expected error but got none")
+	}
+}
+
+func TestService_DeadLetters(t *testing.T) {
+	newOutbox := func() []db.OutboxMessage {
+		return []db.OutboxMessage{
+			{ID: 1, CompanyID: 1, ApprovalRequestID: 7, Channel: "slack", Status: db.OutboxStatusDead, Attempts: 5,
+				NextAttemptAt: "2026-01-02T15:05:00Z", LastError: stringPtr("invalid_auth"), CreatedAt: "2026-01-02T15:00:00Z"},
+			{ID: 2, CompanyID: 1, ApprovalRequestID: 8, Channel: "email", Status: db.OutboxStatusDelivered,
+				NextAttemptAt: "2026-01-02T15:00:00Z", CreatedAt: "2026-01-02T15:00:00Z", DeliveredAt: stringPtr("2026-01-02T15:00:01Z")},
+			{ID: 3, CompanyID: 2, ApprovalRequestID: 9, Channel: "slack", Status: db.OutboxStatusDead, Attempts: 5,
+				NextAttemptAt: "2026-01-02T15:05:00Z", CreatedAt: "2026-01-02T15:00:00Z"},
+		}
+	}
+
+	t.Run("list dead letters", func(t *testing.T) {
+		svc := &service{dbService: &mockDBService{outbox: newOutbox()}, company: company{id: 1, name: "Test Company"}}
+
+		got, err := svc.ListOutboxMessages(api.OutboxStatusDead)
+		if err != nil {
+			t.Fatalf("ListOutboxMessages() unexpected error: %v", err)
+		}
+		want := []api.OutboxMessage{{
+			ID:                1,
+			CompanyID:         1,
+			ApprovalRequestID: 7,
+			Channel:           "slack",
+			Status:            api.OutboxStatusDead,
+			Attempts:          5,
+			NextAttemptAt:     time.Date(2026, 1, 2, 15, 5, 0, 0, time.UTC),
+			LastError:         "invalid_auth",
+			CreatedAt:         time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC),
+		}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ListOutboxMessages() mismatch (-want +got)\n%s", diff)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		id      int
+		wantErr error
+	}{
+		{name: "replay dead letter", id: 1},
+		{name: "delivered notification", id: 2, wantErr: ErrNotDeadLetter},
+		{name: "another company's dead letter", id: 3, wantErr: db.ErrCrossCompanyAccess},
+		{name: "unknown notification", id: 99, wantErr: db.ErrOutboxMessageNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dbService := &mockDBService{outbox: newOutbox()}
+			svc := &service{dbService: dbService, company: company{id: 1, name: "Test Company"}}
+
+			got, err := svc.ReplayDeadLetter(test.id)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("ReplayDeadLetter() error = %v, want %v", err, test.wantErr)
+				}
+				if diff := cmp.Diff(newOutbox(), dbService.outbox); diff != "" {
+					t.Errorf("ReplayDeadLetter() changed the outbox (-want +got)\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReplayDeadLetter() unexpected error: %v", err)
+			}
+
+			if got.Status != api.OutboxStatusPending || got.Attempts != 0 || got.LastError != "invalid_auth" {
+				t.Errorf("ReplayDeadLetter() = %+v, want a pending message with no attempts and the last error kept", got)
+			}
+			stored, err := svc.GetOutboxMessageByID(test.id)
+			if err != nil {
+				t.Fatalf("GetOutboxMessageByID() unexpected error: %v", err)
+			}
+			if stored.Status != api.OutboxStatusPending || stored.Attempts != 0 || !stored.NextAttemptAt.After(time.Date(2026, 1, 2, 15, 5, 0, 0, time.UTC)) {
+				t.Errorf("GetOutboxMessageByID() = %+v, want the replayed message pending and due now", stored)
+			}
+		})
+	}
+}
+
+func TestService_CreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        api.APIKey
+		want       []api.Permission
+		wantUserID *int
+		wantFields []string
+	}{
+		{
+			name: "valid api key",
+			key:  api.APIKey{Name: " ERP ", Permissions: []api.Permission{api.PermissionRead, api.PermissionSubmitInvoices, api.PermissionRead}},
+			want: []api.Permission{api.PermissionSubmitInvoices, api.PermissionRead},
+		},
+		{
+			name:       "api key acting as a user",
+			key:        api.APIKey{Name: "ERP", Permissions: []api.Permission{api.PermissionSubmitInvoices, api.PermissionRead}, UserID: 1},
+			want:       []api.Permission{api.PermissionSubmitInvoices, api.PermissionRead},
+			wantUserID: intPtr(1),
+		},
+		{
+			name:       "missing name and permissions",
+			wantFields: []string{"name", "permissions"},
+		},
+		{
+			name:       "unknown permission",
+			key:        api.APIKey{Name: "ERP", Permissions: []api.Permission{"admin"}},
+			wantFields: []string{"permissions"},
+		},
+		{
+			name:       "user of another company",
+			key:        api.APIKey{Name: "ERP", Permissions: []api.Permission{api.PermissionRead}, UserID: 2},
+			wantFields: []string{"user_id"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dbService := &mockDBService{users: []db.User{
+				{ID: 1, CompanyID: 1, Name: "Finance Team Member", Email: "finance_team@light.com", Role: "submitter"},
+				{ID: 2, CompanyID: 2, Name: "Other", Email: "other@acme.com", Role: "admin"},
+			}}
+			svc := &service{dbService: dbService, company: company{id: 1, name: "Test Company"}}
+
+			got, key, err := svc.CreateAPIKey(test.key)
+			if test.wantFields != nil {
+				var verr *api.ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("CreateAPIKey() error = %v, want a validation error", err)
+				}
+				var fields []string
+				for _, field := range verr.Fields {
+					fields = append(fields, field.Field)
+				}
+				if diff := cmp.Diff(test.wantFields, fields); diff != "" {
+					t.Errorf("CreateAPIKey() fields mismatch (-want +got)\n%s", diff)
+				}
+				if len(dbService.apiKeys) != 0 {
+					t.Errorf("CreateAPIKey() stored %d api keys, want none", len(dbService.apiKeys))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateAPIKey() unexpected error: %v", err)
+			}
+
+			if got.ID != 1 || got.CompanyID != 1 || got.Name != "ERP" || got.Revoked() || got.UserID != test.key.UserID {
+				t.Errorf("CreateAPIKey() = %+v, want key 1 of company 1 named ERP", got)
+			}
+			if diff := cmp.Diff(test.want, got.Permissions); diff != "" {
+				t.Errorf("CreateAPIKey() permissions mismatch (-want +got)\n%s", diff)
+			}
+			stored := dbService.apiKeys[0]
+			if stored.Hash != auth.HashKey(key) || stored.Prefix != auth.Prefix(key) || got.Prefix != stored.Prefix {
+				t.Errorf("CreateAPIKey() stored %+v, want the hash and prefix of the returned key", stored)
+			}
+			if stored.Permissions != "submit_invoices,read" {
+				t.Errorf("CreateAPIKey() stored permissions %q, want %q", stored.Permissions, "submit_invoices,read")
+			}
+			if diff := cmp.Diff(test.wantUserID, stored.UserID); diff != "" {
+				t.Errorf("CreateAPIKey() stored user ID mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_CreateUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		user       api.User
+		want       api.User
+		wantErr    error
+		wantFields []string
+	}{
+		{
+			name: "valid user",
+			user: api.User{Name: " Vera Sander ", Email: " vera_sander@light.com ", Role: api.RoleRuleEditor},
+			want: api.User{ID: 2, CompanyID: 1, Name: "Vera Sander", Email: "vera_sander@light.com", Role: api.RoleRuleEditor},
+		},
+		{
+			name:       "missing fields",
+			wantFields: []string{"name", "email", "role"},
+		},
+		{
+			name:       "unknown role",
+			user:       api.User{Name: "Vera Sander", Email: "vera_sander@light.com", Role: "owner"},
+			wantFields: []string{"role"},
+		},
+		{
+			name:    "email taken",
+			user:    api.User{Name: "Other Admin", Email: "ADMIN@light.com", Role: api.RoleAdmin},
+			wantErr: db.ErrUserAlreadyExists,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dbService := &mockDBService{users: []db.User{
+				{ID: 1, CompanyID: 1, Name: "Light Admin", Email: "admin@light.com", Role: "admin"},
+			}}
+			svc := &service{dbService: dbService, company: company{id: 1, name: "Test Company"}}
+
+			got, err := svc.CreateUser(test.user)
+			if test.wantFields != nil {
+				var verr *api.ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("CreateUser() error = %v, want a validation error", err)
+				}
+				var fields []string
+				for _, field := range verr.Fields {
+					fields = append(fields, field.Field)
+				}
+				if diff := cmp.Diff(test.wantFields, fields); diff != "" {
+					t.Errorf("CreateUser() fields mismatch (-want +got)\n%s", diff)
+				}
+				return
+			}
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("CreateUser() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateUser() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("CreateUser() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_RevokeAPIKey(t *testing.T) {
+	newKeys := func() []db.APIKey {
+		return []db.APIKey{
+			{ID: 1, CompanyID: 1, Name: "ERP", Prefix: "ak_1a2b3c4d", Permissions: "submit_invoices", CreatedAt: "2026-01-02T15:00:00Z"},
+			{ID: 2, CompanyID: 1, Name: "Old", Prefix: "ak_5e6f7a8b", Permissions: "read", CreatedAt: "2026-01-02T15:00:00Z", RevokedAt: stringPtr("2026-01-03T09:00:00Z")},
+			{ID: 3, CompanyID: 2, Name: "Other", Prefix: "ak_9c0d1e2f", Permissions: "read", CreatedAt: "2026-01-02T15:00:00Z"},
+		}
+	}
+
+	t.Run("list api keys", func(t *testing.T) {
+		svc := &service{dbService: &mockDBService{apiKeys: newKeys()}, company: company{id: 1, name: "Test Company"}}
+
+		got, err := svc.ListAPIKeys()
+		if err != nil {
+			t.Fatalf("ListAPIKeys() unexpected error: %v", err)
+		}
+		revokedAt := time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC)
+		want := []api.APIKey{
+			{ID: 1, CompanyID: 1, Name: "ERP", Prefix: "ak_1a2b3c4d", Permissions: []api.Permission{api.PermissionSubmitInvoices}, CreatedAt: time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)},
+			{ID: 2, CompanyID: 1, Name: "Old", Prefix: "ak_5e6f7a8b", Permissions: []api.Permission{api.PermissionRead}, CreatedAt: time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC), RevokedAt: &revokedAt},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ListAPIKeys() mismatch (-want +got)\n%s", diff)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		id      int
+		wantErr error
+	}{
+		{name: "revoke api key", id: 1},
+		{name: "revoked api key", id: 2, wantErr: db.ErrAPIKeyAlreadyRevoked},
+		{name: "another company's api key", id: 3, wantErr: db.ErrCrossCompanyAccess},
+		{name: "unknown api key", id: 99, wantErr: db.ErrAPIKeyNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dbService := &mockDBService{apiKeys: newKeys()}
+			svc := &service{dbService: dbService, company: company{id: 1, name: "Test Company"}}
+
+			err := svc.RevokeAPIKey(test.id)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("RevokeAPIKey() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RevokeAPIKey() unexpected error: %v", err)
+			}
+			if dbService.apiKeys[0].RevokedAt == nil {
+				t.Errorf("RevokeAPIKey() did not revoke the api key")
+			}
+		})
+	}
+}
+
 // Helper functions for creating pointers
-func floatPtr(f float64) *float64 {
-	return &f
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func moneyPtr(cents int64) *money.Money {
+	m := money.New(cents, money.USD)
+	return &m
 }
 
 func stringPtr(s string) *string {
@@ -569,10 +1567,28 @@ type mockLogger struct{}
 func (m *mockLogger) Info(msg string, args ...interface{})  {}
 func (m *mockLogger) Error(msg string, args ...interface{}) {}
 
+// testContacts holds the approver contact of the email and slack channels.
+var testContacts = map[string]ContactFunc{
+	"email": func(a api.Approver) string { return a.Email },
+	"slack": func(a api.Approver) string { return a.SlackID },
+}
+
 type mockDBService struct {
 	// Company methods
+	createCompanyResult    db.Company
+	createCompanyErr       error
+	getCompanyByIDResult   db.Company
+	getCompanyByIDErr      error
 	getCompanyByNameResult db.Company
 	getCompanyByNameErr    error
+	listCompaniesResult    []db.Company
+	updateCompanyErr       error
+	deleteCompanyErr       error
+
+	// Department methods
+	createDepartmentErr   error
+	deleteDepartmentErr   error
+	listDepartmentsResult []db.Department
 
 	// Workflow Rule methods
 	createWorkflowRuleResult db.WorkflowRule
@@ -593,6 +1609,17 @@ type mockDBService struct {
 	deleteApproverErr    error
 	listApproversResult  []db.Approver
 	listApproversErr     error
+
+	// Delivery attempt methods
+	createdDeliveryAttempts   []db.DeliveryAttempt
+	createDeliveryAttemptErr  error
+	listDeliveryAttemptsInput []int
+	// Notification outbox
+	outbox []db.OutboxMessage
+	// API keys
+	apiKeys []db.APIKey
+	// Users
+	users []db.User
 }
 
 // mockDBService implements management.databaseService interface
@@ -613,6 +1640,50 @@ func (m *mockDBService) GetCompanyByName(name string) (db.Company, error) {
 	return m.getCompanyByNameResult, m.getCompanyByNameErr
 }
 
+func (m *mockDBService) CreateCompany(company db.Company) (db.Company, error) {
+	if m.createCompanyErr != nil {
+		return db.Company{}, m.createCompanyErr
+	}
+	return m.createCompanyResult, nil
+}
+
+func (m *mockDBService) GetCompanyByID(id int) (db.Company, error) {
+	return m.getCompanyByIDResult, m.getCompanyByIDErr
+}
+
+func (m *mockDBService) ListCompanies() ([]db.Company, error) {
+	return m.listCompaniesResult, nil
+}
+
+func (m *mockDBService) UpdateCompany(company db.Company) error {
+	return m.updateCompanyErr
+}
+
+func (m *mockDBService) DeleteCompany(id int) error {
+	return m.deleteCompanyErr
+}
+
+func (m *mockDBService) CreateDepartment(department db.Department) (db.Department, error) {
+	if m.createDepartmentErr != nil {
+		return db.Department{}, m.createDepartmentErr
+	}
+	return db.Department{ID: 1, CompanyID: department.CompanyID, Name: department.Name}, nil
+}
+
+func (m *mockDBService) DeleteDepartment(companyID int, name string) error {
+	return m.deleteDepartmentErr
+}
+
+func (m *mockDBService) ListDepartments(companyID int) ([]db.Department, error) {
+	var departments []db.Department
+	for _, department := range m.listDepartmentsResult {
+		if department.CompanyID == companyID {
+			departments = append(departments, department)
+		}
+	}
+	return departments, nil
+}
+
 func (m *mockDBService) FindMatchingRule(companyID int, amount float64, department string, requiresManager bool) (db.WorkflowRule, error) {
 	return db.WorkflowRule{}, nil
 }
@@ -624,7 +1695,7 @@ func (m *mockDBService) CreateWorkflowRule(rule db.WorkflowRule) (db.WorkflowRul
 	return m.createWorkflowRuleResult, nil
 }
 
-func (m *mockDBService) GetWorkflowRuleByID(id int) (db.WorkflowRule, error) {
+func (m *mockDBService) GetWorkflowRuleByID(companyID, id int) (db.WorkflowRule, error) {
 	if m.getWorkflowRuleErr != nil {
 		return db.WorkflowRule{}, m.getWorkflowRuleErr
 	}
@@ -635,7 +1706,7 @@ func (m *mockDBService) UpdateWorkflowRule(rule db.WorkflowRule) error {
 	return m.updateWorkflowRuleErr
 }
 
-func (m *mockDBService) DeleteWorkflowRule(id int) error {
+func (m *mockDBService) DeleteWorkflowRule(companyID, id int) error {
 	return m.deleteWorkflowRuleErr
 }
 
@@ -653,7 +1724,7 @@ func (m *mockDBService) CreateApprover(approver db.Approver) (db.Approver, error
 	return m.createApproverResult, nil
 }
 
-func (m *mockDBService) GetApproverByID(id int) (db.Approver, error) {
+func (m *mockDBService) GetApproverByID(companyID, id int) (db.Approver, error) {
 	if m.getApproverErr != nil {
 		return db.Approver{}, m.getApproverErr
 	}
@@ -664,7 +1735,7 @@ func (m *mockDBService) UpdateApprover(approver db.Approver) error {
 	return m.updateApproverErr
 }
 
-func (m *mockDBService) DeleteApprover(id int) error {
+func (m *mockDBService) DeleteApprover(companyID, id int) error {
 	return m.deleteApproverErr
 }
 
@@ -674,3 +1745,134 @@ func (m *mockDBService) ListApprovers(companyID int) ([]db.Approver, error) {
 	}
 	return m.listApproversResult, nil
 }
+
+func (m *mockDBService) CreateDeliveryAttempt(attempt db.DeliveryAttempt) (db.DeliveryAttempt, error) {
+	if m.createDeliveryAttemptErr != nil {
+		return db.DeliveryAttempt{}, m.createDeliveryAttemptErr
+	}
+	attempt.ID = len(m.createdDeliveryAttempts) + 1
+	m.createdDeliveryAttempts = append(m.createdDeliveryAttempts, attempt)
+	return attempt, nil
+}
+
+func (m *mockDBService) ListDeliveryAttempts(companyID, approvalRequestID int) ([]db.DeliveryAttempt, error) {
+	m.listDeliveryAttemptsInput = []int{companyID, approvalRequestID}
+	return m.createdDeliveryAttempts, nil
+}
+
+func (m *mockDBService) GetOutboxMessageByID(companyID, id int) (db.OutboxMessage, error) {
+	for _, message := range m.outbox {
+		if message.ID != id {
+			continue
+		}
+		if message.CompanyID != companyID {
+			return db.OutboxMessage{}, &db.CrossCompanyError{Entity: "outbox message", ID: id, CompanyID: companyID}
+		}
+		return message, nil
+	}
+	return db.OutboxMessage{}, db.ErrOutboxMessageNotFound
+}
+
+func (m *mockDBService) ListOutboxMessages(companyID int, status string) ([]db.OutboxMessage, error) {
+	var messages []db.OutboxMessage
+	for _, message := range m.outbox {
+		if message.CompanyID == companyID && message.Status == status {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+func (m *mockDBService) UpdateOutboxMessage(message db.OutboxMessage) error {
+	for i := range m.outbox {
+		if m.outbox[i].ID == message.ID {
+			m.outbox[i] = message
+			return nil
+		}
+	}
+	return db.ErrOutboxMessageNotFound
+}
+
+func (m *mockDBService) CreateAPIKey(key db.APIKey) (db.APIKey, error) {
+	key.ID = len(m.apiKeys) + 1
+	m.apiKeys = append(m.apiKeys, key)
+	return key, nil
+}
+
+func (m *mockDBService) ListAPIKeys(companyID int) ([]db.APIKey, error) {
+	var keys []db.APIKey
+	for _, key := range m.apiKeys {
+		if key.CompanyID == companyID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (m *mockDBService) RevokeAPIKey(companyID, id int, revokedAt string) error {
+	for i := range m.apiKeys {
+		if m.apiKeys[i].ID != id {
+			continue
+		}
+		if m.apiKeys[i].CompanyID != companyID {
+			return &db.CrossCompanyError{Entity: "api key", ID: id, CompanyID: companyID}
+		}
+		if m.apiKeys[i].RevokedAt != nil {
+			return db.ErrAPIKeyAlreadyRevoked
+		}
+		m.apiKeys[i].RevokedAt = &revokedAt
+		return nil
+	}
+	return db.ErrAPIKeyNotFound
+}
+
+func (m *mockDBService) CreateUser(user db.User) (db.User, error) {
+	for _, existing := range m.users {
+		if existing.CompanyID == user.CompanyID && strings.EqualFold(existing.Email, user.Email) {
+			return db.User{}, db.ErrUserAlreadyExists
+		}
+	}
+	user.ID = len(m.users) + 1
+	m.users = append(m.users, user)
+	return user, nil
+}
+
+func (m *mockDBService) GetUserByID(companyID, id int) (db.User, error) {
+	for _, user := range m.users {
+		if user.ID != id {
+			continue
+		}
+		if user.CompanyID != companyID {
+			return db.User{}, &db.CrossCompanyError{Entity: "user", ID: id, CompanyID: companyID}
+		}
+		return user, nil
+	}
+	return db.User{}, db.ErrUserNotFound
+}
+
+func (m *mockDBService) GetUserByEmail(companyID int, email string) (db.User, error) {
+	for _, user := range m.users {
+		if user.CompanyID == companyID && strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+	return db.User{}, db.ErrUserNotFound
+}
+
+func (m *mockDBService) DeleteUser(companyID, id int) error {
+	if _, err := m.GetUserByID(companyID, id); err != nil {
+		return err
+	}
+	m.users = slices.DeleteFunc(m.users, func(user db.User) bool { return user.ID == id })
+	return nil
+}
+
+func (m *mockDBService) ListUsers(companyID int) ([]db.User, error) {
+	var users []db.User
+	for _, user := range m.users {
+		if user.CompanyID == companyID {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
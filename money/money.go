@@ -0,0 +1,185 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+const (
+	// USD is the United States dollar.
+	USD Currency = "USD"
+	// DefaultCurrency is the currency used when none is specified.
+	DefaultCurrency = USD
+)
+
+const (
+	// minorUnits is the number of decimal places in the minor unit of a currency.
+	minorUnits = 2
+	// centsPerUnit is the number of minor units in one major unit.
+	centsPerUnit = 100
+)
+
+var (
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrInvalidCurrency  = errors.New("invalid currency")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Money represents an exact monetary amount stored in minor units (cents).
+type Money struct {
+	Cents    int64    `json:"cents"`
+	Currency Currency `json:"currency"`
+}
+
+// New returns a new Money with the given amount in cents and currency.
+// If currency is empty the default currency is used.
+func New(cents int64, currency Currency) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Money{Cents: cents, Currency: currency}
+}
+
+// Parse parses a decimal string such as "5000", "4999.9" or "4999.99"
+// into Money without going through floating point. Amounts with more
+// decimal places than the currency's minor units are rejected.
+func Parse(s string, currency Currency) (Money, error) {
+	c, err := ParseCurrency(string(currency))
+	if err != nil {
+		return Money{}, err
+	}
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Money{}, fmt.Errorf("%w: empty amount", ErrInvalidAmount)
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if hasFrac && frac == "" {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if len(frac) > minorUnits {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidAmount, s, minorUnits)
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	var units int64
+	if whole != "" {
+		u, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || u > (math.MaxInt64-centsPerUnit)/centsPerUnit {
+			return Money{}, fmt.Errorf("%w: %q is out of range", ErrInvalidAmount, s)
+		}
+		units = u
+	}
+
+	var cents int64
+	if frac != "" {
+		frac += strings.Repeat("0", minorUnits-len(frac))
+		f, err := strconv.ParseInt(frac, 10, 64)
+		if err != nil {
+			return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+		cents = f
+	}
+
+	total := units*centsPerUnit + cents
+	if negative {
+		total = -total
+	}
+
+	return Money{Cents: total, Currency: c}, nil
+}
+
+// ParseCurrency validates and normalizes a currency code. An empty code
+// resolves to the default currency.
+func ParseCurrency(s string) (Currency, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return DefaultCurrency, nil
+	}
+	if len(s) != 3 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, s)
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, s)
+		}
+	}
+	return Currency(s), nil
+}
+
+// Compare compares two amounts of the same currency. It returns -1 if m is
+// less than o, 0 if they are equal and +1 if m is greater than o.
+func (m Money) Compare(o Money) (int, error) {
+	if m.Currency != o.Currency {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	switch {
+	case m.Cents < o.Cents:
+		return -1, nil
+	case m.Cents > o.Cents:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Cents == 0
+}
+
+// IsPositive reports whether the amount is greater than zero.
+func (m Money) IsPositive() bool {
+	return m.Cents > 0
+}
+
+// Decimal returns the amount as a decimal string, e.g. "4999.99".
+func (m Money) Decimal() string {
+	cents := m.Cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/centsPerUnit, cents%centsPerUnit)
+}
+
+// String returns the amount with its currency, e.g. "4999.99 USD".
+func (m Money) String() string {
+	currency := m.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return m.Decimal() + " " + string(currency)
+}
+
+// isDigits reports whether s only contains ASCII digits.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
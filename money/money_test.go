@@ -0,0 +1,249 @@
+package money
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		name  string
+		input struct {
+			amount   string
+			currency Currency
+		}
+		want    Money
+		wantErr error
+	}{
+		{
+			name: "whole amount",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "5000", currency: USD},
+			want: Money{Cents: 500000, Currency: USD},
+		},
+		{
+			name: "two decimal places",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "9999.99", currency: USD},
+			want: Money{Cents: 999999, Currency: USD},
+		},
+		{
+			name: "one decimal place",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "4999.9", currency: USD},
+			want: Money{Cents: 499990, Currency: USD},
+		},
+		{
+			name: "leading dot",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: ".05", currency: USD},
+			want: Money{Cents: 5, Currency: USD},
+		},
+		{
+			name: "negative amount",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "-12.34", currency: USD},
+			want: Money{Cents: -1234, Currency: USD},
+		},
+		{
+			name: "empty currency defaults to USD",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "1", currency: ""},
+			want: Money{Cents: 100, Currency: USD},
+		},
+		{
+			name: "lower case currency is normalized",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "1", currency: "sek"},
+			want: Money{Cents: 100, Currency: "SEK"},
+		},
+		{
+			name: "too many decimal places",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "9999.999999", currency: USD},
+			wantErr: ErrInvalidAmount,
+		},
+		{
+			name: "not a number",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "abc", currency: USD},
+			wantErr: ErrInvalidAmount,
+		},
+		{
+			name: "exponent notation",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "1e3", currency: USD},
+			wantErr: ErrInvalidAmount,
+		},
+		{
+			name: "trailing dot",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "10.", currency: USD},
+			wantErr: ErrInvalidAmount,
+		},
+		{
+			name: "empty amount",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "", currency: USD},
+			wantErr: ErrInvalidAmount,
+		},
+		{
+			name: "out of range",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "99999999999999999999", currency: USD},
+			wantErr: ErrInvalidAmount,
+		},
+		{
+			name: "invalid currency",
+			input: struct {
+				amount   string
+				currency Currency
+			}{amount: "1", currency: "US"},
+			wantErr: ErrInvalidCurrency,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := Parse(test.input.amount, test.input.currency)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("Parse() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+			if gotErr != nil {
+				t.Fatalf("Parse() unexpected error: %v", gotErr)
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Parse() = unexpected result (-want +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestMoney_Compare(t *testing.T) {
+	var tests = []struct {
+		name    string
+		a       Money
+		b       Money
+		want    int
+		wantErr error
+	}{
+		{
+			name: "less",
+			a:    New(999999, USD),
+			b:    New(1000000, USD),
+			want: -1,
+		},
+		{
+			name: "equal",
+			a:    New(500000, USD),
+			b:    New(500000, USD),
+			want: 0,
+		},
+		{
+			name: "greater",
+			a:    New(1000001, USD),
+			b:    New(1000000, USD),
+			want: 1,
+		},
+		{
+			name:    "currency mismatch",
+			a:       New(100, USD),
+			b:       New(100, "EUR"),
+			wantErr: ErrCurrencyMismatch,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotErr := test.a.Compare(test.b)
+
+			if test.wantErr != nil {
+				if !errors.Is(gotErr, test.wantErr) {
+					t.Errorf("Compare() error = %v, want %v", gotErr, test.wantErr)
+				}
+				return
+			}
+			if gotErr != nil {
+				t.Fatalf("Compare() unexpected error: %v", gotErr)
+			}
+			if got != test.want {
+				t.Errorf("Compare() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	var tests = []struct {
+		name  string
+		input Money
+		want  string
+	}{
+		{
+			name:  "whole amount",
+			input: New(500000, USD),
+			want:  "5000.00 USD",
+		},
+		{
+			name:  "cents",
+			input: New(999999, USD),
+			want:  "9999.99 USD",
+		},
+		{
+			name:  "small amount",
+			input: New(5, "EUR"),
+			want:  "0.05 EUR",
+		},
+		{
+			name:  "negative amount",
+			input: New(-1234, USD),
+			want:  "-12.34 USD",
+		},
+		{
+			name:  "empty currency",
+			input: Money{Cents: 100},
+			want:  "1.00 USD",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.input.String(); got != test.want {
+				t.Errorf("String() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+)
+
+// maxFormSize is the maximum accepted size of a decision form.
+const maxFormSize = 1 << 16
+
+// DecisionRecorder records decisions on approval requests.
+type DecisionRecorder interface {
+	RecordDecision(decision api.ApprovalDecision) (api.ApprovalRequest, error)
+}
+
+// decisionHandler handles the one-click decision links of approval emails.
+type decisionHandler struct {
+	service  *service
+	recorder DecisionRecorder
+}
+
+// DecisionHandler returns an HTTP handler for the decision links in
+// approval emails, to be served at DecisionPath. Opening a link shows a
+// confirmation page, and only the form it submits records the decision,
+// so that mail scanners following links cannot decide requests. Tokens are
+// verified against the link secret and can be used once.
+func (s *service) DecisionHandler(recorder DecisionRecorder) (http.Handler, error) {
+	if s.links == nil {
+		return nil, ErrMissingLinkSecret
+	}
+	if recorder == nil {
+		return nil, errors.New("decision recorder is required to handle email decisions")
+	}
+	return &decisionHandler{service: s, recorder: recorder}, nil
+}
+
+// ServeHTTP shows the confirmation page for GET requests and records the
+// decision for POST requests.
+func (h *decisionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.confirm(w, r)
+	case http.MethodPost:
+		h.decide(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// confirm renders the confirmation form for a valid token.
+func (h *decisionHandler) confirm(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	claims, err := h.verify(token)
+	if err != nil {
+		h.tokenError(w, err)
+		return
+	}
+
+	h.render(w, http.StatusOK, "decision_confirm.html.tmpl", struct {
+		Action    string
+		RequestID int
+		Path      string
+		Token     string
+	}{
+		Action:    action(claims.Status),
+		RequestID: claims.ApprovalRequestID,
+		Path:      r.URL.Path,
+		Token:     token,
+	})
+}
+
+// decide records the decision of a submitted confirmation form.
+func (h *decisionHandler) decide(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxFormSize)
+	if err := r.ParseForm(); err != nil {
+		h.result(w, http.StatusBadRequest, "Invalid request", "The decision form could not be read.")
+		return
+	}
+
+	claims, err := h.verify(r.PostForm.Get("token"))
+	if err != nil {
+		h.tokenError(w, err)
+		return
+	}
+
+	now := h.service.now()
+	if err := h.service.links.used.claim(claims.Nonce, time.Unix(claims.ExpiresAt, 0), now); err != nil {
+		h.tokenError(w, err)
+		return
+	}
+
+	decision := api.ApprovalDecision{
+		ApprovalRequestID: claims.ApprovalRequestID,
+		Status:            claims.Status,
+		Channel:           ChannelName,
+		DecidedBy:         claims.Approver,
+		DecidedAt:         now,
+	}
+	if _, err := h.recorder.RecordDecision(decision); err != nil {
+		// The link was not used up, allow the approver to try again.
+		h.service.links.used.release(claims.Nonce)
+		h.service.log.Error("Failed to record email approval decision",
+			"approval_request_id", decision.ApprovalRequestID,
+			"decided_by", decision.DecidedBy,
+			"error", err,
+		)
+		h.result(w, http.StatusConflict, "Decision not recorded",
+			"The decision could not be recorded. The approval request may already have been decided.")
+		return
+	}
+
+	h.service.log.Info("Recorded email approval decision",
+		"approval_request_id", decision.ApprovalRequestID,
+		"status", decision.Status,
+		"decided_by", decision.DecidedBy,
+	)
+	h.result(w, http.StatusOK, fmt.Sprintf("Approval request %d %s", decision.ApprovalRequestID, decision.Status),
+		fmt.Sprintf("Your decision was recorded on %s.", now.UTC().Format("2006-01-02 15:04 UTC")))
+}
+
+// verify verifies a token against the link secret and checks that it has
+// not been used.
+func (h *decisionHandler) verify(token string) (linkClaims, error) {
+	if token == "" {
+		return linkClaims{}, ErrInvalidToken
+	}
+	claims, err := h.service.links.verify(token, h.service.now())
+	if err != nil {
+		return linkClaims{}, err
+	}
+	if h.service.links.used.contains(claims.Nonce) {
+		return linkClaims{}, ErrTokenUsed
+	}
+	return claims, nil
+}
+
+// tokenError renders the page for a rejected token.
+func (h *decisionHandler) tokenError(w http.ResponseWriter, err error) {
+	h.service.log.Error("Rejected email decision link", "error", err)
+	switch {
+	case errors.Is(err, ErrTokenExpired):
+		h.result(w, http.StatusGone, "Link expired", "This decision link has expired.")
+	case errors.Is(err, ErrTokenUsed):
+		h.result(w, http.StatusGone, "Link already used", "This decision link has already been used.")
+	default:
+		h.result(w, http.StatusBadRequest, "Invalid link", "This decision link is not valid.")
+	}
+}
+
+// result renders a page with the outcome of a request.
+func (h *decisionHandler) result(w http.ResponseWriter, status int, title, message string) {
+	h.render(w, status, "decision_result.html.tmpl", struct {
+		Title   string
+		Message string
+	}{Title: title, Message: message})
+}
+
+// render writes an HTML page.
+func (h *decisionHandler) render(w http.ResponseWriter, status int, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.WriteHeader(status)
+	if err := htmlTemplates.ExecuteTemplate(w, name, data); err != nil {
+		h.service.log.Error("Failed to render email decision page", "template", name, "error", err)
+	}
+}
+
+// action returns the verb shown on the confirmation page.
+func action(status api.ApprovalStatus) string {
+	if status == api.ApprovalStatusRejected {
+		return "Reject"
+	}
+	return "Approve"
+}
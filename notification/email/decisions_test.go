@@ -0,0 +1,262 @@
+package email
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestService_DecisionHandler(t *testing.T) {
+	var tests = []struct {
+		name     string
+		options  []Option
+		recorder DecisionRecorder
+		wantErr  error
+	}{
+		{
+			name:     "valid handler",
+			options:  []Option{WithLinkSecret(testLinkSecret)},
+			recorder: &mockRecorder{},
+		},
+		{
+			name:     "missing link secret",
+			recorder: &mockRecorder{},
+			wantErr:  ErrMissingLinkSecret,
+		},
+		{
+			name:    "missing recorder",
+			options: []Option{WithLinkSecret(testLinkSecret)},
+			wantErr: errors.New("decision recorder is required"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, err := NewService("smtp://localhost:25", append(test.options, WithLogger(&mockLogger{}))...)
+			if err != nil {
+				t.Fatalf("NewService() unexpected error: %v", err)
+			}
+
+			got, err := svc.DecisionHandler(test.recorder)
+			if test.wantErr != nil {
+				if err == nil || (!errors.Is(err, test.wantErr) && !strings.Contains(err.Error(), test.wantErr.Error())) {
+					t.Errorf("DecisionHandler() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecisionHandler() unexpected error: %v", err)
+			}
+			if got == nil {
+				t.Errorf("DecisionHandler() expected handler but got nil")
+			}
+		})
+	}
+}
+
+func TestDecisionHandler_ServeHTTP(t *testing.T) {
+	sentAt := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	now := sentAt.Add(time.Hour)
+	approvalRequest := api.ApprovalRequest{
+		ID:       7,
+		Approver: api.Approver{Name: "Amanda Svensson", Email: "amanda@light.com"},
+	}
+	approved := api.ApprovalDecision{
+		ApprovalRequestID: 7,
+		Status:            api.ApprovalStatusApproved,
+		Channel:           "email",
+		DecidedBy:         "amanda@light.com",
+		DecidedAt:         now,
+	}
+
+	// request describes one request made to the handler.
+	type request struct {
+		method     string
+		link       string
+		wantStatus int
+		wantBody   string
+	}
+
+	var tests = []struct {
+		name          string
+		recorder      *mockRecorder
+		requests      []request
+		wantDecisions []api.ApprovalDecision
+	}{
+		{
+			name:     "open link then confirm",
+			recorder: &mockRecorder{},
+			requests: []request{
+				{method: http.MethodGet, link: "approve", wantStatus: http.StatusOK, wantBody: `<form method="post" action="/email/decisions">`},
+				{method: http.MethodPost, link: "approve", wantStatus: http.StatusOK, wantBody: "Approval request 7 approved"},
+			},
+			wantDecisions: []api.ApprovalDecision{approved},
+		},
+		{
+			name:     "reject link",
+			recorder: &mockRecorder{},
+			requests: []request{
+				{method: http.MethodPost, link: "reject", wantStatus: http.StatusOK, wantBody: "Approval request 7 rejected"},
+			},
+			wantDecisions: []api.ApprovalDecision{{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusRejected,
+				Channel:           "email",
+				DecidedBy:         "amanda@light.com",
+				DecidedAt:         now,
+			}},
+		},
+		{
+			name:     "opening a link does not decide",
+			recorder: &mockRecorder{},
+			requests: []request{
+				{method: http.MethodGet, link: "approve", wantStatus: http.StatusOK, wantBody: "Approve approval request 7?"},
+				{method: http.MethodGet, link: "approve", wantStatus: http.StatusOK},
+			},
+		},
+		{
+			name:     "replayed link",
+			recorder: &mockRecorder{},
+			requests: []request{
+				{method: http.MethodPost, link: "approve", wantStatus: http.StatusOK},
+				{method: http.MethodPost, link: "approve", wantStatus: http.StatusGone, wantBody: "already been used"},
+				{method: http.MethodGet, link: "approve", wantStatus: http.StatusGone, wantBody: "already been used"},
+			},
+			wantDecisions: []api.ApprovalDecision{approved},
+		},
+		{
+			name:     "decision not recorded",
+			recorder: &mockRecorder{errs: []error{errors.New("approval request already decided")}},
+			requests: []request{
+				{method: http.MethodPost, link: "approve", wantStatus: http.StatusConflict, wantBody: "could not be recorded"},
+				{method: http.MethodPost, link: "approve", wantStatus: http.StatusOK},
+			},
+			wantDecisions: []api.ApprovalDecision{approved, approved},
+		},
+		{
+			name:     "expired link",
+			recorder: &mockRecorder{},
+			requests: []request{
+				{method: http.MethodPost, link: "expired", wantStatus: http.StatusGone, wantBody: "expired"},
+			},
+		},
+		{
+			name:     "tampered link",
+			recorder: &mockRecorder{},
+			requests: []request{
+				{method: http.MethodGet, link: "tampered", wantStatus: http.StatusBadRequest, wantBody: "not valid"},
+				{method: http.MethodPost, link: "tampered", wantStatus: http.StatusBadRequest, wantBody: "not valid"},
+			},
+		},
+		{
+			name:     "missing token",
+			recorder: &mockRecorder{},
+			requests: []request{
+				{method: http.MethodPost, wantStatus: http.StatusBadRequest},
+			},
+		},
+		{
+			name:     "method not allowed",
+			recorder: &mockRecorder{},
+			requests: []request{
+				{method: http.MethodDelete, link: "approve", wantStatus: http.StatusMethodNotAllowed},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, err := NewService("smtp://localhost:25",
+				WithLogger(&mockLogger{}),
+				WithLinkSecret(testLinkSecret),
+				WithLinkBaseURL("https://approvals.light.com"),
+				WithLinkTTL(2*time.Hour),
+			)
+			if err != nil {
+				t.Fatalf("NewService() unexpected error: %v", err)
+			}
+
+			links, err := svc.links.links(approvalRequest, sentAt)
+			if err != nil {
+				t.Fatalf("links() unexpected error: %v", err)
+			}
+			expired, err := svc.links.links(approvalRequest, sentAt.Add(-2*time.Hour))
+			if err != nil {
+				t.Fatalf("links() unexpected error: %v", err)
+			}
+			tokens := map[string]string{
+				"approve":  tokenOf(t, links.approveURL),
+				"reject":   tokenOf(t, links.rejectURL),
+				"expired":  tokenOf(t, expired.approveURL),
+				"tampered": strings.Replace(tokenOf(t, links.approveURL), ".", "x.", 1),
+			}
+
+			svc.now = func() time.Time { return now }
+			handler, err := svc.DecisionHandler(test.recorder)
+			if err != nil {
+				t.Fatalf("DecisionHandler() unexpected error: %v", err)
+			}
+
+			for i, r := range test.requests {
+				var req *http.Request
+				form := url.Values{}
+				if r.link != "" {
+					form.Set("token", tokens[r.link])
+				}
+				if r.method == http.MethodPost {
+					req = httptest.NewRequest(r.method, DecisionPath, strings.NewReader(form.Encode()))
+					req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				} else {
+					req = httptest.NewRequest(r.method, DecisionPath+"?"+form.Encode(), nil)
+				}
+
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+
+				if rec.Code != r.wantStatus {
+					t.Errorf("request %d: ServeHTTP() status = %d, want %d", i, rec.Code, r.wantStatus)
+				}
+				if !strings.Contains(rec.Body.String(), r.wantBody) {
+					t.Errorf("request %d: ServeHTTP() body does not contain %q\n%s", i, r.wantBody, rec.Body.String())
+				}
+			}
+
+			if diff := cmp.Diff(test.wantDecisions, test.recorder.got); diff != "" {
+				t.Errorf("ServeHTTP() decisions mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+// tokenOf returns the token query parameter of a decision link.
+func tokenOf(t *testing.T, link string) string {
+	t.Helper()
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("invalid decision link %q: %v", link, err)
+	}
+	return u.Query().Get("token")
+}
+
+type mockRecorder struct {
+	// errs are returned by successive calls, nil once exhausted.
+	errs []error
+	got  []api.ApprovalDecision
+}
+
+func (m *mockRecorder) RecordDecision(decision api.ApprovalDecision) (api.ApprovalRequest, error) {
+	m.got = append(m.got, decision)
+	if len(m.errs) > 0 {
+		err := m.errs[0]
+		m.errs = m.errs[1:]
+		return api.ApprovalRequest{}, err
+	}
+	return api.ApprovalRequest{ID: decision.ApprovalRequestID}, nil
+}
@@ -0,0 +1,192 @@
+package email
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+)
+
+const (
+	// DecisionPath is the path of the decision links in approval emails.
+	DecisionPath = "/email/decisions"
+	// defaultLinkTTL is how long decision links stay valid.
+	defaultLinkTTL = 72 * time.Hour
+	// tokenContext separates decision link signatures from other uses of
+	// the same secret.
+	tokenContext = "email-decision-v1"
+)
+
+var (
+	// ErrMissingLinkSecret is returned when decision links or the decision
+	// handler are requested without a signing secret.
+	ErrMissingLinkSecret = errors.New("email link secret is required for decision links")
+	// ErrInvalidToken is returned for decision tokens that are malformed or
+	// not signed with the link secret.
+	ErrInvalidToken = errors.New("invalid decision link token")
+	// ErrTokenExpired is returned for decision tokens past their expiry.
+	ErrTokenExpired = errors.New("decision link has expired")
+	// ErrTokenUsed is returned for decision tokens that have already been
+	// used.
+	ErrTokenUsed = errors.New("decision link has already been used")
+)
+
+// linkClaims are the values bound by a decision link token.
+type linkClaims struct {
+	ApprovalRequestID int                `json:"rid"`
+	Approver          string             `json:"sub"`
+	Status            api.ApprovalStatus `json:"dec"`
+	ExpiresAt         int64              `json:"exp"`
+	Nonce             string             `json:"jti"`
+}
+
+// linkSigner creates and verifies decision link tokens. A token is the
+// base64url encoded JSON claims followed by a dot and the base64url
+// encoded HMAC-SHA256 of the claims.
+type linkSigner struct {
+	secret  []byte
+	baseURL string
+	ttl     time.Duration
+	used    *nonceStore
+}
+
+// links returns the approve and reject links of an approval request.
+func (l *linkSigner) links(approvalRequest api.ApprovalRequest, now time.Time) (*decisionLinks, error) {
+	approveURL, err := l.decisionURL(approvalRequest, api.ApprovalStatusApproved, now)
+	if err != nil {
+		return nil, err
+	}
+	rejectURL, err := l.decisionURL(approvalRequest, api.ApprovalStatusRejected, now)
+	if err != nil {
+		return nil, err
+	}
+	return &decisionLinks{approveURL: approveURL, rejectURL: rejectURL, expiresAt: now.Add(l.ttl)}, nil
+}
+
+// decisionURL returns a link that decides the approval request with the
+// given status on behalf of its approver.
+func (l *linkSigner) decisionURL(approvalRequest api.ApprovalRequest, status api.ApprovalStatus, now time.Time) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate link nonce: %w", err)
+	}
+
+	token, err := l.sign(linkClaims{
+		ApprovalRequestID: approvalRequest.ID,
+		Approver:          approvalRequest.Approver.Email,
+		Status:            status,
+		ExpiresAt:         now.Add(l.ttl).Unix(),
+		Nonce:             hex.EncodeToString(nonce),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(l.baseURL, "/") + DecisionPath + "?" + url.Values{"token": {token}}.Encode(), nil
+}
+
+// sign returns the token for the claims.
+func (l *linkSigner) sign(claims linkClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode link claims: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(l.mac(encoded)), nil
+}
+
+// verify checks the signature and expiry of a token and returns its claims.
+// It does not check whether the token has been used.
+func (l *linkSigner) verify(token string, now time.Time) (linkClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return linkClaims{}, ErrInvalidToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, l.mac(encoded)) {
+		return linkClaims{}, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return linkClaims{}, ErrInvalidToken
+	}
+	var claims linkClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return linkClaims{}, ErrInvalidToken
+	}
+	if claims.ApprovalRequestID <= 0 || claims.Approver == "" || claims.Nonce == "" {
+		return linkClaims{}, ErrInvalidToken
+	}
+	if claims.Status != api.ApprovalStatusApproved && claims.Status != api.ApprovalStatusRejected {
+		return linkClaims{}, ErrInvalidToken
+	}
+	if !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return linkClaims{}, ErrTokenExpired
+	}
+
+	return claims, nil
+}
+
+// mac returns the HMAC-SHA256 of the encoded claims.
+func (l *linkSigner) mac(encoded string) []byte {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(tokenContext + "." + encoded))
+	return mac.Sum(nil)
+}
+
+// nonceStore remembers the nonces of used tokens until they expire, so that
+// a token can only be used once.
+type nonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+func newNonceStore() *nonceStore {
+	return &nonceStore{nonces: make(map[string]time.Time)}
+}
+
+// claim marks the nonce as used. It returns ErrTokenUsed if the nonce has
+// already been claimed. Expired nonces are forgotten, as their tokens are
+// rejected on expiry anyway.
+func (n *nonceStore) claim(nonce string, expiresAt, now time.Time) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for k, exp := range n.nonces {
+		if !now.Before(exp) {
+			delete(n.nonces, k)
+		}
+	}
+	if _, ok := n.nonces[nonce]; ok {
+		return ErrTokenUsed
+	}
+	n.nonces[nonce] = expiresAt
+	return nil
+}
+
+// contains reports whether the nonce has been claimed.
+func (n *nonceStore) contains(nonce string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, ok := n.nonces[nonce]
+	return ok
+}
+
+// release forgets a claimed nonce, so that the token can be used again
+// after a decision could not be recorded.
+func (n *nonceStore) release(nonce string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.nonces, nonce)
+}
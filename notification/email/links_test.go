@@ -0,0 +1,158 @@
+package email
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/google/go-cmp/cmp"
+)
+
+const testLinkSecret = "0f1e2d3c4b5a69788796a5b4c3d2e1f0"
+
+func TestLinkSigner_DecisionURL(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	signer := &linkSigner{secret: []byte(testLinkSecret), baseURL: "https://approvals.light.com/", ttl: time.Hour}
+
+	got, err := signer.decisionURL(api.ApprovalRequest{
+		ID:       7,
+		Approver: api.Approver{Email: "amanda@light.com"},
+	}, api.ApprovalStatusRejected, now)
+	if err != nil {
+		t.Fatalf("decisionURL() unexpected error: %v", err)
+	}
+
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatalf("decisionURL() returned invalid url %q: %v", got, err)
+	}
+	if u.Scheme+"://"+u.Host+u.Path != "https://approvals.light.com"+DecisionPath {
+		t.Errorf("decisionURL() = %s, want a link to %s", got, DecisionPath)
+	}
+
+	claims, err := signer.verify(u.Query().Get("token"), now)
+	if err != nil {
+		t.Fatalf("verify() unexpected error: %v", err)
+	}
+	want := linkClaims{
+		ApprovalRequestID: 7,
+		Approver:          "amanda@light.com",
+		Status:            api.ApprovalStatusRejected,
+		ExpiresAt:         now.Add(time.Hour).Unix(),
+		Nonce:             claims.Nonce,
+	}
+	if diff := cmp.Diff(want, claims); diff != "" {
+		t.Errorf("verify() claims mismatch (-want +got)\n%s", diff)
+	}
+	if len(claims.Nonce) != 32 {
+		t.Errorf("verify() nonce = %q, want 16 random bytes", claims.Nonce)
+	}
+}
+
+func TestLinkSigner_Verify(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	signer := &linkSigner{secret: []byte(testLinkSecret)}
+	claims := linkClaims{
+		ApprovalRequestID: 7,
+		Approver:          "amanda@light.com",
+		Status:            api.ApprovalStatusApproved,
+		ExpiresAt:         now.Add(time.Hour).Unix(),
+		Nonce:             "4f2a",
+	}
+
+	mustSign := func(signer *linkSigner, claims linkClaims) string {
+		token, err := signer.sign(claims)
+		if err != nil {
+			t.Fatalf("sign() unexpected error: %v", err)
+		}
+		return token
+	}
+	valid := mustSign(signer, claims)
+	payload, signature, _ := strings.Cut(valid, ".")
+	other := mustSign(signer, linkClaims{
+		ApprovalRequestID: 8,
+		Approver:          "amanda@light.com",
+		Status:            api.ApprovalStatusApproved,
+		ExpiresAt:         now.Add(time.Hour).Unix(),
+		Nonce:             "4f2a",
+	})
+	otherPayload, _, _ := strings.Cut(other, ".")
+
+	var tests = []struct {
+		name    string
+		token   string
+		now     time.Time
+		want    linkClaims
+		wantErr error
+	}{
+		{
+			name:  "valid token",
+			token: valid,
+			now:   now,
+			want:  claims,
+		},
+		{
+			name:    "expired token",
+			token:   valid,
+			now:     now.Add(time.Hour),
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name:    "claims swapped under signature",
+			token:   otherPayload + "." + signature,
+			now:     now,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "signed with another secret",
+			token:   mustSign(&linkSigner{secret: []byte("another-secret")}, claims),
+			now:     now,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "missing signature",
+			token:   payload,
+			now:     now,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "invalid decision",
+			token: mustSign(signer, linkClaims{
+				ApprovalRequestID: 7,
+				Approver:          "amanda@light.com",
+				Status:            api.ApprovalStatusPending,
+				ExpiresAt:         now.Add(time.Hour).Unix(),
+				Nonce:             "4f2a",
+			}),
+			now:     now,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "malformed token",
+			token:   "not a token",
+			now:     now,
+			wantErr: ErrInvalidToken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := signer.verify(test.token, test.now)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("verify() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verify() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("verify() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
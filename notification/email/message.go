@@ -0,0 +1,175 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/notification/templates"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// htmlTemplates are the pages of the decision handler.
+var htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html.tmpl"))
+
+// message is an email ready to be sent.
+type message struct {
+	id   string
+	to   string
+	data []byte
+}
+
+// decisionLinks are the one-click approve and reject links of an approval
+// request email.
+type decisionLinks struct {
+	approveURL string
+	rejectURL  string
+	expiresAt  time.Time
+}
+
+// buildApprovalMessage renders an approval request with the email
+// templates of the approver's locale into a multipart email with a plain
+// text and an HTML alternative, including the decision links if links is
+// not nil. The subject ends with the [#id] token replies are matched to
+// the approval request by.
+func buildApprovalMessage(renderer *templates.Renderer, from string, approvalRequest api.ApprovalRequest, links *decisionLinks, now time.Time) (message, error) {
+	values := templates.NewData(approvalRequest)
+	if links != nil {
+		values.ApproveURL = links.approveURL
+		values.RejectURL = links.rejectURL
+		values.LinksExpireAt = links.expiresAt.UTC().Format("2006-01-02 15:04 UTC")
+	}
+	content, err := renderer.Render(ChannelName, values)
+	if err != nil {
+		return message{}, fmt.Errorf("failed to render email templates: %w", err)
+	}
+	if content.HTML == "" {
+		return message{}, fmt.Errorf("failed to render email templates: %w: %s", templates.ErrTemplateNotFound, templates.HTMLTemplate)
+	}
+	if approvalRequest.ID != 0 {
+		content.Subject = fmt.Sprintf("%s [#%d]", content.Subject, approvalRequest.ID)
+	}
+
+	return buildMessage(from, approvalRequest.Approver, content, now)
+}
+
+// buildDigestMessage renders a digest with the email digest templates of
+// the approver's locale into a multipart email, including the decision
+// links of the requests in links.
+func buildDigestMessage(renderer *templates.Renderer, from string, digest api.Digest, links map[int]*decisionLinks, now time.Time) (message, error) {
+	values := templates.NewDigestData(digest)
+	for i, request := range values.Requests {
+		if l := links[request.RequestID]; l != nil {
+			values.Requests[i].ApproveURL = l.approveURL
+			values.Requests[i].RejectURL = l.rejectURL
+			values.LinksExpireAt = l.expiresAt.UTC().Format("2006-01-02 15:04 UTC")
+		}
+	}
+	content, err := renderer.RenderDigest(ChannelName, values)
+	if err != nil {
+		return message{}, fmt.Errorf("failed to render email digest templates: %w", err)
+	}
+	if content.HTML == "" {
+		return message{}, fmt.Errorf("failed to render email digest templates: %w: %s", templates.ErrTemplateNotFound, templates.DigestHTMLTemplate)
+	}
+
+	return buildMessage(from, digest.Approver, content, now)
+}
+
+// buildMessage builds a multipart email to the approver with the rendered
+// content as plain text and HTML alternatives.
+func buildMessage(from string, approver api.Approver, content templates.Content, now time.Time) (message, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return message{}, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	recipient, err := mail.ParseAddress(approver.Email)
+	if err != nil {
+		return message{}, fmt.Errorf("invalid recipient %q: %w", approver.Email, err)
+	}
+	recipient.Name = approver.Name
+
+	id, err := newMessageID(sender.Address, now)
+	if err != nil {
+		return message{}, err
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{contentType: "text/plain; charset=UTF-8", content: []byte(content.Text)},
+		{contentType: "text/html; charset=UTF-8", content: []byte(content.HTML)},
+	} {
+		if err := writePart(w, part.contentType, part.content); err != nil {
+			return message{}, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return message{}, fmt.Errorf("failed to close multipart body: %w", err)
+	}
+
+	var data bytes.Buffer
+	for _, header := range [][2]string{
+		{"From", sender.String()},
+		{"To", recipient.String()},
+		{"Subject", mime.QEncoding.Encode("UTF-8", content.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", id},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", w.Boundary())},
+	} {
+		fmt.Fprintf(&data, "%s: %s\r\n", header[0], header[1])
+	}
+	data.WriteString("\r\n")
+	data.Write(body.Bytes())
+
+	return message{id: id, to: recipient.Address, data: data.Bytes()}, nil
+}
+
+// writePart writes a quoted-printable encoded part to a multipart body.
+func writePart(w *multipart.Writer, contentType string, content []byte) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s part: %w", contentType, err)
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s part: %w", contentType, err)
+	}
+	return qp.Close()
+}
+
+// newMessageID returns a globally unique Message-ID in the domain of the
+// sender address.
+func newMessageID(sender string, now time.Time) (string, error) {
+	domain := "localhost"
+	if i := strings.LastIndex(sender, "@"); i >= 0 {
+		domain = sender[i+1:]
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate message id: %w", err)
+	}
+	return fmt.Sprintf("<%d.%s@%s>", now.UnixNano(), hex.EncodeToString(b), domain), nil
+}
@@ -0,0 +1,257 @@
+package email
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/notification/templates"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBuildApprovalMessage(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	var tests = []struct {
+		name            string
+		approvalRequest api.ApprovalRequest
+		links           *decisionLinks
+		wantTo          string
+		wantSubject     string
+		wantText        []string
+		wantHTML        []string
+		wantErr         bool
+	}{
+		{
+			name: "invoice with department and manager approval",
+			approvalRequest: api.ApprovalRequest{
+				ID:       7,
+				Approver: api.Approver{Name: "Amanda Svensson", Email: "amanda@light.com"},
+				Invoice: api.InvoiceDetails{
+					Amount:                    money.New(1500000, money.USD),
+					Department:                "Finance",
+					IsManagerApprovalRequired: true,
+				},
+			},
+			wantTo: `"Amanda Svensson" <amanda@light.com>`,
+			wantText: []string{
+				"Hi Amanda Svensson,",
+				"Amount:                    " + money.New(1500000, money.USD).String(),
+				"Department:                Finance",
+				"Manager approval required: Yes",
+				"Request ID:                7",
+			},
+			wantHTML: []string{
+				"<p>Hi Amanda Svensson,</p>",
+				"<tr><th align=\"left\">Department</th><td>Finance</td></tr>",
+				"<tr><th align=\"left\">Manager approval required</th><td>Yes</td></tr>",
+			},
+		},
+		{
+			name: "html in approver name is escaped",
+			approvalRequest: api.ApprovalRequest{
+				ID:       8,
+				Approver: api.Approver{Name: "<b>Eve</b>", Email: "eve@light.com"},
+				Invoice:  api.InvoiceDetails{Amount: money.New(50000, money.USD)},
+			},
+			wantTo: `"<b>Eve</b>" <eve@light.com>`,
+			wantText: []string{
+				"Hi <b>Eve</b>,",
+				"Department:                Not specified",
+				"Manager approval required: No",
+			},
+			wantHTML: []string{
+				"<p>Hi &lt;b&gt;Eve&lt;/b&gt;,</p>",
+			},
+		},
+		{
+			name: "invoice with decision links",
+			approvalRequest: api.ApprovalRequest{
+				ID:       9,
+				Approver: api.Approver{Name: "Amanda Svensson", Email: "amanda@light.com"},
+				Invoice:  api.InvoiceDetails{Amount: money.New(50000, money.USD)},
+			},
+			links: &decisionLinks{
+				approveURL: "https://approvals.light.com/email/decisions?token=approve.sig",
+				rejectURL:  "https://approvals.light.com/email/decisions?token=reject.sig",
+				expiresAt:  now.Add(72 * time.Hour),
+			},
+			wantTo: `"Amanda Svensson" <amanda@light.com>`,
+			wantText: []string{
+				"Approve: https://approvals.light.com/email/decisions?token=approve.sig",
+				"Reject:  https://approvals.light.com/email/decisions?token=reject.sig",
+				"Each link can be used once and expires on 2026-01-05 15:04 UTC.",
+			},
+			wantHTML: []string{
+				`<a href="https://approvals.light.com/email/decisions?token=approve.sig"`,
+				`<a href="https://approvals.light.com/email/decisions?token=reject.sig"`,
+			},
+		},
+		{
+			name: "approver without name",
+			approvalRequest: api.ApprovalRequest{
+				Approver: api.Approver{Email: "ap@light.com"},
+				Invoice:  api.InvoiceDetails{Amount: money.New(50000, money.USD)},
+			},
+			wantTo:   "<ap@light.com>",
+			wantText: []string{"Hello,"},
+			wantHTML: []string{"<p>Hello,</p>"},
+		},
+		{
+			name: "swedish approver with vendor, description and due date",
+			approvalRequest: api.ApprovalRequest{
+				ID:       10,
+				Approver: api.Approver{Name: "Amanda Svensson", Email: "amanda@light.com", Locale: "sv"},
+				Invoice: api.InvoiceDetails{
+					Amount:      money.New(50000, money.USD),
+					Vendor:      "Northwind Media AB",
+					Description: "Annonsplatser",
+					DueDate:     "2026-01-31",
+				},
+			},
+			wantTo:      `"Amanda Svensson" <amanda@light.com>`,
+			wantSubject: "Begäran om fakturagodkännande",
+			wantText: []string{
+				"Hej Amanda Svensson,",
+				"Avdelning:              Ej angiven",
+				"Leverantör:             Northwind Media AB",
+				"Beskrivning:            Annonsplatser",
+				"Förfallodatum:          2026-01-31",
+			},
+			wantHTML: []string{
+				"<tr><th align=\"left\">Leverantör</th><td>Northwind Media AB</td></tr>",
+			},
+		},
+		{
+			name: "invalid recipient",
+			approvalRequest: api.ApprovalRequest{
+				Approver: api.Approver{Name: "John Doe", Email: "not-an-address"},
+				Invoice:  api.InvoiceDetails{Amount: money.New(50000, money.USD)},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := buildApprovalMessage(newTestRenderer(t), "approvals@light.com", test.approvalRequest, test.links, now)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("buildApprovalMessage() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildApprovalMessage() unexpected error: %v", err)
+			}
+
+			msg, err := mail.ReadMessage(strings.NewReader(string(got.data)))
+			if err != nil {
+				t.Fatalf("buildApprovalMessage() returned an invalid email: %v", err)
+			}
+
+			wantSubject := test.wantSubject
+			if wantSubject == "" {
+				wantSubject = "Invoice approval request"
+			}
+			if test.approvalRequest.ID != 0 {
+				wantSubject = fmt.Sprintf("%s [#%d]", wantSubject, test.approvalRequest.ID)
+			}
+			wantHeaders := map[string]string{
+				"From":         "<approvals@light.com>",
+				"To":           test.wantTo,
+				"Subject":      wantSubject,
+				"Date":         "Fri, 02 Jan 2026 15:04:05 +0000",
+				"Message-ID":   got.id,
+				"MIME-Version": "1.0",
+			}
+			gotHeaders := map[string]string{}
+			for key := range wantHeaders {
+				gotHeaders[key] = msg.Header.Get(key)
+			}
+			if gotHeaders["Subject"], err = new(mime.WordDecoder).DecodeHeader(gotHeaders["Subject"]); err != nil {
+				t.Fatalf("buildApprovalMessage() returned an invalid subject: %v", err)
+			}
+			if diff := cmp.Diff(wantHeaders, gotHeaders); diff != "" {
+				t.Errorf("buildApprovalMessage() headers mismatch (-want +got)\n%s", diff)
+			}
+			if !strings.HasSuffix(got.id, "@light.com>") {
+				t.Errorf("buildApprovalMessage() message id = %s, want one in the sender domain", got.id)
+			}
+
+			parts := readParts(t, msg)
+			if diff := cmp.Diff([]string{"text/plain", "text/html"}, []string{parts[0].contentType, parts[1].contentType}); diff != "" {
+				t.Fatalf("buildApprovalMessage() parts mismatch (-want +got)\n%s", diff)
+			}
+			for _, want := range test.wantText {
+				if !strings.Contains(parts[0].body, want) {
+					t.Errorf("buildApprovalMessage() text part missing %q\n%s", want, parts[0].body)
+				}
+			}
+			for _, want := range test.wantHTML {
+				if !strings.Contains(parts[1].body, want) {
+					t.Errorf("buildApprovalMessage() html part missing %q\n%s", want, parts[1].body)
+				}
+			}
+		})
+	}
+}
+
+// newTestRenderer returns a renderer of the built-in templates.
+func newTestRenderer(t *testing.T) *templates.Renderer {
+	t.Helper()
+	renderer, err := templates.NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer() unexpected error: %v", err)
+	}
+	return renderer
+}
+
+type part struct {
+	contentType string
+	body        string
+}
+
+// readParts decodes the parts of a multipart/alternative message.
+func readParts(t *testing.T, msg *mail.Message) []part {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %s, want multipart/alternative", msg.Header.Get("Content-Type"))
+	}
+
+	var parts []part
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := r.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read part: %v", err)
+		}
+		if p.Header.Get("Content-Transfer-Encoding") != "quoted-printable" {
+			t.Errorf("part Content-Transfer-Encoding = %s, want quoted-printable", p.Header.Get("Content-Transfer-Encoding"))
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(p))
+		if err != nil {
+			t.Fatalf("failed to decode part: %v", err)
+		}
+		contentType, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		parts = append(parts, part{contentType: contentType, body: string(body)})
+	}
+
+	if len(parts) != 2 {
+		t.Fatalf("message has %d parts, want 2", len(parts))
+	}
+	return parts
+}
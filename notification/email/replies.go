@@ -0,0 +1,523 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/common"
+)
+
+const (
+	// maxReplySize is the maximum accepted size of a reply.
+	maxReplySize = 1 << 20
+	// defaultReplyInterval is how often Watch checks for new replies by
+	// default.
+	defaultReplyInterval = 10 * time.Second
+	// processedDir is the directory .eml replies are moved to once they
+	// are processed.
+	processedDir = "processed"
+)
+
+var (
+	// ErrUncorrelatedReply is returned when a reply cannot be matched to
+	// an approval request.
+	ErrUncorrelatedReply = errors.New("reply does not refer to an approval request")
+	// ErrNoDecision is returned when a reply contains no approve or reject
+	// keyword.
+	ErrNoDecision = errors.New("reply contains no decision")
+	// ErrAmbiguousReply is returned when a reply does not say which of
+	// several approval requests a decision is on, or decides a request
+	// both ways.
+	ErrAmbiguousReply = errors.New("ambiguous reply")
+)
+
+// subjectTokenPattern matches the approval request token in the subject of
+// approval emails, e.g. [#42].
+var subjectTokenPattern = regexp.MustCompile(`\[#(\d+)\]`)
+
+// requestRefPattern matches a reference to an approval request in a line
+// of a reply, e.g. #42.
+var requestRefPattern = regexp.MustCompile(`#(\d+)\b`)
+
+// quoteHeaderPattern matches the line most mail clients put above the
+// quoted original message, e.g. "On Fri, 2 Jan 2026, Approvals wrote:".
+var quoteHeaderPattern = regexp.MustCompile(`(?i)^(on|am|den) .*(wrote|schrieb|skrev):$`)
+
+// approveKeywords and rejectKeywords are the words, in the supported
+// locales, that make up a line of a reply that approves or rejects.
+var (
+	approveKeywords = []string{
+		"approve", "approved", "accept", "accepted", "yes",
+		"godkänn", "godkänd", "godkänt", "godkänner", "ja",
+		"genehmigt", "genehmigen", "genehmige", "freigegeben",
+	}
+	rejectKeywords = []string{
+		"reject", "rejected", "decline", "declined", "deny", "denied", "no",
+		"avvisa", "avvisad", "avslå", "avslås", "nej",
+		"abgelehnt", "ablehnen", "lehne", "nein",
+	}
+	// conjunctions join the requests a line decides, e.g. "approve #7 and
+	// #9".
+	conjunctions = []string{"and", "och", "und"}
+)
+
+// ReplyRecorder records the decisions in replies to approval emails.
+type ReplyRecorder interface {
+	DecisionRecorder
+	// ApprovalRequestsByMessageID returns the IDs of the approval requests
+	// delivered over the channel in the message with the ID.
+	ApprovalRequestsByMessageID(channel, messageID string) ([]int, error)
+}
+
+// Reply is a reply to an approval email.
+type Reply struct {
+	// From is the address of the sender.
+	From string
+	// Subject is the decoded subject.
+	Subject string
+	// References are the Message-IDs of the In-Reply-To and References
+	// headers, the message replied to first.
+	References []string
+	// Text is the plain text body.
+	Text string
+	// Date is when the sender claims to have sent the reply, or the zero
+	// time if unknown. Decisions are recorded at the time the reply is
+	// processed instead.
+	Date time.Time
+}
+
+// ReplyResult counts the replies handled by a pass over a directory.
+// Retried replies are left in place for the next pass.
+type ReplyResult struct {
+	Replies   int
+	Decisions int
+	Failed    int
+	Retried   int
+}
+
+// ReplyProcessor records the approve and reject decisions in replies to
+// approval emails.
+type ReplyProcessor struct {
+	log      common.Logger
+	recorder ReplyRecorder
+	refused  func(error) bool
+	now      func() time.Time
+}
+
+// ReplyProcessor returns a processor for the replies to the service's
+// approval emails. A reply is matched to the approval requests of the
+// email it replies to by its In-Reply-To and References headers, or else
+// by the [#id] token in its subject, and the decision is recorded as made
+// by the sender, which must be the approver. refused reports whether the
+// recorder refused a decision, in which case recording it again cannot
+// succeed. Replies that failed for any other reason are retried.
+func (s *service) ReplyProcessor(recorder ReplyRecorder, refused func(error) bool) (*ReplyProcessor, error) {
+	if recorder == nil {
+		return nil, errors.New("reply recorder is required to process email replies")
+	}
+	if refused == nil {
+		return nil, errors.New("refused is required to process email replies")
+	}
+	return &ReplyProcessor{log: s.log, recorder: recorder, refused: refused, now: s.now}, nil
+}
+
+// ProcessReply parses an RFC 5322 reply and records its decisions at the
+// time it is processed. A line of the reply above the quoted original
+// message that is only an approve or reject keyword is a decision, so that
+// "No problem, approved" decides nothing. In a reply to a digest, the line
+// must name the request, e.g. "approve #42". It returns the decided
+// approval requests.
+func (p *ReplyProcessor) ProcessReply(r io.Reader) ([]api.ApprovalRequest, error) {
+	reply, err := ParseReply(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := p.correlate(reply)
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := parseDecisions(reply.Text, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	decidedAt := p.now()
+	var decided []api.ApprovalRequest
+	var errs []error
+	for _, id := range slices.Sorted(maps.Keys(decisions)) {
+		request, err := p.recorder.RecordDecision(api.ApprovalDecision{
+			ApprovalRequestID: id,
+			Status:            decisions[id],
+			Channel:           ChannelName,
+			DecidedBy:         reply.From,
+			DecidedAt:         decidedAt,
+		})
+		if err != nil {
+			p.log.Error("failed to record decision from email reply",
+				"approval_request_id", id,
+				"from", reply.From,
+				"error", err,
+			)
+			err = fmt.Errorf("approval request %d: %w", id, err)
+			if !p.refused(err) {
+				err = &retryableError{err: err}
+			}
+			errs = append(errs, err)
+			continue
+		}
+		decided = append(decided, request)
+	}
+	return decided, errors.Join(errs...)
+}
+
+// ProcessDir records the decisions in the replies in dir, which is either
+// a maildir or a directory of .eml files. Processed replies are moved out
+// of the way, also when their decisions were refused: maildir messages
+// from new to cur, marked as seen, and .eml files to the processed
+// subdirectory. Replies that failed for another reason, e.g. because the
+// database was unavailable, are left in place for the next pass.
+func (p *ReplyProcessor) ProcessDir(dir string) (ReplyResult, error) {
+	files, maildir, err := replyFiles(dir)
+	if err != nil {
+		return ReplyResult{}, err
+	}
+
+	var result ReplyResult
+	for _, file := range files {
+		decided, err := p.processFile(file)
+		result.Replies++
+		result.Decisions += len(decided)
+		var retryable *retryableError
+		if errors.As(err, &retryable) {
+			result.Retried++
+			p.log.Error("failed to process email reply, retrying", "file", file, "error", err)
+			continue
+		}
+		if err != nil {
+			result.Failed++
+			p.log.Error("failed to process email reply", "file", file, "error", err)
+		}
+
+		if err := moveProcessed(file, maildir); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// Watch processes the replies in dir every interval, by default every 10
+// seconds, until the context is cancelled.
+func (p *ReplyProcessor) Watch(ctx context.Context, dir string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultReplyInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := p.ProcessDir(dir)
+		if err != nil {
+			p.log.Error("failed to process email replies", "dir", dir, "error", err)
+		} else if result.Replies > 0 {
+			p.log.Info("processed email replies",
+				"replies", result.Replies,
+				"decisions", result.Decisions,
+				"failed", result.Failed,
+				"retried", result.Retried,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// processFile records the decisions in the reply in file.
+func (p *ReplyProcessor) processFile(file string) ([]api.ApprovalRequest, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, &retryableError{err: err}
+	}
+	defer f.Close()
+	return p.ProcessReply(f)
+}
+
+// correlate returns the IDs of the approval requests the reply is about.
+func (p *ReplyProcessor) correlate(reply Reply) ([]int, error) {
+	for _, messageID := range reply.References {
+		ids, err := p.recorder.ApprovalRequestsByMessageID(ChannelName, messageID)
+		if err != nil {
+			return nil, &retryableError{err: fmt.Errorf("failed to find approval requests of message %s: %w", messageID, err)}
+		}
+		if len(ids) > 0 {
+			return ids, nil
+		}
+	}
+
+	if match := subjectTokenPattern.FindStringSubmatch(reply.Subject); match != nil {
+		id, err := strconv.Atoi(match[1])
+		if err == nil {
+			return []int{id}, nil
+		}
+	}
+	return nil, ErrUncorrelatedReply
+}
+
+// ParseReply parses an RFC 5322 reply to an approval email.
+func ParseReply(r io.Reader) (Reply, error) {
+	msg, err := mail.ReadMessage(io.LimitReader(r, maxReplySize))
+	if err != nil {
+		return Reply{}, fmt.Errorf("invalid email reply: %w", err)
+	}
+
+	from, err := msg.Header.AddressList("From")
+	if err != nil || len(from) == 0 {
+		return Reply{}, fmt.Errorf("invalid email reply: missing sender: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+
+	reply := Reply{
+		From:    from[0].Address,
+		Subject: subject,
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		reply.Date = date
+	}
+
+	// The message replied to comes first, then the rest of the thread from
+	// the most recent message back.
+	references := messageIDs(msg.Header.Get("References"))
+	slices.Reverse(references)
+	for _, id := range append(messageIDs(msg.Header.Get("In-Reply-To")), references...) {
+		if !slices.Contains(reply.References, id) {
+			reply.References = append(reply.References, id)
+		}
+	}
+
+	reply.Text, err = plainText(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return Reply{}, fmt.Errorf("invalid email reply: %w", err)
+	}
+	return reply, nil
+}
+
+// messageIDs returns the Message-IDs in a header value.
+func messageIDs(value string) []string {
+	var ids []string
+	for _, field := range strings.Fields(value) {
+		if strings.HasPrefix(field, "<") && strings.HasSuffix(field, ">") {
+			ids = append(ids, field)
+		}
+	}
+	return ids
+}
+
+// plainText returns the plain text of a body with the content type and
+// transfer encoding. Of a multipart body, the first text/plain part is
+// returned.
+func plainText(contentType, transferEncoding string, body io.Reader) (string, error) {
+	mediaType := "text/plain"
+	var params map[string]string
+	if contentType != "" {
+		var err error
+		if mediaType, params, err = mime.ParseMediaType(contentType); err != nil {
+			return "", fmt.Errorf("invalid content type %q: %w", contentType, err)
+		}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return "", errors.New("no plain text part")
+			}
+			if err != nil {
+				return "", fmt.Errorf("invalid multipart body: %w", err)
+			}
+			text, err := plainText(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err == nil {
+				return text, nil
+			}
+		}
+	}
+	if mediaType != "text/plain" {
+		return "", fmt.Errorf("unsupported content type %s", mediaType)
+	}
+
+	switch strings.ToLower(transferEncoding) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	text, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read plain text: %w", err)
+	}
+	return string(text), nil
+}
+
+// parseDecisions returns the decisions in the text of a reply about the
+// approval requests with the IDs, by request ID.
+func parseDecisions(text string, ids []int) (map[int]api.ApprovalStatus, error) {
+	decisions := make(map[int]api.ApprovalStatus)
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if isQuoteStart(line) {
+			break
+		}
+
+		status, ok := lineDecision(line)
+		if !ok {
+			continue
+		}
+
+		targets := ids
+		if refs := requestRefPattern.FindAllStringSubmatch(line, -1); refs != nil {
+			targets = nil
+			for _, ref := range refs {
+				id, _ := strconv.Atoi(ref[1])
+				if !slices.Contains(ids, id) {
+					return nil, fmt.Errorf("%w: #%d is not in the email replied to", ErrAmbiguousReply, id)
+				}
+				targets = append(targets, id)
+			}
+		} else if len(ids) > 1 {
+			return nil, fmt.Errorf("%w: %q does not say which request it decides, e.g. %q", ErrAmbiguousReply, line, fmt.Sprintf("%s #%d", line, ids[0]))
+		}
+
+		for _, id := range targets {
+			if previous, ok := decisions[id]; ok && previous != status {
+				return nil, fmt.Errorf("%w: #%d is both approved and rejected", ErrAmbiguousReply, id)
+			}
+			decisions[id] = status
+		}
+	}
+
+	if len(decisions) == 0 {
+		return nil, ErrNoDecision
+	}
+	return decisions, nil
+}
+
+// lineDecision returns the decision of a line that is only an approve or
+// reject keyword, besides references to requests, conjunctions and
+// punctuation, e.g. "Approved." or "reject #42 and #43".
+func lineDecision(line string) (api.ApprovalStatus, bool) {
+	line = requestRefPattern.ReplaceAllString(strings.ToLower(line), " ")
+	var words []string
+	for _, field := range strings.Fields(line) {
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r > 127)
+		})
+		if word != "" && !slices.Contains(conjunctions, word) {
+			words = append(words, word)
+		}
+	}
+	if len(words) != 1 {
+		return "", false
+	}
+	word := words[0]
+	switch {
+	case slices.Contains(approveKeywords, word):
+		return api.ApprovalStatusApproved, true
+	case slices.Contains(rejectKeywords, word):
+		return api.ApprovalStatusRejected, true
+	}
+	return "", false
+}
+
+// isQuoteStart reports whether a line starts the quoted original message
+// or the signature of a reply.
+func isQuoteStart(line string) bool {
+	return strings.HasPrefix(line, ">") ||
+		line == "--" ||
+		strings.HasPrefix(line, "-----Original Message-----") ||
+		quoteHeaderPattern.MatchString(line)
+}
+
+// replyFiles returns the reply files in dir and whether dir is a maildir.
+func replyFiles(dir string) ([]string, bool, error) {
+	newDir := filepath.Join(dir, "new")
+	if info, err := os.Stat(newDir); err == nil && info.IsDir() {
+		entries, err := os.ReadDir(newDir)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to read maildir: %w", err)
+		}
+		var files []string
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+				files = append(files, filepath.Join(newDir, entry.Name()))
+			}
+		}
+		return files, true, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list replies: %w", err)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, false, fmt.Errorf("failed to read replies: %w", err)
+	}
+	return files, false, nil
+}
+
+// moveProcessed moves a processed reply out of the way: a maildir message
+// to cur, marked as seen, and an .eml file to the processed subdirectory.
+func moveProcessed(file string, maildir bool) error {
+	dir, name := filepath.Split(file)
+	var target string
+	if maildir {
+		target = filepath.Join(dir, "..", "cur", name+":2,S")
+	} else {
+		target = filepath.Join(dir, processedDir, name)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to move processed reply: %w", err)
+	}
+	if err := os.Rename(file, target); err != nil {
+		return fmt.Errorf("failed to move processed reply: %w", err)
+	}
+	return nil
+}
+
+// retryableError wraps a failure to process a reply that can succeed on
+// the next pass.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,350 @@
+package email
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Reply
+		wantErr bool
+	}{
+		{
+			name: "plain text reply",
+			input: "From: Amanda Svensson <amanda@light.com>\r\n" +
+				"Subject: Re: Invoice approval request [#7]\r\n" +
+				"Date: Fri, 02 Jan 2026 16:00:00 +0000\r\n" +
+				"In-Reply-To: <1.a@light.com>\r\n" +
+				"References: <0.a@light.com> <1.a@light.com>\r\n" +
+				"\r\n" +
+				"Approved, thanks.\r\n",
+			want: Reply{
+				From:       "amanda@light.com",
+				Subject:    "Re: Invoice approval request [#7]",
+				References: []string{"<1.a@light.com>", "<0.a@light.com>"},
+				Text:       "Approved, thanks.\r\n",
+				Date:       time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "multipart reply with encoded subject and quoted-printable text",
+			input: "From: amanda@light.com\r\n" +
+				"Subject: =?UTF-8?Q?SV:_Beg=C3=A4ran_om_fakturagodk=C3=A4nnande_[#8]?=\r\n" +
+				"Content-Type: multipart/alternative; boundary=b\r\n" +
+				"\r\n" +
+				"--b\r\n" +
+				"Content-Type: text/plain; charset=UTF-8\r\n" +
+				"Content-Transfer-Encoding: quoted-printable\r\n" +
+				"\r\n" +
+				"Godk=C3=A4nd\r\n" +
+				"--b\r\n" +
+				"Content-Type: text/html; charset=UTF-8\r\n" +
+				"\r\n" +
+				"<p>Godkänd</p>\r\n" +
+				"--b--\r\n",
+			want: Reply{
+				From:    "amanda@light.com",
+				Subject: "SV: Begäran om fakturagodkännande [#8]",
+				Text:    "Godkänd",
+			},
+		},
+		{
+			name: "base64 text",
+			input: "From: amanda@light.com\r\n" +
+				"Subject: Re: Invoice approval request [#7]\r\n" +
+				"Content-Type: text/plain; charset=UTF-8\r\n" +
+				"Content-Transfer-Encoding: base64\r\n" +
+				"\r\n" +
+				"UmVqZWN0\r\n",
+			want: Reply{
+				From:    "amanda@light.com",
+				Subject: "Re: Invoice approval request [#7]",
+				Text:    "Reject",
+			},
+		},
+		{
+			name:    "missing sender",
+			input:   "Subject: Re: Invoice approval request [#7]\r\n\r\nApproved\r\n",
+			wantErr: true,
+		},
+		{
+			name: "no plain text",
+			input: "From: amanda@light.com\r\n" +
+				"Content-Type: text/html\r\n" +
+				"\r\n" +
+				"<p>Approved</p>\r\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseReply(strings.NewReader(test.input))
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("ParseReply() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReply() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ParseReply() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReplyProcessor_ProcessReply(t *testing.T) {
+	now := time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC)
+	decision := func(id int, status api.ApprovalStatus) api.ApprovalDecision {
+		return api.ApprovalDecision{
+			ApprovalRequestID: id,
+			Status:            status,
+			Channel:           ChannelName,
+			DecidedBy:         "amanda@light.com",
+			DecidedAt:         now,
+		}
+	}
+	reply := func(headers, body string) string {
+		return "From: Amanda Svensson <amanda@light.com>\r\n" + headers + "\r\n" + body
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		messages map[string][]int
+		errs     []error
+		want     []api.ApprovalDecision
+		wantErr  error
+	}{
+		{
+			name:     "correlated by In-Reply-To",
+			input:    reply("Subject: Re: Invoice approval request\r\nIn-Reply-To: <1.a@light.com>\r\n", "Approve\r\n"),
+			messages: map[string][]int{"<1.a@light.com>": {7}},
+			want:     []api.ApprovalDecision{decision(7, api.ApprovalStatusApproved)},
+		},
+		{
+			name:     "correlated by References of a reply to a reply",
+			input:    reply("In-Reply-To: <2.b@example.com>\r\nReferences: <1.a@light.com> <2.b@example.com>\r\n", "no\r\n"),
+			messages: map[string][]int{"<1.a@light.com>": {7}},
+			want:     []api.ApprovalDecision{decision(7, api.ApprovalStatusRejected)},
+		},
+		{
+			name:  "correlated by subject token",
+			input: reply("Subject: Re: Invoice approval request [#7]\r\n", "Rejected.\r\nWrong vendor.\r\n"),
+			want:  []api.ApprovalDecision{decision(7, api.ApprovalStatusRejected)},
+		},
+		{
+			name:    "keyword in a sentence",
+			input:   reply("Subject: Re: Invoice approval request [#7]\r\n", "No problem, approved once the PO is attached.\r\n"),
+			wantErr: ErrNoDecision,
+		},
+		{
+			name:  "decided when processed, whatever the date of the reply",
+			input: reply("Subject: Re: Invoice approval request [#7]\r\nDate: Mon, 1 Dec 2025 09:00:00 +0000\r\n", "APPROVE\r\n"),
+			want:  []api.ApprovalDecision{decision(7, api.ApprovalStatusApproved)},
+		},
+		{
+			name:  "localized keyword",
+			input: reply("Subject: AW: Rechnungsfreigabe [#7]\r\n", "Genehmigt.\r\n"),
+			want:  []api.ApprovalDecision{decision(7, api.ApprovalStatusApproved)},
+		},
+		{
+			name: "quoted text is ignored",
+			input: reply("Subject: Re: Invoice approval request [#7]\r\n",
+				"Please send me the invoice first.\r\n\r\nOn Fri, 2 Jan 2026, Approvals wrote:\r\n> Approve or reject the request.\r\n"),
+			wantErr: ErrNoDecision,
+		},
+		{
+			name:     "digest with a decision per request",
+			input:    reply("In-Reply-To: <1.a@light.com>\r\n", "Approve #7 and #9\r\nreject #8\r\n"),
+			messages: map[string][]int{"<1.a@light.com>": {7, 8, 9}},
+			want: []api.ApprovalDecision{
+				decision(7, api.ApprovalStatusApproved),
+				decision(8, api.ApprovalStatusRejected),
+				decision(9, api.ApprovalStatusApproved),
+			},
+		},
+		{
+			name:     "digest without request",
+			input:    reply("In-Reply-To: <1.a@light.com>\r\n", "Approve\r\n"),
+			messages: map[string][]int{"<1.a@light.com>": {7, 8}},
+			wantErr:  ErrAmbiguousReply,
+		},
+		{
+			name:     "request not in the email replied to",
+			input:    reply("In-Reply-To: <1.a@light.com>\r\n", "Approve #12\r\n"),
+			messages: map[string][]int{"<1.a@light.com>": {7, 8}},
+			wantErr:  ErrAmbiguousReply,
+		},
+		{
+			name:    "approved and rejected",
+			input:   reply("Subject: Re: Invoice approval request [#7]\r\n", "Approve\r\nReject\r\n"),
+			wantErr: ErrAmbiguousReply,
+		},
+		{
+			name:    "uncorrelated",
+			input:   reply("Subject: Lunch?\r\n", "Yes\r\n"),
+			wantErr: ErrUncorrelatedReply,
+		},
+		{
+			name:    "sender is not the approver",
+			input:   reply("Subject: Re: Invoice approval request [#7]\r\n", "Approve\r\n"),
+			errs:    []error{errNotAssigned},
+			want:    []api.ApprovalDecision{decision(7, api.ApprovalStatusApproved)},
+			wantErr: errNotAssigned,
+		},
+		{
+			name:    "recording failed",
+			input:   reply("Subject: Re: Invoice approval request [#7]\r\n", "Approve\r\n"),
+			errs:    []error{errUnavailable},
+			want:    []api.ApprovalDecision{decision(7, api.ApprovalStatusApproved)},
+			wantErr: errUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, err := NewService("smtp://localhost:25", WithLogger(&mockLogger{}))
+			if err != nil {
+				t.Fatalf("NewService() unexpected error: %v", err)
+			}
+			svc.now = func() time.Time { return now }
+			recorder := &mockReplyRecorder{mockRecorder: mockRecorder{errs: test.errs}, messages: test.messages}
+			processor, err := svc.ReplyProcessor(recorder, refused)
+			if err != nil {
+				t.Fatalf("ReplyProcessor() unexpected error: %v", err)
+			}
+
+			_, err = processor.ProcessReply(strings.NewReader(test.input))
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("ProcessReply() error = %v, want %v", err, test.wantErr)
+			}
+			if diff := cmp.Diff(test.want, recorder.got); diff != "" {
+				t.Errorf("ProcessReply() decisions mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReplyProcessor_ProcessDir(t *testing.T) {
+	approve := "From: amanda@light.com\r\nSubject: Re: Invoice approval request [#7]\r\n\r\nApprove\r\n"
+	unrelated := "From: amanda@light.com\r\nSubject: Lunch?\r\n\r\nYes\r\n"
+
+	tests := []struct {
+		name string
+		// files are the replies written to dir, by path.
+		files map[string]string
+		// errs are returned by the recorder for successive decisions.
+		errs []error
+		want ReplyResult
+		// wantFiles are the files in dir after processing.
+		wantFiles []string
+	}{
+		{
+			name:      "maildir",
+			files:     map[string]string{"new/1.host": approve, "new/2.host": unrelated, "tmp/3.host": approve},
+			want:      ReplyResult{Replies: 2, Decisions: 1, Failed: 1},
+			wantFiles: []string{"cur/1.host:2,S", "cur/2.host:2,S", "tmp/3.host"},
+		},
+		{
+			name:      "eml directory",
+			files:     map[string]string{"1.eml": approve, "2.eml": unrelated, "notes.txt": approve},
+			want:      ReplyResult{Replies: 2, Decisions: 1, Failed: 1},
+			wantFiles: []string{"notes.txt", "processed/1.eml", "processed/2.eml"},
+		},
+		{
+			name:      "refused decision",
+			files:     map[string]string{"1.eml": approve},
+			errs:      []error{errNotAssigned},
+			want:      ReplyResult{Replies: 1, Failed: 1},
+			wantFiles: []string{"processed/1.eml"},
+		},
+		{
+			name:      "decision to retry",
+			files:     map[string]string{"1.eml": approve},
+			errs:      []error{errUnavailable},
+			want:      ReplyResult{Replies: 1, Retried: 1},
+			wantFiles: []string{"1.eml"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range test.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			svc, err := NewService("smtp://localhost:25", WithLogger(&mockLogger{}))
+			if err != nil {
+				t.Fatalf("NewService() unexpected error: %v", err)
+			}
+			processor, err := svc.ReplyProcessor(&mockReplyRecorder{mockRecorder: mockRecorder{errs: test.errs}}, refused)
+			if err != nil {
+				t.Fatalf("ReplyProcessor() unexpected error: %v", err)
+			}
+
+			got, err := processor.ProcessDir(dir)
+			if err != nil {
+				t.Fatalf("ProcessDir() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ProcessDir() mismatch (-want +got)\n%s", diff)
+			}
+
+			var gotFiles []string
+			err = filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+				if err == nil && !entry.IsDir() {
+					rel, _ := filepath.Rel(dir, path)
+					gotFiles = append(gotFiles, filepath.ToSlash(rel))
+				}
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.wantFiles, gotFiles); diff != "" {
+				t.Errorf("ProcessDir() files mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+var (
+	errNotAssigned = errors.New("decision made by an approver the request is not assigned to")
+	errUnavailable = errors.New("database is unavailable")
+)
+
+// refused reports whether the decision was refused with errNotAssigned.
+func refused(err error) bool {
+	return errors.Is(err, errNotAssigned)
+}
+
+type mockReplyRecorder struct {
+	mockRecorder
+	// messages are the approval request IDs by message ID.
+	messages map[string][]int
+}
+
+func (m *mockReplyRecorder) ApprovalRequestsByMessageID(channel, messageID string) ([]int, error) {
+	return m.messages[messageID], nil
+}
@@ -0,0 +1,388 @@
+// Package smtptest provides an in-process SMTP server for testing code that
+// sends email, in the spirit of net/http/httptest.
+package smtptest
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a message received by the server.
+type Message struct {
+	From string
+	To   []string
+	Data []byte
+	// TLS reports whether the message was received over STARTTLS.
+	TLS bool
+	// Username is the authenticated user, empty if the session did not
+	// authenticate.
+	Username string
+}
+
+// Options holds the configuration for the server.
+type Options struct {
+	// Username and Password enable AUTH PLAIN. When set, mail is only
+	// accepted from authenticated sessions.
+	Username string
+	Password string
+	// StartTLS enables the STARTTLS extension with a self-signed
+	// certificate for 127.0.0.1 and localhost.
+	StartTLS bool
+	// RejectRecipients lists recipients refused with a permanent error.
+	RejectRecipients []string
+}
+
+// Option is a function that configures the server.
+type Option func(*Options)
+
+// Server is an SMTP server listening on a local loopback address.
+type Server struct {
+	// Addr is the host:port address of the server.
+	Addr string
+
+	opts      Options
+	listener  net.Listener
+	tlsConfig *tls.Config
+	certPool  *x509.CertPool
+
+	mu       sync.Mutex
+	messages []Message
+	wg       sync.WaitGroup
+}
+
+// NewServer starts and returns a new server. The caller should call Close
+// when finished, to shut it down.
+func NewServer(options ...Option) (*Server, error) {
+	opts := Options{}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("smtptest: failed to listen: %w", err)
+	}
+
+	s := &Server{
+		Addr:     listener.Addr().String(),
+		opts:     opts,
+		listener: listener,
+	}
+
+	if opts.StartTLS {
+		if err := s.generateCertificate(); err != nil {
+			listener.Close()
+			return nil, err
+		}
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// WithAuth requires clients to authenticate with AUTH PLAIN.
+func WithAuth(username, password string) Option {
+	return func(o *Options) {
+		o.Username = username
+		o.Password = password
+	}
+}
+
+// WithStartTLS enables the STARTTLS extension.
+func WithStartTLS() Option {
+	return func(o *Options) {
+		o.StartTLS = true
+	}
+}
+
+// WithRejectRecipients refuses mail to the given recipients.
+func WithRejectRecipients(recipients ...string) Option {
+	return func(o *Options) {
+		o.RejectRecipients = append(o.RejectRecipients, recipients...)
+	}
+}
+
+// Host returns the host of the server address.
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.Addr)
+	return host
+}
+
+// Port returns the port of the server address.
+func (s *Server) Port() string {
+	_, port, _ := net.SplitHostPort(s.Addr)
+	return port
+}
+
+// RootCAs returns a certificate pool trusting the server's STARTTLS
+// certificate. It is nil unless STARTTLS is enabled.
+func (s *Server) RootCAs() *x509.CertPool {
+	return s.certPool
+}
+
+// Messages returns the messages received so far.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// Close shuts down the server and waits for open sessions to finish.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+// serve accepts connections until the listener is closed.
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// session holds the state of a single SMTP session.
+type session struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	tls      bool
+	username string
+	from     string
+	to       []string
+}
+
+func (s *session) reply(format string, args ...any) {
+	fmt.Fprintf(s.conn, format+"\r\n", args...)
+}
+
+// handle runs an SMTP session on conn.
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	sess := &session{conn: conn, reader: bufio.NewReader(conn)}
+	sess.reply("220 smtptest ESMTP ready")
+
+	for {
+		line, err := sess.reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			s.ehlo(sess)
+		case "STARTTLS":
+			if s.tlsConfig == nil || sess.tls {
+				sess.reply("502 5.5.1 STARTTLS not available")
+				continue
+			}
+			sess.reply("220 2.0.0 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			sess.conn = tlsConn
+			sess.reader = bufio.NewReader(tlsConn)
+			sess.tls = true
+		case "AUTH":
+			s.auth(sess, arg)
+		case "MAIL":
+			if s.opts.Username != "" && sess.username == "" {
+				sess.reply("530 5.7.0 Authentication required")
+				continue
+			}
+			from, ok := pathArg(arg, "FROM:")
+			if !ok {
+				sess.reply("501 5.5.4 Syntax error in parameters")
+				continue
+			}
+			sess.from, sess.to = from, nil
+			sess.reply("250 2.1.0 OK")
+		case "RCPT":
+			to, ok := pathArg(arg, "TO:")
+			if !ok {
+				sess.reply("501 5.5.4 Syntax error in parameters")
+				continue
+			}
+			if s.rejected(to) {
+				sess.reply("550 5.1.1 Recipient rejected")
+				continue
+			}
+			sess.to = append(sess.to, to)
+			sess.reply("250 2.1.5 OK")
+		case "DATA":
+			if sess.from == "" || len(sess.to) == 0 {
+				sess.reply("503 5.5.1 Bad sequence of commands")
+				continue
+			}
+			sess.reply("354 Start mail input; end with <CRLF>.<CRLF>")
+			data, err := readData(sess.reader)
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, Message{
+				From:     sess.from,
+				To:       sess.to,
+				Data:     data,
+				TLS:      sess.tls,
+				Username: sess.username,
+			})
+			s.mu.Unlock()
+			sess.from, sess.to = "", nil
+			sess.reply("250 2.0.0 OK queued")
+		case "RSET":
+			sess.from, sess.to = "", nil
+			sess.reply("250 2.0.0 OK")
+		case "NOOP":
+			sess.reply("250 2.0.0 OK")
+		case "QUIT":
+			sess.reply("221 2.0.0 Bye")
+			return
+		default:
+			sess.reply("500 5.5.2 Command not recognized")
+		}
+	}
+}
+
+// ehlo replies to EHLO with the extensions enabled for the session.
+func (s *Server) ehlo(sess *session) {
+	extensions := []string{"smtptest"}
+	if s.tlsConfig != nil && !sess.tls {
+		extensions = append(extensions, "STARTTLS")
+	}
+	if s.opts.Username != "" {
+		extensions = append(extensions, "AUTH PLAIN")
+	}
+	for i, ext := range extensions {
+		sep := "-"
+		if i == len(extensions)-1 {
+			sep = " "
+		}
+		sess.reply("250%s%s", sep, ext)
+	}
+}
+
+// auth handles AUTH PLAIN with an initial response.
+func (s *Server) auth(sess *session, arg string) {
+	mechanism, initial, _ := strings.Cut(arg, " ")
+	if s.opts.Username == "" || !strings.EqualFold(mechanism, "PLAIN") {
+		sess.reply("504 5.5.4 Unrecognized authentication type")
+		return
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(initial)
+	if err != nil {
+		sess.reply("501 5.5.2 Invalid base64 data")
+		return
+	}
+	parts := bytes.Split(decoded, []byte{0})
+	if len(parts) != 3 || string(parts[1]) != s.opts.Username || string(parts[2]) != s.opts.Password {
+		sess.reply("535 5.7.8 Authentication credentials invalid")
+		return
+	}
+
+	sess.username = s.opts.Username
+	sess.reply("235 2.7.0 Authentication successful")
+}
+
+// rejected reports whether mail to the recipient is refused.
+func (s *Server) rejected(recipient string) bool {
+	for _, r := range s.opts.RejectRecipients {
+		if strings.EqualFold(r, recipient) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathArg extracts the address from a MAIL FROM or RCPT TO argument such
+// as "FROM:<a@example.com> BODY=8BITMIME".
+func pathArg(arg, prefix string) (string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", false
+	}
+	addr, _, _ := strings.Cut(strings.TrimSpace(arg[len(prefix):]), " ")
+	return strings.Trim(addr, "<>"), true
+}
+
+// readData reads a DATA section up to the terminating line with a single
+// dot, undoing dot-stuffing.
+func readData(r *bufio.Reader) ([]byte, error) {
+	var data bytes.Buffer
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if line == ".\r\n" {
+			return data.Bytes(), nil
+		}
+		data.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
+
+// generateCertificate creates a self-signed certificate for STARTTLS.
+func (s *Server) generateCertificate() error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("smtptest: failed to generate key: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"smtptest"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:              []string{"localhost"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("smtptest: failed to create certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return fmt.Errorf("smtptest: failed to parse certificate: %w", err)
+	}
+
+	s.certPool = x509.NewCertPool()
+	s.certPool.AddCert(cert)
+	s.tlsConfig = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+)
+
+const (
+	// defaultRateLimitBurst is the number of notifications an approver can
+	// receive on a channel at once.
+	defaultRateLimitBurst = 5
+	// defaultRateLimitInterval is how long it takes until an approver can
+	// receive one more notification on a channel.
+	defaultRateLimitInterval = time.Minute
+	// MaxRetryAfter is the longest Retry-After of a channel that is
+	// honoured.
+	MaxRetryAfter = 5 * time.Minute
+)
+
+// ErrRateLimited is returned when a notification is suppressed because the
+// approver has received too many notifications on the channel.
+var ErrRateLimited = errors.New("notification rate limit exceeded")
+
+// RateLimitError is returned by rate limited senders when a notification
+// is suppressed, and by senders whose channel rejected a notification
+// because of its own rate limit. It matches ErrRateLimited.
+type RateLimitError struct {
+	Channel   string
+	ContactID string
+	// RetryAfter is how long it takes until the approver can receive a
+	// notification on the channel again.
+	RetryAfter time.Duration
+	// Err is the error of the channel that rejected the notification, if
+	// any.
+	Err error
+}
+
+// Error returns the channel, contact and wait of the suppressed
+// notification.
+func (e *RateLimitError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%v: %s on %s: %v", ErrRateLimited, e.ContactID, e.Channel, e.Err)
+	}
+	return fmt.Sprintf("%v: %s on %s, retry after %s", ErrRateLimited, e.ContactID, e.Channel, e.RetryAfter)
+}
+
+// Is reports whether target is ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// Unwrap returns the error of the channel, if any.
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// ParseRetryAfter parses a Retry-After header given as a number of
+// seconds, as Slack, Teams and webhooks send it. It returns zero if the
+// header is missing or invalid. Longer waits are capped at MaxRetryAfter,
+// so that a channel cannot hold back its notifications indefinitely.
+func ParseRetryAfter(v string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	if seconds > int(MaxRetryAfter/time.Second) {
+		return MaxRetryAfter
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// RateLimiter limits the notifications sent to each approver on each
+// channel with a token bucket: an approver can receive Burst notifications
+// on a channel at once, and one more every Interval after that. It is safe
+// for concurrent use.
+type RateLimiter struct {
+	burst    int
+	interval time.Duration
+	now      func() time.Time
+	mu       sync.Mutex
+	buckets  map[bucketKey]*bucket
+}
+
+// bucketKey identifies the token bucket of an approver's contact on a
+// channel.
+type bucketKey struct {
+	channel   string
+	contactID string
+}
+
+// bucket holds the tokens left in a token bucket when it was last used.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiterOptions holds the configuration for the rate limiter.
+type RateLimiterOptions struct {
+	Burst    int
+	Interval time.Duration
+}
+
+// RateLimiterOption is a function that configures the rate limiter.
+type RateLimiterOption func(*RateLimiter)
+
+// NewRateLimiter returns a rate limiter, by default of 5 notifications at
+// once and one more a minute.
+func NewRateLimiter(options ...RateLimiterOption) *RateLimiter {
+	l := &RateLimiter{
+		burst:    defaultRateLimitBurst,
+		interval: defaultRateLimitInterval,
+		now:      time.Now,
+		buckets:  make(map[bucketKey]*bucket),
+	}
+	for _, option := range options {
+		option(l)
+	}
+	return l
+}
+
+// Limit returns the channel with its sender behind the rate limiter. Sends
+// to an approver who has no tokens left on the channel are suppressed with
+// a *RateLimitError. A digest counts as one notification.
+func (l *RateLimiter) Limit(channel Channel) Channel {
+	limited := &limitedSender{
+		limiter:   l,
+		channel:   channel.Name,
+		contactID: channel.ContactID,
+		sender:    channel.Sender,
+	}
+	if digestSender, ok := channel.Sender.(DigestSender); ok {
+		channel.Sender = &limitedDigestSender{limitedSender: limited, digestSender: digestSender}
+	} else {
+		channel.Sender = limited
+	}
+	return channel
+}
+
+// take takes a token from the bucket of the contact on the channel. If the
+// bucket is empty, it returns a *RateLimitError with the wait until the
+// next token.
+func (l *RateLimiter) take(channel, contactID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	key := bucketKey{channel: channel, contactID: contactID}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), last: now}
+		l.buckets[key] = b
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(float64(l.burst), b.tokens+float64(elapsed)/float64(l.interval))
+		b.last = now
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return nil
+	}
+
+	return &RateLimitError{
+		Channel:    channel,
+		ContactID:  contactID,
+		RetryAfter: time.Duration((1 - b.tokens) * float64(l.interval)).Round(time.Second),
+	}
+}
+
+// limitedSender is a sender behind a rate limiter.
+type limitedSender struct {
+	limiter   *RateLimiter
+	channel   string
+	contactID func(approver api.Approver) string
+	sender    Sender
+}
+
+// SendApprovalRequest sends the approval request if the approver has not
+// reached the rate limit of the channel.
+func (s *limitedSender) SendApprovalRequest(approvalRequest api.ApprovalRequest) (api.ApprovalResponse, error) {
+	if err := s.limiter.take(s.channel, s.contactID(approvalRequest.Approver)); err != nil {
+		return api.ApprovalResponse{}, err
+	}
+	return s.sender.SendApprovalRequest(approvalRequest)
+}
+
+// limitedDigestSender is a sender of a channel with digests behind a rate
+// limiter.
+type limitedDigestSender struct {
+	*limitedSender
+	digestSender DigestSender
+}
+
+// SendDigest sends the digest if the approver has not reached the rate
+// limit of the channel.
+func (s *limitedDigestSender) SendDigest(digest api.Digest) (api.ApprovalResponse, error) {
+	if err := s.limiter.take(s.channel, s.contactID(digest.Approver)); err != nil {
+		return api.ApprovalResponse{}, err
+	}
+	return s.digestSender.SendDigest(digest)
+}
+
+// WithRateLimiterOptions configures the rate limiter with the given
+// RateLimiterOptions. Zero values keep the defaults.
+func WithRateLimiterOptions(options RateLimiterOptions) RateLimiterOption {
+	return func(l *RateLimiter) {
+		if options.Burst > 0 {
+			l.burst = options.Burst
+		}
+		if options.Interval > 0 {
+			l.interval = options.Interval
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package notification
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRateLimiter_Limit(t *testing.T) {
+	start := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	amanda := api.Approver{Name: "Amanda Svensson", Email: "amanda@light.com"}
+	sarah := api.Approver{Name: "Sarah Johnson", Email: "sarah@light.com"}
+
+	type send struct {
+		at       time.Duration
+		channel  string
+		approver api.Approver
+	}
+	tests := []struct {
+		name  string
+		sends []send
+		// want is the wait of each send, or -1 for a send that went through.
+		want []time.Duration
+	}{
+		{
+			name: "burst then suppressed until a token is refilled",
+			sends: []send{
+				{at: 0, channel: "email", approver: amanda},
+				{at: 0, channel: "email", approver: amanda},
+				{at: 0, channel: "email", approver: amanda},
+				{at: 20 * time.Second, channel: "email", approver: amanda},
+				{at: time.Minute, channel: "email", approver: amanda},
+			},
+			want: []time.Duration{-1, -1, time.Minute, 40 * time.Second, -1},
+		},
+		{
+			name: "buckets per approver and channel",
+			sends: []send{
+				{at: 0, channel: "email", approver: amanda},
+				{at: 0, channel: "email", approver: amanda},
+				{at: 0, channel: "email", approver: sarah},
+				{at: 0, channel: "slack", approver: amanda},
+				{at: 0, channel: "email", approver: amanda},
+			},
+			want: []time.Duration{-1, -1, -1, -1, time.Minute},
+		},
+		{
+			name: "refill is capped at the burst",
+			sends: []send{
+				{at: 0, channel: "email", approver: amanda},
+				{at: time.Hour, channel: "email", approver: amanda},
+				{at: time.Hour, channel: "email", approver: amanda},
+				{at: time.Hour, channel: "email", approver: amanda},
+			},
+			want: []time.Duration{-1, -1, -1, time.Minute},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := start
+			limiter := NewRateLimiter(WithRateLimiterOptions(RateLimiterOptions{Burst: 2, Interval: time.Minute}))
+			limiter.now = func() time.Time { return now }
+			channels := map[string]Channel{
+				"email": limiter.Limit(testChannel("email")),
+				"slack": limiter.Limit(testChannel("slack")),
+			}
+
+			var got []time.Duration
+			for _, send := range test.sends {
+				now = start.Add(send.at)
+				_, err := channels[send.channel].Sender.SendApprovalRequest(api.ApprovalRequest{Approver: send.approver})
+				var limitErr *RateLimitError
+				switch {
+				case err == nil:
+					got = append(got, -1)
+				case errors.As(err, &limitErr) && errors.Is(err, ErrRateLimited):
+					got = append(got, limitErr.RetryAfter)
+				default:
+					t.Fatalf("SendApprovalRequest() unexpected error: %v", err)
+				}
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("SendApprovalRequest() waits mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_Limit_Digests(t *testing.T) {
+	limiter := NewRateLimiter(WithRateLimiterOptions(RateLimiterOptions{Burst: 1, Interval: time.Minute}))
+
+	plain := limiter.Limit(testChannel("webhook"))
+	if _, ok := plain.Sender.(DigestSender); ok {
+		t.Errorf("Limit() sender of a channel without digests implements DigestSender")
+	}
+
+	channel := testChannel("slack")
+	channel.Sender = &mockDigestSender{}
+	limited := limiter.Limit(channel)
+	sender, ok := limited.Sender.(DigestSender)
+	if !ok {
+		t.Fatalf("Limit() sender of a channel with digests does not implement DigestSender")
+	}
+
+	digest := api.Digest{Approver: api.Approver{Email: "amanda@light.com"}}
+	if _, err := sender.SendDigest(digest); err != nil {
+		t.Fatalf("SendDigest() unexpected error: %v", err)
+	}
+	if _, err := sender.SendDigest(digest); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("SendDigest() error = %v, want %v", err, ErrRateLimited)
+	}
+}
+
+type mockDigestSender struct {
+	mockSender
+}
+
+func (m *mockDigestSender) SendDigest(digest api.Digest) (api.ApprovalResponse, error) {
+	return api.ApprovalResponse{}, nil
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "seconds", value: "30", want: 30 * time.Second},
+		{name: "surrounding spaces", value: " 5 ", want: 5 * time.Second},
+		{name: "capped", value: "86400", want: MaxRetryAfter},
+		{name: "missing", value: "", want: 0},
+		{name: "negative", value: "-1", want: 0},
+		{name: "http date", value: "Wed, 21 Oct 2026 07:28:00 GMT", want: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ParseRetryAfter(test.value); got != test.want {
+				t.Errorf("ParseRetryAfter(%q) = %s, want %s", test.value, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Package notification provides the registry of the channels approval
+// requests are sent over. The channels themselves live in subpackages,
+// such as notification/slack and notification/email.
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+)
+
+var (
+	// ErrUnknownChannel is returned when no channel is registered under a
+	// name.
+	ErrUnknownChannel = errors.New("unknown notification channel")
+	// ErrDuplicateChannel is returned when a channel is registered under a
+	// name that is already taken.
+	ErrDuplicateChannel = errors.New("notification channel already registered")
+	// ErrInvalidChannel is returned when a channel is registered without a
+	// valid name, sender or contact function.
+	ErrInvalidChannel = errors.New("invalid notification channel")
+)
+
+// channelNamePattern is the format of channel names: lower case letters,
+// digits, dashes and underscores, starting with a letter.
+var channelNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// Sender sends approval requests to approvers.
+type Sender interface {
+	SendApprovalRequest(approvalRequest api.ApprovalRequest) (api.ApprovalResponse, error)
+}
+
+// DigestSender sends digests of approval requests to approvers. Senders
+// of channels that can batch approval requests into one message implement
+// it; on other channels, the requests of a digest are sent one by one.
+type DigestSender interface {
+	SendDigest(digest api.Digest) (api.ApprovalResponse, error)
+}
+
+// Channel is a named channel approval requests are sent over.
+type Channel struct {
+	// Name is the name workflow rules reference the channel by, e.g. slack.
+	Name string
+	// Sender sends approval requests over the channel.
+	Sender Sender
+	// ContactID returns the approver's address on the channel, e.g. the
+	// Slack user ID. Decisions are only accepted from this address.
+	ContactID func(approver api.Approver) string
+}
+
+// Registry holds the channels registered by name. It is safe for
+// concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	channels map[string]Channel
+}
+
+// NewRegistry returns a registry with the given channels registered.
+func NewRegistry(channels ...Channel) (*Registry, error) {
+	r := &Registry{channels: make(map[string]Channel)}
+	for _, channel := range channels {
+		if err := r.Register(channel); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register registers a channel under its name.
+func (r *Registry) Register(channel Channel) error {
+	if !channelNamePattern.MatchString(channel.Name) {
+		return fmt.Errorf("%w: name %q must match %s", ErrInvalidChannel, channel.Name, channelNamePattern)
+	}
+	if channel.Sender == nil {
+		return fmt.Errorf("%w: %s has no sender", ErrInvalidChannel, channel.Name)
+	}
+	if channel.ContactID == nil {
+		return fmt.Errorf("%w: %s has no contact id function", ErrInvalidChannel, channel.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.channels[channel.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateChannel, channel.Name)
+	}
+	r.channels[channel.Name] = channel
+	return nil
+}
+
+// Lookup returns the channel registered under name.
+func (r *Registry) Lookup(name string) (Channel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	channel, ok := r.channels[name]
+	if !ok {
+		return Channel{}, fmt.Errorf("%w: %q (must be one of: %s)", ErrUnknownChannel, name, strings.Join(r.names(), ", "))
+	}
+	return channel, nil
+}
+
+// Names returns the names of the registered channels in alphabetical
+// order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.names()
+}
+
+func (r *Registry) names() []string {
+	names := make([]string, 0, len(r.channels))
+	for name := range r.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,127 @@
+package notification
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegistry_Register(t *testing.T) {
+	var tests = []struct {
+		name     string
+		channels []Channel
+		want     []string
+		wantErr  error
+	}{
+		{
+			name:     "register channels",
+			channels: []Channel{testChannel("slack"), testChannel("email"), testChannel("ms-teams")},
+			want:     []string{"email", "ms-teams", "slack"},
+		},
+		{
+			name:     "no channels",
+			channels: nil,
+			want:     []string{},
+		},
+		{
+			name:     "duplicate name",
+			channels: []Channel{testChannel("slack"), testChannel("slack")},
+			wantErr:  ErrDuplicateChannel,
+		},
+		{
+			name:     "invalid name",
+			channels: []Channel{testChannel("Slack Channel")},
+			wantErr:  ErrInvalidChannel,
+		},
+		{
+			name:     "missing sender",
+			channels: []Channel{{Name: "slack", ContactID: func(a api.Approver) string { return a.SlackID }}},
+			wantErr:  ErrInvalidChannel,
+		},
+		{
+			name:     "missing contact id function",
+			channels: []Channel{{Name: "slack", Sender: &mockSender{}}},
+			wantErr:  ErrInvalidChannel,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewRegistry(test.channels...)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("NewRegistry() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewRegistry() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got.Names()); diff != "" {
+				t.Errorf("Names() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	registry, err := NewRegistry(testChannel("slack"), testChannel("email"))
+	if err != nil {
+		t.Fatalf("NewRegistry() unexpected error: %v", err)
+	}
+
+	var tests = []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{
+			name:  "registered channel",
+			input: "email",
+		},
+		{
+			name:    "unknown channel",
+			input:   "sms",
+			wantErr: ErrUnknownChannel,
+		},
+		{
+			name:    "names are case sensitive",
+			input:   "Slack",
+			wantErr: ErrUnknownChannel,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := registry.Lookup(test.input)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("Lookup() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Lookup() unexpected error: %v", err)
+			}
+			if got.Name != test.input {
+				t.Errorf("Lookup() = %s, want %s", got.Name, test.input)
+			}
+		})
+	}
+}
+
+func testChannel(name string) Channel {
+	return Channel{
+		Name:      name,
+		Sender:    &mockSender{},
+		ContactID: func(a api.Approver) string { return a.Email },
+	}
+}
+
+type mockSender struct{}
+
+func (m *mockSender) SendApprovalRequest(approvalRequest api.ApprovalRequest) (api.ApprovalResponse, error) {
+	return api.ApprovalResponse{}, nil
+}
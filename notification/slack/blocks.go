@@ -0,0 +1,168 @@
+package slack
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/notification/templates"
+)
+
+const (
+	// approveActionID is the action ID of the Approve button.
+	approveActionID = "approve_invoice"
+	// rejectActionID is the action ID of the Reject button.
+	rejectActionID = "reject_invoice"
+	// decisionBlockID is the block ID of the block holding the buttons.
+	decisionBlockID = "approval_decision"
+	// digestBlockPrefix prefixes the block IDs of the blocks holding the
+	// buttons of the requests in a digest. It is followed by the request ID.
+	digestBlockPrefix = decisionBlockID + "_"
+)
+
+// textObject is a Block Kit text object.
+type textObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// buttonElement is a Block Kit button element.
+type buttonElement struct {
+	Type     string     `json:"type"`
+	Text     textObject `json:"text"`
+	ActionID string     `json:"action_id"`
+	Value    string     `json:"value"`
+	Style    string     `json:"style,omitempty"`
+}
+
+// block is a Block Kit layout block. Only the fields used by this package
+// are modelled.
+type block struct {
+	Type     string       `json:"type"`
+	BlockID  string       `json:"block_id,omitempty"`
+	Text     *textObject  `json:"text,omitempty"`
+	Fields   []textObject `json:"fields,omitempty"`
+	Elements []any        `json:"elements,omitempty"`
+}
+
+// approvalBlocks returns the blocks of an approval request message with
+// Approve and Reject buttons.
+func approvalBlocks(content templates.Content, approvalRequest api.ApprovalRequest) []block {
+	value := strconv.Itoa(approvalRequest.ID)
+
+	return append(invoiceBlocks(content), block{
+		Type:    "actions",
+		BlockID: decisionBlockID,
+		Elements: []any{
+			buttonElement{
+				Type:     "button",
+				Text:     plainText("Approve"),
+				ActionID: approveActionID,
+				Value:    value,
+				Style:    "primary",
+			},
+			buttonElement{
+				Type:     "button",
+				Text:     plainText("Reject"),
+				ActionID: rejectActionID,
+				Value:    value,
+				Style:    "danger",
+			},
+		},
+	})
+}
+
+// digestBlocks returns the blocks of a digest message: the rendered digest
+// followed by Approve and Reject buttons for every request in it.
+func digestBlocks(content templates.Content, digest api.Digest) []block {
+	blocks := invoiceBlocks(content)
+	for _, request := range digest.Requests {
+		value := strconv.Itoa(request.ID)
+		blocks = append(blocks, block{
+			Type:    "actions",
+			BlockID: digestBlockPrefix + value,
+			Elements: []any{
+				buttonElement{
+					Type:     "button",
+					Text:     plainText("Approve #" + value),
+					ActionID: approveActionID,
+					Value:    value,
+					Style:    "primary",
+				},
+				buttonElement{
+					Type:     "button",
+					Text:     plainText("Reject #" + value),
+					ActionID: rejectActionID,
+					Value:    value,
+					Style:    "danger",
+				},
+			},
+		})
+	}
+	return blocks
+}
+
+// decidedDigestBlocks returns the blocks of a digest message once a
+// decision on one of its requests has been made. The buttons of that
+// request, in the block with blockID, are replaced by who decided and
+// when; the other blocks are kept.
+func decidedDigestBlocks(blocks []block, blockID string, decision api.ApprovalDecision) []block {
+	decided := make([]block, 0, len(blocks))
+	for _, b := range blocks {
+		if b.BlockID == blockID {
+			b = block{
+				Type:     "context",
+				BlockID:  blockID,
+				Elements: []any{markdown(fmt.Sprintf("#%d: %s", decision.ApprovalRequestID, decisionText(decision)))},
+			}
+		}
+		decided = append(decided, b)
+	}
+	return decided
+}
+
+// decidedBlocks returns the blocks of an approval request message once a
+// decision has been made. The buttons are replaced by who decided and when.
+func decidedBlocks(content templates.Content, decision api.ApprovalDecision) []block {
+	return append(invoiceBlocks(content), block{
+		Type:     "context",
+		Elements: []any{markdown(decisionText(decision))},
+	})
+}
+
+// invoiceBlocks returns the blocks describing the invoice: the rendered
+// subject as header and the rendered text as mrkdwn section.
+func invoiceBlocks(content templates.Content) []block {
+	text := markdown(strings.TrimSpace(content.Text))
+
+	return []block{
+		{Type: "header", Text: &textObject{Type: "plain_text", Text: content.Subject}},
+		{Type: "section", Text: &text},
+	}
+}
+
+// decisionText describes who made the decision and when. The time is
+// rendered in the reader's time zone by Slack, with UTC as fallback.
+func decisionText(decision api.ApprovalDecision) string {
+	verb := "Approved"
+	icon := ":white_check_mark:"
+	if decision.Status == api.ApprovalStatusRejected {
+		verb = "Rejected"
+		icon = ":x:"
+	}
+	decidedAt := decision.DecidedAt.UTC()
+
+	return fmt.Sprintf("%s *%s* by <@%s> on <!date^%d^{date_short_pretty} at {time}|%s>",
+		icon, verb, decision.DecidedBy, decidedAt.Unix(), decidedAt.Format("2006-01-02 15:04 UTC"))
+}
+
+// plainText returns a plain text object.
+func plainText(text string) textObject {
+	return textObject{Type: "plain_text", Text: text}
+}
+
+// markdown returns a mrkdwn text object.
+func markdown(text string) textObject {
+	return textObject{Type: "mrkdwn", Text: text}
+}
@@ -0,0 +1,169 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+)
+
+const (
+	// defaultBaseURL is the base URL of the Slack Web API.
+	defaultBaseURL = "https://slack.com/api"
+	// defaultTimeout is the timeout for a single Slack Web API call.
+	defaultTimeout = 10 * time.Second
+)
+
+var (
+	// ErrAPI is matched by every APIError with errors.Is.
+	ErrAPI = errors.New("slack api error")
+	// ErrRateLimited is matched by every RateLimitError with errors.Is.
+	ErrRateLimited = errors.New("slack rate limit exceeded")
+)
+
+// APIError is returned when the Slack Web API responds with ok=false.
+type APIError struct {
+	Method string
+	Code   string
+}
+
+// Error returns a description of the failed API call.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrAPI, e.Method, e.Code)
+}
+
+// Is reports whether target is ErrAPI.
+func (e *APIError) Is(target error) bool {
+	return target == ErrAPI
+}
+
+// RateLimitError is returned when the Slack Web API rejects a call because
+// of rate limiting. RetryAfter is zero if Slack did not say when to retry.
+type RateLimitError struct {
+	Method     string
+	RetryAfter time.Duration
+}
+
+// Error returns a description of the rate limited API call.
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s: %s: retry after %s", ErrRateLimited, e.Method, e.RetryAfter)
+	}
+	return fmt.Sprintf("%s: %s", ErrRateLimited, e.Method)
+}
+
+// Is reports whether target is ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// client is a minimal Slack Web API client.
+type client struct {
+	token      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// postMessageRequest is the body of a chat.postMessage call.
+// Text is the notification fallback when Blocks are set.
+type postMessageRequest struct {
+	Channel string  `json:"channel"`
+	Text    string  `json:"text"`
+	Blocks  []block `json:"blocks,omitempty"`
+}
+
+// updateMessageRequest is the body of a chat.update call.
+type updateMessageRequest struct {
+	Channel string  `json:"channel"`
+	TS      string  `json:"ts"`
+	Text    string  `json:"text"`
+	Blocks  []block `json:"blocks"`
+}
+
+// apiResponse holds the fields common to every Web API response.
+type apiResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+	Warning string `json:"warning,omitempty"`
+}
+
+// postMessageResponse is the body returned by chat.postMessage.
+type postMessageResponse struct {
+	apiResponse
+	Channel string `json:"channel,omitempty"`
+	TS      string `json:"ts,omitempty"`
+}
+
+// postMessage posts a message with chat.postMessage.
+func (c *client) postMessage(msg postMessageRequest) (postMessageResponse, error) {
+	var resp postMessageResponse
+	if err := c.call("chat.postMessage", msg, &resp); err != nil {
+		return postMessageResponse{}, err
+	}
+	return resp, nil
+}
+
+// updateMessage updates a previously posted message with chat.update.
+func (c *client) updateMessage(msg updateMessageRequest) error {
+	var resp apiResponse
+	return c.call("chat.update", msg, &resp)
+}
+
+// call performs a Web API method call with a JSON body and decodes the
+// response into out. Slack reports most failures with HTTP 200 and ok=false,
+// which call returns as an *APIError.
+func (c *client) call(method string, body any, out any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(c.baseURL, "/")+"/"+method, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitError{Method: method, RetryAfter: notification.ParseRetryAfter(resp.Header.Get("Retry-After"))}
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to call %s: unexpected status %d: %s", method, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", method, err)
+	}
+
+	var result apiResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if !result.OK {
+		if result.Error == "ratelimited" {
+			return &RateLimitError{Method: method, RetryAfter: notification.ParseRetryAfter(resp.Header.Get("Retry-After"))}
+		}
+		return &APIError{Method: method, Code: result.Error}
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+
+	return nil
+}
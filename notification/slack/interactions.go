@@ -0,0 +1,245 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+)
+
+const (
+	// signatureVersion is the version prefix of Slack request signatures.
+	signatureVersion = "v0"
+	// maxRequestAge is how far the request timestamp may be from the current
+	// time before the request is rejected as a possible replay.
+	maxRequestAge = 5 * time.Minute
+	// maxPayloadSize is the maximum accepted size of an interaction request.
+	maxPayloadSize = 1 << 20
+)
+
+var (
+	// ErrMissingSigningSecret is returned when the interaction handler is
+	// requested without a signing secret.
+	ErrMissingSigningSecret = errors.New("slack signing secret is required to handle interactions")
+	// ErrInvalidSignature is returned when an interaction request is not
+	// signed with the signing secret.
+	ErrInvalidSignature = errors.New("invalid slack request signature")
+	// ErrStaleRequest is returned when an interaction request timestamp is
+	// too old or too far in the future.
+	ErrStaleRequest = errors.New("slack request timestamp out of range")
+)
+
+// DecisionRecorder records decisions on approval requests.
+type DecisionRecorder interface {
+	RecordDecision(decision api.ApprovalDecision) (api.ApprovalRequest, error)
+}
+
+// interactionPayload is the part of a Slack interaction payload used by
+// the interaction handler.
+type interactionPayload struct {
+	Type string `json:"type"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Container struct {
+		ChannelID string `json:"channel_id"`
+		MessageTS string `json:"message_ts"`
+	} `json:"container"`
+	Message struct {
+		Blocks []block `json:"blocks"`
+	} `json:"message"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		BlockID  string `json:"block_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// interactionHandler handles Slack interaction requests.
+type interactionHandler struct {
+	service  *service
+	recorder DecisionRecorder
+}
+
+// InteractionHandler returns an HTTP handler for the Slack app's
+// interactivity request URL. It verifies that requests are signed with the
+// signing secret, records Approve and Reject button clicks with recorder and
+// updates the original message with the decision.
+func (s *service) InteractionHandler(recorder DecisionRecorder) (http.Handler, error) {
+	if s.signingSecret == "" {
+		return nil, ErrMissingSigningSecret
+	}
+	if recorder == nil {
+		return nil, errors.New("decision recorder is required to handle slack interactions")
+	}
+	return &interactionHandler{service: s, recorder: recorder}, nil
+}
+
+// ServeHTTP handles a single interaction request. Slack expects a 200
+// response within three seconds, so decisions that cannot be recorded are
+// logged rather than reported back.
+func (h *interactionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.verifySignature(r.Header, body); err != nil {
+		h.service.log.Error("Rejected slack interaction request", "error", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	payload, err := parseInteractionPayload(body)
+	if err != nil {
+		h.service.log.Error("Invalid slack interaction payload", "error", err)
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	decision, ok, err := h.service.toDecision(payload)
+	if err != nil {
+		h.service.log.Error("Invalid slack interaction action", "error", err)
+		http.Error(w, "invalid action", http.StatusBadRequest)
+		return
+	}
+	if !ok {
+		// Not an approval decision, nothing to do.
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	approvalRequest, err := h.recorder.RecordDecision(decision)
+	if err != nil {
+		h.service.log.Error("Failed to record slack approval decision",
+			"approval_request_id", decision.ApprovalRequestID,
+			"decided_by", decision.DecidedBy,
+			"error", err,
+		)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// A decision in a digest only updates the decided request's buttons.
+	if blockID, ok := digestBlockID(payload); ok {
+		err = h.service.updateDigestMessage(payload.Container.ChannelID, payload.Container.MessageTS, payload.Message.Blocks, blockID, decision)
+	} else {
+		err = h.service.UpdateApprovalMessage(payload.Container.ChannelID, payload.Container.MessageTS, approvalRequest, decision)
+	}
+	if err != nil {
+		h.service.log.Error("Failed to update slack approval message",
+			"approval_request_id", decision.ApprovalRequestID,
+			"error", err,
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifySignature verifies the X-Slack-Signature header of a request
+// against its timestamp and body.
+func (s *service) verifySignature(header http.Header, body []byte) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing or malformed timestamp", ErrInvalidSignature)
+	}
+
+	age := s.now().Sub(time.Unix(seconds, 0))
+	if age > maxRequestAge || age < -maxRequestAge {
+		return ErrStaleRequest
+	}
+
+	expected := sign(s.signingSecret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// sign returns the Slack signature of a request body sent at timestamp.
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s:%s:", signatureVersion, timestamp)
+	mac.Write(body)
+	return signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseInteractionPayload parses the form encoded payload of an
+// interaction request.
+func parseInteractionPayload(body []byte) (interactionPayload, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return interactionPayload{}, fmt.Errorf("failed to parse form: %w", err)
+	}
+
+	var payload interactionPayload
+	if err := json.Unmarshal([]byte(values.Get("payload")), &payload); err != nil {
+		return interactionPayload{}, fmt.Errorf("failed to decode payload: %w", err)
+	}
+	return payload, nil
+}
+
+// digestBlockID returns the block ID of the clicked buttons if they belong
+// to a request in a digest message.
+func digestBlockID(payload interactionPayload) (string, bool) {
+	for _, action := range payload.Actions {
+		if strings.HasPrefix(action.BlockID, digestBlockPrefix) {
+			return action.BlockID, true
+		}
+	}
+	return "", false
+}
+
+// toDecision converts a block_actions payload with an Approve or Reject
+// button click to a decision. It reports false for any other interaction.
+func (s *service) toDecision(payload interactionPayload) (api.ApprovalDecision, bool, error) {
+	if payload.Type != "block_actions" {
+		return api.ApprovalDecision{}, false, nil
+	}
+
+	for _, action := range payload.Actions {
+		var status api.ApprovalStatus
+		switch action.ActionID {
+		case approveActionID:
+			status = api.ApprovalStatusApproved
+		case rejectActionID:
+			status = api.ApprovalStatusRejected
+		default:
+			continue
+		}
+
+		id, err := strconv.Atoi(action.Value)
+		if err != nil {
+			return api.ApprovalDecision{}, false, fmt.Errorf("invalid approval request id %q", action.Value)
+		}
+
+		return api.ApprovalDecision{
+			ApprovalRequestID: id,
+			Status:            status,
+			Channel:           ChannelName,
+			DecidedBy:         payload.User.ID,
+			DecidedAt:         s.now(),
+		}, true, nil
+	}
+
+	return api.ApprovalDecision{}, false, nil
+}
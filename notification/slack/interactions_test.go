@@ -0,0 +1,354 @@
+package slack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/notification/templates"
+	"github.com/google/go-cmp/cmp"
+)
+
+const testSigningSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+func TestService_InteractionHandler(t *testing.T) {
+	var tests = []struct {
+		name     string
+		options  []Option
+		recorder DecisionRecorder
+		wantErr  error
+	}{
+		{
+			name:     "valid handler",
+			options:  []Option{WithSigningSecret(testSigningSecret)},
+			recorder: &mockRecorder{},
+		},
+		{
+			name:     "missing signing secret",
+			recorder: &mockRecorder{},
+			wantErr:  ErrMissingSigningSecret,
+		},
+		{
+			name:    "missing recorder",
+			options: []Option{WithSigningSecret(testSigningSecret)},
+			wantErr: errors.New("decision recorder is required"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, err := NewService("xoxb-test", append(test.options, WithLogger(&mockLogger{}))...)
+			if err != nil {
+				t.Fatalf("NewService() unexpected error: %v", err)
+			}
+
+			got, err := svc.InteractionHandler(test.recorder)
+			if test.wantErr != nil {
+				if err == nil || (!errors.Is(err, test.wantErr) && !strings.Contains(err.Error(), test.wantErr.Error())) {
+					t.Errorf("InteractionHandler() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InteractionHandler() unexpected error: %v", err)
+			}
+			if got == nil {
+				t.Errorf("InteractionHandler() expected handler but got nil")
+			}
+		})
+	}
+}
+
+func TestInteractionHandler_ServeHTTP(t *testing.T) {
+	now := time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC)
+	approvalRequest := api.ApprovalRequest{
+		ID:       7,
+		Approver: api.Approver{Name: "Amanda Svensson", SlackID: "U345678"},
+		Invoice:  api.InvoiceDetails{Amount: money.New(1500000, money.USD), Department: "Finance"},
+	}
+
+	var tests = []struct {
+		name         string
+		method       string
+		payload      string
+		timestamp    time.Time
+		secret       string
+		recorder     *mockRecorder
+		wantStatus   int
+		wantDecision []api.ApprovalDecision
+		wantUpdate   string
+	}{
+		{
+			name:       "approve button",
+			method:     http.MethodPost,
+			payload:    blockActionsPayload(approveActionID, "7"),
+			timestamp:  now,
+			secret:     testSigningSecret,
+			recorder:   &mockRecorder{request: approvalRequest},
+			wantStatus: http.StatusOK,
+			wantDecision: []api.ApprovalDecision{{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusApproved,
+				Channel:           "slack",
+				DecidedBy:         "U345678",
+				DecidedAt:         now,
+			}},
+			wantUpdate: ":white_check_mark: *Approved* by <@U345678> on <!date^1767369600^{date_short_pretty} at {time}|2026-01-02 16:00 UTC>",
+		},
+		{
+			name:       "reject button",
+			method:     http.MethodPost,
+			payload:    blockActionsPayload(rejectActionID, "7"),
+			timestamp:  now.Add(-time.Minute),
+			secret:     testSigningSecret,
+			recorder:   &mockRecorder{request: approvalRequest},
+			wantStatus: http.StatusOK,
+			wantDecision: []api.ApprovalDecision{{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusRejected,
+				Channel:           "slack",
+				DecidedBy:         "U345678",
+				DecidedAt:         now,
+			}},
+			wantUpdate: ":x: *Rejected* by <@U345678> on <!date^1767369600^{date_short_pretty} at {time}|2026-01-02 16:00 UTC>",
+		},
+		{
+			name:       "invalid signature",
+			method:     http.MethodPost,
+			payload:    blockActionsPayload(approveActionID, "7"),
+			timestamp:  now,
+			secret:     "wrong-secret",
+			recorder:   &mockRecorder{request: approvalRequest},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "stale request",
+			method:     http.MethodPost,
+			payload:    blockActionsPayload(approveActionID, "7"),
+			timestamp:  now.Add(-10 * time.Minute),
+			secret:     testSigningSecret,
+			recorder:   &mockRecorder{request: approvalRequest},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodGet,
+			timestamp:  now,
+			secret:     testSigningSecret,
+			recorder:   &mockRecorder{},
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "other interaction type",
+			method:     http.MethodPost,
+			payload:    `{"type":"view_submission","user":{"id":"U345678"}}`,
+			timestamp:  now,
+			secret:     testSigningSecret,
+			recorder:   &mockRecorder{},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid approval request id",
+			method:     http.MethodPost,
+			payload:    blockActionsPayload(approveActionID, "abc"),
+			timestamp:  now,
+			secret:     testSigningSecret,
+			recorder:   &mockRecorder{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "malformed payload",
+			method:     http.MethodPost,
+			payload:    `{not json`,
+			timestamp:  now,
+			secret:     testSigningSecret,
+			recorder:   &mockRecorder{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "decision not recorded",
+			method:     http.MethodPost,
+			payload:    blockActionsPayload(approveActionID, "7"),
+			timestamp:  now,
+			secret:     testSigningSecret,
+			recorder:   &mockRecorder{err: errors.New("approval request already decided")},
+			wantStatus: http.StatusOK,
+			wantDecision: []api.ApprovalDecision{{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusApproved,
+				Channel:           "slack",
+				DecidedBy:         "U345678",
+				DecidedAt:         now,
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var gotUpdate *updateMessageRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/chat.update" {
+					t.Errorf("unexpected slack api call %s", r.URL.Path)
+				}
+				var raw map[string]json.RawMessage
+				if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+					t.Errorf("invalid chat.update body: %v", err)
+				}
+				var update updateMessageRequest
+				json.Unmarshal(raw["channel"], &update.Channel)
+				json.Unmarshal(raw["ts"], &update.TS)
+				json.Unmarshal(raw["text"], &update.Text)
+				gotUpdate = &update
+				fmt.Fprint(w, `{"ok":true}`)
+			}))
+			defer server.Close()
+
+			svc, err := NewService("xoxb-test",
+				WithLogger(&mockLogger{}),
+				WithBaseURL(server.URL),
+				WithSigningSecret(testSigningSecret),
+			)
+			if err != nil {
+				t.Fatalf("NewService() unexpected error: %v", err)
+			}
+			svc.now = func() time.Time { return now }
+
+			handler, err := svc.InteractionHandler(test.recorder)
+			if err != nil {
+				t.Fatalf("InteractionHandler() unexpected error: %v", err)
+			}
+
+			body := url.Values{"payload": {test.payload}}.Encode()
+			req := httptest.NewRequest(test.method, "/slack/interactions", strings.NewReader(body))
+			timestamp := strconv.FormatInt(test.timestamp.Unix(), 10)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+			req.Header.Set("X-Slack-Signature", sign(test.secret, timestamp, []byte(body)))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("ServeHTTP() status = %d, want %d", rec.Code, test.wantStatus)
+			}
+			if diff := cmp.Diff(test.wantDecision, test.recorder.got); diff != "" {
+				t.Errorf("ServeHTTP() decisions mismatch (-want +got)\n%s", diff)
+			}
+
+			if test.wantUpdate == "" {
+				if gotUpdate != nil {
+					t.Errorf("ServeHTTP() unexpected message update %+v", gotUpdate)
+				}
+				return
+			}
+			if gotUpdate == nil {
+				t.Fatalf("ServeHTTP() expected the original message to be updated")
+			}
+			want := &updateMessageRequest{Channel: "D123456", TS: "1700000000.000100", Text: test.wantUpdate}
+			if diff := cmp.Diff(want, gotUpdate); diff != "" {
+				t.Errorf("ServeHTTP() update mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDecidedBlocks(t *testing.T) {
+	approvalRequest := api.ApprovalRequest{
+		ID:       7,
+		Approver: api.Approver{Name: "Amanda Svensson", SlackID: "U345678"},
+		Invoice:  api.InvoiceDetails{Amount: money.New(1500000, money.USD)},
+	}
+
+	content := templates.Content{Subject: "Invoice approval request", Text: "An invoice is waiting for your approval."}
+
+	pending := approvalBlocks(content, approvalRequest)
+	actions := pending[len(pending)-1]
+	if actions.Type != "actions" || len(actions.Elements) != 2 {
+		t.Fatalf("approvalBlocks() last block = %+v, want Approve and Reject buttons", actions)
+	}
+	for i, wantActionID := range []string{approveActionID, rejectActionID} {
+		button := actions.Elements[i].(buttonElement)
+		if button.ActionID != wantActionID || button.Value != "7" {
+			t.Errorf("approvalBlocks() button %d = %+v, want action %s with value 7", i, button, wantActionID)
+		}
+	}
+
+	decided := decidedBlocks(content, api.ApprovalDecision{
+		Status:    api.ApprovalStatusApproved,
+		DecidedBy: "U345678",
+		DecidedAt: time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC),
+	})
+	if len(decided) != len(pending) {
+		t.Fatalf("decidedBlocks() returned %d blocks, want %d", len(decided), len(pending))
+	}
+	if diff := cmp.Diff(pending[:len(pending)-1], decided[:len(decided)-1]); diff != "" {
+		t.Errorf("decidedBlocks() invoice blocks mismatch (-want +got)\n%s", diff)
+	}
+	if context := decided[len(decided)-1]; context.Type != "context" {
+		t.Errorf("decidedBlocks() last block type = %s, want context", context.Type)
+	}
+}
+
+func TestDecidedDigestBlocks(t *testing.T) {
+	digest := api.Digest{
+		Approver: api.Approver{Name: "Amanda Svensson", SlackID: "U345678"},
+		Requests: []api.ApprovalRequest{{ID: 7}, {ID: 8}},
+	}
+	content := templates.Content{Subject: "Invoice approval digest", Text: "2 invoices are waiting for your approval."}
+
+	pending := digestBlocks(content, digest)
+	if len(pending) != 4 {
+		t.Fatalf("digestBlocks() returned %d blocks, want 4", len(pending))
+	}
+
+	decided := decidedDigestBlocks(pending, digestBlockPrefix+"7", api.ApprovalDecision{
+		ApprovalRequestID: 7,
+		Status:            api.ApprovalStatusRejected,
+		DecidedBy:         "U345678",
+		DecidedAt:         time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC),
+	})
+	if len(decided) != len(pending) {
+		t.Fatalf("decidedDigestBlocks() returned %d blocks, want %d", len(decided), len(pending))
+	}
+	if context := decided[2]; context.Type != "context" || context.BlockID != digestBlockPrefix+"7" {
+		t.Errorf("decidedDigestBlocks() block of request 7 = %+v, want a context block", context)
+	}
+	// The other request can still be decided on.
+	if diff := cmp.Diff(pending[3], decided[3]); diff != "" {
+		t.Errorf("decidedDigestBlocks() block of request 8 mismatch (-want +got)\n%s", diff)
+	}
+}
+
+// blockActionsPayload returns a block_actions payload for a button click by
+// U345678 on the message D123456/1700000000.000100.
+func blockActionsPayload(actionID, value string) string {
+	return fmt.Sprintf(`{
+		"type": "block_actions",
+		"user": {"id": "U345678", "username": "amanda"},
+		"container": {"type": "message", "channel_id": "D123456", "message_ts": "1700000000.000100"},
+		"actions": [{"action_id": %q, "block_id": %q, "value": %q}]
+	}`, actionID, decisionBlockID, value)
+}
+
+type mockRecorder struct {
+	request api.ApprovalRequest
+	err     error
+	got     []api.ApprovalDecision
+}
+
+func (m *mockRecorder) RecordDecision(decision api.ApprovalDecision) (api.ApprovalRequest, error) {
+	m.got = append(m.got, decision)
+	if m.err != nil {
+		return api.ApprovalRequest{}, m.err
+	}
+	return m.request, nil
+}
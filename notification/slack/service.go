@@ -1,32 +1,54 @@
 package slack
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/KatrinSalt/backend-challenge-go/api"
 	"github.com/KatrinSalt/backend-challenge-go/common"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+	"github.com/KatrinSalt/backend-challenge-go/notification/templates"
 )
 
+// ChannelName is the name of the Slack notification channel.
+const ChannelName = "slack"
+
 type service struct {
-	log    common.Logger
-	client string
+	log           common.Logger
+	client        *client
+	templates     *templates.Renderer
+	signingSecret string
+	now           func() time.Time
 }
 
 // Options holds the configuration for the service.
 type Options struct {
-	Logger common.Logger
+	Logger        common.Logger
+	BaseURL       string
+	HTTPClient    *http.Client
+	SigningSecret string
+	Templates     *templates.Renderer
 }
 
 // Option is a function that configures the service.
 type Option func(*service)
 
-// NewService returns a new service.
+// NewService returns a new service. The connection string is the bot token
+// used to authenticate against the Slack Web API.
 func NewService(connectionString string, options ...Option) (*service, error) {
 	if connectionString == "" {
 		return nil, fmt.Errorf("slack client connection string is required to start slack service")
 	}
 	s := service{
-		client: connectionString,
+		client: &client{
+			token:      connectionString,
+			baseURL:    defaultBaseURL,
+			httpClient: &http.Client{Timeout: defaultTimeout},
+		},
+		now: time.Now,
 	}
 
 	for _, option := range options {
@@ -36,35 +58,204 @@ func NewService(connectionString string, options ...Option) (*service, error) {
 	if s.log == nil {
 		s.log = common.NewLogger()
 	}
+	if s.templates == nil {
+		var err error
+		if s.templates, err = templates.NewRenderer(); err != nil {
+			return nil, err
+		}
+	}
 
 	return &s, nil
 }
 
-// SendApprovalRequest sends an approval request via email.
+// SendApprovalRequest sends an approval request as a direct message to the
+// approver's Slack user, with the invoice details rendered from the Slack
+// templates in the approver's locale and Approve and Reject buttons.
 func (s *service) SendApprovalRequest(approvalRequest api.ApprovalRequest) (api.ApprovalResponse, error) {
+	if approvalRequest.Approver.SlackID == "" {
+		return api.ApprovalResponse{}, fmt.Errorf("failed to send slack approval request to %s: %w", approvalRequest.Approver.Name, api.ErrMissingSlackID)
+	}
+
 	s.log.Info("Sending approval request via slack",
 		"approver_name", approvalRequest.Approver.Name,
 		"approver_role", approvalRequest.Approver.Role,
 		"approver_slack_id", approvalRequest.Approver.SlackID,
-		"invoice_amount", approvalRequest.Invoice.Amount,
+		"invoice_amount", approvalRequest.Invoice.Amount.String(),
 	)
 
+	content, err := s.templates.Render(ChannelName, templates.NewData(approvalRequest))
+	if err != nil {
+		return api.ApprovalResponse{}, fmt.Errorf("failed to render slack approval request: %w", err)
+	}
+
+	msg, err := s.client.postMessage(postMessageRequest{
+		Channel: approvalRequest.Approver.SlackID,
+		Text:    summary(content),
+		Blocks:  approvalBlocks(content, approvalRequest),
+	})
+	if err != nil {
+		s.log.Error("Failed to send approval request via slack",
+			"approver_slack_id", approvalRequest.Approver.SlackID,
+			"error", err,
+		)
+		return api.ApprovalResponse{}, fmt.Errorf("failed to send slack approval request: %w", sendError(approvalRequest.Approver.SlackID, err))
+	}
+	if msg.Warning != "" {
+		s.log.Info("Slack accepted approval request with warning", "warning", msg.Warning)
+	}
+
 	resp := api.ApprovalResponse{
 		ApproverName:      approvalRequest.Approver.Name,
 		ApproverRole:      approvalRequest.Approver.Role,
-		ApproverChannel:   "slack",
+		ApproverChannel:   ChannelName,
 		ApproverContactID: approvalRequest.Approver.SlackID,
+		ConversationID:    msg.Channel,
+		MessageID:         msg.TS,
 	}
 
 	return resp, nil
 }
 
+// sendError returns the error of a message that could not be sent to the
+// contact. A rate limit of Slack is returned as a *notification.RateLimitError,
+// so that the notification is deferred until Slack accepts messages again
+// rather than counted as a failed attempt.
+func sendError(contactID string, err error) error {
+	var limitErr *RateLimitError
+	if errors.As(err, &limitErr) {
+		return &notification.RateLimitError{
+			Channel:    ChannelName,
+			ContactID:  contactID,
+			RetryAfter: limitErr.RetryAfter,
+			Err:        err,
+		}
+	}
+	return err
+}
+
+// Channel returns the service as a notification channel. Approvers are
+// addressed by their Slack user ID.
+func (s *service) Channel() notification.Channel {
+	return notification.Channel{
+		Name:      ChannelName,
+		Sender:    s,
+		ContactID: ContactID,
+	}
+}
+
+// ContactID returns the approver's Slack user ID, which is their contact on
+// the channel.
+func ContactID(approver api.Approver) string {
+	return approver.SlackID
+}
+
+// UpdateApprovalMessage replaces the buttons of a delivered approval request
+// message with the decision, showing who decided and when.
+func (s *service) UpdateApprovalMessage(conversationID, messageID string, approvalRequest api.ApprovalRequest, decision api.ApprovalDecision) error {
+	content, err := s.templates.Render(ChannelName, templates.NewData(approvalRequest))
+	if err != nil {
+		return fmt.Errorf("failed to render slack approval message: %w", err)
+	}
+
+	err = s.client.updateMessage(updateMessageRequest{
+		Channel: conversationID,
+		TS:      messageID,
+		Text:    decisionText(decision),
+		Blocks:  decidedBlocks(content, decision),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update slack approval message: %w", err)
+	}
+	return nil
+}
+
+// SendDigest sends a digest of approval requests as one direct message to
+// the approver's Slack user, with a summary table rendered from the Slack
+// digest templates in the approver's locale and Approve and Reject buttons
+// for every request.
+func (s *service) SendDigest(digest api.Digest) (api.ApprovalResponse, error) {
+	if digest.Approver.SlackID == "" {
+		return api.ApprovalResponse{}, fmt.Errorf("failed to send slack digest to %s: %w", digest.Approver.Name, api.ErrMissingSlackID)
+	}
+
+	s.log.Info("Sending approval digest via slack",
+		"approver_name", digest.Approver.Name,
+		"approver_slack_id", digest.Approver.SlackID,
+		"approval_requests", len(digest.Requests),
+	)
+
+	content, err := s.templates.RenderDigest(ChannelName, templates.NewDigestData(digest))
+	if err != nil {
+		return api.ApprovalResponse{}, fmt.Errorf("failed to render slack digest: %w", err)
+	}
+
+	msg, err := s.client.postMessage(postMessageRequest{
+		Channel: digest.Approver.SlackID,
+		Text:    summary(content),
+		Blocks:  digestBlocks(content, digest),
+	})
+	if err != nil {
+		s.log.Error("Failed to send approval digest via slack",
+			"approver_slack_id", digest.Approver.SlackID,
+			"error", err,
+		)
+		return api.ApprovalResponse{}, fmt.Errorf("failed to send slack digest: %w", sendError(digest.Approver.SlackID, err))
+	}
+	if msg.Warning != "" {
+		s.log.Info("Slack accepted approval digest with warning", "warning", msg.Warning)
+	}
+
+	return api.ApprovalResponse{
+		ApproverName:      digest.Approver.Name,
+		ApproverRole:      digest.Approver.Role,
+		ApproverChannel:   ChannelName,
+		ApproverContactID: digest.Approver.SlackID,
+		ConversationID:    msg.Channel,
+		MessageID:         msg.TS,
+	}, nil
+}
+
+// updateDigestMessage replaces the buttons of one request in a delivered
+// digest message, those in the block with blockID, with the decision. The
+// other blocks of the message are kept as they are.
+func (s *service) updateDigestMessage(conversationID, messageID string, blocks []block, blockID string, decision api.ApprovalDecision) error {
+	err := s.client.updateMessage(updateMessageRequest{
+		Channel: conversationID,
+		TS:      messageID,
+		Text:    decisionText(decision),
+		Blocks:  decidedDigestBlocks(blocks, blockID, decision),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update slack digest message: %w", err)
+	}
+	return nil
+}
+
+// summary returns the notification text of an approval request message:
+// the first line of the rendered text.
+func summary(content templates.Content) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(content.Text), "\n")
+	return line
+}
+
 // WithOptions configures the service with the given Options.
 func WithOptions(options Options) Option {
 	return func(s *service) {
 		if options.Logger != nil {
 			s.log = options.Logger
 		}
+		if options.BaseURL != "" {
+			s.client.baseURL = options.BaseURL
+		}
+		if options.HTTPClient != nil {
+			s.client.httpClient = options.HTTPClient
+		}
+		if options.SigningSecret != "" {
+			s.signingSecret = options.SigningSecret
+		}
+		if options.Templates != nil {
+			s.templates = options.Templates
+		}
 	}
 }
 
@@ -74,3 +265,38 @@ func WithLogger(logger common.Logger) Option {
 		s.log = logger
 	}
 }
+
+// WithBaseURL configures the base URL of the Slack Web API, for example to
+// point the service at a local stub server.
+func WithBaseURL(baseURL string) Option {
+	return func(s *service) {
+		if baseURL != "" {
+			s.client.baseURL = baseURL
+		}
+	}
+}
+
+// WithHTTPClient configures the HTTP client used to call the Slack Web API.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(s *service) {
+		s.client.httpClient = httpClient
+	}
+}
+
+// WithSigningSecret configures the signing secret of the Slack app, used to
+// verify interaction requests sent by Slack.
+func WithSigningSecret(secret string) Option {
+	return func(s *service) {
+		s.signingSecret = secret
+	}
+}
+
+// WithTemplates configures the renderer of the approval request messages.
+// The built-in templates are used by default.
+func WithTemplates(renderer *templates.Renderer) Option {
+	return func(s *service) {
+		if renderer != nil {
+			s.templates = renderer
+		}
+	}
+}
@@ -1,35 +1,56 @@
 package slack
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestNewService(t *testing.T) {
 	var tests = []struct {
-		name       string
-		connection string
-		options    []Option
-		wantErr    bool
+		name        string
+		connection  string
+		options     []Option
+		wantBaseURL string
+		wantErr     bool
 	}{
 		{
-			name:       "valid service creation",
-			connection: "slack://test",
-			options:    []Option{},
-			wantErr:    false,
+			name:        "valid service creation",
+			connection:  "slack://test",
+			options:     []Option{},
+			wantBaseURL: defaultBaseURL,
+			wantErr:     false,
+		},
+		{
+			name:        "valid service with custom logger",
+			connection:  "slack://test",
+			options:     []Option{WithLogger(&mockLogger{})},
+			wantBaseURL: defaultBaseURL,
+			wantErr:     false,
 		},
 		{
-			name:       "valid service with custom logger",
-			connection: "slack://test",
-			options:    []Option{WithLogger(&mockLogger{})},
-			wantErr:    false,
+			name:        "valid service with custom base url",
+			connection:  "xoxb-test",
+			options:     []Option{WithBaseURL("http://localhost:8080/api")},
+			wantBaseURL: "http://localhost:8080/api",
+			wantErr:     false,
 		},
 		{
-			name:       "valid service with options",
-			connection: "slack://test",
-			options:    []Option{WithOptions(Options{Logger: &mockLogger{}})},
-			wantErr:    false,
+			name:        "valid service with options",
+			connection:  "slack://test",
+			options:     []Option{WithOptions(Options{Logger: &mockLogger{}, BaseURL: "http://stub"})},
+			wantBaseURL: "http://stub",
+			wantErr:     false,
 		},
 		{
 			name:       "empty connection string",
@@ -54,8 +75,11 @@ func TestNewService(t *testing.T) {
 				if got == nil {
 					t.Errorf("NewService() expected service but got nil")
 				}
-				if got != nil && got.client != test.connection {
-					t.Errorf("NewService() client = %v, want %v", got.client, test.connection)
+				if got != nil && got.client.token != test.connection {
+					t.Errorf("NewService() token = %v, want %v", got.client.token, test.connection)
+				}
+				if got != nil && got.client.baseURL != test.wantBaseURL {
+					t.Errorf("NewService() baseURL = %v, want %v", got.client.baseURL, test.wantBaseURL)
 				}
 			}
 		})
@@ -65,17 +89,18 @@ func TestNewService(t *testing.T) {
 func TestService_SendApprovalRequest(t *testing.T) {
 	var tests = []struct {
 		name            string
-		service         *service
+		status          int
+		header          map[string]string
+		body            string
 		approvalRequest api.ApprovalRequest
 		wantResp        api.ApprovalResponse
-		wantErr         bool
+		wantErr         error
+		wantRetryAfter  time.Duration
 	}{
 		{
-			name: "successful slack approval request",
-			service: &service{
-				log:    &mockLogger{},
-				client: "slack://test",
-			},
+			name:   "successful slack approval request",
+			status: http.StatusOK,
+			body:   `{"ok":true,"channel":"D123456","ts":"1700000000.000100"}`,
 			approvalRequest: api.ApprovalRequest{
 				Approver: api.Approver{
 					Name:    "John Doe",
@@ -84,7 +109,7 @@ func TestService_SendApprovalRequest(t *testing.T) {
 					SlackID: "U123456",
 				},
 				Invoice: api.InvoiceDetails{
-					Amount: 500.0,
+					Amount: money.New(50000, money.USD),
 				},
 			},
 			wantResp: api.ApprovalResponse{
@@ -92,106 +117,166 @@ func TestService_SendApprovalRequest(t *testing.T) {
 				ApproverRole:      "Manager",
 				ApproverChannel:   "slack",
 				ApproverContactID: "U123456",
+				ConversationID:    "D123456",
+				MessageID:         "1700000000.000100",
 			},
-			wantErr: false,
 		},
 		{
-			name: "approval request with empty approver name",
-			service: &service{
-				log:    &mockLogger{},
-				client: "slack://test",
-			},
+			name:   "successful request with warning",
+			status: http.StatusOK,
+			body:   `{"ok":true,"channel":"D123456","ts":"1700000000.000200","warning":"missing_charset"}`,
 			approvalRequest: api.ApprovalRequest{
 				Approver: api.Approver{
-					Name:    "", // Empty name
-					Role:    "Manager",
-					Email:   "test@example.com",
+					Name:    "José María O'Connor-Smith",
+					Role:    "Senior Manager",
+					Email:   "jose@example.com",
 					SlackID: "U123456",
 				},
 				Invoice: api.InvoiceDetails{
-					Amount: 500.0,
+					Amount: money.New(50000, money.USD),
 				},
 			},
 			wantResp: api.ApprovalResponse{
-				ApproverName:      "", // Should preserve empty name
-				ApproverRole:      "Manager",
+				ApproverName:      "José María O'Connor-Smith",
+				ApproverRole:      "Senior Manager",
 				ApproverChannel:   "slack",
 				ApproverContactID: "U123456",
+				ConversationID:    "D123456",
+				MessageID:         "1700000000.000200",
 			},
-			wantErr: false,
 		},
 		{
-			name: "approval request with empty role",
-			service: &service{
-				log:    &mockLogger{},
-				client: "slack://test",
-			},
+			name:   "slack api error",
+			status: http.StatusOK,
+			body:   `{"ok":false,"error":"channel_not_found"}`,
 			approvalRequest: api.ApprovalRequest{
-				Approver: api.Approver{
-					Name:    "Test User",
-					Role:    "", // Empty role
-					Email:   "test@example.com",
-					SlackID: "U123456",
-				},
-				Invoice: api.InvoiceDetails{
-					Amount: 500.0,
-				},
+				Approver: api.Approver{Name: "John Doe", SlackID: "U000000"},
+				Invoice:  api.InvoiceDetails{Amount: money.New(50000, money.USD)},
 			},
-			wantResp: api.ApprovalResponse{
-				ApproverName:      "Test User",
-				ApproverRole:      "", // Should preserve empty role
-				ApproverChannel:   "slack",
-				ApproverContactID: "U123456",
+			wantErr: ErrAPI,
+		},
+		{
+			name:   "rate limited with retry after",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{"Retry-After": "30"},
+			approvalRequest: api.ApprovalRequest{
+				Approver: api.Approver{Name: "John Doe", SlackID: "U123456"},
+				Invoice:  api.InvoiceDetails{Amount: money.New(50000, money.USD)},
 			},
-			wantErr: false,
+			wantErr:        ErrRateLimited,
+			wantRetryAfter: 30 * time.Second,
 		},
 		{
-			name: "approval request with special characters in name",
-			service: &service{
-				log:    &mockLogger{},
-				client: "slack://test",
+			name:   "rate limited in response body",
+			status: http.StatusOK,
+			body:   `{"ok":false,"error":"ratelimited"}`,
+			approvalRequest: api.ApprovalRequest{
+				Approver: api.Approver{Name: "John Doe", SlackID: "U123456"},
+				Invoice:  api.InvoiceDetails{Amount: money.New(50000, money.USD)},
 			},
+			wantErr: ErrRateLimited,
+		},
+		{
+			name:   "unexpected http status",
+			status: http.StatusInternalServerError,
+			body:   "internal error",
 			approvalRequest: api.ApprovalRequest{
-				Approver: api.Approver{
-					Name:    "José María O'Connor-Smith",
-					Role:    "Senior Manager",
-					Email:   "jose@example.com",
-					SlackID: "U123456",
-				},
-				Invoice: api.InvoiceDetails{
-					Amount: 500.0,
-				},
+				Approver: api.Approver{Name: "John Doe", SlackID: "U123456"},
+				Invoice:  api.InvoiceDetails{Amount: money.New(50000, money.USD)},
 			},
-			wantResp: api.ApprovalResponse{
-				ApproverName:      "José María O'Connor-Smith",
-				ApproverRole:      "Senior Manager",
-				ApproverChannel:   "slack",
-				ApproverContactID: "U123456",
+			wantErr: errors.New("unexpected status 500"),
+		},
+		{
+			name:   "malformed response body",
+			status: http.StatusOK,
+			body:   `not json`,
+			approvalRequest: api.ApprovalRequest{
+				Approver: api.Approver{Name: "John Doe", SlackID: "U123456"},
+				Invoice:  api.InvoiceDetails{Amount: money.New(50000, money.USD)},
 			},
-			wantErr: false,
+			wantErr: errors.New("failed to decode chat.postMessage response"),
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got, err := test.service.SendApprovalRequest(test.approvalRequest)
+			var gotReq postMessageRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/chat.postMessage" {
+					t.Errorf("SendApprovalRequest() path = %s, want /chat.postMessage", r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer xoxb-test" {
+					t.Errorf("SendApprovalRequest() Authorization = %q, want %q", got, "Bearer xoxb-test")
+				}
+				if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+					t.Errorf("SendApprovalRequest() invalid request body: %v", err)
+				}
+				for k, v := range test.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(test.status)
+				fmt.Fprint(w, test.body)
+			}))
+			defer server.Close()
 
-			if test.wantErr {
+			svc, err := NewService("xoxb-test", WithLogger(&mockLogger{}), WithBaseURL(server.URL+"/"))
+			if err != nil {
+				t.Fatalf("NewService() unexpected error: %v", err)
+			}
+
+			got, err := svc.SendApprovalRequest(test.approvalRequest)
+
+			if gotReq.Channel != test.approvalRequest.Approver.SlackID {
+				t.Errorf("SendApprovalRequest() channel = %q, want %q", gotReq.Channel, test.approvalRequest.Approver.SlackID)
+			}
+			if !strings.Contains(gotReq.Text, test.approvalRequest.Invoice.Amount.String()) {
+				t.Errorf("SendApprovalRequest() text = %q, want it to contain the invoice amount", gotReq.Text)
+			}
+
+			if test.wantErr != nil {
 				if err == nil {
-					t.Errorf("SendApprovalRequest() expected error but got none")
+					t.Fatalf("SendApprovalRequest() expected error but got none")
 				}
-			} else {
-				if err != nil {
-					t.Errorf("SendApprovalRequest() unexpected error: %v", err)
+				if !errors.Is(err, test.wantErr) && !strings.Contains(err.Error(), test.wantErr.Error()) {
+					t.Errorf("SendApprovalRequest() error = %v, want %v", err, test.wantErr)
 				}
-				if got != test.wantResp {
-					t.Errorf("SendApprovalRequest() = %v, want %v", got, test.wantResp)
+				var rateLimitErr *RateLimitError
+				if errors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter != test.wantRetryAfter {
+					t.Errorf("SendApprovalRequest() retry after = %v, want %v", rateLimitErr.RetryAfter, test.wantRetryAfter)
 				}
+				var limitErr *notification.RateLimitError
+				if errors.Is(err, ErrRateLimited) && (!errors.As(err, &limitErr) || limitErr.RetryAfter != test.wantRetryAfter) {
+					t.Errorf("SendApprovalRequest() error = %v, want a notification rate limit retrying after %v", err, test.wantRetryAfter)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("SendApprovalRequest() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.wantResp, got); diff != "" {
+				t.Errorf("SendApprovalRequest() = unexpected result (-want +got):\n%s\n", diff)
 			}
 		})
 	}
 }
 
+func TestService_SendApprovalRequest_ConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	svc, err := NewService("xoxb-test", WithLogger(&mockLogger{}), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewService() unexpected error: %v", err)
+	}
+
+	if _, err := svc.SendApprovalRequest(api.ApprovalRequest{
+		Approver: api.Approver{Name: "John Doe", SlackID: "U123456"},
+	}); err == nil {
+		t.Errorf("SendApprovalRequest() expected error but got none")
+	}
+}
+
 // Mock implementations for testing
 type mockLogger struct{}
 
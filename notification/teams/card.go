@@ -0,0 +1,106 @@
+package teams
+
+import (
+	"strings"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/notification/templates"
+)
+
+const (
+	// adaptiveCardContentType is the content type of Adaptive Card
+	// attachments.
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	// adaptiveCardSchema is the JSON schema of Adaptive Cards.
+	adaptiveCardSchema = "http://adaptivecards.io/schemas/adaptive-card.json"
+	// adaptiveCardVersion is the Adaptive Card version used. Teams supports
+	// up to 1.5; 1.4 keeps the cards readable on mobile clients as well.
+	adaptiveCardVersion = "1.4"
+)
+
+// message is the body posted to an incoming webhook.
+type message struct {
+	Type        string       `json:"type"`
+	Attachments []attachment `json:"attachments"`
+}
+
+// attachment is a message attachment holding a card.
+type attachment struct {
+	ContentType string       `json:"contentType"`
+	Content     adaptiveCard `json:"content"`
+}
+
+// adaptiveCard is an Adaptive Card. Only the fields used by this package
+// are modelled.
+type adaptiveCard struct {
+	Schema  string        `json:"$schema"`
+	Type    string        `json:"type"`
+	Version string        `json:"version"`
+	Body    []cardElement `json:"body"`
+	MSTeams *msTeams      `json:"msteams,omitempty"`
+}
+
+// cardElement is a TextBlock element of the card body.
+type cardElement struct {
+	Type   string `json:"type"`
+	Text   string `json:"text,omitempty"`
+	Size   string `json:"size,omitempty"`
+	Weight string `json:"weight,omitempty"`
+	Wrap   bool   `json:"wrap,omitempty"`
+}
+
+// msTeams holds the Teams specific card properties.
+type msTeams struct {
+	Width    string    `json:"width,omitempty"`
+	Entities []mention `json:"entities,omitempty"`
+}
+
+// mention is a mention entity. Text is the <at> tag in the card text that
+// is replaced by the mention.
+type mention struct {
+	Type      string           `json:"type"`
+	Text      string           `json:"text"`
+	Mentioned mentionedAccount `json:"mentioned"`
+}
+
+// mentionedAccount identifies the mentioned user by their Teams user ID,
+// which is their Microsoft Entra object ID or user principal name.
+type mentionedAccount struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// cardMessage returns the webhook message of an approval request or a
+// digest with the rendered content. The card mentions the approver, so
+// Teams notifies them even when the webhook posts to a shared channel.
+func cardMessage(content templates.Content, approver api.Approver) message {
+	return message{
+		Type: "message",
+		Attachments: []attachment{{
+			ContentType: adaptiveCardContentType,
+			Content: adaptiveCard{
+				Schema:  adaptiveCardSchema,
+				Type:    "AdaptiveCard",
+				Version: adaptiveCardVersion,
+				Body: []cardElement{
+					{Type: "TextBlock", Text: content.Subject, Size: "Medium", Weight: "Bolder", Wrap: true},
+					{Type: "TextBlock", Text: strings.TrimSpace(content.Text), Wrap: true},
+				},
+				MSTeams: &msTeams{
+					Width: "Full",
+					Entities: []mention{{
+						Type:      "mention",
+						Text:      mentionTag(approver),
+						Mentioned: mentionedAccount{ID: approver.TeamsID, Name: approver.Name},
+					}},
+				},
+			},
+		}},
+	}
+}
+
+// mentionTag returns the <at> tag that mentions the approver in the card
+// text.
+func mentionTag(approver api.Approver) string {
+	return "<at>" + approver.Name + "</at>"
+}
@@ -0,0 +1,122 @@
+package teams
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+)
+
+const (
+	// defaultTimeout is the timeout for a single webhook call.
+	defaultTimeout = 10 * time.Second
+)
+
+var (
+	// ErrWebhook is matched by every WebhookError with errors.Is.
+	ErrWebhook = errors.New("teams webhook error")
+	// ErrRateLimited is matched by every RateLimitError with errors.Is.
+	ErrRateLimited = errors.New("teams rate limit exceeded")
+)
+
+// WebhookError is returned when the webhook rejects a message.
+type WebhookError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error returns a description of the rejected message.
+func (e *WebhookError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s: status %d", ErrWebhook, e.StatusCode)
+	}
+	return fmt.Sprintf("%s: status %d: %s", ErrWebhook, e.StatusCode, e.Message)
+}
+
+// Is reports whether target is ErrWebhook.
+func (e *WebhookError) Is(target error) bool {
+	return target == ErrWebhook
+}
+
+// RateLimitError is returned when the webhook rejects a message because of
+// rate limiting. RetryAfter is zero if Teams did not say when to retry.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+// Error returns a description of the rate limited call.
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s: retry after %s", ErrRateLimited, e.RetryAfter)
+	}
+	return ErrRateLimited.Error()
+}
+
+// Is reports whether target is ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// client posts messages to a Teams incoming webhook.
+type client struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// post posts the message to the webhook. Workflows webhooks answer with
+// 202 Accepted and no body. Legacy Office 365 connector webhooks answer
+// with 200 OK and the body "1", and report some failures with 200 OK and
+// an error text, which post returns as a *WebhookError.
+func (c *client) post(msg message) error {
+	b, err := encodeMessage(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode teams message: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.webhookURL, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to create teams webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call teams webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	text := strings.TrimSpace(string(body))
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &RateLimitError{RetryAfter: notification.ParseRetryAfter(resp.Header.Get("Retry-After"))}
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return &WebhookError{StatusCode: resp.StatusCode, Message: text}
+	case resp.StatusCode == http.StatusOK && text != "" && text != "1":
+		if strings.Contains(text, "429") {
+			return &RateLimitError{}
+		}
+		return &WebhookError{StatusCode: resp.StatusCode, Message: text}
+	}
+
+	return nil
+}
+
+// encodeMessage encodes the message as JSON. HTML escaping is disabled so
+// the <at> mention tags are sent as is.
+func encodeMessage(msg message) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(msg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,236 @@
+package teams
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/common"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+	"github.com/KatrinSalt/backend-challenge-go/notification/templates"
+)
+
+// ChannelName is the name of the Microsoft Teams notification channel.
+const ChannelName = "teams"
+
+// ErrInvalidConnectionString is returned when the connection string is not
+// an http or https webhook URL.
+var ErrInvalidConnectionString = errors.New("invalid teams connection string")
+
+type service struct {
+	log       common.Logger
+	client    *client
+	templates *templates.Renderer
+}
+
+// Options holds the configuration for the service.
+type Options struct {
+	Logger     common.Logger
+	HTTPClient *http.Client
+	Templates  *templates.Renderer
+}
+
+// Option is a function that configures the service.
+type Option func(*service)
+
+// NewService returns a new service. The connection string is the URL of
+// the Teams incoming webhook, or Workflows webhook, that approval requests
+// are posted to.
+func NewService(connectionString string, options ...Option) (*service, error) {
+	if connectionString == "" {
+		return nil, fmt.Errorf("teams webhook url is required to start teams service")
+	}
+	u, err := url.Parse(connectionString)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConnectionString, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%w: scheme must be http or https, got %q", ErrInvalidConnectionString, u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: host is required", ErrInvalidConnectionString)
+	}
+
+	s := service{
+		client: &client{
+			webhookURL: connectionString,
+			httpClient: &http.Client{Timeout: defaultTimeout},
+		},
+	}
+
+	for _, option := range options {
+		option(&s)
+	}
+
+	if s.log == nil {
+		s.log = common.NewLogger()
+	}
+	if s.templates == nil {
+		if s.templates, err = templates.NewRenderer(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &s, nil
+}
+
+// SendApprovalRequest posts an approval request as an Adaptive Card that
+// mentions the approver's Teams user, rendered from the Teams templates in
+// the approver's locale. Incoming webhooks do not return an
+// ID for the posted message, so the response has no message ID.
+func (s *service) SendApprovalRequest(approvalRequest api.ApprovalRequest) (api.ApprovalResponse, error) {
+	if approvalRequest.Approver.TeamsID == "" {
+		return api.ApprovalResponse{}, fmt.Errorf("failed to send teams approval request to %s: %w", approvalRequest.Approver.Name, api.ErrMissingTeamsID)
+	}
+
+	s.log.Info("Sending approval request via teams",
+		"approver_name", approvalRequest.Approver.Name,
+		"approver_role", approvalRequest.Approver.Role,
+		"approver_teams_id", approvalRequest.Approver.TeamsID,
+		"invoice_amount", approvalRequest.Invoice.Amount.String(),
+	)
+
+	msg, err := s.approvalMessage(approvalRequest)
+	if err != nil {
+		return api.ApprovalResponse{}, err
+	}
+
+	if err := s.client.post(msg); err != nil {
+		s.log.Error("Failed to send approval request via teams",
+			"approver_teams_id", approvalRequest.Approver.TeamsID,
+			"error", err,
+		)
+		return api.ApprovalResponse{}, fmt.Errorf("failed to send teams approval request: %w", sendError(approvalRequest.Approver.TeamsID, err))
+	}
+
+	return api.ApprovalResponse{
+		ApproverName:      approvalRequest.Approver.Name,
+		ApproverRole:      approvalRequest.Approver.Role,
+		ApproverChannel:   ChannelName,
+		ApproverContactID: approvalRequest.Approver.TeamsID,
+	}, nil
+}
+
+// approvalMessage renders the webhook message of an approval request. The
+// templates address the approver with the mention tag.
+func (s *service) approvalMessage(approvalRequest api.ApprovalRequest) (message, error) {
+	data := templates.NewData(approvalRequest)
+	data.Mention = mentionTag(approvalRequest.Approver)
+
+	content, err := s.templates.Render(ChannelName, data)
+	if err != nil {
+		return message{}, fmt.Errorf("failed to render teams approval request: %w", err)
+	}
+	return cardMessage(content, approvalRequest.Approver), nil
+}
+
+// SendDigest posts a digest of approval requests as one Adaptive Card that
+// mentions the approver's Teams user, with a summary of the requests
+// rendered from the Teams digest templates in the approver's locale.
+func (s *service) SendDigest(digest api.Digest) (api.ApprovalResponse, error) {
+	if digest.Approver.TeamsID == "" {
+		return api.ApprovalResponse{}, fmt.Errorf("failed to send teams digest to %s: %w", digest.Approver.Name, api.ErrMissingTeamsID)
+	}
+
+	s.log.Info("Sending approval digest via teams",
+		"approver_name", digest.Approver.Name,
+		"approver_teams_id", digest.Approver.TeamsID,
+		"approval_requests", len(digest.Requests),
+	)
+
+	data := templates.NewDigestData(digest)
+	data.Mention = mentionTag(digest.Approver)
+	content, err := s.templates.RenderDigest(ChannelName, data)
+	if err != nil {
+		return api.ApprovalResponse{}, fmt.Errorf("failed to render teams digest: %w", err)
+	}
+
+	if err := s.client.post(cardMessage(content, digest.Approver)); err != nil {
+		s.log.Error("Failed to send approval digest via teams",
+			"approver_teams_id", digest.Approver.TeamsID,
+			"error", err,
+		)
+		return api.ApprovalResponse{}, fmt.Errorf("failed to send teams digest: %w", sendError(digest.Approver.TeamsID, err))
+	}
+
+	return api.ApprovalResponse{
+		ApproverName:      digest.Approver.Name,
+		ApproverRole:      digest.Approver.Role,
+		ApproverChannel:   ChannelName,
+		ApproverContactID: digest.Approver.TeamsID,
+	}, nil
+}
+
+// sendError returns the error of a message that could not be sent to the
+// contact. A rate limit of Teams is returned as a *notification.RateLimitError,
+// so that the notification is deferred until Teams accepts messages again
+// rather than counted as a failed attempt.
+func sendError(contactID string, err error) error {
+	var limitErr *RateLimitError
+	if errors.As(err, &limitErr) {
+		return &notification.RateLimitError{
+			Channel:    ChannelName,
+			ContactID:  contactID,
+			RetryAfter: limitErr.RetryAfter,
+			Err:        err,
+		}
+	}
+	return err
+}
+
+// Channel returns the service as a notification channel. Approvers are
+// addressed by their Teams user ID.
+func (s *service) Channel() notification.Channel {
+	return notification.Channel{
+		Name:      ChannelName,
+		Sender:    s,
+		ContactID: ContactID,
+	}
+}
+
+// ContactID returns the approver's Teams user ID, which is their contact on
+// the channel.
+func ContactID(approver api.Approver) string {
+	return approver.TeamsID
+}
+
+// WithOptions configures the service with the given Options.
+func WithOptions(options Options) Option {
+	return func(s *service) {
+		if options.Logger != nil {
+			s.log = options.Logger
+		}
+		if options.HTTPClient != nil {
+			s.client.httpClient = options.HTTPClient
+		}
+		if options.Templates != nil {
+			s.templates = options.Templates
+		}
+	}
+}
+
+// WithLogger configures the service with the given logger.
+func WithLogger(logger common.Logger) Option {
+	return func(s *service) {
+		s.log = logger
+	}
+}
+
+// WithHTTPClient configures the HTTP client used to call the webhook.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(s *service) {
+		s.client.httpClient = httpClient
+	}
+}
+
+// WithTemplates configures the renderer of the approval request cards. The
+// built-in templates are used by default.
+func WithTemplates(renderer *templates.Renderer) Option {
+	return func(s *service) {
+		if renderer != nil {
+			s.templates = renderer
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package teams
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewService(t *testing.T) {
+	var tests = []struct {
+		name        string
+		connection  string
+		options     []Option
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:       "valid service creation",
+			connection: "https://light.webhook.office.com/webhookb2/abc",
+			options:    []Option{},
+		},
+		{
+			name:       "valid service with options",
+			connection: "http://localhost:8080/teams",
+			options:    []Option{WithOptions(Options{Logger: &mockLogger{}, HTTPClient: &http.Client{}})},
+		},
+		{
+			name:       "empty connection string",
+			connection: "",
+			options:    []Option{},
+			wantErr:    true,
+		},
+		{
+			name:        "unsupported scheme",
+			connection:  "teams://light",
+			options:     []Option{},
+			wantErr:     true,
+			expectedErr: ErrInvalidConnectionString,
+		},
+		{
+			name:        "missing host",
+			connection:  "https:///webhook",
+			options:     []Option{},
+			wantErr:     true,
+			expectedErr: ErrInvalidConnectionString,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewService(test.connection, test.options...)
+
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("NewService() expected error but got none")
+				}
+				if test.expectedErr != nil && !errors.Is(err, test.expectedErr) {
+					t.Errorf("NewService() expected error %v but got %v", test.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewService() unexpected error: %v", err)
+			}
+			if got.client.webhookURL != test.connection {
+				t.Errorf("NewService() webhook url = %v, want %v", got.client.webhookURL, test.connection)
+			}
+		})
+	}
+}
+
+func TestService_SendApprovalRequest(t *testing.T) {
+	approvalRequest := api.ApprovalRequest{
+		ID: 7,
+		Approver: api.Approver{
+			Name:    "Amanda Svensson",
+			Role:    "CFO",
+			Email:   "amanda_svensson@light.com",
+			SlackID: "U345678",
+			TeamsID: "amanda_svensson@light.com",
+		},
+		Invoice: api.InvoiceDetails{
+			Amount:     money.New(1500000, money.USD),
+			Department: "Finance",
+		},
+	}
+
+	var tests = []struct {
+		name           string
+		status         int
+		header         map[string]string
+		body           string
+		wantResp       api.ApprovalResponse
+		wantErr        error
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:   "workflows webhook",
+			status: http.StatusAccepted,
+			wantResp: api.ApprovalResponse{
+				ApproverName:      "Amanda Svensson",
+				ApproverRole:      "CFO",
+				ApproverChannel:   "teams",
+				ApproverContactID: "amanda_svensson@light.com",
+			},
+		},
+		{
+			name:   "connector webhook",
+			status: http.StatusOK,
+			body:   "1",
+			wantResp: api.ApprovalResponse{
+				ApproverName:      "Amanda Svensson",
+				ApproverRole:      "CFO",
+				ApproverChannel:   "teams",
+				ApproverContactID: "amanda_svensson@light.com",
+			},
+		},
+		{
+			name:    "connector error in body",
+			status:  http.StatusOK,
+			body:    "Webhook message delivery failed with error: Microsoft Teams endpoint returned HTTP error 403",
+			wantErr: ErrWebhook,
+		},
+		{
+			name:    "connector rate limit in body",
+			status:  http.StatusOK,
+			body:    "Webhook message delivery failed with error: Microsoft Teams endpoint returned HTTP error 429",
+			wantErr: ErrRateLimited,
+		},
+		{
+			name:    "bad request",
+			status:  http.StatusBadRequest,
+			body:    "Bad payload received by generic incoming webhook.",
+			wantErr: ErrWebhook,
+		},
+		{
+			name:           "rate limited with retry after",
+			status:         http.StatusTooManyRequests,
+			header:         map[string]string{"Retry-After": "30"},
+			wantErr:        ErrRateLimited,
+			wantRetryAfter: 30 * time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var gotMsg message
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/webhook" {
+					t.Errorf("SendApprovalRequest() request = %s %s, want POST /webhook", r.Method, r.URL.Path)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&gotMsg); err != nil {
+					t.Errorf("SendApprovalRequest() invalid request body: %v", err)
+				}
+				for k, v := range test.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(test.status)
+				fmt.Fprint(w, test.body)
+			}))
+			defer server.Close()
+
+			svc, err := NewService(server.URL+"/webhook", WithLogger(&mockLogger{}))
+			if err != nil {
+				t.Fatalf("NewService() unexpected error: %v", err)
+			}
+
+			got, err := svc.SendApprovalRequest(approvalRequest)
+
+			wantMsg, msgErr := svc.approvalMessage(approvalRequest)
+			if msgErr != nil {
+				t.Fatalf("approvalMessage() unexpected error: %v", msgErr)
+			}
+			if diff := cmp.Diff(wantMsg, gotMsg); diff != "" {
+				t.Errorf("SendApprovalRequest() posted message mismatch (-want +got)\n%s", diff)
+			}
+
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("SendApprovalRequest() error = %v, want %v", err, test.wantErr)
+				}
+				var rateLimitErr *RateLimitError
+				if errors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter != test.wantRetryAfter {
+					t.Errorf("SendApprovalRequest() retry after = %v, want %v", rateLimitErr.RetryAfter, test.wantRetryAfter)
+				}
+				var limitErr *notification.RateLimitError
+				if errors.Is(err, ErrRateLimited) && (!errors.As(err, &limitErr) || limitErr.RetryAfter != test.wantRetryAfter) {
+					t.Errorf("SendApprovalRequest() error = %v, want a notification rate limit retrying after %v", err, test.wantRetryAfter)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SendApprovalRequest() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.wantResp, got); diff != "" {
+				t.Errorf("SendApprovalRequest() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_SendApprovalRequest_MissingTeamsID(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	svc, err := NewService(server.URL, WithLogger(&mockLogger{}))
+	if err != nil {
+		t.Fatalf("NewService() unexpected error: %v", err)
+	}
+
+	_, err = svc.SendApprovalRequest(api.ApprovalRequest{
+		Approver: api.Approver{Name: "John Doe", Email: "john@example.com", SlackID: "U123456"},
+		Invoice:  api.InvoiceDetails{Amount: money.New(50000, money.USD)},
+	})
+	if !errors.Is(err, api.ErrMissingTeamsID) {
+		t.Errorf("SendApprovalRequest() error = %v, want %v", err, api.ErrMissingTeamsID)
+	}
+	if called {
+		t.Errorf("SendApprovalRequest() called the webhook for an approver without a Teams ID")
+	}
+}
+
+func TestApprovalMessage(t *testing.T) {
+	svc, err := NewService("https://example.webhook.office.com/webhook", WithLogger(&mockLogger{}))
+	if err != nil {
+		t.Fatalf("NewService() unexpected error: %v", err)
+	}
+
+	msg, err := svc.approvalMessage(api.ApprovalRequest{
+		ID:       3,
+		Approver: api.Approver{Name: "Vera Sander", TeamsID: "0b7f4a4e-5c7d-4a4c-9a8e-1f2d3c4b5a69", Locale: "de"},
+		Invoice:  api.InvoiceDetails{Amount: money.New(750000, money.USD), Vendor: "Contoso GmbH", IsManagerApprovalRequired: true},
+	})
+	if err != nil {
+		t.Fatalf("approvalMessage() unexpected error: %v", err)
+	}
+
+	b, err := encodeMessage(msg)
+	if err != nil {
+		t.Fatalf("encodeMessage() unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`"contentType":"application/vnd.microsoft.card.adaptive"`,
+		`"type":"AdaptiveCard"`,
+		`"text":"Anfrage zur Rechnungsfreigabe"`,
+		`Hallo <at>Vera Sander</at>, eine Rechnung über 7500.00 USD wartet auf Ihre Freigabe.`,
+		`- **Abteilung:** Nicht angegeben`,
+		`- **Lieferant:** Contoso GmbH`,
+		`- **Freigabe durch Vorgesetzte nötig:** Ja`,
+		`- **Anfrage-ID:** 3`,
+		`"mentioned":{"id":"0b7f4a4e-5c7d-4a4c-9a8e-1f2d3c4b5a69","name":"Vera Sander"}`,
+	} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("approvalMessage() = %s, want it to contain %s", b, want)
+		}
+	}
+}
+
+// Mock implementations for testing
+type mockLogger struct{}
+
+func (l *mockLogger) Info(msg string, args ...any) {
+	// Mock implementation - just ignore for testing
+}
+
+func (l *mockLogger) Error(msg string, args ...any) {
+	// Mock implementation - just ignore for testing
+}
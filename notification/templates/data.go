@@ -0,0 +1,185 @@
+package templates
+
+import (
+	"strconv"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+)
+
+// Data holds the values templates are rendered with.
+type Data struct {
+	// Company is the name of the company the invoice belongs to. Its
+	// templates override the built-in ones.
+	Company string
+	// Locale is the locale the notification is rendered in, e.g. sv.
+	Locale       string
+	RequestID    int
+	ApproverName string
+	ApproverRole string
+	// Mention addresses the approver in the message body. It is the
+	// approver's name, unless the channel mentions approvers in another
+	// way, e.g. with an <at> tag in Teams.
+	Mention         string
+	Amount          string
+	Department      string
+	Vendor          string
+	Description     string
+	DueDate         string
+	ManagerApproval bool
+	// ApproveURL, RejectURL and LinksExpireAt are set by channels with
+	// one-click decision links.
+	ApproveURL    string
+	RejectURL     string
+	LinksExpireAt string
+}
+
+// NewData returns the template data for an approval request, in the
+// approver's locale.
+func NewData(approvalRequest api.ApprovalRequest) Data {
+	return Data{
+		Company:         approvalRequest.Company,
+		Locale:          approvalRequest.Approver.Locale,
+		RequestID:       approvalRequest.ID,
+		ApproverName:    approvalRequest.Approver.Name,
+		ApproverRole:    approvalRequest.Approver.Role,
+		Mention:         approvalRequest.Approver.Name,
+		Amount:          approvalRequest.Invoice.Amount.String(),
+		Department:      approvalRequest.Invoice.Department,
+		Vendor:          approvalRequest.Invoice.Vendor,
+		Description:     approvalRequest.Invoice.Description,
+		DueDate:         approvalRequest.Invoice.DueDate,
+		ManagerApproval: approvalRequest.Invoice.IsManagerApprovalRequired,
+	}
+}
+
+// DigestData holds the values digest templates are rendered with.
+type DigestData struct {
+	// Company is the name of the company the invoices belong to. Its
+	// templates override the built-in ones.
+	Company string
+	// Locale is the locale the digest is rendered in, e.g. sv.
+	Locale       string
+	ApproverName string
+	ApproverRole string
+	// Mention addresses the approver, as in Data.
+	Mention string
+	// Count is the number of approval requests in the digest.
+	Count    int
+	Requests []DigestRequest
+	// LinksExpireAt is set by channels with one-click decision links.
+	LinksExpireAt string
+}
+
+// DigestRequest holds the values of an approval request in a digest.
+type DigestRequest struct {
+	RequestID       int
+	Amount          string
+	Department      string
+	Vendor          string
+	Description     string
+	DueDate         string
+	ManagerApproval bool
+	// ApproveURL and RejectURL are set by channels with one-click decision
+	// links.
+	ApproveURL string
+	RejectURL  string
+}
+
+// NewDigestData returns the template data for a digest, in the approver's
+// locale.
+func NewDigestData(digest api.Digest) DigestData {
+	data := DigestData{
+		Company:      digest.Company,
+		Locale:       digest.Approver.Locale,
+		ApproverName: digest.Approver.Name,
+		ApproverRole: digest.Approver.Role,
+		Mention:      digest.Approver.Name,
+		Count:        len(digest.Requests),
+	}
+	for _, request := range digest.Requests {
+		data.Requests = append(data.Requests, DigestRequest{
+			RequestID:       request.ID,
+			Amount:          request.Invoice.Amount.String(),
+			Department:      request.Invoice.Department,
+			Vendor:          request.Invoice.Vendor,
+			Description:     request.Invoice.Description,
+			DueDate:         request.Invoice.DueDate,
+			ManagerApproval: request.Invoice.IsManagerApprovalRequired,
+		})
+	}
+	return data
+}
+
+// SampleApprovalRequest returns an approval request for a sample invoice,
+// used to check templates and to preview them.
+func SampleApprovalRequest(company, locale string) api.ApprovalRequest {
+	return api.ApprovalRequest{
+		ID:      42,
+		Company: company,
+		Approver: api.Approver{
+			Name:   "Amanda Svensson",
+			Role:   "CFO",
+			Email:  "amanda@example.com",
+			Locale: locale,
+		},
+		Invoice: api.InvoiceDetails{
+			Amount:                    money.New(1250000, money.USD),
+			Department:                "Marketing",
+			Vendor:                    "Northwind Media AB",
+			Description:               "Spring campaign ad placements",
+			DueDate:                   "2026-03-31",
+			IsManagerApprovalRequired: true,
+		},
+	}
+}
+
+// SampleData returns the template data of the sample approval request,
+// with decision links set.
+func SampleData() Data {
+	data := NewData(SampleApprovalRequest("", api.DefaultLocale))
+	data.ApproveURL = "https://approvals.example.com/email/decisions?token=approve"
+	data.RejectURL = "https://approvals.example.com/email/decisions?token=reject"
+	data.LinksExpireAt = "2026-03-24 12:00 UTC"
+	return data
+}
+
+// SampleDigest returns a digest of sample invoices, used to check digest
+// templates and to preview them.
+func SampleDigest(company, locale string) api.Digest {
+	first := SampleApprovalRequest(company, locale)
+
+	second := SampleApprovalRequest(company, locale)
+	second.ID = 43
+	second.Invoice = api.InvoiceDetails{
+		Amount:     money.New(89900, money.USD),
+		Department: "Finance",
+		Vendor:     "Contoso Office Supply",
+		DueDate:    "2026-04-15",
+	}
+
+	third := SampleApprovalRequest(company, locale)
+	third.ID = 44
+	third.Invoice = api.InvoiceDetails{
+		Amount: money.New(24000, money.USD),
+	}
+
+	return api.Digest{
+		Company:  company,
+		Approver: first.Approver,
+		Requests: []api.ApprovalRequest{first, second, third},
+	}
+}
+
+// SampleDigestData returns the template data of the sample digest, with
+// decision links set.
+func SampleDigestData() DigestData {
+	data := NewDigestData(SampleDigest("", api.DefaultLocale))
+	for i := range data.Requests {
+		token := strconv.Itoa(data.Requests[i].RequestID)
+		data.Requests[i].ApproveURL = "https://approvals.example.com/email/decisions?token=approve-" + token
+		data.Requests[i].RejectURL = "https://approvals.example.com/email/decisions?token=reject-" + token
+	}
+	data.LinksExpireAt = "2026-03-24 12:00 UTC"
+	return data
+}
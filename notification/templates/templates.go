@@ -0,0 +1,283 @@
+// Package templates renders the content of approval request notifications
+// from text/template and html/template templates. Every channel has its
+// own templates in every supported locale, and companies can override them
+// with templates of their own.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"unicode/utf8"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+)
+
+const (
+	// SubjectTemplate is the subject or title of a notification. It is
+	// required.
+	SubjectTemplate = "subject.txt.tmpl"
+	// TextTemplate is the plain text body of a notification. It is
+	// required.
+	TextTemplate = "body.txt.tmpl"
+	// HTMLTemplate is the HTML body of a notification. It is optional and
+	// only used by channels that send HTML, such as email.
+	HTMLTemplate = "body.html.tmpl"
+
+	// DigestSubjectTemplate, DigestTextTemplate and DigestHTMLTemplate are
+	// the templates of a digest of approval requests, rendered with
+	// DigestData. As for single requests, the HTML body is optional.
+	DigestSubjectTemplate = "digest_subject.txt.tmpl"
+	DigestTextTemplate    = "digest_body.txt.tmpl"
+	DigestHTMLTemplate    = "digest_body.html.tmpl"
+
+	// digestPrefix is the prefix of the names of the digest templates.
+	digestPrefix = "digest_"
+)
+
+var (
+	// ErrTemplateNotFound is returned when a channel has no template in
+	// the requested or the default locale.
+	ErrTemplateNotFound = errors.New("notification template not found")
+	// ErrInvalidTemplate is returned when a template cannot be parsed or
+	// rendered.
+	ErrInvalidTemplate = errors.New("invalid notification template")
+)
+
+//go:embed builtin
+var builtinFS embed.FS
+
+// funcs are the functions available to templates, in addition to the
+// built-in ones.
+var funcs = map[string]any{
+	"pad": pad,
+}
+
+// Content is a rendered notification.
+type Content struct {
+	Subject string
+	Text    string
+	// HTML is empty when the channel has no HTML template.
+	HTML string
+}
+
+// Renderer renders notifications from the built-in templates and the
+// company overrides. It is safe for concurrent use.
+type Renderer struct {
+	dir       string
+	builtin   templateSet
+	overrides templateSet
+}
+
+// templateSet holds parsed templates keyed by their path.
+type templateSet struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// Options holds the configuration for the renderer.
+type Options struct {
+	Dir string
+}
+
+// Option is a function that configures the renderer.
+type Option func(*Renderer)
+
+// NewRenderer returns a renderer for the built-in templates. With a
+// directory configured, templates in it at <company>/<channel>/<locale>/
+// override the built-in ones for that company. Every template is parsed
+// and rendered with sample data up front, so that a broken override is
+// reported at startup rather than when an approval request is sent.
+func NewRenderer(options ...Option) (*Renderer, error) {
+	r := &Renderer{}
+	for _, option := range options {
+		option(r)
+	}
+
+	builtin, err := fs.Sub(builtinFS, "builtin")
+	if err != nil {
+		return nil, err
+	}
+	if r.builtin, err = loadTemplates(builtin, 3); err != nil {
+		return nil, fmt.Errorf("failed to load built-in templates: %w", err)
+	}
+	if r.dir != "" {
+		if r.overrides, err = loadTemplates(os.DirFS(r.dir), 4); err != nil {
+			return nil, fmt.Errorf("failed to load templates from %s: %w", r.dir, err)
+		}
+	} else {
+		r.overrides = newTemplateSet()
+	}
+
+	return r, nil
+}
+
+// Render renders the notification of a channel in the locale of data. The
+// templates are looked up in the locale, then in the default locale; in
+// each, a template of data's company takes precedence over the built-in
+// one.
+func (r *Renderer) Render(channel string, data Data) (Content, error) {
+	return r.renderContent(channel, data.Company, data.Locale, data, [3]string{SubjectTemplate, TextTemplate, HTMLTemplate})
+}
+
+// RenderDigest renders the digest notification of a channel in the locale
+// of data. The templates are looked up as by Render.
+func (r *Renderer) RenderDigest(channel string, data DigestData) (Content, error) {
+	return r.renderContent(channel, data.Company, data.Locale, data, [3]string{DigestSubjectTemplate, DigestTextTemplate, DigestHTMLTemplate})
+}
+
+// renderContent renders the subject, text and HTML templates of a channel.
+func (r *Renderer) renderContent(channel, company, locale string, data any, names [3]string) (Content, error) {
+	var content Content
+	var err error
+	if content.Subject, err = r.render(channel, names[0], company, locale, data, true); err != nil {
+		return Content{}, err
+	}
+	content.Subject = strings.TrimSpace(content.Subject)
+	if content.Text, err = r.render(channel, names[1], company, locale, data, true); err != nil {
+		return Content{}, err
+	}
+	if content.HTML, err = r.render(channel, names[2], company, locale, data, false); err != nil {
+		return Content{}, err
+	}
+	return content, nil
+}
+
+// render renders the named template of a channel. A missing template is
+// an error only if it is required.
+func (r *Renderer) render(channel, name, company, locale string, data any, required bool) (string, error) {
+	if locale == "" {
+		locale = api.DefaultLocale
+	}
+
+	for _, loc := range []string{locale, api.DefaultLocale} {
+		for _, key := range []struct {
+			set  templateSet
+			path string
+		}{
+			{set: r.overrides, path: path.Join(company, channel, loc, name)},
+			{set: r.builtin, path: path.Join(channel, loc, name)},
+		} {
+			out, ok, err := key.set.execute(key.path, data)
+			if err != nil {
+				return "", err
+			}
+			if ok {
+				return out, nil
+			}
+		}
+	}
+
+	if required {
+		return "", fmt.Errorf("%w: %s for channel %s in locale %s", ErrTemplateNotFound, name, channel, locale)
+	}
+	return "", nil
+}
+
+// newTemplateSet returns an empty template set.
+func newTemplateSet() templateSet {
+	return templateSet{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+}
+
+// loadTemplates parses the templates of fsys that are depth directories
+// deep, counting the file. Templates ending in .html.tmpl are parsed with
+// html/template, other .tmpl files with text/template. Each template is
+// rendered with the sample data, or the sample digest data for digest
+// templates.
+func loadTemplates(fsys fs.FS, depth int) (templateSet, error) {
+	set := newTemplateSet()
+	sample, sampleDigest := SampleData(), SampleDigestData()
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(p, ".tmpl") || len(strings.Split(p, "/")) != depth {
+			return nil
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+
+		if strings.HasSuffix(p, ".html.tmpl") {
+			t, err := htmltemplate.New(path.Base(p)).Funcs(funcs).Parse(string(content))
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+			}
+			set.html[p] = t
+		} else {
+			t, err := texttemplate.New(path.Base(p)).Funcs(funcs).Parse(string(content))
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+			}
+			set.text[p] = t
+		}
+
+		if strings.HasPrefix(path.Base(p), digestPrefix) {
+			_, _, err = set.execute(p, sampleDigest)
+		} else {
+			_, _, err = set.execute(p, sample)
+		}
+		return err
+	})
+	if err != nil {
+		return templateSet{}, err
+	}
+
+	return set, nil
+}
+
+// execute renders the template at path p. It reports false if the set has
+// no template at p.
+func (s templateSet) execute(p string, data any) (string, bool, error) {
+	var b bytes.Buffer
+	var err error
+	if t, ok := s.html[p]; ok {
+		err = t.Execute(&b, data)
+	} else if t, ok := s.text[p]; ok {
+		err = t.Execute(&b, data)
+	} else {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("%w: %s: %w", ErrInvalidTemplate, p, err)
+	}
+	return b.String(), true, nil
+}
+
+// pad left-aligns s in a column of width characters. Longer values are
+// not cut, so that no information is lost, but are followed by a space to
+// keep them apart from the next column.
+func pad(width int, s string) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s + " "
+}
+
+// WithOptions configures the renderer with the given Options.
+func WithOptions(options Options) Option {
+	return func(r *Renderer) {
+		if options.Dir != "" {
+			r.dir = options.Dir
+		}
+	}
+}
+
+// WithDir configures the directory holding the company overrides.
+func WithDir(dir string) Option {
+	return func(r *Renderer) {
+		r.dir = dir
+	}
+}
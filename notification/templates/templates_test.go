@@ -0,0 +1,217 @@
+package templates
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+)
+
+func TestRenderer_Render_Builtin(t *testing.T) {
+	renderer, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer() unexpected error: %v", err)
+	}
+
+	wantGreeting := map[string]string{"en": "Hi Amanda Svensson", "sv": "Hej Amanda Svensson", "de": "Hallo Amanda Svensson"}
+	for _, channel := range []string{"email", "slack", "teams"} {
+		for _, locale := range api.Locales {
+			t.Run(channel+"/"+locale, func(t *testing.T) {
+				data := NewData(SampleApprovalRequest("Light", locale))
+
+				got, err := renderer.Render(channel, data)
+				if err != nil {
+					t.Fatalf("Render() unexpected error: %v", err)
+				}
+				if got.Subject == "" || strings.Contains(got.Subject, "\n") {
+					t.Errorf("Render() subject = %q, want a single line", got.Subject)
+				}
+				for _, want := range []string{wantGreeting[locale], data.Amount, data.Vendor, data.Description, data.DueDate} {
+					if !strings.Contains(got.Text, want) {
+						t.Errorf("Render() text missing %q\n%s", want, got.Text)
+					}
+				}
+				if (got.HTML != "") != (channel == "email") {
+					t.Errorf("Render() html = %q, want html for email only", got.HTML)
+				}
+			})
+		}
+	}
+}
+
+func TestRenderer_RenderDigest_Builtin(t *testing.T) {
+	renderer, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer() unexpected error: %v", err)
+	}
+
+	for _, channel := range []string{"email", "slack", "teams"} {
+		for _, locale := range api.Locales {
+			t.Run(channel+"/"+locale, func(t *testing.T) {
+				data := NewDigestData(SampleDigest("Light", locale))
+
+				got, err := renderer.RenderDigest(channel, data)
+				if err != nil {
+					t.Fatalf("RenderDigest() unexpected error: %v", err)
+				}
+				if got.Subject == "" || strings.Contains(got.Subject, "\n") {
+					t.Errorf("RenderDigest() subject = %q, want a single line", got.Subject)
+				}
+				for _, request := range data.Requests {
+					for _, want := range []string{"#" + strconv.Itoa(request.RequestID), request.Amount} {
+						if !strings.Contains(got.Text, want) {
+							t.Errorf("RenderDigest() text missing %q\n%s", want, got.Text)
+						}
+					}
+				}
+				if (got.HTML != "") != (channel == "email") {
+					t.Errorf("RenderDigest() html = %q, want html for email only", got.HTML)
+				}
+			})
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		width int
+		s     string
+		want  string
+	}{
+		{width: 6, s: "#42", want: "#42   "},
+		{width: 6, s: "Förfal", want: "Förfal "},
+		{width: 4, s: "Åsa", want: "Åsa "},
+		{width: 2, s: "Marketing", want: "Marketing "},
+	}
+
+	for _, test := range tests {
+		if got := pad(test.width, test.s); got != test.want {
+			t.Errorf("pad(%d, %q) = %q, want %q", test.width, test.s, got, test.want)
+		}
+	}
+}
+
+func TestRenderer_Render(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "Light/slack/sv/subject.txt.tmpl", "Faktura från {{.Vendor}} att godkänna")
+	writeTemplate(t, dir, "Light/email/en/body.html.tmpl", "<p>{{.Description}}</p>")
+
+	renderer, err := NewRenderer(WithDir(dir))
+	if err != nil {
+		t.Fatalf("NewRenderer() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		channel     string
+		data        Data
+		wantSubject string
+		wantText    string
+		wantHTML    string
+		wantErr     error
+	}{
+		{
+			name:        "company override in the approver's locale",
+			channel:     "slack",
+			data:        Data{Company: "Light", Locale: "sv", Vendor: "Northwind"},
+			wantSubject: "Faktura från Northwind att godkänna",
+			wantText:    "En faktura på",
+		},
+		{
+			name:        "other companies use the built-in templates",
+			channel:     "slack",
+			data:        Data{Company: "Acme", Locale: "sv", Vendor: "Northwind"},
+			wantSubject: "Begäran om fakturagodkännande",
+		},
+		{
+			name:        "built-in template in the locale wins over a company override in the default locale",
+			channel:     "email",
+			data:        Data{Company: "Light", Locale: "de", Description: "Ads"},
+			wantSubject: "Anfrage zur Rechnungsfreigabe",
+			wantHTML:    "eine Rechnung wartet auf Ihre Freigabe",
+		},
+		{
+			name:        "html override is escaped",
+			channel:     "email",
+			data:        Data{Company: "Light", Locale: "en", Description: "<b>Ads</b>"},
+			wantSubject: "Invoice approval request",
+			wantHTML:    "<p>&lt;b&gt;Ads&lt;/b&gt;</p>",
+		},
+		{
+			name:        "unsupported locale falls back to the default locale",
+			channel:     "teams",
+			data:        Data{Locale: "fr", Mention: "Vera"},
+			wantSubject: "Invoice approval request",
+			wantText:    "Hi Vera,",
+		},
+		{
+			name:    "channel without templates",
+			channel: "webhook",
+			data:    Data{Locale: "en"},
+			wantErr: ErrTemplateNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := renderer.Render(test.channel, test.data)
+
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("Render() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() unexpected error: %v", err)
+			}
+			if got.Subject != test.wantSubject {
+				t.Errorf("Render() subject = %q, want %q", got.Subject, test.wantSubject)
+			}
+			if !strings.Contains(got.Text, test.wantText) {
+				t.Errorf("Render() text = %q, want it to contain %q", got.Text, test.wantText)
+			}
+			if !strings.Contains(got.HTML, test.wantHTML) {
+				t.Errorf("Render() html = %q, want it to contain %q", got.HTML, test.wantHTML)
+			}
+		})
+	}
+}
+
+func TestNewRenderer_InvalidOverride(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "parse error", content: "{{if .Vendor}}"},
+		{name: "unknown field", content: "{{.Supplier}}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTemplate(t, dir, "Light/email/en/subject.txt.tmpl", test.content)
+
+			_, err := NewRenderer(WithDir(dir))
+			if !errors.Is(err, ErrInvalidTemplate) {
+				t.Errorf("NewRenderer() error = %v, want %v", err, ErrInvalidTemplate)
+			}
+		})
+	}
+}
+
+// writeTemplate writes a template to name in dir.
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
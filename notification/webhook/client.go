@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+)
+
+const (
+	// defaultTimeout is the timeout for a single webhook call.
+	defaultTimeout = 10 * time.Second
+)
+
+// ErrUnexpectedStatus is matched by every StatusError with errors.Is.
+var ErrUnexpectedStatus = errors.New("webhook returned unexpected status")
+
+// StatusError is returned when the webhook answers with a non-2xx status.
+// RetryAfter is set when the webhook said when to retry.
+type StatusError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+// Error returns a description of the rejected request.
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s: status %d", ErrUnexpectedStatus, e.StatusCode)
+	}
+	return fmt.Sprintf("%s: status %d: %s", ErrUnexpectedStatus, e.StatusCode, e.Message)
+}
+
+// Is reports whether target is ErrUnexpectedStatus.
+func (e *StatusError) Is(target error) bool {
+	return target == ErrUnexpectedStatus
+}
+
+// client posts signed events to the webhook.
+type client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// delivery is a single attempt to post an event.
+type delivery struct {
+	body           []byte
+	secret         string
+	idempotencyKey string
+	attempt        int
+	timestamp      time.Time
+}
+
+// post posts the event body of a delivery attempt. It returns the status
+// code of the response, or zero if no response was received.
+func (c *client) post(d delivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(d.body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(d.timestamp.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set(VersionHeader, Version)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(d.secret, timestamp, d.body))
+	req.Header.Set(IdempotencyKeyHeader, d.idempotencyKey)
+	req.Header.Set(AttemptHeader, strconv.Itoa(d.attempt))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, &StatusError{
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(body)),
+			RetryAfter: notification.ParseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 512))
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+)
+
+const (
+	// Version is the version of the event payload. It is sent in the
+	// X-Webhook-Version header and the version field of the event, and is
+	// bumped on incompatible payload changes.
+	Version = "v1"
+	// EventApprovalRequested is the type of the event sent when an approval
+	// request is created.
+	EventApprovalRequested = "approval_request.created"
+
+	// SignatureHeader holds the signature of the request.
+	SignatureHeader = "X-Webhook-Signature"
+	// TimestampHeader holds the time the request was signed, in seconds
+	// since the Unix epoch.
+	TimestampHeader = "X-Webhook-Timestamp"
+	// IdempotencyKeyHeader holds the key shared by all attempts to deliver
+	// the same event. Receivers use it to drop duplicate deliveries.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// VersionHeader holds the version of the event payload.
+	VersionHeader = "X-Webhook-Version"
+	// AttemptHeader holds the number of the delivery attempt, starting at 1.
+	AttemptHeader = "X-Webhook-Attempt"
+
+	// signatureVersion is the version prefix of webhook signatures.
+	signatureVersion = "v1"
+	// maxRequestAge is how far a request timestamp may be from the current
+	// time before VerifySignature rejects the request as a possible replay.
+	maxRequestAge = 5 * time.Minute
+)
+
+var (
+	// ErrInvalidSignature is returned by VerifySignature when a request is
+	// not signed with the secret.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrStaleRequest is returned by VerifySignature when a request
+	// timestamp is too old or too far in the future.
+	ErrStaleRequest = errors.New("webhook request timestamp out of range")
+)
+
+// Event is the JSON body posted to the webhook. ID is the idempotency key
+// of the delivery.
+type Event struct {
+	Version   string              `json:"version"`
+	Type      string              `json:"type"`
+	ID        string              `json:"id"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      api.ApprovalRequest `json:"data"`
+}
+
+// Sign returns the signature of a request body signed at timestamp, in the
+// form v1=<hex encoded HMAC-SHA256>. The HMAC is computed over
+// "v1:<timestamp>:<body>" with the shared secret as key.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s:%s:", signatureVersion, timestamp)
+	mac.Write(body)
+	return signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature verifies the signature and timestamp headers of a
+// webhook request against its body. Receivers of the webhook can use it to
+// authenticate requests.
+func VerifySignature(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get(TimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing or malformed timestamp", ErrInvalidSignature)
+	}
+
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > maxRequestAge || age < -maxRequestAge {
+		return ErrStaleRequest
+	}
+
+	expected := Sign(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get(SignatureHeader))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,303 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/common"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+)
+
+// ChannelName is the name of the webhook notification channel.
+const ChannelName = "webhook"
+
+var (
+	// ErrInvalidConnectionString is returned when the connection string is
+	// not an http or https URL.
+	ErrInvalidConnectionString = errors.New("invalid webhook connection string")
+	// ErrMissingSecret is returned when an approval request is sent without
+	// a signing secret configured.
+	ErrMissingSecret = errors.New("webhook secret is required to sign webhook requests")
+	// ErrDeliveryFailed is returned when an approval request could not be
+	// delivered. It wraps the error of the attempt.
+	ErrDeliveryFailed = errors.New("webhook delivery failed")
+)
+
+// AttemptRecorder records delivery attempts, and lists the attempts
+// already made to deliver an approval request.
+type AttemptRecorder interface {
+	RecordDeliveryAttempt(attempt api.DeliveryAttempt) error
+	ListDeliveryAttempts(approvalRequestID int) ([]api.DeliveryAttempt, error)
+}
+
+type service struct {
+	log      common.Logger
+	client   *client
+	secret   string
+	recorder AttemptRecorder
+	now      func() time.Time
+}
+
+// Options holds the configuration for the service.
+type Options struct {
+	Logger     common.Logger
+	HTTPClient *http.Client
+	Secret     string
+	Recorder   AttemptRecorder
+}
+
+// Option is a function that configures the service.
+type Option func(*service)
+
+// NewService returns a new service. The connection string is the URL the
+// approval requests are posted to.
+func NewService(connectionString string, options ...Option) (*service, error) {
+	if connectionString == "" {
+		return nil, fmt.Errorf("webhook url is required to start webhook service")
+	}
+	u, err := url.Parse(connectionString)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConnectionString, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%w: scheme must be http or https, got %q", ErrInvalidConnectionString, u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: host is required", ErrInvalidConnectionString)
+	}
+
+	s := service{
+		client: &client{
+			url:        connectionString,
+			httpClient: &http.Client{Timeout: defaultTimeout},
+		},
+		now: time.Now,
+	}
+
+	for _, option := range options {
+		option(&s)
+	}
+
+	if s.log == nil {
+		s.log = common.NewLogger()
+	}
+
+	return &s, nil
+}
+
+// SendApprovalRequest posts the approval request as a signed, versioned
+// JSON event. It makes a single attempt, which is recorded with the
+// recorder, if one is configured. Failed attempts are retried by the
+// caller; a 429 response is returned as a *notification.RateLimitError
+// with the webhook's Retry-After, so that the retry waits for it. The
+// idempotency key of the delivery is returned as the message ID.
+func (s *service) SendApprovalRequest(approvalRequest api.ApprovalRequest) (api.ApprovalResponse, error) {
+	if s.secret == "" {
+		return api.ApprovalResponse{}, fmt.Errorf("failed to send webhook approval request to %s: %w", approvalRequest.Approver.Name, ErrMissingSecret)
+	}
+
+	key := s.idempotencyKey(approvalRequest)
+
+	body, err := json.Marshal(Event{
+		Version:   Version,
+		Type:      EventApprovalRequested,
+		ID:        key,
+		CreatedAt: s.now().UTC(),
+		Data:      approvalRequest,
+	})
+	if err != nil {
+		return api.ApprovalResponse{}, fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	s.log.Info("Sending approval request via webhook",
+		"approver_name", approvalRequest.Approver.Name,
+		"approver_role", approvalRequest.Approver.Role,
+		"approval_request_id", approvalRequest.ID,
+		"idempotency_key", key,
+		"invoice_amount", approvalRequest.Invoice.Amount.String(),
+	)
+
+	if err := s.deliver(approvalRequest, key, body); err != nil {
+		s.log.Error("Failed to send approval request via webhook",
+			"approval_request_id", approvalRequest.ID,
+			"idempotency_key", key,
+			"error", err,
+		)
+		return api.ApprovalResponse{}, fmt.Errorf("failed to send webhook approval request: %w", err)
+	}
+
+	return api.ApprovalResponse{
+		ApproverName:      approvalRequest.Approver.Name,
+		ApproverRole:      approvalRequest.Approver.Role,
+		ApproverChannel:   ChannelName,
+		ApproverContactID: approvalRequest.Approver.Email,
+		MessageID:         key,
+	}, nil
+}
+
+// Channel returns the service as a notification channel. Approvers are
+// identified by their email address in the posted event.
+func (s *service) Channel() notification.Channel {
+	return notification.Channel{
+		Name:      ChannelName,
+		Sender:    s,
+		ContactID: ContactID,
+	}
+}
+
+// ContactID returns the approver's email address, which is their contact on
+// the channel.
+func ContactID(approver api.Approver) string {
+	return approver.Email
+}
+
+// deliver posts the event body once, signed with the current time, and
+// records the attempt. Attempts are numbered across retries by the
+// attempts recorded before.
+func (s *service) deliver(approvalRequest api.ApprovalRequest, key string, body []byte) error {
+	attempt := s.nextAttempt(approvalRequest.ID)
+	attemptedAt := s.now()
+	statusCode, err := s.client.post(delivery{
+		body:           body,
+		secret:         s.secret,
+		idempotencyKey: key,
+		attempt:        attempt,
+		timestamp:      attemptedAt,
+	})
+	s.record(api.DeliveryAttempt{
+		ApprovalRequestID: approvalRequest.ID,
+		Channel:           ChannelName,
+		IdempotencyKey:    key,
+		Attempt:           attempt,
+		StatusCode:        statusCode,
+		Error:             errorText(err),
+		Succeeded:         err == nil,
+		AttemptedAt:       attemptedAt,
+	})
+	if err == nil {
+		return nil
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests {
+		return &notification.RateLimitError{
+			Channel:    ChannelName,
+			ContactID:  ContactID(approvalRequest.Approver),
+			RetryAfter: statusErr.RetryAfter,
+			Err:        err,
+		}
+	}
+	return fmt.Errorf("%w: %w", ErrDeliveryFailed, err)
+}
+
+// nextAttempt returns the number of the next attempt to deliver the
+// approval request over the channel, starting at 1.
+func (s *service) nextAttempt(approvalRequestID int) int {
+	if s.recorder == nil {
+		return 1
+	}
+	attempts, err := s.recorder.ListDeliveryAttempts(approvalRequestID)
+	if err != nil {
+		s.log.Error("Failed to list webhook delivery attempts",
+			"approval_request_id", approvalRequestID,
+			"error", err,
+		)
+		return 1
+	}
+	next := 1
+	for _, attempt := range attempts {
+		if attempt.Channel == ChannelName && attempt.Attempt >= next {
+			next = attempt.Attempt + 1
+		}
+	}
+	return next
+}
+
+// record records a delivery attempt. Failing to record an attempt does not
+// fail the delivery.
+func (s *service) record(attempt api.DeliveryAttempt) {
+	if s.recorder == nil {
+		return
+	}
+	if err := s.recorder.RecordDeliveryAttempt(attempt); err != nil {
+		s.log.Error("Failed to record webhook delivery attempt",
+			"approval_request_id", attempt.ApprovalRequestID,
+			"attempt", attempt.Attempt,
+			"error", err,
+		)
+	}
+}
+
+// errorText returns the text of err, or an empty string if err is nil.
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// idempotencyKey returns the idempotency key of the approval request on
+// the channel. It is derived from the company and ID of the request, so
+// every attempt to deliver the request carries the same key, and keyed
+// with the secret, so receivers cannot guess the keys of other requests.
+func (s *service) idempotencyKey(approvalRequest api.ApprovalRequest) string {
+	mac := hmac.New(sha256.New, []byte(s.secret))
+	mac.Write([]byte(approvalRequest.Company + ":" + strconv.Itoa(approvalRequest.ID) + ":" + ChannelName))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// WithOptions configures the service with the given Options.
+func WithOptions(options Options) Option {
+	return func(s *service) {
+		if options.Logger != nil {
+			s.log = options.Logger
+		}
+		if options.HTTPClient != nil {
+			s.client.httpClient = options.HTTPClient
+		}
+		if options.Secret != "" {
+			s.secret = options.Secret
+		}
+		if options.Recorder != nil {
+			s.recorder = options.Recorder
+		}
+	}
+}
+
+// WithLogger configures the service with the given logger.
+func WithLogger(logger common.Logger) Option {
+	return func(s *service) {
+		s.log = logger
+	}
+}
+
+// WithHTTPClient configures the HTTP client used to call the webhook.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(s *service) {
+		s.client.httpClient = httpClient
+	}
+}
+
+// WithSecret configures the secret requests are signed with.
+func WithSecret(secret string) Option {
+	return func(s *service) {
+		s.secret = secret
+	}
+}
+
+// WithRecorder configures the recorder delivery attempts are recorded
+// with.
+func WithRecorder(recorder AttemptRecorder) Option {
+	return func(s *service) {
+		s.recorder = recorder
+	}
+}
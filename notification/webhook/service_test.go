@@ -0,0 +1,334 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewService(t *testing.T) {
+	var tests = []struct {
+		name        string
+		connection  string
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:       "valid service creation",
+			connection: "https://tools.light.com/hooks/approvals",
+		},
+		{
+			name:       "empty connection string",
+			connection: "",
+			wantErr:    true,
+		},
+		{
+			name:        "unsupported scheme",
+			connection:  "ftp://tools.light.com",
+			wantErr:     true,
+			expectedErr: ErrInvalidConnectionString,
+		},
+		{
+			name:        "missing host",
+			connection:  "https:///hooks",
+			wantErr:     true,
+			expectedErr: ErrInvalidConnectionString,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewService(test.connection, WithLogger(&mockLogger{}))
+
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("NewService() expected error but got none")
+				}
+				if test.expectedErr != nil && !errors.Is(err, test.expectedErr) {
+					t.Errorf("NewService() expected error %v but got %v", test.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewService() unexpected error: %v", err)
+			}
+			if got.client.url != test.connection {
+				t.Errorf("NewService() url = %v, want %v", got.client.url, test.connection)
+			}
+		})
+	}
+}
+
+func TestService_SendApprovalRequest(t *testing.T) {
+	const secret = "webhook-secret"
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	approvalRequest := api.ApprovalRequest{
+		ID: 7,
+		Approver: api.Approver{
+			Name:  "Amanda Svensson",
+			Role:  "CFO",
+			Email: "amanda_svensson@light.com",
+		},
+		Invoice: api.InvoiceDetails{
+			Amount:     money.New(1500000, money.USD),
+			Department: "Finance",
+		},
+	}
+
+	var tests = []struct {
+		name           string
+		status         int
+		retryAfter     string
+		previous       []api.DeliveryAttempt
+		wantAttempt    api.DeliveryAttempt
+		wantErr        error
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:        "delivered",
+			status:      http.StatusNoContent,
+			wantAttempt: api.DeliveryAttempt{Attempt: 1, StatusCode: http.StatusNoContent, Succeeded: true},
+		},
+		{
+			name:   "numbered after the attempts recorded before",
+			status: http.StatusOK,
+			previous: []api.DeliveryAttempt{
+				{Channel: ChannelName, Attempt: 1, StatusCode: http.StatusBadGateway},
+				{Channel: "slack", Attempt: 4},
+				{Channel: ChannelName, Attempt: 2, StatusCode: http.StatusBadGateway},
+			},
+			wantAttempt: api.DeliveryAttempt{Attempt: 3, StatusCode: http.StatusOK, Succeeded: true},
+		},
+		{
+			name:        "server error is not retried",
+			status:      http.StatusInternalServerError,
+			wantAttempt: api.DeliveryAttempt{Attempt: 1, StatusCode: http.StatusInternalServerError, Error: "webhook returned unexpected status: status 500: failed"},
+			wantErr:     ErrDeliveryFailed,
+		},
+		{
+			name:           "rate limited with retry after",
+			status:         http.StatusTooManyRequests,
+			retryAfter:     "10",
+			wantAttempt:    api.DeliveryAttempt{Attempt: 1, StatusCode: http.StatusTooManyRequests, Error: "webhook returned unexpected status: status 429: failed"},
+			wantErr:        notification.ErrRateLimited,
+			wantRetryAfter: 10 * time.Second,
+		},
+		{
+			name:           "rate limited with a retry after above the maximum",
+			status:         http.StatusTooManyRequests,
+			retryAfter:     "86400",
+			wantAttempt:    api.DeliveryAttempt{Attempt: 1, StatusCode: http.StatusTooManyRequests, Error: "webhook returned unexpected status: status 429: failed"},
+			wantErr:        notification.ErrRateLimited,
+			wantRetryAfter: notification.MaxRetryAfter,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var keys []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if err := VerifySignature(secret, r.Header, body, now); err != nil {
+					t.Errorf("SendApprovalRequest() request signature: %v", err)
+				}
+				if got := r.Header.Get(VersionHeader); got != Version {
+					t.Errorf("SendApprovalRequest() version header = %q, want %q", got, Version)
+				}
+				if got := r.Header.Get(AttemptHeader); got != strconv.Itoa(test.wantAttempt.Attempt) {
+					t.Errorf("SendApprovalRequest() attempt header = %q, want %d", got, test.wantAttempt.Attempt)
+				}
+				keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+
+				var event Event
+				if err := json.Unmarshal(body, &event); err != nil {
+					t.Errorf("SendApprovalRequest() invalid request body: %v", err)
+				}
+				if diff := cmp.Diff(approvalRequest, event.Data); diff != "" {
+					t.Errorf("SendApprovalRequest() event data mismatch (-want +got)\n%s", diff)
+				}
+
+				if test.retryAfter != "" {
+					w.Header().Set("Retry-After", test.retryAfter)
+				}
+				w.WriteHeader(test.status)
+				if test.status >= 300 {
+					fmt.Fprint(w, "failed")
+				}
+			}))
+			defer server.Close()
+
+			recorder := &mockRecorder{attempts: test.previous}
+			svc, err := NewService(server.URL,
+				WithLogger(&mockLogger{}),
+				WithSecret(secret),
+				WithRecorder(recorder),
+			)
+			if err != nil {
+				t.Fatalf("NewService() unexpected error: %v", err)
+			}
+			svc.now = func() time.Time { return now }
+
+			got, err := svc.SendApprovalRequest(approvalRequest)
+
+			if len(keys) != 1 || keys[0] != svc.idempotencyKey(approvalRequest) {
+				t.Fatalf("SendApprovalRequest() idempotency keys = %q, want one request with %q", keys, svc.idempotencyKey(approvalRequest))
+			}
+			test.wantAttempt.ApprovalRequestID = 7
+			test.wantAttempt.Channel = ChannelName
+			test.wantAttempt.IdempotencyKey = keys[0]
+			test.wantAttempt.AttemptedAt = now
+			if diff := cmp.Diff(append(test.previous, test.wantAttempt), recorder.attempts); diff != "" {
+				t.Errorf("SendApprovalRequest() recorded attempts mismatch (-want +got)\n%s", diff)
+			}
+
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) || !errors.Is(err, ErrUnexpectedStatus) {
+					t.Errorf("SendApprovalRequest() error = %v, want %v", err, test.wantErr)
+				}
+				var limitErr *notification.RateLimitError
+				if errors.As(err, &limitErr) && limitErr.RetryAfter != test.wantRetryAfter {
+					t.Errorf("SendApprovalRequest() retry after = %s, want %s", limitErr.RetryAfter, test.wantRetryAfter)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SendApprovalRequest() unexpected error: %v", err)
+			}
+			want := api.ApprovalResponse{
+				ApproverName:      "Amanda Svensson",
+				ApproverRole:      "CFO",
+				ApproverChannel:   ChannelName,
+				ApproverContactID: "amanda_svensson@light.com",
+				MessageID:         keys[0],
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("SendApprovalRequest() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_SendApprovalRequest_MissingSecret(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	svc, err := NewService(server.URL, WithLogger(&mockLogger{}))
+	if err != nil {
+		t.Fatalf("NewService() unexpected error: %v", err)
+	}
+
+	_, err = svc.SendApprovalRequest(api.ApprovalRequest{
+		Approver: api.Approver{Name: "John Doe", Email: "john@example.com"},
+		Invoice:  api.InvoiceDetails{Amount: money.New(50000, money.USD)},
+	})
+	if !errors.Is(err, ErrMissingSecret) {
+		t.Errorf("SendApprovalRequest() error = %v, want %v", err, ErrMissingSecret)
+	}
+	if called {
+		t.Errorf("SendApprovalRequest() called the webhook without a secret")
+	}
+}
+
+func TestService_IdempotencyKey(t *testing.T) {
+	svc := &service{secret: "webhook-secret"}
+	request := api.ApprovalRequest{ID: 7, Company: "Light"}
+
+	key := svc.idempotencyKey(request)
+	if key == "" || key != svc.idempotencyKey(request) {
+		t.Errorf("idempotencyKey() = %q, want the same key for every attempt", key)
+	}
+	for _, other := range []api.ApprovalRequest{{ID: 8, Company: "Light"}, {ID: 7, Company: "Acme"}} {
+		if svc.idempotencyKey(other) == key {
+			t.Errorf("idempotencyKey(%+v) = %q, want a key other than that of %+v", other, key, request)
+		}
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "webhook-secret"
+	now := time.Unix(1767366245, 0)
+	body := []byte(`{"version":"v1"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	var tests = []struct {
+		name      string
+		timestamp string
+		signature string
+		wantErr   error
+	}{
+		{
+			name:      "valid signature",
+			timestamp: timestamp,
+			signature: Sign(secret, timestamp, body),
+		},
+		{
+			name:      "signed with another secret",
+			timestamp: timestamp,
+			signature: Sign("other-secret", timestamp, body),
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "missing timestamp",
+			signature: Sign(secret, timestamp, body),
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "stale timestamp",
+			timestamp: strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10),
+			signature: Sign(secret, strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10), body),
+			wantErr:   ErrStaleRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set(TimestampHeader, test.timestamp)
+			header.Set(SignatureHeader, test.signature)
+
+			err := VerifySignature(secret, header, body, now)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("VerifySignature() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// Mock implementations for testing
+type mockLogger struct{}
+
+func (l *mockLogger) Info(msg string, args ...any) {
+	// Mock implementation - just ignore for testing
+}
+
+func (l *mockLogger) Error(msg string, args ...any) {
+	// Mock implementation - just ignore for testing
+}
+
+type mockRecorder struct {
+	attempts []api.DeliveryAttempt
+}
+
+func (r *mockRecorder) RecordDeliveryAttempt(attempt api.DeliveryAttempt) error {
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func (r *mockRecorder) ListDeliveryAttempts(approvalRequestID int) ([]api.DeliveryAttempt, error) {
+	return r.attempts, nil
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/KatrinSalt/backend-challenge-go/access"
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/auth"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+)
+
+// Authenticator resolves API keys to the company they belong to.
+type Authenticator interface {
+	Authenticate(key string) (auth.Principal, error)
+}
+
+// CompanyServicesFunc returns the management and workflow services of the
+// company with the name.
+type CompanyServicesFunc func(company string) (management.Service, workflow.Service, error)
+
+// companyServices holds the services of the company of a request.
+type companyServices struct {
+	management management.Service
+	workflow   workflow.Service
+}
+
+// companyServicesKey is the context key of the services of the company of a
+// request.
+type companyServicesKey struct{}
+
+// authorize returns the handler of a route that requires the permission.
+// The request must carry an API key with the permission as a bearer token,
+// and is served with the services of the company the key belongs to, acting
+// as the user of the key if it has one. Routes without a permission, and
+// every route if the server has no authenticator, are served as they are.
+func (s *Server) authorize(permission api.Permission, next http.HandlerFunc) http.HandlerFunc {
+	if s.authenticator == nil || permission == "" {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, err := s.authenticator.Authenticate(bearerToken(r))
+		if errors.Is(err, auth.ErrInvalidAPIKey) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			s.writeError(w, &Error{Status: http.StatusUnauthorized, Code: CodeUnauthorized, Message: "a valid API key is required", Err: err})
+			return
+		}
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		if !principal.Can(permission) {
+			s.writeError(w, &Error{Status: http.StatusForbidden, Code: CodeForbidden, Message: fmt.Sprintf("the API key does not have the %s permission", permission)})
+			return
+		}
+
+		managementSvc, workflowSvc, err := s.companyServices(principal.Company)
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		// A key that acts as a user is only allowed what the user's role allows
+		if principal.User != nil {
+			managementSvc = access.Management(managementSvc, *principal.User)
+			workflowSvc = access.Workflow(workflowSvc, *principal.User)
+		}
+		ctx := auth.NewContext(r.Context(), principal)
+		ctx = context.WithValue(ctx, companyServicesKey{}, companyServices{management: managementSvc, workflow: workflowSvc})
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// bearerToken returns the bearer token of the Authorization header of the
+// request, or an empty string if it has none.
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// managementFor returns the management service of the company of the
+// request.
+func (s *Server) managementFor(r *http.Request) management.Service {
+	if services, ok := r.Context().Value(companyServicesKey{}).(companyServices); ok {
+		return services.management
+	}
+	return s.management
+}
+
+// workflowFor returns the workflow service of the company of the request.
+func (s *Server) workflowFor(r *http.Request) workflow.Service {
+	if services, ok := r.Context().Value(companyServicesKey{}).(companyServices); ok {
+		return services.workflow
+	}
+	return s.workflow
+}
+
+// companyID parses the id path parameter of a company route, like pathID.
+// An API key only has access to its own company, so other companies are
+// reported as not found.
+func (s *Server) companyID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, ok := s.pathID(w, r)
+	if !ok {
+		return 0, false
+	}
+	if principal, ok := auth.FromContext(r.Context()); ok && principal.CompanyID != id {
+		s.writeError(w, fmt.Errorf("company %d: %w", id, db.ErrCompanyNotFound))
+		return 0, false
+	}
+	return id, true
+}
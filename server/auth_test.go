@@ -0,0 +1,235 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/auth"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServer_Handler_Authentication(t *testing.T) {
+	principals := map[string]auth.Principal{
+		"ak_reader": {KeyID: 1, CompanyID: 2, Company: "Acme", Permissions: []api.Permission{api.PermissionRead}},
+		"ak_admin":  {KeyID: 2, CompanyID: 2, Company: "Acme", Permissions: api.Permissions},
+		"ak_rules":  {KeyID: 5, CompanyID: 2, Company: "Acme", Permissions: []api.Permission{api.PermissionRead, api.PermissionManageRules}},
+		"ak_viewer": {KeyID: 3, CompanyID: 2, Company: "Acme", Permissions: api.Permissions, User: &api.User{
+			ID: 4, CompanyID: 2, Name: "Auditor", Email: "auditor@acme.com", Role: api.RoleViewer,
+		}},
+		"ak_clerk": {KeyID: 4, CompanyID: 2, Company: "Acme", Permissions: api.Permissions, User: &api.User{
+			ID: 5, CompanyID: 2, Name: "Clerk", Email: "clerk@acme.com", Role: api.RoleSubmitter,
+		}},
+	}
+	acme := &mockManagementService{
+		company:  api.Company{ID: 2, Name: "Acme"},
+		approver: api.Approver{ID: 3, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@acme.com"},
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+		// wantSubmitter is the submitter of the invoice processed, if any.
+		wantSubmitter string
+	}{
+		{
+			name:       "health needs no api key",
+			method:     http.MethodGet,
+			path:       "/health",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"ok"}`,
+		},
+		{
+			name:       "no api key",
+			method:     http.MethodGet,
+			path:       "/approvers/3",
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `{"error":{"code":"unauthorized","message":"a valid API key is required: invalid api key: no api key"}}`,
+		},
+		{
+			name:       "unknown api key",
+			key:        "ak_unknown",
+			method:     http.MethodGet,
+			path:       "/approvers/3",
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `{"error":{"code":"unauthorized","message":"a valid API key is required: invalid api key: unknown api key"}}`,
+		},
+		{
+			name:       "served for the company of the api key",
+			key:        "ak_reader",
+			method:     http.MethodGet,
+			path:       "/approvers/3",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":3,"name":"Amanda Svensson","role":"CFO","email":"amanda@acme.com","slack_id":""}`,
+		},
+		{
+			name:       "missing permission",
+			key:        "ak_reader",
+			method:     http.MethodDelete,
+			path:       "/approvers/3",
+			wantStatus: http.StatusForbidden,
+			wantBody:   `{"error":{"code":"forbidden","message":"the API key does not have the manage_approvers permission"}}`,
+		},
+		{
+			name:       "rules permission does not delete companies",
+			key:        "ak_rules",
+			method:     http.MethodDelete,
+			path:       "/companies/2",
+			wantStatus: http.StatusForbidden,
+			wantBody:   `{"error":{"code":"forbidden","message":"the API key does not have the manage_companies permission"}}`,
+		},
+		{
+			name:       "rules permission does not replay dead letters",
+			key:        "ak_rules",
+			method:     http.MethodPost,
+			path:       "/outbox/1/replay",
+			wantStatus: http.StatusForbidden,
+			wantBody:   `{"error":{"code":"forbidden","message":"the API key does not have the manage_notifications permission"}}`,
+		},
+		{
+			name:       "allowed by the role of the user of the api key",
+			key:        "ak_viewer",
+			method:     http.MethodGet,
+			path:       "/approvers/3",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":3,"name":"Amanda Svensson","role":"CFO","email":"amanda@acme.com","slack_id":""}`,
+		},
+		{
+			name:       "not allowed by the role of the user of the api key",
+			key:        "ak_viewer",
+			method:     http.MethodDelete,
+			path:       "/approvers/3",
+			wantStatus: http.StatusForbidden,
+			wantBody:   `{"error":{"code":"forbidden","message":"permission denied: auditor@acme.com has the role viewer, which does not allow manage_approvers"}}`,
+		},
+		{
+			name:       "only the company of the api key is listed",
+			key:        "ak_reader",
+			method:     http.MethodGet,
+			path:       "/companies",
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"id":2,"name":"Acme"}]`,
+		},
+		{
+			name:       "other companies are not found",
+			key:        "ak_admin",
+			method:     http.MethodDelete,
+			path:       "/companies/1",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"error":{"code":"not_found","message":"company 1: company not found"}}`,
+		},
+		{
+			name:       "api keys without a user cannot name the submitter",
+			key:        "ak_admin",
+			method:     http.MethodPost,
+			path:       "/invoices",
+			body:       `{"amount":{"cents":1500000,"currency":"USD"},"submitted_by":"amanda@acme.com"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"validation_failed","message":"validation failed","fields":[{"field":"submitted_by","message":"can only be set by API keys that act as a user"}]}}`,
+		},
+		{
+			name:       "api keys without a user submit invoices without a submitter",
+			key:        "ak_admin",
+			method:     http.MethodPost,
+			path:       "/invoices",
+			body:       `{"amount":{"cents":1500000,"currency":"USD"}}`,
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"approval_request_id":7,"approver_name":"Amanda Svensson","approver_role":"CFO","approver_channel":"slack","approver_contact_id":"U345678"}`,
+		},
+		{
+			name:          "api keys with a user submit invoices as their user",
+			key:           "ak_clerk",
+			method:        http.MethodPost,
+			path:          "/invoices",
+			body:          `{"amount":{"cents":1500000,"currency":"USD"},"submitted_by":"amanda@acme.com"}`,
+			wantStatus:    http.StatusCreated,
+			wantBody:      `{"approval_request_id":7,"approver_name":"Amanda Svensson","approver_role":"CFO","approver_channel":"slack","approver_contact_id":"U345678"}`,
+			wantSubmitter: "clerk@acme.com",
+		},
+		{
+			name:       "api keys cannot create companies",
+			key:        "ak_admin",
+			method:     http.MethodPost,
+			path:       "/companies",
+			body:       `{"name":"Globex"}`,
+			wantStatus: http.StatusForbidden,
+			wantBody:   `{"error":{"code":"forbidden","message":"API keys cannot create companies"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wf := &mockWorkflowService{resp: api.ApprovalResponse{
+				ApprovalRequestID: 7, ApproverName: "Amanda Svensson", ApproverRole: "CFO", ApproverChannel: "slack", ApproverContactID: "U345678",
+			}}
+			companyServices := func(company string) (management.Service, workflow.Service, error) {
+				if company != "Acme" {
+					return nil, nil, errors.New("unexpected company " + company)
+				}
+				return acme, wf, nil
+			}
+			// The services of the configured company must not be used.
+			srv, err := NewServer(&mockManagementService{}, &mockWorkflowService{},
+				WithLogger(&mockLogger{}),
+				WithAuthentication(&mockAuthenticator{principals: principals}, companyServices),
+			)
+			if err != nil {
+				t.Fatalf("NewServer() unexpected error: %v", err)
+			}
+
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			if test.key != "" {
+				req.Header.Set("Authorization", "Bearer "+test.key)
+			}
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, test.wantStatus)
+			}
+			if diff := cmp.Diff(test.wantBody, strings.TrimSpace(rec.Body.String())); diff != "" {
+				t.Errorf("body mismatch (-want +got)\n%s", diff)
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Errorf("401 response without a WWW-Authenticate header")
+			}
+			if test.wantSubmitter != "" && (len(wf.invoices) != 1 || wf.invoices[0].SubmittedBy != test.wantSubmitter) {
+				t.Errorf("ProcessInvoice() invoices = %+v, want one submitted by %s", wf.invoices, test.wantSubmitter)
+			}
+		})
+	}
+}
+
+func TestNewServer_Authentication(t *testing.T) {
+	_, err := NewServer(&mockManagementService{}, &mockWorkflowService{}, WithAuthentication(&mockAuthenticator{}, nil))
+	if err == nil {
+		t.Errorf("NewServer() expected an error for an authenticator without company services")
+	}
+}
+
+// mockAuthenticator authenticates the API keys of principals.
+type mockAuthenticator struct {
+	principals map[string]auth.Principal
+}
+
+func (m *mockAuthenticator) Authenticate(key string) (auth.Principal, error) {
+	if key == "" {
+		return auth.Principal{}, fmt.Errorf("%w: no api key", auth.ErrInvalidAPIKey)
+	}
+	principal, ok := m.principals[key]
+	if !ok {
+		return auth.Principal{}, fmt.Errorf("%w: unknown api key", auth.ErrInvalidAPIKey)
+	}
+	return principal, nil
+}
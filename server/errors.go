@@ -0,0 +1,221 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+)
+
+// Error codes of ErrorResponse.
+const (
+	CodeInvalidRequest   = "invalid_request"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeValidationFailed = "validation_failed"
+	CodeNotFound         = "not_found"
+	CodeAlreadyExists    = "already_exists"
+	CodeConflict         = "conflict"
+	CodeNoMatchingRule   = "no_matching_rule"
+	CodeDeliveryFailed   = "delivery_failed"
+	CodeInternal         = "internal_error"
+)
+
+// ErrorResponse is the body of every error response.
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes an error. Fields lists the invalid fields of a
+// request that failed validation.
+type ErrorBody struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError describes why a field of a request is invalid. Field is the
+// JSON name of the field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is an error with the HTTP status and code it is reported with.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Err     error
+}
+
+// Error returns the message, followed by the underlying error if any.
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// errorMapping maps the errors matching err with errors.Is to a status and
+// code.
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// errorMappings are the typed errors of the services and the status and
+// code they are reported with, in the order they are matched. Validation
+// errors and errors not listed are handled by toError.
+var errorMappings = []errorMapping{
+	// Entities of other companies are reported as not found, so that their
+	// existence is not disclosed.
+	{err: db.ErrCrossCompanyAccess, status: http.StatusNotFound, code: CodeNotFound},
+	{err: db.ErrCompanyNotFound, status: http.StatusNotFound, code: CodeNotFound},
+	{err: db.ErrDepartmentNotFound, status: http.StatusNotFound, code: CodeNotFound},
+	{err: db.ErrWorkflowRuleNotFound, status: http.StatusNotFound, code: CodeNotFound},
+	{err: db.ErrApproverNotFound, status: http.StatusNotFound, code: CodeNotFound},
+	{err: db.ErrApprovalRequestNotFound, status: http.StatusNotFound, code: CodeNotFound},
+	{err: db.ErrOutboxMessageNotFound, status: http.StatusNotFound, code: CodeNotFound},
+	{err: db.ErrCompanyAlreadyExists, status: http.StatusConflict, code: CodeAlreadyExists},
+	{err: db.ErrDepartmentAlreadyExists, status: http.StatusConflict, code: CodeAlreadyExists},
+	{err: db.ErrWorkflowRuleAlreadyExists, status: http.StatusConflict, code: CodeAlreadyExists},
+	{err: db.ErrApproverAlreadyExists, status: http.StatusConflict, code: CodeAlreadyExists},
+	{err: db.ErrCompanyNotEmpty, status: http.StatusConflict, code: CodeConflict},
+	{err: db.ErrApprovalRequestAlreadyDecided, status: http.StatusConflict, code: CodeConflict},
+	{err: management.ErrDepartmentInUse, status: http.StatusConflict, code: CodeConflict},
+	{err: management.ErrNotDeadLetter, status: http.StatusConflict, code: CodeConflict},
+	{err: api.ErrPermissionDenied, status: http.StatusForbidden, code: CodeForbidden},
+	{err: api.ErrMissingField, status: http.StatusBadRequest, code: CodeValidationFailed},
+	{err: api.ErrMissingContact, status: http.StatusBadRequest, code: CodeValidationFailed},
+	{err: api.ErrUnsupportedLocale, status: http.StatusBadRequest, code: CodeValidationFailed},
+	{err: api.ErrUnsupportedNotificationPreference, status: http.StatusBadRequest, code: CodeValidationFailed},
+	{err: api.ErrMissingCompanyName, status: http.StatusBadRequest, code: CodeValidationFailed},
+	{err: api.ErrMissingDepartmentName, status: http.StatusBadRequest, code: CodeValidationFailed},
+	{err: api.ErrInvalidApprovalChannel, status: http.StatusBadRequest, code: CodeValidationFailed},
+	{err: api.ErrInvalidAmountRange, status: http.StatusBadRequest, code: CodeValidationFailed},
+	{err: api.ErrInvalidBound, status: http.StatusBadRequest, code: CodeValidationFailed},
+	{err: api.ErrInvalidDueDate, status: http.StatusBadRequest, code: CodeValidationFailed},
+	{err: db.ErrInvalidOutboxStatus, status: http.StatusBadRequest, code: CodeInvalidRequest},
+	{err: money.ErrInvalidAmount, status: http.StatusBadRequest, code: CodeInvalidRequest},
+	{err: money.ErrInvalidCurrency, status: http.StatusBadRequest, code: CodeInvalidRequest},
+	{err: workflow.ErrUnreachableApprover, status: http.StatusUnprocessableEntity, code: CodeValidationFailed},
+	{err: workflow.ErrUnsupportedApprovalChannel, status: http.StatusUnprocessableEntity, code: CodeValidationFailed},
+	{err: workflow.ErrNoIndependentApprover, status: http.StatusUnprocessableEntity, code: CodeValidationFailed},
+	{err: workflow.ErrDeadLettered, status: http.StatusBadGateway, code: CodeDeliveryFailed},
+}
+
+// toError returns the status and body an error is reported with. Errors
+// that are not typed are reported as internal errors, without their
+// message.
+func toError(err error) (int, ErrorResponse) {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Status, ErrorResponse{Error: ErrorBody{Code: apiErr.Code, Message: apiErr.Error()}}
+	}
+
+	var verr *api.ValidationError
+	if errors.As(err, &verr) {
+		body := ErrorBody{Code: CodeValidationFailed, Message: api.ErrValidation.Error()}
+		for _, field := range verr.Fields {
+			body.Fields = append(body.Fields, FieldError{Field: field.Field, Message: field.Err.Error()})
+		}
+		return http.StatusBadRequest, ErrorResponse{Error: body}
+	}
+
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.err) {
+			return mapping.status, ErrorResponse{Error: ErrorBody{Code: mapping.code, Message: err.Error()}}
+		}
+	}
+
+	return http.StatusInternalServerError, ErrorResponse{Error: ErrorBody{Code: CodeInternal, Message: http.StatusText(http.StatusInternalServerError)}}
+}
+
+// writeError writes the error response of err.
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	status, body := toError(err)
+	if status >= http.StatusInternalServerError {
+		s.log.Error("API request failed", "status", status, "error", err)
+	}
+	s.writeJSON(w, status, body)
+}
+
+// writeJSON writes v as the JSON body of a response with the status.
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.log.Error("failed to write API response", "error", err)
+	}
+}
+
+// respond writes the error response of err if it is not nil, and v with
+// the status otherwise. A 204 No Content response has no body.
+func (s *Server) respond(w http.ResponseWriter, status int, v any, err error) {
+	switch {
+	case err != nil:
+		s.writeError(w, err)
+	case status == http.StatusNoContent:
+		w.WriteHeader(status)
+	default:
+		s.writeJSON(w, status, v)
+	}
+}
+
+// decode decodes the JSON request body into v, after validating it against
+// the request body schema of the route in the OpenAPI document. If the body
+// is invalid, it writes a 400 Bad Request and returns false.
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		s.writeError(w, &Error{Status: http.StatusBadRequest, Code: CodeInvalidRequest, Message: "invalid JSON body", Err: err})
+		return false
+	}
+	if err := s.validator.Validate(r.Pattern, body); err != nil {
+		s.writeError(w, err)
+		return false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		s.writeError(w, &Error{Status: http.StatusBadRequest, Code: CodeInvalidRequest, Message: "invalid JSON body", Err: err})
+		return false
+	}
+	if decoder.More() {
+		s.writeError(w, &Error{Status: http.StatusBadRequest, Code: CodeInvalidRequest, Message: "invalid JSON body: more than one value"})
+		return false
+	}
+	return true
+}
+
+// recoverPanics reports panics in handlers as internal errors instead of
+// dropping the connection.
+func (s *Server) recoverPanics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				s.writeError(w, fmt.Errorf("panic serving %s %s: %v", r.Method, r.URL.Path, v))
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,315 @@
+package server
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+)
+
+// OpenAPIPath is the path the OpenAPI document of the API is served at.
+const OpenAPIPath = "/openapi.json"
+
+// openAPIDocument is the OpenAPI 3 document of the API. Request bodies are
+// validated against it.
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// openAPI is the part of an OpenAPI 3 document the server uses.
+type openAPI struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// operation is an OpenAPI operation.
+type operation struct {
+	OperationID string `json:"operationId"`
+	RequestBody *struct {
+		Required bool `json:"required"`
+		Content  map[string]struct {
+			Schema *schema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+// schema is the subset of the OpenAPI 3 schema object the document uses.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	Enum                 []any              `json:"enum"`
+	Pattern              string             `json:"pattern"`
+	MinLength            *int               `json:"minLength"`
+	Minimum              *float64           `json:"minimum"`
+	Nullable             bool               `json:"nullable"`
+	ReadOnly             bool               `json:"readOnly"`
+
+	pattern *regexp.Regexp
+}
+
+// requestValidator validates request bodies against the schemas of the
+// OpenAPI document.
+type requestValidator struct {
+	schemas map[string]*schema
+	// bodies are the request body schemas by route pattern, e.g.
+	// "POST /invoices".
+	bodies map[string]*schema
+}
+
+// newRequestValidator returns a validator for the request bodies of the
+// operations in the OpenAPI document.
+func newRequestValidator(document []byte) (*requestValidator, error) {
+	var doc openAPI
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	v := &requestValidator{schemas: doc.Components.Schemas, bodies: map[string]*schema{}}
+	for name, s := range v.schemas {
+		if err := v.compile(s); err != nil {
+			return nil, fmt.Errorf("invalid OpenAPI schema %s: %w", name, err)
+		}
+	}
+	for path, item := range doc.Paths {
+		for method, raw := range item {
+			if method == "parameters" {
+				continue
+			}
+			var op operation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, fmt.Errorf("invalid OpenAPI operation %s %s: %w", method, path, err)
+			}
+			if op.RequestBody == nil {
+				continue
+			}
+			body := op.RequestBody.Content["application/json"].Schema
+			if body == nil {
+				return nil, fmt.Errorf("invalid OpenAPI operation %s %s: request body has no application/json schema", method, path)
+			}
+			if err := v.compile(body); err != nil {
+				return nil, fmt.Errorf("invalid OpenAPI operation %s %s: %w", method, path, err)
+			}
+			v.bodies[strings.ToUpper(method)+" "+path] = body
+		}
+	}
+	return v, nil
+}
+
+// compile checks the references of a schema and compiles its patterns.
+func (v *requestValidator) compile(s *schema) error {
+	if s.Ref != "" {
+		if _, err := v.resolve(s); err != nil {
+			return err
+		}
+	}
+	if s.Pattern != "" && s.pattern == nil {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = pattern
+	}
+	for _, property := range s.Properties {
+		if err := v.compile(property); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return v.compile(s.Items)
+	}
+	return nil
+}
+
+// resolve returns the schema a reference points to, or the schema itself
+// if it is not a reference.
+func (v *requestValidator) resolve(s *schema) (*schema, error) {
+	if s.Ref == "" {
+		return s, nil
+	}
+	name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+	if !ok || v.schemas[name] == nil {
+		return nil, fmt.Errorf("unresolved reference %q", s.Ref)
+	}
+	return v.schemas[name], nil
+}
+
+// Validate validates the JSON body of a request to the route pattern
+// against its schema. Routes without a request body schema are not
+// validated. It returns an *api.ValidationError listing every field that
+// does not match, keyed by its JSON path.
+func (v *requestValidator) Validate(pattern string, body []byte) error {
+	s, ok := v.bodies[pattern]
+	if !ok {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return &Error{Status: http.StatusBadRequest, Code: CodeInvalidRequest, Message: "invalid JSON body", Err: err}
+	}
+
+	verr := &api.ValidationError{}
+	v.validate(verr, "", s, value)
+	return verr.ErrOrNil()
+}
+
+// validate validates value at path against the schema, adding the errors
+// to verr.
+func (v *requestValidator) validate(verr *api.ValidationError, path string, s *schema, value any) {
+	s, err := v.resolve(s)
+	if err != nil {
+		verr.Add(fieldPath(path), err)
+		return
+	}
+	field := fieldPath(path)
+
+	if value == nil {
+		if !s.Nullable {
+			verr.Add(field, errors.New("must not be null"))
+		}
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			verr.Add(field, errors.New("must be an object"))
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				verr.Add(fieldPath(join(path, name)), api.ErrMissingField)
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					verr.Add(fieldPath(join(path, name)), errors.New("unknown field"))
+				}
+				continue
+			}
+			v.validate(verr, join(path, name), property, object[name])
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			verr.Add(field, errors.New("must be an array"))
+			return
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				v.validate(verr, path+"["+strconv.Itoa(i)+"]", s.Items, item)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			verr.Add(field, errors.New("must be a string"))
+			return
+		}
+		if s.MinLength != nil && len(strings.TrimSpace(str)) < *s.MinLength {
+			verr.Add(field, errors.New("must not be empty"))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(str) {
+			verr.Add(field, fmt.Errorf("must match %s", s.Pattern))
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			verr.Add(field, errors.New("must be "+article(s.Type)))
+			return
+		}
+		f, err := number.Float64()
+		if s.Type == "integer" {
+			_, err = number.Int64()
+		}
+		if err != nil {
+			verr.Add(field, errors.New("must be "+article(s.Type)))
+			return
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			verr.Add(field, fmt.Errorf("must be at least %v", *s.Minimum))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			verr.Add(field, errors.New("must be a boolean"))
+			return
+		}
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		options := make([]string, len(s.Enum))
+		for i, option := range s.Enum {
+			options[i] = fmt.Sprint(option)
+		}
+		verr.Add(field, fmt.Errorf("must be one of: %s", strings.Join(options, ", ")))
+	}
+}
+
+// inEnum reports whether the decoded JSON value is one of the enum values.
+func inEnum(enum []any, value any) bool {
+	if number, ok := value.(json.Number); ok {
+		f, err := number.Float64()
+		if err != nil {
+			return false
+		}
+		value = f
+	}
+	return slices.Contains(enum, value)
+}
+
+// article returns the JSON type name with its indefinite article.
+func article(typ string) string {
+	if typ == "integer" {
+		return "an integer"
+	}
+	return "a " + typ
+}
+
+// join returns the JSON path of a property of the value at path.
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// fieldPath returns the field name of an error at path, "body" for the
+// whole body.
+func fieldPath(path string) string {
+	if path == "" {
+		return "body"
+	}
+	return path
+}
+
+// serveOpenAPI serves the OpenAPI document.
+func (s *Server) serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPIDocument)
+}
@@ -0,0 +1,349 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOpenAPI_Schemas(t *testing.T) {
+	v, err := newRequestValidator(openAPIDocument)
+	if err != nil {
+		t.Fatalf("newRequestValidator() unexpected error: %v", err)
+	}
+
+	// The schemas of the OpenAPI document and the types they describe.
+	types := map[string]reflect.Type{
+		"Money":            reflect.TypeFor[money.Money](),
+		"InvoiceRequest":   reflect.TypeFor[api.InvoiceRequest](),
+		"ApprovalResponse": reflect.TypeFor[api.ApprovalResponse](),
+		"Company":          reflect.TypeFor[api.Company](),
+		"Department":       reflect.TypeFor[departmentRequest](),
+		"WorkflowRule":     reflect.TypeFor[api.WorkflowRule](),
+		"Approver":         reflect.TypeFor[api.Approver](),
+		"OrgChartNode":     reflect.TypeFor[api.OrgChartNode](),
+		"DeliveryAttempt":  reflect.TypeFor[api.DeliveryAttempt](),
+		"OutboxMessage":    reflect.TypeFor[api.OutboxMessage](),
+		"ErrorResponse":    reflect.TypeFor[ErrorResponse](),
+		"ErrorBody":        reflect.TypeFor[ErrorBody](),
+		"FieldError":       reflect.TypeFor[FieldError](),
+	}
+	for name := range v.schemas {
+		if _, ok := types[name]; !ok {
+			t.Errorf("schema %s does not describe a Go type", name)
+		}
+	}
+
+	for name, typ := range types {
+		t.Run(name, func(t *testing.T) {
+			s, ok := v.schemas[name]
+			if !ok {
+				t.Fatalf("no schema for %s", typ)
+			}
+
+			var fields, alwaysEncoded []string
+			for i := range typ.NumField() {
+				field := typ.Field(i)
+				tag, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+				if tag == "-" || !field.IsExported() {
+					continue
+				}
+				fields = append(fields, tag)
+				if opts != "omitempty" {
+					alwaysEncoded = append(alwaysEncoded, tag)
+				}
+
+				property, ok := s.Properties[tag]
+				if !ok {
+					t.Errorf("field %s (%s) is not in the schema", field.Name, tag)
+					continue
+				}
+				if got, want := schemaType(property), goSchemaType(field.Type); got != want {
+					t.Errorf("property %s type = %s, want %s for %s", tag, got, want, field.Type)
+				}
+			}
+
+			var properties []string
+			for property := range s.Properties {
+				properties = append(properties, property)
+			}
+			slices.Sort(properties)
+			slices.Sort(fields)
+			if diff := cmp.Diff(fields, properties); diff != "" {
+				t.Errorf("properties mismatch (-fields +properties)\n%s", diff)
+			}
+
+			for _, required := range s.Required {
+				if !slices.Contains(fields, required) {
+					t.Errorf("required property %s is not a field", required)
+				}
+			}
+			// Response schemas require exactly the fields that are always
+			// encoded.
+			if s.AdditionalProperties == nil {
+				required := slices.Sorted(slices.Values(s.Required))
+				slices.Sort(alwaysEncoded)
+				if diff := cmp.Diff(alwaysEncoded, required); diff != "" {
+					t.Errorf("required mismatch (-always encoded +required)\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAPI_Enums(t *testing.T) {
+	v, err := newRequestValidator(openAPIDocument)
+	if err != nil {
+		t.Fatalf("newRequestValidator() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		schema   string
+		property string
+		want     []string
+	}{
+		{schema: "ErrorBody", property: "code", want: []string{
+			CodeInvalidRequest, CodeUnauthorized, CodeForbidden, CodeValidationFailed, CodeNotFound,
+			CodeAlreadyExists, CodeConflict, CodeNoMatchingRule, CodeDeliveryFailed, CodeInternal,
+		}},
+		{schema: "Approver", property: "locale", want: api.Locales},
+		{schema: "Approver", property: "notification_preference", want: api.NotificationPreferences},
+		{schema: "WorkflowRule", property: "min_bound", want: []string{string(api.BoundInclusive), string(api.BoundExclusive)}},
+		{schema: "WorkflowRule", property: "max_bound", want: []string{string(api.BoundInclusive), string(api.BoundExclusive)}},
+		{schema: "OutboxMessage", property: "status", want: []string{
+			string(api.OutboxStatusPending), string(api.OutboxStatusDelivered), string(api.OutboxStatusDead),
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.schema+"."+test.property, func(t *testing.T) {
+			var got []string
+			for _, value := range v.schemas[test.schema].Properties[test.property].Enum {
+				got = append(got, value.(string))
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("enum mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOpenAPI_Routes(t *testing.T) {
+	var doc openAPI
+	if err := json.Unmarshal(openAPIDocument, &doc); err != nil {
+		t.Fatalf("invalid OpenAPI document: %v", err)
+	}
+	var operations []string
+	for path, item := range doc.Paths {
+		for method := range item {
+			if method != "parameters" {
+				operations = append(operations, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+
+	srv, err := NewServer(&mockManagementService{}, &mockWorkflowService{}, WithLogger(&mockLogger{}))
+	if err != nil {
+		t.Fatalf("NewServer() unexpected error: %v", err)
+	}
+	var routes []string
+	for _, route := range srv.routes() {
+		routes = append(routes, route.pattern)
+	}
+
+	slices.Sort(operations)
+	slices.Sort(routes)
+	if diff := cmp.Diff(routes, operations); diff != "" {
+		t.Errorf("routes mismatch (-routes +operations)\n%s", diff)
+	}
+}
+
+func TestServer_Handler_OpenAPI(t *testing.T) {
+	srv, err := NewServer(&mockManagementService{}, &mockWorkflowService{}, WithLogger(&mockLogger{}))
+	if err != nil {
+		t.Fatalf("NewServer() unexpected error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	if rec.Body.String() != string(openAPIDocument) {
+		t.Errorf("body is not the OpenAPI document")
+	}
+}
+
+func TestRequestValidator_Validate(t *testing.T) {
+	v, err := newRequestValidator(openAPIDocument)
+	if err != nil {
+		t.Fatalf("newRequestValidator() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		body    string
+		want    []string
+	}{
+		{
+			name:    "valid invoice",
+			pattern: "POST /invoices",
+			body:    `{"amount":{"cents":1500000,"currency":"USD"},"department":"Finance","due_date":"2026-11-01"}`,
+		},
+		{
+			name:    "missing amount",
+			pattern: "POST /invoices",
+			body:    `{"department":"Finance"}`,
+			want:    []string{"amount: is required"},
+		},
+		{
+			name:    "wrong types",
+			pattern: "POST /invoices",
+			body:    `{"amount":{"cents":"15000","currency":1},"is_manager_approval_required":"yes"}`,
+			want: []string{
+				"amount.cents: must be an integer",
+				"amount.currency: must be a string",
+				"is_manager_approval_required: must be a boolean",
+			},
+		},
+		{
+			name:    "fractional cents",
+			pattern: "POST /invoices",
+			body:    `{"amount":{"cents":150.5}}`,
+			want:    []string{"amount.cents: must be an integer"},
+		},
+		{
+			name:    "bad pattern",
+			pattern: "POST /invoices",
+			body:    `{"amount":{"cents":100,"currency":"dollars"},"due_date":"01/11/2026"}`,
+			want: []string{
+				"amount.currency: must match ^[A-Za-z]{3}$",
+				`due_date: must match ^\d{4}-\d{2}-\d{2}$`,
+			},
+		},
+		{
+			name:    "unknown nested field",
+			pattern: "POST /invoices",
+			body:    `{"amount":{"cents":100,"curency":"USD"}}`,
+			want:    []string{"amount.curency: unknown field"},
+		},
+		{
+			name:    "not an object",
+			pattern: "POST /invoices",
+			body:    `[]`,
+			want:    []string{"body: must be an object"},
+		},
+		{
+			name:    "bad enum",
+			pattern: "POST /approvers",
+			body:    `{"name":"Amanda","role":"CFO","email":"amanda@light.com","locale":"fr","notification_preference":"weekly"}`,
+			want: []string{
+				"locale: must be one of: en, sv, de",
+				"notification_preference: must be one of: immediate, hourly_digest, daily_digest",
+			},
+		},
+		{
+			name:    "workflow rule",
+			pattern: "PUT /workflow-rules/{id}",
+			body:    `{"id":1,"min_amount":{"cents":500000,"currency":"USD"},"department":null,"is_manager_approval_required":2,"approver_id":0,"approval_channel":""}`,
+			want: []string{
+				"approval_channel: must not be empty",
+				"approver_id: must be at least 1",
+				"is_manager_approval_required: must be one of: 0, 1",
+			},
+		},
+		{
+			name:    "null not allowed",
+			pattern: "POST /companies",
+			body:    `{"name":null,"departments":["Finance",""]}`,
+			want: []string{
+				"departments[1]: must not be empty",
+				"name: must not be null",
+			},
+		},
+		{
+			name:    "route without body",
+			pattern: "GET /approvers",
+			body:    `not json`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			err := v.Validate(test.pattern, []byte(test.body))
+			var verr *api.ValidationError
+			if errors.As(err, &verr) {
+				for _, field := range verr.Fields {
+					got = append(got, field.Error())
+				}
+			} else if err != nil {
+				t.Fatalf("Validate() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Validate() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+// schemaType returns the type of a schema, or the schema it references.
+func schemaType(s *schema) string {
+	if s.Ref != "" {
+		return s.Ref
+	}
+	if s.Type == "array" && s.Items != nil {
+		return "array of " + schemaType(s.Items)
+	}
+	if s.Format == "date-time" {
+		return s.Type + " " + s.Format
+	}
+	return s.Type
+}
+
+// goSchemaType returns the schema type a Go type is encoded as.
+func goSchemaType(typ reflect.Type) string {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch typ {
+	case reflect.TypeFor[money.Money]():
+		return "#/components/schemas/Money"
+	case reflect.TypeFor[ErrorBody]():
+		return "#/components/schemas/ErrorBody"
+	case reflect.TypeFor[FieldError]():
+		return "#/components/schemas/FieldError"
+	case reflect.TypeFor[api.Approver]():
+		return "#/components/schemas/Approver"
+	case reflect.TypeFor[api.OrgChartNode]():
+		return "#/components/schemas/OrgChartNode"
+	case reflect.TypeFor[time.Time]():
+		return "string date-time"
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int64:
+		return "integer"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice:
+		return "array of " + goSchemaType(typ.Elem())
+	}
+	return typ.String()
+}
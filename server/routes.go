@@ -0,0 +1,382 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/auth"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+)
+
+// Handler returns the HTTP handler of the API:
+//
+//	GET    /health
+//	GET    /openapi.json
+//	POST   /invoices
+//	GET    /companies, POST /companies
+//	GET    /companies/{id}, PUT /companies/{id}, DELETE /companies/{id}
+//	GET    /departments, POST /departments
+//	DELETE /departments/{name}
+//	GET    /workflow-rules, POST /workflow-rules
+//	GET    /workflow-rules/{id}, PUT /workflow-rules/{id}, DELETE /workflow-rules/{id}
+//	GET    /approvers, POST /approvers
+//	GET    /approvers/{id}, PUT /approvers/{id}, DELETE /approvers/{id}
+//	GET    /org-chart?approver_id={id}
+//	GET    /approval-requests/{id}/delivery-attempts
+//	GET    /outbox?status=pending|delivered|dead
+//	POST   /outbox/{id}/replay
+//
+// Requests and responses are JSON encoded api types, described by the
+// OpenAPI document served at /openapi.json. Request bodies are validated
+// against it. Errors are returned as an ErrorResponse.
+//
+// If the server has an authenticator, every route but /health and
+// /openapi.json requires an API key as a bearer token, and is served for
+// the company the key belongs to. Keys without the permission of a route
+// are rejected with 403 Forbidden.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, route := range s.routes() {
+		mux.HandleFunc(route.pattern, s.authorize(route.permission, route.handler))
+	}
+	for path, handler := range s.handlers {
+		mux.Handle(path, handler)
+	}
+	return s.recoverPanics(mux)
+}
+
+// route is an endpoint of the API. pattern is its ServeMux pattern, such
+// as "GET /approvers/{id}", which matches its path in the OpenAPI document.
+// permission is the permission an API key needs to use the route, if the
+// server authenticates requests.
+type route struct {
+	pattern    string
+	permission api.Permission
+	handler    http.HandlerFunc
+}
+
+// routes returns the endpoints of the API.
+func (s *Server) routes() []route {
+	return []route{
+		{"GET /health", "", s.health},
+		{"GET " + OpenAPIPath, "", s.serveOpenAPI},
+		{"POST /invoices", api.PermissionSubmitInvoices, s.submitInvoice},
+
+		{"GET /companies", api.PermissionRead, s.listCompanies},
+		{"POST /companies", api.PermissionManageCompanies, s.createCompany},
+		{"GET /companies/{id}", api.PermissionRead, s.getCompany},
+		{"PUT /companies/{id}", api.PermissionManageCompanies, s.updateCompany},
+		{"DELETE /companies/{id}", api.PermissionManageCompanies, s.deleteCompany},
+
+		{"GET /departments", api.PermissionRead, s.listDepartments},
+		{"POST /departments", api.PermissionManageRules, s.addDepartment},
+		{"DELETE /departments/{name}", api.PermissionManageRules, s.removeDepartment},
+
+		{"GET /workflow-rules", api.PermissionRead, s.listWorkflowRules},
+		{"POST /workflow-rules", api.PermissionManageRules, s.createWorkflowRule},
+		{"GET /workflow-rules/{id}", api.PermissionRead, s.getWorkflowRule},
+		{"PUT /workflow-rules/{id}", api.PermissionManageRules, s.updateWorkflowRule},
+		{"DELETE /workflow-rules/{id}", api.PermissionManageRules, s.deleteWorkflowRule},
+
+		{"GET /approvers", api.PermissionRead, s.listApprovers},
+		{"POST /approvers", api.PermissionManageApprovers, s.createApprover},
+		{"GET /approvers/{id}", api.PermissionRead, s.getApprover},
+		{"PUT /approvers/{id}", api.PermissionManageApprovers, s.updateApprover},
+		{"DELETE /approvers/{id}", api.PermissionManageApprovers, s.deleteApprover},
+		{"GET /org-chart", api.PermissionRead, s.getOrgChart},
+
+		{"GET /approval-requests/{id}/delivery-attempts", api.PermissionRead, s.listDeliveryAttempts},
+		{"GET /outbox", api.PermissionRead, s.listOutboxMessages},
+		{"POST /outbox/{id}/replay", api.PermissionManageNotifications, s.replayDeadLetter},
+	}
+}
+
+// health reports that the server is up.
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// submitInvoice processes an invoice through the approval workflow. A new
+// approval request is reported with 201 Created, and a duplicate of an
+// approval request made within the dedup window with 200 OK.
+func (s *Server) submitInvoice(w http.ResponseWriter, r *http.Request) {
+	var invoice api.InvoiceRequest
+	if !s.decode(w, r, &invoice) {
+		return
+	}
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		if err := principal.CheckSubmitter(invoice); err != nil {
+			s.writeError(w, err)
+			return
+		}
+	}
+
+	resp, err := s.workflowFor(r).ProcessInvoice(invoice)
+	if errors.Is(err, db.ErrWorkflowRuleNotFound) {
+		s.writeError(w, &Error{Status: http.StatusUnprocessableEntity, Code: CodeNoMatchingRule, Message: "no workflow rule matches the invoice", Err: err})
+		return
+	}
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if resp.Duplicate {
+		status = http.StatusOK
+	}
+	s.writeJSON(w, status, resp)
+}
+
+// listCompanies lists the companies. An API key only has access to its own
+// company.
+func (s *Server) listCompanies(w http.ResponseWriter, r *http.Request) {
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		company, err := s.managementFor(r).GetCompanyByID(principal.CompanyID)
+		s.respond(w, http.StatusOK, []api.Company{company}, err)
+		return
+	}
+	companies, err := s.managementFor(r).ListCompanies()
+	s.respond(w, http.StatusOK, orEmpty(companies), err)
+}
+
+// createCompany creates a company. API keys belong to a company, so they
+// cannot create other companies.
+func (s *Server) createCompany(w http.ResponseWriter, r *http.Request) {
+	if _, ok := auth.FromContext(r.Context()); ok {
+		s.writeError(w, &Error{Status: http.StatusForbidden, Code: CodeForbidden, Message: "API keys cannot create companies"})
+		return
+	}
+	var company api.Company
+	if !s.decode(w, r, &company) {
+		return
+	}
+	created, err := s.managementFor(r).CreateCompany(company)
+	s.respond(w, http.StatusCreated, created, err)
+}
+
+func (s *Server) getCompany(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.companyID(w, r)
+	if !ok {
+		return
+	}
+	company, err := s.managementFor(r).GetCompanyByID(id)
+	s.respond(w, http.StatusOK, company, err)
+}
+
+// updateCompany replaces the company and returns it as stored.
+func (s *Server) updateCompany(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.companyID(w, r)
+	if !ok {
+		return
+	}
+	var company api.Company
+	if !s.decode(w, r, &company) {
+		return
+	}
+	company.ID = id
+	if err := s.managementFor(r).UpdateCompany(company); err != nil {
+		s.writeError(w, err)
+		return
+	}
+	updated, err := s.managementFor(r).GetCompanyByID(id)
+	s.respond(w, http.StatusOK, updated, err)
+}
+
+func (s *Server) deleteCompany(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.companyID(w, r)
+	if !ok {
+		return
+	}
+	s.respond(w, http.StatusNoContent, nil, s.managementFor(r).DeleteCompany(id))
+}
+
+// departmentRequest is the body of a request to add a department.
+type departmentRequest struct {
+	Name string `json:"name"`
+}
+
+func (s *Server) listDepartments(w http.ResponseWriter, r *http.Request) {
+	departments, err := s.managementFor(r).ListDepartments()
+	s.respond(w, http.StatusOK, orEmpty(departments), err)
+}
+
+func (s *Server) addDepartment(w http.ResponseWriter, r *http.Request) {
+	var department departmentRequest
+	if !s.decode(w, r, &department) {
+		return
+	}
+	name, err := s.managementFor(r).AddDepartment(department.Name)
+	s.respond(w, http.StatusCreated, departmentRequest{Name: name}, err)
+}
+
+func (s *Server) removeDepartment(w http.ResponseWriter, r *http.Request) {
+	s.respond(w, http.StatusNoContent, nil, s.managementFor(r).RemoveDepartment(r.PathValue("name")))
+}
+
+func (s *Server) listWorkflowRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := s.managementFor(r).ListWorkflowRules()
+	s.respond(w, http.StatusOK, orEmpty(rules), err)
+}
+
+func (s *Server) createWorkflowRule(w http.ResponseWriter, r *http.Request) {
+	var rule api.WorkflowRule
+	if !s.decode(w, r, &rule) {
+		return
+	}
+	created, err := s.managementFor(r).CreateWorkflowRule(rule)
+	s.respond(w, http.StatusCreated, created, err)
+}
+
+func (s *Server) getWorkflowRule(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.pathID(w, r)
+	if !ok {
+		return
+	}
+	rule, err := s.managementFor(r).GetWorkflowRuleByID(id)
+	s.respond(w, http.StatusOK, rule, err)
+}
+
+// updateWorkflowRule replaces the workflow rule and returns it as stored.
+func (s *Server) updateWorkflowRule(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.pathID(w, r)
+	if !ok {
+		return
+	}
+	var rule api.WorkflowRule
+	if !s.decode(w, r, &rule) {
+		return
+	}
+	rule.ID = id
+	if err := s.managementFor(r).UpdateWorkflowRule(rule); err != nil {
+		s.writeError(w, err)
+		return
+	}
+	updated, err := s.managementFor(r).GetWorkflowRuleByID(id)
+	s.respond(w, http.StatusOK, updated, err)
+}
+
+func (s *Server) deleteWorkflowRule(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.pathID(w, r)
+	if !ok {
+		return
+	}
+	s.respond(w, http.StatusNoContent, nil, s.managementFor(r).DeleteWorkflowRule(id))
+}
+
+func (s *Server) listApprovers(w http.ResponseWriter, r *http.Request) {
+	approvers, err := s.managementFor(r).ListApprovers()
+	s.respond(w, http.StatusOK, orEmpty(approvers), err)
+}
+
+func (s *Server) createApprover(w http.ResponseWriter, r *http.Request) {
+	var approver api.Approver
+	if !s.decode(w, r, &approver) {
+		return
+	}
+	created, err := s.managementFor(r).CreateApprover(approver)
+	s.respond(w, http.StatusCreated, created, err)
+}
+
+func (s *Server) getApprover(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.pathID(w, r)
+	if !ok {
+		return
+	}
+	approver, err := s.managementFor(r).GetApproverByID(id)
+	s.respond(w, http.StatusOK, approver, err)
+}
+
+// updateApprover replaces the approver and returns them as stored.
+func (s *Server) updateApprover(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.pathID(w, r)
+	if !ok {
+		return
+	}
+	var approver api.Approver
+	if !s.decode(w, r, &approver) {
+		return
+	}
+	approver.ID = id
+	if err := s.managementFor(r).UpdateApprover(approver); err != nil {
+		s.writeError(w, err)
+		return
+	}
+	updated, err := s.managementFor(r).GetApproverByID(id)
+	s.respond(w, http.StatusOK, updated, err)
+}
+
+func (s *Server) deleteApprover(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.pathID(w, r)
+	if !ok {
+		return
+	}
+	s.respond(w, http.StatusNoContent, nil, s.managementFor(r).DeleteApprover(id))
+}
+
+func (s *Server) listDeliveryAttempts(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.pathID(w, r)
+	if !ok {
+		return
+	}
+	attempts, err := s.managementFor(r).ListDeliveryAttempts(id)
+	s.respond(w, http.StatusOK, orEmpty(attempts), err)
+}
+
+// getOrgChart returns the reporting hierarchy of the approvers, or of the
+// approver of the approver_id query parameter if it is set.
+func (s *Server) getOrgChart(w http.ResponseWriter, r *http.Request) {
+	var id int
+	if value := r.URL.Query().Get("approver_id"); value != "" {
+		var err error
+		if id, err = strconv.Atoi(value); err != nil || id <= 0 {
+			s.writeError(w, &Error{Status: http.StatusBadRequest, Code: CodeInvalidRequest, Message: fmt.Sprintf("invalid approver_id %q: must be a positive integer", value)})
+			return
+		}
+	}
+	chart, err := s.managementFor(r).OrgChart(id)
+	s.respond(w, http.StatusOK, orEmpty(chart), err)
+}
+
+// listOutboxMessages lists the notifications in the outbox with the
+// status of the status query parameter, dead letters by default.
+func (s *Server) listOutboxMessages(w http.ResponseWriter, r *http.Request) {
+	status := api.OutboxStatus(r.URL.Query().Get("status"))
+	if status == "" {
+		status = api.OutboxStatusDead
+	}
+	messages, err := s.managementFor(r).ListOutboxMessages(status)
+	s.respond(w, http.StatusOK, orEmpty(messages), err)
+}
+
+func (s *Server) replayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.pathID(w, r)
+	if !ok {
+		return
+	}
+	message, err := s.managementFor(r).ReplayDeadLetter(id)
+	s.respond(w, http.StatusOK, message, err)
+}
+
+// pathID parses the id path parameter. If it is not a positive integer, it
+// writes a 400 Bad Request and returns false.
+func (s *Server) pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		s.writeError(w, &Error{Status: http.StatusBadRequest, Code: CodeInvalidRequest, Message: fmt.Sprintf("invalid id %q: must be a positive integer", r.PathValue("id"))})
+		return 0, false
+	}
+	return id, true
+}
+
+// orEmpty returns an empty slice for nil, so that lists are encoded as []
+// rather than null.
+func orEmpty[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
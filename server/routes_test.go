@@ -0,0 +1,334 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServer_Handler(t *testing.T) {
+	approver := api.Approver{ID: 3, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com"}
+
+	tests := []struct {
+		name       string
+		management *mockManagementService
+		workflow   *mockWorkflowService
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "health",
+			method:     http.MethodGet,
+			path:       "/health",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"ok"}`,
+		},
+		{
+			name:       "get approver",
+			management: &mockManagementService{approver: approver},
+			method:     http.MethodGet,
+			path:       "/approvers/3",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":3,"name":"Amanda Svensson","role":"CFO","email":"amanda@light.com","slack_id":""}`,
+		},
+		{
+			name:       "approver not found",
+			management: &mockManagementService{err: fmt.Errorf("failed to get approver: %w", db.ErrApproverNotFound)},
+			method:     http.MethodGet,
+			path:       "/approvers/4",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"error":{"code":"not_found","message":"failed to get approver: approver not found"}}`,
+		},
+		{
+			name:       "invalid id",
+			method:     http.MethodGet,
+			path:       "/approvers/abc",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"invalid_request","message":"invalid id \"abc\": must be a positive integer"}}`,
+		},
+		{
+			name:       "empty list",
+			management: &mockManagementService{},
+			method:     http.MethodGet,
+			path:       "/approvers",
+			wantStatus: http.StatusOK,
+			wantBody:   `[]`,
+		},
+		{
+			name:       "org chart of an approver",
+			management: &mockManagementService{approver: approver},
+			method:     http.MethodGet,
+			path:       "/org-chart?approver_id=3",
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"approver":{"id":3,"name":"Amanda Svensson","role":"CFO","email":"amanda@light.com","slack_id":""}}]`,
+		},
+		{
+			name:       "org chart of an invalid approver",
+			method:     http.MethodGet,
+			path:       "/org-chart?approver_id=abc",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"invalid_request","message":"invalid approver_id \"abc\": must be a positive integer"}}`,
+		},
+		{
+			name:       "update approver",
+			management: &mockManagementService{approver: approver},
+			method:     http.MethodPut,
+			path:       "/approvers/3",
+			body:       `{"name":"Amanda Svensson","role":"CFO","email":"amanda@light.com"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":3,"name":"Amanda Svensson","role":"CFO","email":"amanda@light.com","slack_id":""}`,
+		},
+		{
+			name: "create workflow rule fails validation",
+			management: &mockManagementService{err: &api.ValidationError{Fields: []*api.FieldError{
+				{Field: "approver_id", Err: management.ErrUnknownApprover},
+			}}},
+			method:     http.MethodPost,
+			path:       "/workflow-rules",
+			body:       `{"approver_id":9,"approval_channel":"slack"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"validation_failed","message":"validation failed","fields":[{"field":"approver_id","message":"unknown approver"}]}}`,
+		},
+		{
+			name:       "department in use",
+			management: &mockManagementService{err: fmt.Errorf("failed to remove department Finance: %w", management.ErrDepartmentInUse)},
+			method:     http.MethodDelete,
+			path:       "/departments/Finance",
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"error":{"code":"conflict","message":"failed to remove department Finance: department is used by workflow rules"}}`,
+		},
+		{
+			name:       "delete approver",
+			management: &mockManagementService{},
+			method:     http.MethodDelete,
+			path:       "/approvers/3",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "submit invoice",
+			workflow:   &mockWorkflowService{resp: api.ApprovalResponse{ApprovalRequestID: 1, ApproverName: "Amanda Svensson", ApproverRole: "CFO", ApproverChannel: "email", ApproverContactID: "amanda@light.com"}},
+			method:     http.MethodPost,
+			path:       "/invoices",
+			body:       `{"amount":{"cents":1500000,"currency":"USD"},"department":"Finance"}`,
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"approval_request_id":1,"approver_name":"Amanda Svensson","approver_role":"CFO","approver_channel":"email","approver_contact_id":"amanda@light.com"}`,
+		},
+		{
+			name:       "submit duplicate invoice",
+			workflow:   &mockWorkflowService{resp: api.ApprovalResponse{ApprovalRequestID: 1, ApproverName: "Amanda Svensson", ApproverRole: "CFO", ApproverChannel: "email", ApproverContactID: "amanda@light.com", Duplicate: true}},
+			method:     http.MethodPost,
+			path:       "/invoices",
+			body:       `{"amount":{"cents":1500000,"currency":"USD"}}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"approval_request_id":1,"approver_name":"Amanda Svensson","approver_role":"CFO","approver_channel":"email","approver_contact_id":"amanda@light.com","duplicate":true}`,
+		},
+		{
+			name:       "no matching rule",
+			workflow:   &mockWorkflowService{err: fmt.Errorf("failed to find matching rule: %w", db.ErrWorkflowRuleNotFound)},
+			method:     http.MethodPost,
+			path:       "/invoices",
+			body:       `{"amount":{"cents":100,"currency":"USD"}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `{"error":{"code":"no_matching_rule","message":"no workflow rule matches the invoice: failed to find matching rule: workflow rule not found"}}`,
+		},
+		{
+			name:       "unknown field",
+			method:     http.MethodPost,
+			path:       "/invoices",
+			body:       `{"amount":{"cents":100},"ammount":1}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"validation_failed","message":"validation failed","fields":[{"field":"ammount","message":"unknown field"}]}}`,
+		},
+		{
+			name:       "untyped error",
+			workflow:   &mockWorkflowService{err: fmt.Errorf("database is locked")},
+			method:     http.MethodPost,
+			path:       "/invoices",
+			body:       `{"amount":{"cents":100}}`,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"error":{"code":"internal_error","message":"Internal Server Error"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.management == nil {
+				test.management = &mockManagementService{}
+			}
+			if test.workflow == nil {
+				test.workflow = &mockWorkflowService{}
+			}
+			srv, err := NewServer(test.management, test.workflow, WithLogger(&mockLogger{}))
+			if err != nil {
+				t.Fatalf("NewServer() unexpected error: %v", err)
+			}
+
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, test.wantStatus)
+			}
+			if diff := cmp.Diff(test.wantBody, strings.TrimSpace(rec.Body.String())); diff != "" {
+				t.Errorf("body mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_Handler_SubmitInvoice(t *testing.T) {
+	wf := &mockWorkflowService{}
+	srv, err := NewServer(&mockManagementService{}, wf, WithLogger(&mockLogger{}))
+	if err != nil {
+		t.Fatalf("NewServer() unexpected error: %v", err)
+	}
+
+	body := `{"amount":{"cents":1500000,"currency":"USD"},"department":"marketing","vendor":"Northwind","due_date":"2026-11-01"}`
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/invoices", strings.NewReader(body)))
+
+	want := []api.InvoiceRequest{{
+		Amount:     money.New(1500000, money.USD),
+		Department: "marketing",
+		Vendor:     "Northwind",
+		DueDate:    "2026-11-01",
+	}}
+	if diff := cmp.Diff(want, wf.invoices); diff != "" {
+		t.Errorf("ProcessInvoice() invoices mismatch (-want +got)\n%s", diff)
+	}
+}
+
+func TestServer_ListenAndServe(t *testing.T) {
+	released := make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-released
+		w.WriteHeader(http.StatusOK)
+	})
+	srv, err := NewServer(&mockManagementService{}, &mockWorkflowService{}, WithOptions(Options{
+		Logger:   &mockLogger{},
+		Addr:     "127.0.0.1:0",
+		Handlers: map[string]http.Handler{"/slow": slow},
+	}))
+	if err != nil {
+		t.Fatalf("NewServer() unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	addrs := make(chan net.Addr, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.ListenAndServe(ctx, func(addr net.Addr) { addrs <- addr })
+	}()
+	addr := <-addrs
+
+	// A request in flight when the server shuts down is completed.
+	statuses := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr.String() + "/slow")
+		if err != nil {
+			statuses <- 0
+			return
+		}
+		resp.Body.Close()
+		statuses <- resp.StatusCode
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(released)
+
+	if status := <-statuses; status != http.StatusOK {
+		t.Errorf("in-flight request status = %d, want %d", status, http.StatusOK)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("ListenAndServe() unexpected error: %v", err)
+	}
+}
+
+func TestToError_Body(t *testing.T) {
+	_, body := toError(&api.ValidationError{Fields: []*api.FieldError{{Field: "amount", Err: money.ErrInvalidAmount}}})
+	got, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"error":{"code":"validation_failed","message":"validation failed","fields":[{"field":"amount","message":"invalid amount"}]}}`
+	if string(got) != want {
+		t.Errorf("toError() body = %s, want %s", got, want)
+	}
+}
+
+type mockManagementService struct {
+	management.Service
+	company  api.Company
+	approver api.Approver
+	err      error
+}
+
+func (m *mockManagementService) GetCompanyByID(id int) (api.Company, error) {
+	return m.company, m.err
+}
+
+func (m *mockManagementService) GetApproverByID(id int) (api.Approver, error) {
+	return m.approver, m.err
+}
+
+func (m *mockManagementService) UpdateApprover(approver api.Approver) error {
+	return m.err
+}
+
+func (m *mockManagementService) DeleteApprover(id int) error {
+	return m.err
+}
+
+func (m *mockManagementService) ListApprovers() ([]api.Approver, error) {
+	return nil, m.err
+}
+
+func (m *mockManagementService) OrgChart(id int) ([]api.OrgChartNode, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []api.OrgChartNode{{Approver: m.approver}}, nil
+}
+
+func (m *mockManagementService) CreateWorkflowRule(rule api.WorkflowRule) (api.WorkflowRule, error) {
+	return rule, m.err
+}
+
+func (m *mockManagementService) RemoveDepartment(name string) error {
+	return m.err
+}
+
+type mockWorkflowService struct {
+	workflow.Service
+	resp     api.ApprovalResponse
+	err      error
+	invoices []api.InvoiceRequest
+}
+
+func (m *mockWorkflowService) ProcessInvoice(invoice api.InvoiceRequest) (api.ApprovalResponse, error) {
+	m.invoices = append(m.invoices, invoice)
+	return m.resp, m.err
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(msg string, args ...any)  {}
+func (m *mockLogger) Error(msg string, args ...any) {}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/common"
+	"github.com/KatrinSalt/backend-challenge-go/management"
+	"github.com/KatrinSalt/backend-challenge-go/workflow"
+)
+
+const (
+	// defaultAddr is the address the server listens on by default.
+	defaultAddr = ":8080"
+	// defaultShutdownTimeout is how long in-flight requests are given to
+	// complete when the server shuts down.
+	defaultShutdownTimeout = 10 * time.Second
+	// maxBodySize is the maximum accepted size of a request body.
+	maxBodySize = 1 << 20
+)
+
+// Server serves the management operations and invoice submission as a JSON
+// REST API.
+type Server struct {
+	log             common.Logger
+	management      management.Service
+	workflow        workflow.Service
+	addr            string
+	shutdownTimeout time.Duration
+	handlers        map[string]http.Handler
+	validator       *requestValidator
+	authenticator   Authenticator
+	companyServices CompanyServicesFunc
+}
+
+// Options holds the configuration for the server.
+type Options struct {
+	Logger          common.Logger
+	Addr            string
+	ShutdownTimeout time.Duration
+	// Handlers are additional handlers served by path, such as the Slack
+	// interaction and email decision handlers.
+	Handlers map[string]http.Handler
+	// Authenticator, if set, authenticates the API keys requests are
+	// made with, and CompanyServices returns the services of the
+	// company of a key.
+	Authenticator   Authenticator
+	CompanyServices CompanyServicesFunc
+}
+
+// Option is a function that configures the server.
+type Option func(*Server)
+
+// NewServer returns a server for the company of the management and workflow
+// services, listening on :8080 by default. With authentication, requests
+// are served for the company of their API key instead.
+func NewServer(managementSvc management.Service, workflowSvc workflow.Service, options ...Option) (*Server, error) {
+	if managementSvc == nil {
+		return nil, errors.New("management service is required to start the server")
+	}
+	if workflowSvc == nil {
+		return nil, errors.New("workflow service is required to start the server")
+	}
+
+	s := &Server{
+		management:      managementSvc,
+		workflow:        workflowSvc,
+		addr:            defaultAddr,
+		shutdownTimeout: defaultShutdownTimeout,
+		handlers:        map[string]http.Handler{},
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if s.log == nil {
+		s.log = common.NewLogger()
+	}
+	if s.authenticator != nil && s.companyServices == nil {
+		return nil, errors.New("company services are required to authenticate API keys")
+	}
+
+	validator, err := newRequestValidator(openAPIDocument)
+	if err != nil {
+		return nil, err
+	}
+	s.validator = validator
+
+	return s, nil
+}
+
+// ListenAndServe serves the API until the context is cancelled, then shuts
+// the server down gracefully, giving in-flight requests the shutdown
+// timeout to complete. ready, if not nil, is called with the address the
+// server listens on once it accepts connections.
+func (s *Server) ListenAndServe(ctx context.Context, ready func(addr net.Addr)) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(listener)
+	}()
+	s.log.Info("API server listening", "addr", listener.Addr().String())
+	if ready != nil {
+		ready(listener.Addr())
+	}
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.log.Info("API server shutting down", "timeout", s.shutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// WithOptions configures the server with the given Options.
+func WithOptions(options Options) Option {
+	return func(s *Server) {
+		if options.Logger != nil {
+			s.log = options.Logger
+		}
+		if options.Addr != "" {
+			s.addr = options.Addr
+		}
+		if options.ShutdownTimeout > 0 {
+			s.shutdownTimeout = options.ShutdownTimeout
+		}
+		for path, handler := range options.Handlers {
+			s.handlers[path] = handler
+		}
+		if options.Authenticator != nil {
+			s.authenticator = options.Authenticator
+			s.companyServices = options.CompanyServices
+		}
+	}
+}
+
+// WithAuthentication configures the server to require API keys, which are
+// authenticated with the authenticator, and to serve requests with the
+// services of the company of the key.
+func WithAuthentication(authenticator Authenticator, companyServices CompanyServicesFunc) Option {
+	return func(s *Server) {
+		s.authenticator = authenticator
+		s.companyServices = companyServices
+	}
+}
+
+// WithLogger configures the server with the given logger.
+func WithLogger(logger common.Logger) Option {
+	return func(s *Server) {
+		s.log = logger
+	}
+}
@@ -2,13 +2,13 @@ package workflow
 
 import (
 	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
 )
 
-// approvalChannel represents the channel for sending approval requests.
-type approvalChannel string
-
-// approver represents the approver and the channel for sending approval requests.
+// approver represents the approver and the channels for sending approval
+// requests that the approver can be reached on, in the order they are
+// tried.
 type approver struct {
-	approver        api.Approver
-	approvalChannel approvalChannel
+	approver api.Approver
+	channels []notification.Channel
 }
@@ -0,0 +1,175 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+)
+
+const (
+	// dailyDigestHour is the hour of the day, in UTC, daily digests are
+	// sent at.
+	dailyDigestHour = 8
+	// maxDigestSize is the maximum number of approval requests in one
+	// digest. Larger batches are split, which keeps digests within the
+	// message size limits of the channels.
+	maxDigestSize = 20
+)
+
+// errDigestUnsupported is returned when a channel cannot send digests.
+var errDigestUnsupported = errors.New("channel does not support digests")
+
+// nextDigestAt returns when the next digest of the preference is sent
+// after now: at the top of the next hour for hourly digests, and at
+// dailyDigestHour UTC for daily digests.
+func nextDigestAt(preference string, now time.Time) time.Time {
+	now = now.UTC()
+	if preference == api.NotificationHourlyDigest {
+		return now.Truncate(time.Hour).Add(time.Hour)
+	}
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), dailyDigestHour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// digestBatch is a batch of due outbox messages sent to one approver over
+// one channel in a digest, with the approval requests of the messages.
+type digestBatch struct {
+	channel  string
+	messages []db.OutboxMessage
+	requests []api.ApprovalRequest
+}
+
+// digestKey identifies the approver and channel of a digest.
+type digestKey struct {
+	approverID int
+	channel    string
+}
+
+// batchDigests splits due outbox messages into the messages delivered one
+// by one and the batches of digest messages, per approver and channel, of
+// at most maxDigestSize messages. Digest messages that are alone in their
+// batch, or whose payload cannot be decoded, are delivered one by one.
+func batchDigests(messages []db.OutboxMessage) ([]db.OutboxMessage, []digestBatch) {
+	var single []db.OutboxMessage
+	var keys []digestKey
+	batches := make(map[digestKey]*digestBatch)
+
+	for _, message := range messages {
+		if message.Digest == "" {
+			single = append(single, message)
+			continue
+		}
+		var approvalRequest api.ApprovalRequest
+		if err := json.Unmarshal([]byte(message.Payload), &approvalRequest); err != nil {
+			single = append(single, message)
+			continue
+		}
+		approvalRequest.ID = message.ApprovalRequestID
+
+		key := digestKey{approverID: approvalRequest.Approver.ID, channel: message.Channel}
+		batch, ok := batches[key]
+		if !ok {
+			batch = &digestBatch{channel: message.Channel}
+			batches[key] = batch
+			keys = append(keys, key)
+		}
+		batch.messages = append(batch.messages, message)
+		batch.requests = append(batch.requests, approvalRequest)
+	}
+
+	var digests []digestBatch
+	for _, key := range keys {
+		batch := batches[key]
+		if len(batch.messages) == 1 {
+			single = append(single, batch.messages[0])
+			continue
+		}
+		for start := 0; start < len(batch.messages); start += maxDigestSize {
+			end := min(start+maxDigestSize, len(batch.messages))
+			digests = append(digests, digestBatch{
+				channel:  batch.channel,
+				messages: batch.messages[start:end],
+				requests: batch.requests[start:end],
+			})
+		}
+	}
+
+	return single, digests
+}
+
+// deliverDigestBatch sends a batch of outbox messages as one digest and
+// counts the outcome in result. If the channel cannot send digests, or the
+// digest fails, the messages are delivered one by one, with their fallback
+// channels and retries. A digest suppressed by the rate limit of the
+// channel is deferred as a whole. The caller must have claimed the
+// messages.
+func (s *service) deliverDigestBatch(batch digestBatch, result *DispatchResult) {
+	resp, err := s.sendDigest(batch)
+	if err == nil {
+		for i, message := range batch.messages {
+			resp.ApprovalRequestID = batch.requests[i].ID
+			s.completeOutboxMessage(message, batch.channel, resp)
+		}
+		result.Delivered += len(batch.messages)
+		result.Digests++
+		return
+	}
+	if errors.Is(err, notification.ErrRateLimited) {
+		for _, message := range batch.messages {
+			s.deferOutboxMessage(message, err)
+		}
+		result.RateLimited += len(batch.messages)
+		return
+	}
+
+	if !errors.Is(err, errDigestUnsupported) {
+		s.log.Error("approval digest delivery failed, sending the requests one by one",
+			"approver_id", batch.requests[0].Approver.ID,
+			"channel", batch.channel,
+			"approval_requests", len(batch.requests),
+			"error", err,
+		)
+	}
+	for _, message := range batch.messages {
+		_, status, err := s.deliverOutboxMessage(message)
+		result.count(status, err)
+	}
+}
+
+// sendDigest sends the approval requests of a batch as one digest over
+// its channel.
+func (s *service) sendDigest(batch digestBatch) (api.ApprovalResponse, error) {
+	channel, err := s.channels.Lookup(batch.channel)
+	if err != nil {
+		return api.ApprovalResponse{}, fmt.Errorf("%w: %w", ErrUnsupportedApprovalChannel, err)
+	}
+	sender, ok := channel.Sender.(notification.DigestSender)
+	if !ok {
+		return api.ApprovalResponse{}, errDigestUnsupported
+	}
+
+	resp, err := sender.SendDigest(api.Digest{
+		Company:  s.company.name,
+		Approver: batch.requests[0].Approver,
+		Requests: batch.requests,
+	})
+	if err != nil {
+		return api.ApprovalResponse{}, err
+	}
+
+	s.log.Info("approval digest delivered",
+		"approver_id", batch.requests[0].Approver.ID,
+		"channel", batch.channel,
+		"approval_requests", len(batch.requests),
+	)
+	return resp, nil
+}
@@ -0,0 +1,214 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNextDigestAt(t *testing.T) {
+	tests := []struct {
+		name       string
+		preference string
+		now        time.Time
+		want       time.Time
+	}{
+		{
+			name:       "hourly digest at the top of the next hour",
+			preference: api.NotificationHourlyDigest,
+			now:        time.Date(2026, 1, 2, 15, 20, 0, 0, time.UTC),
+			want:       time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "hourly digest on the hour",
+			preference: api.NotificationHourlyDigest,
+			now:        time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC),
+			want:       time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "daily digest later the same day",
+			preference: api.NotificationDailyDigest,
+			now:        time.Date(2026, 1, 2, 6, 30, 0, 0, time.UTC),
+			want:       time.Date(2026, 1, 2, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "daily digest the next day",
+			preference: api.NotificationDailyDigest,
+			now:        time.Date(2026, 1, 2, 8, 0, 0, 0, time.UTC),
+			want:       time.Date(2026, 1, 3, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "daily digest in UTC",
+			preference: api.NotificationDailyDigest,
+			now:        time.Date(2026, 1, 2, 23, 30, 0, 0, time.FixedZone("CET", 3600)),
+			want:       time.Date(2026, 1, 3, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := nextDigestAt(test.preference, test.now)
+			if !got.Equal(test.want) {
+				t.Errorf("nextDigestAt() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestService_DispatchOutbox_Digests(t *testing.T) {
+	start := time.Date(2026, 1, 2, 15, 20, 0, 0, time.UTC)
+	sendErr := errors.New("slack is down")
+
+	tests := []struct {
+		name string
+		// invoices is the number of invoices processed before the digest.
+		invoices int
+		// digests reports whether the channel sends digests.
+		digests     bool
+		digestErr   error
+		wantResult  DispatchResult
+		wantDigests [][]int
+		wantSends   int
+	}{
+		{
+			name:        "batched into one digest",
+			invoices:    3,
+			digests:     true,
+			wantResult:  DispatchResult{Delivered: 3, Digests: 1},
+			wantDigests: [][]int{{1, 2, 3}},
+		},
+		{
+			name:       "a single request is sent on its own",
+			invoices:   1,
+			digests:    true,
+			wantResult: DispatchResult{Delivered: 1},
+			wantSends:  1,
+		},
+		{
+			name:        "split into digests of at most maxDigestSize requests",
+			invoices:    maxDigestSize + 2,
+			digests:     true,
+			wantResult:  DispatchResult{Delivered: maxDigestSize + 2, Digests: 2},
+			wantDigests: [][]int{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}, {21, 22}},
+		},
+		{
+			name:       "sent one by one over channels without digests",
+			invoices:   2,
+			wantResult: DispatchResult{Delivered: 2},
+			wantSends:  2,
+		},
+		{
+			name:       "sent one by one when the digest fails",
+			invoices:   2,
+			digests:    true,
+			digestErr:  sendErr,
+			wantResult: DispatchResult{Delivered: 2},
+			wantSends:  2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := start
+			var sender notification.Sender = &flakySender{}
+			digestSender := &digestSender{err: test.digestErr}
+			if test.digests {
+				sender = digestSender
+			}
+			database := &mockDatabaseService{
+				company:  db.Company{ID: 1, Name: "Test Company"},
+				approver: db.Approver{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", SlackID: "U345678", NotificationPreference: api.NotificationHourlyDigest},
+				rule:     db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack"},
+			}
+			svc := &service{
+				log:      &mockLogger{},
+				company:  company{name: "Test Company"},
+				db:       database,
+				channels: newTestRegistry(t, sender),
+				retry:    RetryPolicy{MaxAttempts: 3, InitialBackoff: 5 * time.Second, MaxBackoff: time.Minute},
+				now:      func() time.Time { return now },
+			}
+
+			wantDigestAt := time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC)
+			for range test.invoices {
+				resp, err := svc.processInvoice(api.InvoiceRequest{
+					CompanyName: "Test Company",
+					Amount:      money.New(1500000, money.USD),
+				})
+				if err != nil {
+					t.Fatalf("processInvoice() unexpected error: %v", err)
+				}
+				if resp.DigestAt == nil || !resp.DigestAt.Equal(wantDigestAt) {
+					t.Fatalf("processInvoice() digest at = %v, want %v", resp.DigestAt, wantDigestAt)
+				}
+			}
+
+			// Nothing is sent before the digest is due.
+			result, err := svc.DispatchOutbox()
+			if err != nil {
+				t.Fatalf("DispatchOutbox() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(DispatchResult{}, result); diff != "" {
+				t.Errorf("DispatchOutbox() before the digest mismatch (-want +got)\n%s", diff)
+			}
+
+			now = wantDigestAt
+			result, err = svc.DispatchOutbox()
+			if err != nil {
+				t.Fatalf("DispatchOutbox() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.wantResult, result); diff != "" {
+				t.Errorf("DispatchOutbox() mismatch (-want +got)\n%s", diff)
+			}
+
+			if test.digestErr == nil {
+				if diff := cmp.Diff(test.wantDigests, digestSender.digests); diff != "" {
+					t.Errorf("sent digests mismatch (-want +got)\n%s", diff)
+				}
+			}
+			sends := digestSender.sent
+			if !test.digests {
+				sends = sender.(*flakySender).sent
+			}
+			if len(sends) != test.wantSends {
+				t.Errorf("sent %d notifications, want %d", len(sends), test.wantSends)
+			}
+			for _, message := range database.outbox {
+				if message.Status != db.OutboxStatusDelivered {
+					t.Errorf("outbox message %d status = %s, want %s", message.ID, message.Status, db.OutboxStatusDelivered)
+				}
+			}
+		})
+	}
+}
+
+// digestSender is a flakySender that also sends digests. It fails digests
+// with err, and records the approval request IDs of the digests it sent.
+type digestSender struct {
+	flakySender
+	err     error
+	digests [][]int
+}
+
+func (s *digestSender) SendDigest(digest api.Digest) (api.ApprovalResponse, error) {
+	if s.err != nil {
+		return api.ApprovalResponse{}, s.err
+	}
+	var ids []int
+	for _, request := range digest.Requests {
+		ids = append(ids, request.ID)
+	}
+	s.digests = append(s.digests, ids)
+	return api.ApprovalResponse{
+		ApproverName:      digest.Approver.Name,
+		ApproverRole:      digest.Approver.Role,
+		ApproverChannel:   "slack",
+		ApproverContactID: digest.Approver.SlackID,
+	}, nil
+}
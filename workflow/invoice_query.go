@@ -1,9 +1,11 @@
 package workflow
 
+import "github.com/KatrinSalt/backend-challenge-go/money"
+
 // invoiceQuery represents the details of an invoice needed to find matching workflow rule.
 type invoiceQuery struct {
 	companyID                 int
-	amount                    float64
+	amount                    money.Money
 	department                string
 	isManagerApprovalRequired bool
 }
@@ -0,0 +1,407 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+)
+
+const (
+	// defaultMaxDeliveryAttempts is the number of delivery attempts made
+	// before a notification is moved to the dead letters.
+	defaultMaxDeliveryAttempts = 5
+	// defaultInitialRetryBackoff is the wait before the first retry of a
+	// notification. It doubles with every retry.
+	defaultInitialRetryBackoff = 5 * time.Second
+	// defaultMaxRetryBackoff caps the wait between retries.
+	defaultMaxRetryBackoff = 5 * time.Minute
+	// defaultPollInterval is how often the dispatcher looks for due
+	// notifications.
+	defaultPollInterval = time.Second
+	// dispatchBatchSize is the maximum number of notifications delivered in
+	// one dispatch pass.
+	dispatchBatchSize = 50
+	// outboxClaimLease is how long a claim on notifications lasts. It
+	// outlasts a dispatch pass, and lets another pass retry the
+	// notifications of a process that stopped while sending them.
+	outboxClaimLease = 15 * time.Minute
+)
+
+// ErrDeadLettered is returned when a notification failed its last delivery
+// attempt and was moved to the dead letters.
+var ErrDeadLettered = errors.New("notification moved to dead letters")
+
+// RetryPolicy configures how failed notifications are retried. The wait
+// before a retry starts at InitialBackoff and doubles with every retry, up
+// to MaxBackoff. A notification that failed MaxAttempts times is moved to
+// the dead letters.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// backoff returns the wait before the next attempt of a notification that
+// failed attempts times.
+func (p RetryPolicy) backoff(attempts int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < attempts && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	return wait
+}
+
+// DispatchResult counts the notifications handled by a dispatch pass.
+// Digests counts the digests sent; their notifications are counted in
+// Delivered. RateLimited counts the notifications suppressed by the rate
+// limit of their channel, which are deferred without using up an attempt.
+type DispatchResult struct {
+	Delivered    int
+	Retried      int
+	DeadLettered int
+	Digests      int
+	RateLimited  int
+}
+
+// count counts a notification with the outcome of its delivery attempt.
+func (r *DispatchResult) count(status string, err error) {
+	switch {
+	case err == nil:
+		r.Delivered++
+	case errors.Is(err, notification.ErrRateLimited):
+		r.RateLimited++
+	case status == db.OutboxStatusDead:
+		r.DeadLettered++
+	default:
+		r.Retried++
+	}
+}
+
+// DispatchOutbox delivers the company's notifications that are due. The
+// due notifications of approvers who receive digests are batched per
+// approver and channel into digests. Failed notifications are rescheduled
+// with backoff, or moved to the dead letters once they used up their
+// attempts.
+//
+// The due notifications are claimed in the database before they are
+// sent, so that concurrent dispatch passes and inline deliveries, in this
+// or another process sharing the database, do not send the same
+// notification twice. Recording the outcome of a delivery attempt
+// releases the claim.
+func (s *service) DispatchOutbox() (DispatchResult, error) {
+	messages, err := s.claimDueOutboxMessages()
+	if err != nil {
+		return DispatchResult{}, err
+	}
+
+	var result DispatchResult
+	single, batches := batchDigests(messages)
+	for _, message := range single {
+		_, status, err := s.deliverOutboxMessage(message)
+		result.count(status, err)
+	}
+	for _, batch := range batches {
+		s.deliverDigestBatch(batch, &result)
+	}
+
+	return result, nil
+}
+
+// claimDueOutboxMessages claims the company's due notifications that are
+// not being delivered already.
+func (s *service) claimDueOutboxMessages() ([]db.OutboxMessage, error) {
+	companyID, err := s.getCompanyID(s.company.name)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	messages, err := s.db.ClaimDueOutboxMessages(companyID, now.Format(time.RFC3339), now.Add(outboxClaimLease).Format(time.RFC3339), dispatchBatchSize)
+	if err != nil {
+		s.log.Error("failed to claim due notifications", "error", err)
+		return nil, err
+	}
+	return messages, nil
+}
+
+// recordApprovalRequest records the pending approval request for the
+// invoice and the matching rule together with its notification, claimed
+// unless it waits for a digest. If the invoice was sent to
+// the approver within the dedup window, nothing is recorded and the earlier
+// request is returned as a duplicate.
+func (s *service) recordApprovalRequest(rule db.WorkflowRule, approverInfo approver, invoice api.InvoiceRequest, approvalRequest api.ApprovalRequest) (db.ApprovalRequest, db.OutboxMessage, bool, error) {
+	s.dispatchMu.Lock()
+	defer s.dispatchMu.Unlock()
+
+	request := toDBApprovalRequest(rule, approverInfo.approver.ID, invoice, s.now())
+	duplicate, ok, err := s.findDuplicate(request)
+	if err != nil {
+		return db.ApprovalRequest{}, db.OutboxMessage{}, false, err
+	}
+	if ok {
+		return duplicate, db.OutboxMessage{}, true, nil
+	}
+
+	request, message, err := s.enqueueApprovalRequest(request, approverInfo, approvalRequest)
+	if err != nil {
+		return db.ApprovalRequest{}, db.OutboxMessage{}, false, err
+	}
+	return request, message, false, nil
+}
+
+// RunDispatcher dispatches due notifications every poll interval until ctx
+// is done.
+func (s *service) RunDispatcher(ctx context.Context) error {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := s.DispatchOutbox(); err != nil {
+				s.log.Error("failed to dispatch notifications", "error", err)
+			}
+		}
+	}
+}
+
+// toDBApprovalRequest returns the pending approval request for the invoice
+// and the matching rule, created at now.
+func toDBApprovalRequest(rule db.WorkflowRule, approverID int, invoiceReq api.InvoiceRequest, now time.Time) db.ApprovalRequest {
+	return db.ApprovalRequest{
+		CompanyID:                 rule.CompanyID,
+		WorkflowRuleID:            rule.ID,
+		ApproverID:                approverID,
+		Amount:                    invoiceReq.Amount.Cents,
+		Currency:                  string(invoiceReq.Amount.Currency),
+		Department:                optional(invoiceReq.Department),
+		Vendor:                    optional(invoiceReq.Vendor),
+		Description:               optional(invoiceReq.Description),
+		DueDate:                   optional(invoiceReq.DueDate),
+		IsManagerApprovalRequired: invoiceReq.IsManagerApprovalRequired,
+		ApprovalChannel:           rule.ApprovalChannel,
+		CreatedAt:                 now.UTC().Format(time.RFC3339),
+		SubmittedBy:               optional(invoiceReq.SubmittedBy),
+	}
+}
+
+// enqueueApprovalRequest records the pending approval request together
+// with the outbox message that sends it to the approver over the channels
+// they can be reached on. The message is due at once, and claimed for its
+// first delivery attempt, or with the next digest if the approver receives
+// digests.
+func (s *service) enqueueApprovalRequest(request db.ApprovalRequest, approverInfo approver, approvalRequest api.ApprovalRequest) (db.ApprovalRequest, db.OutboxMessage, error) {
+	payload, err := json.Marshal(approvalRequest)
+	if err != nil {
+		return db.ApprovalRequest{}, db.OutboxMessage{}, fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	var fallbacks []string
+	for _, channel := range approverInfo.channels[1:] {
+		fallbacks = append(fallbacks, channel.Name)
+	}
+
+	now := request.CreatedAt
+	var digest string
+	nextAttemptAt := now
+	claimedUntil := optional(s.now().Add(outboxClaimLease).UTC().Format(time.RFC3339))
+	if approverInfo.approver.IsDigest() {
+		digest = approverInfo.approver.NotificationPreference
+		nextAttemptAt = nextDigestAt(digest, s.now()).Format(time.RFC3339)
+		claimedUntil = nil
+	}
+
+	request, message, err := s.db.CreateApprovalRequestWithNotification(request, db.OutboxMessage{
+		Channel:          approverInfo.channels[0].Name,
+		FallbackChannels: db.JoinChannels(fallbacks),
+		Digest:           digest,
+		Payload:          string(payload),
+		NextAttemptAt:    nextAttemptAt,
+		CreatedAt:        now,
+		ClaimedUntil:     claimedUntil,
+	})
+	if err != nil {
+		s.log.Error("failed to record approval request", "workflow_rule_id", request.WorkflowRuleID, "error", err)
+		return db.ApprovalRequest{}, db.OutboxMessage{}, err
+	}
+	return request, message, nil
+}
+
+// deliverOutboxMessage makes one delivery attempt of an outbox message and
+// records its outcome. It returns the response of the channel and the new
+// status of the message. The caller must have claimed the message.
+func (s *service) deliverOutboxMessage(message db.OutboxMessage) (api.ApprovalResponse, string, error) {
+	resp, channel, err := s.sendOutboxMessage(message)
+	if errors.Is(err, notification.ErrRateLimited) {
+		s.deferOutboxMessage(message, err)
+		return api.ApprovalResponse{}, db.OutboxStatusPending, err
+	}
+	if err != nil {
+		status := s.failOutboxMessage(message, err)
+		return api.ApprovalResponse{}, status, err
+	}
+	resp.ApprovalRequestID = message.ApprovalRequestID
+	s.completeOutboxMessage(message, channel, resp)
+
+	return resp, db.OutboxStatusDelivered, nil
+}
+
+// completeOutboxMessage records that the approval request of an outbox
+// message was delivered over the channel, and marks the message as
+// delivered. The request has been delivered at this point, so failing to
+// record the delivery is logged rather than returned.
+func (s *service) completeOutboxMessage(message db.OutboxMessage, channel string, resp api.ApprovalResponse) {
+	if err := s.db.SetApprovalRequestDelivery(message.CompanyID, message.ApprovalRequestID, channel, resp.ConversationID, resp.MessageID); err != nil {
+		s.log.Error("failed to record approval request delivery", "approval_request_id", message.ApprovalRequestID, "error", err)
+	}
+
+	deliveredAt := s.now().UTC().Format(time.RFC3339)
+	message.Status = db.OutboxStatusDelivered
+	message.DeliveredAt = &deliveredAt
+	message.LastError = nil
+	if err := s.db.UpdateOutboxMessage(message); err != nil {
+		s.log.Error("failed to mark notification as delivered", "outbox_message_id", message.ID, "error", err)
+	}
+}
+
+// sendOutboxMessage sends the approval request of an outbox message over
+// its channel, and over its fallback channels in order while the delivery
+// fails. It returns the name of the channel the request was delivered over.
+// If every channel fails, the errors of all channels are returned, and the
+// failure is permanent only if it is permanent on every channel. A send
+// suppressed by the rate limit of the primary channel is not a failure, so
+// it is returned without trying the next channel. A fallback channel's rate
+// limit counts as a failure instead, so that the attempts of the channels
+// that failed before it are recorded.
+func (s *service) sendOutboxMessage(message db.OutboxMessage) (api.ApprovalResponse, string, error) {
+	var approvalRequest api.ApprovalRequest
+	if err := json.Unmarshal([]byte(message.Payload), &approvalRequest); err != nil {
+		return api.ApprovalResponse{}, "", &permanentError{err: fmt.Errorf("failed to decode notification: %w", err)}
+	}
+	approvalRequest.ID = message.ApprovalRequestID
+
+	names := append([]string{message.Channel}, db.SplitChannels(message.FallbackChannels)...)
+	var errs []error
+	permanent := true
+	for i, name := range names {
+		resp, err := s.sendOverChannel(name, approvalRequest)
+		if err == nil {
+			if i > 0 {
+				s.log.Info("approval request delivered over fallback channel",
+					"approval_request_id", message.ApprovalRequestID,
+					"channel", name,
+				)
+			}
+			return resp, name, nil
+		}
+
+		if len(names) == 1 || (i == 0 && errors.Is(err, notification.ErrRateLimited)) {
+			return api.ApprovalResponse{}, "", err
+		}
+
+		var perr *permanentError
+		permanent = permanent && errors.As(err, &perr)
+		if errors.Is(err, notification.ErrRateLimited) {
+			// Keep the rate limit out of the error chain, so that the
+			// message is retried rather than deferred.
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+		} else {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+		if i < len(names)-1 {
+			s.log.Error("approval request delivery failed, trying the next channel",
+				"approval_request_id", message.ApprovalRequestID,
+				"channel", name,
+				"next_channel", names[i+1],
+				"error", err,
+			)
+		}
+	}
+
+	err := errors.Join(errs...)
+	if permanent {
+		err = &permanentError{err: err}
+	}
+	return api.ApprovalResponse{}, "", err
+}
+
+// sendOverChannel sends the approval request over the named channel.
+func (s *service) sendOverChannel(name string, approvalRequest api.ApprovalRequest) (api.ApprovalResponse, error) {
+	channel, err := s.channels.Lookup(name)
+	if err != nil {
+		return api.ApprovalResponse{}, &permanentError{err: fmt.Errorf("%w: %w", ErrUnsupportedApprovalChannel, err)}
+	}
+	return channel.Sender.SendApprovalRequest(approvalRequest)
+}
+
+// failOutboxMessage records a failed delivery attempt of an outbox message
+// and returns its new status. The message is rescheduled with backoff, or
+// moved to the dead letters if the failure is permanent or it used up its
+// attempts.
+func (s *service) failOutboxMessage(message db.OutboxMessage, sendErr error) string {
+	lastError := sendErr.Error()
+	message.Attempts++
+	message.LastError = &lastError
+
+	var permanent *permanentError
+	if errors.As(sendErr, &permanent) || message.Attempts >= s.retry.MaxAttempts {
+		message.Status = db.OutboxStatusDead
+		s.log.Error("notification moved to dead letters",
+			"outbox_message_id", message.ID,
+			"approval_request_id", message.ApprovalRequestID,
+			"channel", message.Channel,
+			"attempts", message.Attempts,
+			"error", sendErr,
+		)
+	} else {
+		message.Status = db.OutboxStatusPending
+		message.NextAttemptAt = s.now().Add(s.retry.backoff(message.Attempts)).UTC().Format(time.RFC3339)
+		s.log.Error("notification delivery failed, will retry",
+			"outbox_message_id", message.ID,
+			"approval_request_id", message.ApprovalRequestID,
+			"channel", message.Channel,
+			"attempts", message.Attempts,
+			"next_attempt_at", message.NextAttemptAt,
+			"error", sendErr,
+		)
+	}
+
+	if err := s.db.UpdateOutboxMessage(message); err != nil {
+		s.log.Error("failed to record failed notification delivery", "outbox_message_id", message.ID, "error", err)
+	}
+	return message.Status
+}
+
+// permanentError wraps a delivery error that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+// Error returns the wrapped error's text.
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// optional returns a pointer to the value, or nil if it is empty.
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,395 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: 5 * time.Second, MaxBackoff: time.Minute}
+
+	var got []time.Duration
+	for attempts := 1; attempts <= 6; attempts++ {
+		got = append(got, policy.backoff(attempts))
+	}
+	want := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("backoff() mismatch (-want +got)\n%s", diff)
+	}
+}
+
+func TestService_Outbox(t *testing.T) {
+	start := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	sendErr := errors.New("slack is down")
+
+	tests := []struct {
+		name string
+		// errs are returned by the sender on consecutive sends.
+		errs []error
+		// dispatches are the times, relative to start, of the dispatch passes
+		// after the invoice was processed.
+		dispatches  []time.Duration
+		wantQueued  bool
+		wantErr     error
+		wantResults []DispatchResult
+		wantStatus  string
+		wantSends   int
+	}{
+		{
+			name:       "delivered inline",
+			wantStatus: db.OutboxStatusDelivered,
+			wantSends:  1,
+		},
+		{
+			name:       "retried by the dispatcher after the backoff",
+			errs:       []error{sendErr},
+			dispatches: []time.Duration{4 * time.Second, 5 * time.Second},
+			wantQueued: true,
+			wantResults: []DispatchResult{
+				{},
+				{Delivered: 1},
+			},
+			wantStatus: db.OutboxStatusDelivered,
+			wantSends:  2,
+		},
+		{
+			name:       "dead lettered after the last attempt",
+			errs:       []error{sendErr, sendErr, sendErr},
+			dispatches: []time.Duration{5 * time.Second, 15 * time.Second, time.Hour},
+			wantQueued: true,
+			wantResults: []DispatchResult{
+				{Retried: 1},
+				{DeadLettered: 1},
+				{},
+			},
+			wantStatus: db.OutboxStatusDead,
+			wantSends:  3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := start
+			sender := &flakySender{errs: test.errs}
+			database := &mockDatabaseService{
+				company:  db.Company{ID: 1, Name: "Test Company"},
+				approver: db.Approver{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", SlackID: "U345678"},
+				rule:     db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack"},
+			}
+			svc := &service{
+				log:      &mockLogger{},
+				company:  company{name: "Test Company"},
+				db:       database,
+				channels: newTestRegistry(t, sender),
+				retry:    RetryPolicy{MaxAttempts: 3, InitialBackoff: 5 * time.Second, MaxBackoff: time.Minute},
+				now:      func() time.Time { return now },
+			}
+
+			resp, err := svc.processInvoice(api.InvoiceRequest{
+				CompanyName: "Test Company",
+				Amount:      money.New(1500000, money.USD),
+			})
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("processInvoice() error = %v, want %v", err, test.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("processInvoice() unexpected error: %v", err)
+			}
+			if resp.Queued != test.wantQueued {
+				t.Errorf("processInvoice() queued = %v, want %v", resp.Queued, test.wantQueued)
+			}
+			if resp.ApprovalRequestID != 1 || resp.ApproverContactID != "U345678" {
+				t.Errorf("processInvoice() = %+v, want request 1 sent to U345678", resp)
+			}
+
+			var results []DispatchResult
+			for _, at := range test.dispatches {
+				now = start.Add(at)
+				result, err := svc.DispatchOutbox()
+				if err != nil {
+					t.Fatalf("DispatchOutbox() unexpected error: %v", err)
+				}
+				results = append(results, result)
+			}
+			if diff := cmp.Diff(test.wantResults, results); diff != "" {
+				t.Errorf("DispatchOutbox() mismatch (-want +got)\n%s", diff)
+			}
+
+			if len(database.outbox) != 1 {
+				t.Fatalf("outbox has %d messages, want 1", len(database.outbox))
+			}
+			message := database.outbox[0]
+			if message.Status != test.wantStatus {
+				t.Errorf("outbox message status = %s, want %s", message.Status, test.wantStatus)
+			}
+			if message.Attempts != len(test.errs) {
+				t.Errorf("outbox message attempts = %d, want %d", message.Attempts, len(test.errs))
+			}
+			if len(sender.sent) != test.wantSends {
+				t.Errorf("sent %d notifications, want %d", len(sender.sent), test.wantSends)
+			}
+			for _, sent := range sender.sent {
+				if sent.ID != 1 || sent.Approver.SlackID != "U345678" {
+					t.Errorf("sent %+v, want approval request 1 for U345678", sent)
+				}
+			}
+		})
+	}
+}
+
+func TestService_processInvoice_DeadLetteredInline(t *testing.T) {
+	database := &mockDatabaseService{
+		company:  db.Company{ID: 1, Name: "Test Company"},
+		approver: db.Approver{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", SlackID: "U345678"},
+		rule:     db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack"},
+	}
+	svc := &service{
+		log:      &mockLogger{},
+		company:  company{name: "Test Company"},
+		db:       database,
+		channels: newTestRegistry(t, &mockNotificationService{err: errors.New("invalid_auth")}),
+		retry:    RetryPolicy{MaxAttempts: 1, InitialBackoff: time.Second, MaxBackoff: time.Second},
+		now:      time.Now,
+	}
+
+	_, err := svc.processInvoice(api.InvoiceRequest{CompanyName: "Test Company", Amount: money.New(1500000, money.USD)})
+	if !errors.Is(err, ErrDeadLettered) {
+		t.Errorf("processInvoice() error = %v, want %v", err, ErrDeadLettered)
+	}
+	if len(database.outbox) != 1 || database.outbox[0].Status != db.OutboxStatusDead {
+		t.Errorf("outbox = %+v, want one dead message", database.outbox)
+	}
+}
+
+// flakySender fails with errs on consecutive sends and succeeds once they
+// are used up.
+type flakySender struct {
+	errs []error
+	sent []api.ApprovalRequest
+}
+
+func (s *flakySender) SendApprovalRequest(approvalRequest api.ApprovalRequest) (api.ApprovalResponse, error) {
+	s.sent = append(s.sent, approvalRequest)
+	if len(s.sent) <= len(s.errs) {
+		return api.ApprovalResponse{}, s.errs[len(s.sent)-1]
+	}
+	return api.ApprovalResponse{
+		ApproverName:      approvalRequest.Approver.Name,
+		ApproverRole:      approvalRequest.Approver.Role,
+		ApproverChannel:   "slack",
+		ApproverContactID: approvalRequest.Approver.SlackID,
+	}, nil
+}
+
+func TestService_processInvoice_FallbackChannels(t *testing.T) {
+	sendErr := errors.New("slack is down")
+	approver := db.Approver{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", SlackID: "U345678"}
+
+	tests := []struct {
+		name          string
+		company       db.Company
+		approver      db.Approver
+		rule          db.WorkflowRule
+		slackErrs     []error
+		emailErrs     []error
+		wantErr       error
+		wantQueued    bool
+		wantAttempts  int
+		wantDelivered string
+		wantSlack     int
+		wantEmail     int
+	}{
+		{
+			name:          "delivered over the primary channel",
+			approver:      approver,
+			rule:          db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack", FallbackChannels: "email"},
+			wantDelivered: "slack",
+			wantSlack:     1,
+		},
+		{
+			name:          "falls back to email when slack fails",
+			approver:      approver,
+			rule:          db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack", FallbackChannels: "email"},
+			slackErrs:     []error{sendErr},
+			wantDelivered: "email",
+			wantSlack:     1,
+			wantEmail:     1,
+		},
+		{
+			name:          "company fallback channels apply to rules without their own",
+			company:       db.Company{FallbackChannels: "email"},
+			approver:      approver,
+			rule:          db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack"},
+			slackErrs:     []error{sendErr},
+			wantDelivered: "email",
+			wantSlack:     1,
+			wantEmail:     1,
+		},
+		{
+			name:          "skips channels the approver has no contact on",
+			approver:      db.Approver{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com"},
+			rule:          db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack", FallbackChannels: "email"},
+			wantDelivered: "email",
+			wantEmail:     1,
+		},
+		{
+			name:         "queued for a retry when every channel fails",
+			approver:     approver,
+			rule:         db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack", FallbackChannels: "email"},
+			slackErrs:    []error{sendErr},
+			emailErrs:    []error{errors.New("smtp: connection refused")},
+			wantQueued:   true,
+			wantAttempts: 1,
+			wantSlack:    1,
+			wantEmail:    1,
+		},
+		{
+			name:       "deferred without an attempt when the primary channel is rate limited",
+			approver:   approver,
+			rule:       db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack", FallbackChannels: "email"},
+			slackErrs:  []error{notification.ErrRateLimited},
+			wantQueued: true,
+			wantSlack:  1,
+		},
+		{
+			name:         "retried when a fallback channel is rate limited after the primary failed",
+			approver:     approver,
+			rule:         db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack", FallbackChannels: "email"},
+			slackErrs:    []error{sendErr},
+			emailErrs:    []error{notification.ErrRateLimited},
+			wantQueued:   true,
+			wantAttempts: 1,
+			wantSlack:    1,
+			wantEmail:    1,
+		},
+		{
+			name:     "approver unreachable on every channel",
+			approver: db.Approver{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", TeamsID: "amanda@light.onmicrosoft.com"},
+			rule:     db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack", FallbackChannels: "email"},
+			wantErr:  ErrUnreachableApprover,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dbCompany := test.company
+			dbCompany.ID, dbCompany.Name = 1, "Test Company"
+			database := &mockDatabaseService{company: dbCompany, approver: test.approver, rule: test.rule}
+			slackSender := &flakySender{errs: test.slackErrs}
+			emailSender := &flakySender{errs: test.emailErrs}
+			registry, err := notification.NewRegistry(
+				notification.Channel{Name: "slack", Sender: slackSender, ContactID: func(a api.Approver) string { return a.SlackID }},
+				notification.Channel{Name: "email", Sender: emailSender, ContactID: func(a api.Approver) string { return a.Email }},
+			)
+			if err != nil {
+				t.Fatalf("NewRegistry() unexpected error: %v", err)
+			}
+			svc := &service{
+				log:      &mockLogger{},
+				company:  company{name: "Test Company"},
+				db:       database,
+				channels: registry,
+				retry:    RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Minute},
+				now:      time.Now,
+			}
+
+			resp, err := svc.processInvoice(api.InvoiceRequest{CompanyName: "Test Company", Amount: money.New(1500000, money.USD)})
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("processInvoice() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("processInvoice() unexpected error: %v", err)
+			}
+
+			if resp.Queued != test.wantQueued {
+				t.Errorf("processInvoice() queued = %v, want %v", resp.Queued, test.wantQueued)
+			}
+			if got := database.outbox[0].Attempts; got != test.wantAttempts {
+				t.Errorf("outbox message attempts = %d, want %d", got, test.wantAttempts)
+			}
+			if database.deliveredChannel != test.wantDelivered {
+				t.Errorf("delivered channel = %q, want %q", database.deliveredChannel, test.wantDelivered)
+			}
+			if len(slackSender.sent) != test.wantSlack {
+				t.Errorf("sent %d slack notifications, want %d", len(slackSender.sent), test.wantSlack)
+			}
+			if len(emailSender.sent) != test.wantEmail {
+				t.Errorf("sent %d email notifications, want %d", len(emailSender.sent), test.wantEmail)
+			}
+		})
+	}
+}
+
+// blockingSender blocks every send until release is closed and reports
+// each send on entered.
+type blockingSender struct {
+	entered chan api.ApprovalRequest
+	release chan struct{}
+}
+
+func (s *blockingSender) SendApprovalRequest(approvalRequest api.ApprovalRequest) (api.ApprovalResponse, error) {
+	s.entered <- approvalRequest
+	<-s.release
+	return api.ApprovalResponse{ApproverName: approvalRequest.Approver.Name, ApproverChannel: "slack"}, nil
+}
+
+func TestService_DispatchOutbox_SkipsClaimedMessages(t *testing.T) {
+	database := &mockDatabaseService{
+		company:  db.Company{ID: 1, Name: "Test Company"},
+		approver: db.Approver{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", SlackID: "U345678"},
+		rule:     db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack"},
+	}
+	sender := &blockingSender{entered: make(chan api.ApprovalRequest, 1), release: make(chan struct{})}
+	svc := &service{
+		log:      &mockLogger{},
+		company:  company{name: "Test Company"},
+		db:       database,
+		channels: newTestRegistry(t, sender),
+		retry:    RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Second},
+		now:      time.Now,
+	}
+
+	processed := make(chan error, 1)
+	go func() {
+		_, err := svc.processInvoice(api.InvoiceRequest{CompanyName: "Test Company", Amount: money.New(1500000, money.USD)})
+		processed <- err
+	}()
+	<-sender.entered
+
+	dispatched := make(chan DispatchResult, 1)
+	go func() {
+		result, err := svc.DispatchOutbox()
+		if err != nil {
+			t.Errorf("DispatchOutbox() unexpected error: %v", err)
+		}
+		dispatched <- result
+	}()
+
+	select {
+	case result := <-dispatched:
+		if diff := cmp.Diff(DispatchResult{}, result); diff != "" {
+			t.Errorf("DispatchOutbox() mismatch (-want +got):\n%s", diff)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("DispatchOutbox() blocked on a send in progress")
+	}
+
+	close(sender.release)
+	if err := <-processed; err != nil {
+		t.Errorf("processInvoice() unexpected error: %v", err)
+	}
+	if len(database.outbox) != 1 || database.outbox[0].Status != db.OutboxStatusDelivered {
+		t.Errorf("outbox = %+v, want one delivered message", database.outbox)
+	}
+}
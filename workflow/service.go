@@ -2,58 +2,124 @@ package workflow
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/KatrinSalt/backend-challenge-go/api"
 	"github.com/KatrinSalt/backend-challenge-go/common"
 	"github.com/KatrinSalt/backend-challenge-go/db"
-)
-
-const (
-	slackApprovalChannel approvalChannel = "slack"
-	emailApprovalChannel approvalChannel = "email"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
 )
 
 var (
 	// ErrUnsupportedApprovalChannel is returned when an unsupported approval channel is used.
 	ErrUnsupportedApprovalChannel = errors.New("unsupported approval channel")
+	// ErrNotAssignedApprover is returned when a decision is made by someone
+	// other than the approver the request was sent to.
+	ErrNotAssignedApprover = errors.New("decision was not made by the assigned approver")
+	// ErrUnreachableApprover is returned when the approver has no contact on
+	// any of the channels of the matching workflow rule.
+	ErrUnreachableApprover = errors.New("approver has no contact on the rule's channels")
+	// ErrUnknownDepartment is returned when an invoice's department is not
+	// one of the company's departments.
+	ErrUnknownDepartment = errors.New("unknown department")
+	// ErrNoIndependentApprover is returned when the approver of an invoice
+	// submitted it and has no alternate approver or manager who did not.
+	ErrNoIndependentApprover = errors.New("no approver other than the submitter")
+	// ErrSelfApproval is returned when the submitter of an invoice decides
+	// its approval request.
+	ErrSelfApproval = errors.New("the submitter of an invoice cannot decide its approval request")
 )
 
+// DecisionRefused reports whether RecordDecision refused a decision, as
+// opposed to failing to record it. Recording a refused decision again is
+// refused the same way.
+func DecisionRefused(err error) bool {
+	for _, refusal := range []error{
+		api.ErrMissingApprovalRequestID,
+		api.ErrInvalidApprovalStatus,
+		api.ErrMissingDecidedBy,
+		api.ErrPermissionDenied,
+		db.ErrApprovalRequestNotFound,
+		db.ErrApprovalRequestAlreadyDecided,
+		ErrUnsupportedApprovalChannel,
+		ErrNotAssignedApprover,
+		ErrSelfApproval,
+	} {
+		if errors.Is(err, refusal) {
+			return true
+		}
+	}
+	return false
+}
+
 // database interface for the database operations.
 type databaseService interface {
 	GetCompanyByName(name string) (db.Company, error)
-	GetApproverByID(id int) (db.Approver, error)
-	FindMatchingRule(companyID int, amount float64, department string, requiresManager bool) (db.WorkflowRule, error)
+	ListDepartments(companyID int) ([]db.Department, error)
+	GetApproverByID(companyID, id int) (db.Approver, error)
+	ListApprovers(companyID int) ([]db.Approver, error)
+	FindMatchingRule(companyID int, amount money.Money, department string, requiresManager bool) (db.WorkflowRule, error)
+	GetApprovalRequestByID(companyID, id int) (db.ApprovalRequest, error)
+	FindDuplicateApprovalRequest(request db.ApprovalRequest, since string) (db.ApprovalRequest, error)
+	ListApprovalRequestsByMessageID(companyID int, channel, messageID string) ([]db.ApprovalRequest, error)
+	SetApprovalRequestDelivery(companyID, id int, channel, conversationID, messageID string) error
+	DecideApprovalRequest(companyID, id int, status, decidedBy, decidedAt string) error
+	CreateApprovalRequestWithNotification(request db.ApprovalRequest, message db.OutboxMessage) (db.ApprovalRequest, db.OutboxMessage, error)
+	ClaimDueOutboxMessages(companyID int, now, claimedUntil string, limit int) ([]db.OutboxMessage, error)
+	UpdateOutboxMessage(message db.OutboxMessage) error
 }
 
-type notificationService interface {
-	SendApprovalRequest(approvalRequest api.ApprovalRequest) (api.ApprovalResponse, error)
+// channelRegistry looks up the notification channels approval requests are
+// sent over by name.
+type channelRegistry interface {
+	Lookup(name string) (notification.Channel, error)
 }
 
 // Service interface for the workflow service.
 type Service interface {
 	ValidateCompany() error
-	Run() error
+	Run(submitter string) error
+	ProcessInvoice(invoice api.InvoiceRequest) (api.ApprovalResponse, error)
+	RecordDecision(decision api.ApprovalDecision) (api.ApprovalRequest, error)
+	ApprovalRequestsByMessageID(channel, messageID string) ([]int, error)
+	ApprovalRequestStatus(id int) (api.ApprovalRequestStatus, error)
+	DispatchOutbox() (DispatchResult, error)
+	RunDispatcher(ctx context.Context) error
+	Suppressed() Suppressed
 }
 
 type service struct {
-	log       common.Logger
-	company   company
-	db        databaseService
-	slack     notificationService
-	email     notificationService
-	reader    *bufio.Reader
-	userInput userInput
+	log          common.Logger
+	company      company
+	db           databaseService
+	channels     channelRegistry
+	reader       *bufio.Reader
+	userInput    userInput
+	retry        RetryPolicy
+	pollInterval time.Duration
+	dedupWindow  time.Duration
+	now          func() time.Time
+	// dispatchMu guards suppressed, and serializes recording approval
+	// requests so that duplicates are not recorded at the same time. It is
+	// never held while a notification is sent.
+	dispatchMu sync.Mutex
+	suppressed Suppressed
 }
 
 // userInput contains all the user input fields for invoice processing.
 type userInput struct {
-	amount                    float64
+	amount                    money.Money
 	department                string
+	vendor                    string
+	description               string
+	dueDate                   string
 	isManagerApprovalRequired bool
 }
 
@@ -65,38 +131,45 @@ type company struct {
 
 // Options holds the configuration for the service.
 type Options struct {
-	Logger common.Logger
+	Logger       common.Logger
+	RetryPolicy  RetryPolicy
+	PollInterval time.Duration
+	DedupWindow  time.Duration
 }
 
 // Option is a function that configures the service.
 type Option func(*service)
 
-// NewService returns a new service.
-func NewService(companyName string, companyDepartments []string, db databaseService, slack, email notificationService, options ...Option) (Service, error) {
+// NewService returns a new service. Approval requests are sent over the
+// channels of the registry that the matching workflow rules name. They are
+// recorded in a notification outbox first, and notifications that fail are
+// retried by the dispatcher. An invoice is not sent to the same approver
+// again within the dedup window, 24 hours by default.
+func NewService(companyName string, db databaseService, channels channelRegistry, options ...Option) (Service, error) {
 	if companyName == "" {
 		return nil, errors.New("company name is required to start workflow service")
 	}
-	if len(companyDepartments) == 0 {
-		return nil, errors.New("company allowed departments are required to start workflow service")
-	}
 	if db == nil {
 		return nil, errors.New("database service is required to start workflow service")
 	}
-	if slack == nil {
-		return nil, errors.New("slack notification service is required to start workflow service")
-	}
-	if email == nil {
-		return nil, errors.New("email notification service is required to start workflow service")
+	if channels == nil {
+		return nil, errors.New("notification channel registry is required to start workflow service")
 	}
 	s := &service{
 		company: company{
-			name:        companyName,
-			departments: companyDepartments,
+			name: companyName,
+		},
+		db:       db,
+		channels: channels,
+		reader:   bufio.NewReader(os.Stdin),
+		retry: RetryPolicy{
+			MaxAttempts:    defaultMaxDeliveryAttempts,
+			InitialBackoff: defaultInitialRetryBackoff,
+			MaxBackoff:     defaultMaxRetryBackoff,
 		},
-		db:     db,
-		slack:  slack,
-		email:  email,
-		reader: bufio.NewReader(os.Stdin),
+		pollInterval: defaultPollInterval,
+		dedupWindow:  defaultDedupWindow,
+		now:          time.Now,
 	}
 
 	for _, option := range options {
@@ -105,12 +178,16 @@ func NewService(companyName string, companyDepartments []string, db databaseServ
 	if s.log == nil {
 		s.log = common.NewLogger()
 	}
+	if s.retry.MaxAttempts < 1 {
+		s.retry.MaxAttempts = 1
+	}
 
 	return s, nil
 }
 
-// Run starts the CLI service and handles user interaction.
-func (s *service) Run() error {
+// Run starts the CLI service and handles user interaction. The invoices
+// are submitted by submitter, the email of a user, if set.
+func (s *service) Run(submitter string) error {
 	s.log.Info("Starting CLI service")
 
 	fmt.Println("🧾 Invoice Approval Workflow")
@@ -130,7 +207,7 @@ func (s *service) Run() error {
 		s.displayUserInput()
 
 		// Process the invoice.
-		s.processInvoiceInteractively()
+		s.processInvoiceInteractively(submitter)
 
 		// Ask if user wants to process another invoice.
 		if !s.askToContinue() {
@@ -139,27 +216,45 @@ func (s *service) Run() error {
 		fmt.Println()
 	}
 
+	if suppressed := s.Suppressed(); suppressed != (Suppressed{}) {
+		fmt.Printf("🔕 Suppressed notifications: %d duplicate(s), %d rate limited\n", suppressed.Duplicates, suppressed.RateLimited)
+	}
 	fmt.Println("The Invoice Approval Workflow is completed!")
 	return nil
 }
 
 // processInvoiceInteractively processes the invoice interactively.
-func (s *service) processInvoiceInteractively() {
+func (s *service) processInvoiceInteractively(submitter string) {
 	// Convert to invoice request
 	invoice := s.toInvoiceRequest()
+	invoice.SubmittedBy = submitter
 
 	// Process the invoice
 	fmt.Println("\n🔄 Processing invoice...")
 	resp, err := s.processInvoice(invoice)
 	if err != nil {
 		fmt.Printf("❌ Failed to process invoice: %v\n", err)
+		fmt.Println()
+		return
 	}
 
-	fmt.Println("✅ Invoice processed successfully and sent for approval!")
+	switch {
+	case resp.Duplicate:
+		fmt.Printf("♻️  Invoice already sent for approval as request #%d within the last %s. No new approval request was sent.\n", resp.ApprovalRequestID, s.dedupWindow)
+	case resp.DigestAt != nil:
+		fmt.Printf("📬 Invoice processed. The approval request will be sent in the approver's digest at %s.\n", resp.DigestAt.UTC().Format("2006-01-02 15:04 UTC"))
+	case resp.Queued:
+		fmt.Println("⏳ Invoice processed, but the approval request could not be sent yet. It will be retried.")
+	default:
+		fmt.Println("✅ Invoice processed successfully and sent for approval!")
+	}
 	fmt.Printf("👔 Approver: %s\n", resp.ApproverName)
 	fmt.Printf("👔 Role: %s\n", resp.ApproverRole)
 	fmt.Printf("👔 Channel: %s\n", resp.ApproverChannel)
 	fmt.Printf("👔 Contact ID: %s\n", resp.ApproverContactID)
+	if resp.MessageID != "" {
+		fmt.Printf("📨 Message ID: %s\n", resp.MessageID)
+	}
 	fmt.Println()
 }
 
@@ -179,6 +274,27 @@ func (s *service) getUserInput() error {
 	}
 	s.userInput.department = department
 
+	// Get vendor.
+	vendor, err := s.getText("🏭 Enter vendor", "vendor")
+	if err != nil {
+		return err
+	}
+	s.userInput.vendor = vendor
+
+	// Get description.
+	description, err := s.getText("📝 Enter description", "description")
+	if err != nil {
+		return err
+	}
+	s.userInput.description = description
+
+	// Get due date.
+	dueDate, err := s.getDueDate()
+	if err != nil {
+		return err
+	}
+	s.userInput.dueDate = dueDate
+
 	// Get manager approval requirement.
 	managerApproval, err := s.getManagerApprovalRequired()
 	if err != nil {
@@ -189,15 +305,68 @@ func (s *service) getUserInput() error {
 	return nil
 }
 
+// ProcessInvoice validates an invoice of the service's company and sends
+// it for approval, as the interactive workflow does. The company name may
+// be left empty. The department is matched case-insensitively against the
+// company's departments and normalized to their case. Invalid invoices are
+// rejected with an *api.ValidationError. An invoice is never sent to an
+// approver who submitted it, see routeApprover.
+func (s *service) ProcessInvoice(invoice api.InvoiceRequest) (api.ApprovalResponse, error) {
+	verr := &api.ValidationError{}
+	if err := verr.Merge(invoice.Validate()); err != nil {
+		return api.ApprovalResponse{}, err
+	}
+
+	if invoice.CompanyName == "" {
+		invoice.CompanyName = s.company.name
+	} else if invoice.CompanyName != s.company.name {
+		verr.Add("company_name", fmt.Errorf("%w: invoices can only be submitted for company %s", db.ErrCrossCompanyAccess, s.company.name))
+	}
+
+	if currency, err := money.ParseCurrency(string(invoice.Amount.Currency)); err == nil {
+		invoice.Amount = money.New(invoice.Amount.Cents, currency)
+	}
+
+	invoice.SubmittedBy = strings.TrimSpace(invoice.SubmittedBy)
+
+	if invoice.Department != "" {
+		department, err := s.normalizeDepartment(invoice.Department)
+		if err != nil {
+			verr.Add("department", err)
+		}
+		invoice.Department = department
+	}
+
+	if err := verr.ErrOrNil(); err != nil {
+		return api.ApprovalResponse{}, err
+	}
+	return s.processInvoice(invoice)
+}
+
+// normalizeDepartment returns the company department matching name
+// case-insensitively.
+func (s *service) normalizeDepartment(name string) (string, error) {
+	departments := s.getCompanyDepartments()
+	for _, department := range departments {
+		if strings.EqualFold(department, strings.TrimSpace(name)) {
+			return department, nil
+		}
+	}
+	if len(departments) == 0 {
+		return "", fmt.Errorf("%w: %q (company %s has no departments)", ErrUnknownDepartment, name, s.company.name)
+	}
+	return "", fmt.Errorf("%w: %q (must be one of: %s)", ErrUnknownDepartment, name, strings.Join(departments, ", "))
+}
+
 // processInvoice processes the invoice.
 func (s *service) processInvoice(invoice api.InvoiceRequest) (api.ApprovalResponse, error) {
 	// Verify if the company exists in the system.
-	companyID, err := s.getCompanyID(invoice.CompanyName)
+	company, err := s.getCompany(invoice.CompanyName)
 	if err != nil {
 		return api.ApprovalResponse{}, err
 	}
 
-	invoiceQ := toInvoiceQuery(companyID, invoice)
+	invoiceQ := toInvoiceQuery(company.ID, invoice)
 
 	// Find matching rule given the invoice details.
 	rule, err := s.findMatchingRule(invoiceQ)
@@ -205,20 +374,75 @@ func (s *service) processInvoice(invoice api.InvoiceRequest) (api.ApprovalRespon
 		return api.ApprovalResponse{}, err
 	}
 
-	// Get approver details.
-	approverInfo, err := s.getApproverInfo(rule)
+	// Get the approver who did not submit the invoice, and the channels to
+	// reach them on.
+	approverInfo, err := s.routeApprover(rule, ruleChannels(rule, company), invoice.SubmittedBy)
 	if err != nil {
 		return api.ApprovalResponse{}, err
 	}
 
-	// Send approval request.
-	resp, err := s.sendApprovalRequest(approverInfo, invoice)
+	// Validate the approval request before recording it.
+	approvalRequest := toApprovalRequest(0, company.Name, approverInfo, invoice)
+	if err := approvalRequest.Validate(); err != nil {
+		return api.ApprovalResponse{}, err
+	}
+
+	// Record the approval request together with its notification, unless
+	// it is a duplicate. A notification that is due at once is claimed for
+	// the first delivery attempt below, so the dispatcher does not pick it
+	// up in between.
+	request, message, duplicate, err := s.recordApprovalRequest(rule, approverInfo, invoice, approvalRequest)
 	if err != nil {
 		return api.ApprovalResponse{}, err
 	}
+	if duplicate {
+		return api.ApprovalResponse{
+			ApprovalRequestID: request.ID,
+			ApproverName:      approverInfo.approver.Name,
+			ApproverRole:      approverInfo.approver.Role,
+			ApproverChannel:   approverInfo.channels[0].Name,
+			ApproverContactID: approverInfo.channels[0].ContactID(approverInfo.approver),
+			Duplicate:         true,
+		}, nil
+	}
 
-	return resp, nil
+	// Approvers with a digest preference get the request with the next
+	// digest, sent by the dispatcher.
+	if message.Digest != "" {
+		digestAt, err := time.Parse(time.RFC3339, message.NextAttemptAt)
+		if err != nil {
+			return api.ApprovalResponse{}, fmt.Errorf("invalid digest time %q: %w", message.NextAttemptAt, err)
+		}
+		return api.ApprovalResponse{
+			ApprovalRequestID: request.ID,
+			ApproverName:      approverInfo.approver.Name,
+			ApproverRole:      approverInfo.approver.Role,
+			ApproverChannel:   approverInfo.channels[0].Name,
+			ApproverContactID: approverInfo.channels[0].ContactID(approverInfo.approver),
+			DigestAt:          &digestAt,
+		}, nil
+	}
 
+	// The first delivery attempt is made without holding dispatchMu, so a
+	// slow channel does not hold up other invoices or the dispatcher.
+	resp, status, err := s.deliverOutboxMessage(message)
+	if err != nil {
+		if status == db.OutboxStatusDead {
+			return api.ApprovalResponse{}, fmt.Errorf("%w: %w", ErrDeadLettered, err)
+		}
+		// The request is recorded and its notification stays in the outbox,
+		// so the dispatcher retries it.
+		return api.ApprovalResponse{
+			ApprovalRequestID: request.ID,
+			ApproverName:      approverInfo.approver.Name,
+			ApproverRole:      approverInfo.approver.Role,
+			ApproverChannel:   approverInfo.channels[0].Name,
+			ApproverContactID: approverInfo.channels[0].ContactID(approverInfo.approver),
+			Queued:            true,
+		}, nil
+	}
+
+	return resp, nil
 }
 
 // displayUserInput displays the service's userInput in a formatted way.
@@ -227,8 +451,8 @@ func (s *service) displayUserInput() {
 	fmt.Println("==================")
 
 	// Display amount (show "Not specified" if 0)
-	if s.userInput.amount > 0 {
-		fmt.Printf("💰 Amount: $%.2f\n", s.userInput.amount)
+	if s.userInput.amount.IsPositive() {
+		fmt.Printf("💰 Amount: %s\n", s.userInput.amount)
 	} else {
 		fmt.Printf("💰 Amount: Not specified\n")
 	}
@@ -240,6 +464,11 @@ func (s *service) displayUserInput() {
 		fmt.Printf("🏢 Department: Not specified\n")
 	}
 
+	// Display vendor, description and due date (show "Not specified" if empty)
+	fmt.Printf("🏭 Vendor: %s\n", orNotSpecified(s.userInput.vendor))
+	fmt.Printf("📝 Description: %s\n", orNotSpecified(s.userInput.description))
+	fmt.Printf("📅 Due Date: %s\n", orNotSpecified(s.userInput.dueDate))
+
 	// Display manager approval requirement
 	managerApproval := "No"
 	if s.userInput.isManagerApprovalRequired {
@@ -249,27 +478,28 @@ func (s *service) displayUserInput() {
 }
 
 // getInvoiceAmount prompts the user for invoice amount and validates it.
-func (s *service) getInvoiceAmount() (float64, error) {
-	fmt.Print("💰 Enter invoice amount (USD) or press Enter to skip: $")
+func (s *service) getInvoiceAmount() (money.Money, error) {
+	fmt.Printf("💰 Enter invoice amount (%s) or press Enter to skip: ", money.DefaultCurrency)
 
 	amountStr, err := s.reader.ReadString('\n')
 	if err != nil {
-		return 0, fmt.Errorf("failed to read amount: %v", err)
+		return money.Money{}, fmt.Errorf("failed to read amount: %v", err)
 	}
 	amountStr = strings.TrimSpace(amountStr)
 
 	// Allow empty input (skip)
 	if amountStr == "" {
-		return 0, nil // Return 0 to indicate skipped
+		return money.New(0, money.DefaultCurrency), nil // Return 0 to indicate skipped
 	}
 
-	amount, err := strconv.ParseFloat(amountStr, 64)
+	// Parse the amount exactly into cents.
+	amount, err := money.Parse(amountStr, money.DefaultCurrency)
 	if err != nil {
-		fmt.Printf("❌ Error: invalid amount format. Please enter a valid number or press Enter to skip.\n")
+		fmt.Printf("❌ Error: invalid amount format. Please enter a valid amount with at most 2 decimal places or press Enter to skip.\n")
 		return s.getInvoiceAmount() // Direct recursive call
 	}
 
-	if amount <= 0 {
+	if !amount.IsPositive() {
 		fmt.Printf("❌ Error: amount must be greater than 0. Please try again or press Enter to skip.\n")
 		return s.getInvoiceAmount() // Direct recursive call
 	}
@@ -282,6 +512,11 @@ func (s *service) getDepartment() (string, error) {
 	// Get allowed departments from the workflow service
 	allowedDepartments := s.getCompanyDepartments()
 
+	// Skip the prompt if the company has no departments to choose from
+	if len(allowedDepartments) == 0 {
+		return "", nil
+	}
+
 	// Create a map for case-insensitive comparison
 	allowedDeptMap := make(map[string]string)
 	for _, dept := range allowedDepartments {
@@ -316,6 +551,48 @@ func (s *service) getDepartment() (string, error) {
 	return s.getDepartment() // Direct recursive call
 }
 
+// getText prompts the user for an optional free-text invoice field.
+func (s *service) getText(prompt, field string) (string, error) {
+	fmt.Printf("%s or press Enter to skip: ", prompt)
+
+	value, err := s.reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", field, err)
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// getDueDate prompts the user for the invoice due date and validates it.
+func (s *service) getDueDate() (string, error) {
+	fmt.Printf("📅 Enter due date (YYYY-MM-DD) or press Enter to skip: ")
+
+	dueDate, err := s.reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read due date: %v", err)
+	}
+	dueDate = strings.TrimSpace(dueDate)
+
+	// Allow empty input (skip)
+	if dueDate == "" {
+		return "", nil
+	}
+
+	if _, err := api.ParseDueDate(dueDate); err != nil {
+		fmt.Printf("❌ Error: due date must be a date in the format YYYY-MM-DD. Please try again or press Enter to skip.\n")
+		return s.getDueDate() // Direct recursive call
+	}
+
+	return dueDate, nil
+}
+
+// orNotSpecified returns the value, or "Not specified" if it is empty.
+func orNotSpecified(value string) string {
+	if value == "" {
+		return "Not specified"
+	}
+	return value
+}
+
 // getManagerApprovalRequired prompts the user for manager approval requirement and validates it.
 func (s *service) getManagerApprovalRequired() (bool, error) {
 	fmt.Print("👔 Does this invoice require manager approval? (y/n) or press Enter to skip: ")
@@ -364,10 +641,24 @@ func (s *service) askToContinue() bool {
 	}
 }
 
+// ValidateCompany verifies that the company exists and loads its departments.
 func (s *service) ValidateCompany() error {
-	if _, err := s.getCompanyID(s.company.name); err != nil {
+	companyID, err := s.getCompanyID(s.company.name)
+	if err != nil {
 		return err
 	}
+
+	departments, err := s.db.ListDepartments(companyID)
+	if err != nil {
+		s.log.Error("failed to load company departments", "company_name", s.company.name, "error", err)
+		return err
+	}
+
+	s.company.departments = make([]string, len(departments))
+	for i, department := range departments {
+		s.company.departments[i] = department.Name
+	}
+
 	return nil
 }
 
@@ -377,6 +668,9 @@ func (s *service) toInvoiceRequest() api.InvoiceRequest {
 		CompanyName:               s.company.name,
 		Amount:                    s.userInput.amount,
 		Department:                s.userInput.department,
+		Vendor:                    s.userInput.vendor,
+		Description:               s.userInput.description,
+		DueDate:                   s.userInput.dueDate,
 		IsManagerApprovalRequired: s.userInput.isManagerApprovalRequired,
 	}
 }
@@ -388,14 +682,23 @@ func (s *service) getCompanyDepartments() []string {
 
 // getCompanyID returns the company ID.
 func (s *service) getCompanyID(companyName string) (int, error) {
-	company, err := s.db.GetCompanyByName(companyName)
+	company, err := s.getCompany(companyName)
 	if err != nil {
-		s.log.Error("failed to find company in the system", "company_name", companyName, "error", err)
 		return 0, err
 	}
 	return company.ID, nil
 }
 
+// getCompany returns the company.
+func (s *service) getCompany(companyName string) (db.Company, error) {
+	company, err := s.db.GetCompanyByName(companyName)
+	if err != nil {
+		s.log.Error("failed to find company in the system", "company_name", companyName, "error", err)
+		return db.Company{}, err
+	}
+	return company, nil
+}
+
 // findMatchingRule finds the matching rule given the invoice details.
 func (s *service) findMatchingRule(q invoiceQuery) (db.WorkflowRule, error) {
 	// Find matching rule given the invoice details.
@@ -407,75 +710,354 @@ func (s *service) findMatchingRule(q invoiceQuery) (db.WorkflowRule, error) {
 	return rule, nil
 }
 
-// getApproverInfo returns the approver information.
-func (s *service) getApproverInfo(rule db.WorkflowRule) (approver, error) {
-	a, err := s.db.GetApproverByID(rule.ApproverID)
+// routeApprover returns the approver the rule routes the submitter's
+// invoice to and the channels to reach them on. If that approver submitted
+// the invoice, it is routed to their alternate approver instead, or to their
+// manager if they have no alternate, so that nobody decides their own
+// invoice. Approvers are matched to the submitter by email, which is unique
+// within the company.
+func (s *service) routeApprover(rule db.WorkflowRule, channelNames []string, submitter string) (approver, error) {
+	a, err := s.ruleApprover(rule, submitter)
 	if err != nil {
-		s.log.Error("failed to find approver in the system", "approver_id", rule.ApproverID, "error", err)
 		return approver{}, err
 	}
 
-	// Determine notification channel.
-	var channel approvalChannel
+	if isSubmitter(a.Email, submitter) {
+		if a, err = s.independentApprover(rule, a, submitter); err != nil {
+			return approver{}, err
+		}
+	}
 
-	switch rule.ApprovalChannel {
-	case 0: // Slack
-		channel = slackApprovalChannel
-	case 1: // Email
-		channel = emailApprovalChannel
-	default:
-		// Unsupported approval channel.
-		channel = ""
+	return s.getApproverInfo(rule, a, channelNames)
+}
+
+// independentApprover returns the alternate approver, or else the manager,
+// of an approver who submitted the invoice. Replacements who submitted the
+// invoice themselves are skipped.
+func (s *service) independentApprover(rule db.WorkflowRule, a db.Approver, submitter string) (db.Approver, error) {
+	for _, routeTo := range []*int{a.AlternateApproverID, a.ManagerID} {
+		if routeTo == nil {
+			continue
+		}
+		replacement, err := s.db.GetApproverByID(rule.CompanyID, *routeTo)
+		if err != nil {
+			s.log.Error("failed to find approver in the system", "approver_id", *routeTo, "error", err)
+			return db.Approver{}, err
+		}
+		if isSubmitter(replacement.Email, submitter) {
+			continue
+		}
+		s.log.Info("Routing invoice away from the approver who submitted it",
+			"workflow_rule_id", rule.ID,
+			"approver_id", a.ID,
+			"routed_to_approver_id", replacement.ID,
+		)
+		return replacement, nil
 	}
 
-	return approver{
-		approver:        toAPIApprover(a),
-		approvalChannel: channel,
-	}, nil
+	s.log.Error("approver submitted the invoice and has no alternate approver or manager who did not", "workflow_rule_id", rule.ID, "approver_id", a.ID)
+	return db.Approver{}, fmt.Errorf("%w: %s submitted the invoice and has no alternate approver or manager who did not", ErrNoIndependentApprover, a.Name)
 }
 
-// sendApprovalRequest sends the approval request.
-func (s *service) sendApprovalRequest(approver approver, invoiceReq api.InvoiceRequest) (api.ApprovalResponse, error) {
-	approvalRequest := toApprovalRequest(approver, invoiceReq)
+// ruleApprover returns the approver the rule routes the submitter's invoice
+// to. Rules with manager levels route to the submitter's manager that many
+// levels up the reporting hierarchy, and to the rule's approver if there is
+// no such manager.
+func (s *service) ruleApprover(rule db.WorkflowRule, submitter string) (db.Approver, error) {
+	if rule.ManagerLevels > 0 && submitter != "" {
+		manager, ok, err := s.submitterManager(rule.CompanyID, submitter, rule.ManagerLevels)
+		if err != nil {
+			return db.Approver{}, err
+		}
+		if ok {
+			s.log.Info("Routing invoice to the manager of its submitter",
+				"workflow_rule_id", rule.ID,
+				"manager_levels", rule.ManagerLevels,
+				"approver_id", manager.ID,
+			)
+			return manager, nil
+		}
+		s.log.Info("Submitter has no manager that many levels up, routing invoice to the rule's approver",
+			"workflow_rule_id", rule.ID,
+			"manager_levels", rule.ManagerLevels,
+			"approver_id", rule.ApproverID,
+		)
+	}
 
-	// Validate the approval request.
-	if err := approvalRequest.Validate(); err != nil {
-		return api.ApprovalResponse{}, err
+	a, err := s.db.GetApproverByID(rule.CompanyID, rule.ApproverID)
+	if err != nil {
+		s.log.Error("failed to find approver in the system", "approver_id", rule.ApproverID, "error", err)
+		return db.Approver{}, err
 	}
+	return a, nil
+}
 
-	// Send approval request.
-	switch approver.approvalChannel {
-	case slackApprovalChannel:
-		return s.slack.SendApprovalRequest(approvalRequest)
-	case emailApprovalChannel:
-		return s.email.SendApprovalRequest(approvalRequest)
-	default:
-		return api.ApprovalResponse{}, ErrUnsupportedApprovalChannel
+// submitterManager returns the manager the given number of levels up the
+// reporting hierarchy from the approver with the submitter's email. It
+// reports false if the submitter is not an approver or has no manager that
+// far up.
+func (s *service) submitterManager(companyID int, submitter string, levels int) (db.Approver, bool, error) {
+	approvers, err := s.db.ListApprovers(companyID)
+	if err != nil {
+		s.log.Error("failed to list approvers", "company_id", companyID, "error", err)
+		return db.Approver{}, false, err
+	}
+
+	byID := make(map[int]db.Approver, len(approvers))
+	var current db.Approver
+	found := false
+	for _, a := range approvers {
+		byID[a.ID] = a
+		if isSubmitter(a.Email, submitter) {
+			current, found = a, true
+		}
+	}
+	if !found {
+		return db.Approver{}, false, nil
+	}
+
+	for range levels {
+		if current.ManagerID == nil {
+			return db.Approver{}, false, nil
+		}
+		manager, ok := byID[*current.ManagerID]
+		if !ok {
+			return db.Approver{}, false, nil
+		}
+		current = manager
+	}
+	return current, true, nil
+}
+
+// isSubmitter reports whether the email is the submitter's, ignoring case.
+func isSubmitter(email, submitter string) bool {
+	return submitter != "" && strings.EqualFold(strings.TrimSpace(email), submitter)
+}
+
+// getApproverInfo returns the approver information and the channels, out
+// of the rule's channels, that the approver has a contact on.
+func (s *service) getApproverInfo(rule db.WorkflowRule, a db.Approver, channelNames []string) (approver, error) {
+	info := approver{approver: toAPIApprover(a)}
+
+	for _, name := range channelNames {
+		channel, err := s.channels.Lookup(name)
+		if err != nil {
+			s.log.Error("workflow rule uses an unsupported approval channel", "workflow_rule_id", rule.ID, "approval_channel", name, "error", err)
+			return approver{}, fmt.Errorf("%w: %w", ErrUnsupportedApprovalChannel, err)
+		}
+		if channel.ContactID(info.approver) != "" {
+			info.channels = append(info.channels, channel)
+		}
+	}
+
+	if len(info.channels) == 0 {
+		s.log.Error("approver cannot be reached on the workflow rule's channels", "workflow_rule_id", rule.ID, "approver_id", a.ID, "channels", strings.Join(channelNames, ","))
+		return approver{}, fmt.Errorf("%w: %s has no contact on %s", ErrUnreachableApprover, a.Name, strings.Join(channelNames, ", "))
+	}
+
+	return info, nil
+}
+
+// ruleChannels returns the channels approval requests of the rule are sent
+// over, in the order they are tried: the approval channel, then the rule's
+// fallback channels or, if it has none, the company's.
+func ruleChannels(rule db.WorkflowRule, company db.Company) []string {
+	fallbacks := db.SplitChannels(rule.FallbackChannels)
+	if len(fallbacks) == 0 {
+		fallbacks = db.SplitChannels(company.FallbackChannels)
+	}
+	return api.ChannelChain(rule.ApprovalChannel, fallbacks)
+}
+
+// RecordDecision records an approver's decision on a pending approval
+// request of the service's company. The decision must be made by the
+// approver the request was sent to, and not by the submitter of the
+// invoice. It returns the decided request.
+func (s *service) RecordDecision(decision api.ApprovalDecision) (api.ApprovalRequest, error) {
+	if err := decision.Validate(); err != nil {
+		return api.ApprovalRequest{}, err
+	}
+
+	companyID, err := s.getCompanyID(s.company.name)
+	if err != nil {
+		return api.ApprovalRequest{}, err
+	}
+
+	request, err := s.db.GetApprovalRequestByID(companyID, decision.ApprovalRequestID)
+	if err != nil {
+		s.log.Error("failed to find approval request", "approval_request_id", decision.ApprovalRequestID, "error", err)
+		return api.ApprovalRequest{}, err
+	}
+
+	a, err := s.db.GetApproverByID(companyID, request.ApproverID)
+	if err != nil {
+		s.log.Error("failed to find approver in the system", "approver_id", request.ApproverID, "error", err)
+		return api.ApprovalRequest{}, err
+	}
+
+	channel, err := s.channels.Lookup(decision.Channel)
+	if err != nil {
+		s.log.Error("decision made over an unsupported approval channel", "approval_request_id", request.ID, "channel", decision.Channel, "error", err)
+		return api.ApprovalRequest{}, fmt.Errorf("%w: %w", ErrUnsupportedApprovalChannel, err)
+	}
+
+	if !strings.EqualFold(channel.ContactID(toAPIApprover(a)), decision.DecidedBy) {
+		s.log.Error("rejected decision from unassigned approver",
+			"approval_request_id", request.ID,
+			"channel", decision.Channel,
+			"decided_by", decision.DecidedBy,
+		)
+		return api.ApprovalRequest{}, ErrNotAssignedApprover
+	}
+
+	if request.SubmittedBy != nil && (isSubmitter(a.Email, *request.SubmittedBy) || isSubmitter(decision.User, *request.SubmittedBy)) {
+		s.log.Error("rejected decision from the submitter of the invoice",
+			"approval_request_id", request.ID,
+			"channel", decision.Channel,
+			"decided_by", decision.DecidedBy,
+		)
+		return api.ApprovalRequest{}, ErrSelfApproval
+	}
+
+	decidedAt := decision.DecidedAt
+	if decidedAt.IsZero() {
+		decidedAt = s.now()
+	}
+	if err := s.db.DecideApprovalRequest(companyID, request.ID, string(decision.Status), decision.DecidedBy, decidedAt.UTC().Format(time.RFC3339)); err != nil {
+		s.log.Error("failed to record decision", "approval_request_id", request.ID, "error", err)
+		return api.ApprovalRequest{}, err
 	}
+
+	s.log.Info("Recorded approval decision",
+		"approval_request_id", request.ID,
+		"status", decision.Status,
+		"decided_by", decision.DecidedBy,
+	)
+
+	return toAPIApprovalRequest(request, s.company.name, a), nil
+}
+
+// ApprovalRequestsByMessageID returns the IDs of the approval requests of
+// the service's company delivered over the channel in the message with the
+// ID, so that replies to the message can be matched to them.
+func (s *service) ApprovalRequestsByMessageID(channel, messageID string) ([]int, error) {
+	companyID, err := s.getCompanyID(s.company.name)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := s.db.ListApprovalRequestsByMessageID(companyID, channel, messageID)
+	if err != nil {
+		s.log.Error("failed to find approval requests by message", "channel", channel, "message_id", messageID, "error", err)
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(requests))
+	for _, request := range requests {
+		ids = append(ids, request.ID)
+	}
+	return ids, nil
+}
+
+// ApprovalRequestStatus returns the status of the approval request of the
+// service's company with the ID.
+func (s *service) ApprovalRequestStatus(id int) (api.ApprovalRequestStatus, error) {
+	companyID, err := s.getCompanyID(s.company.name)
+	if err != nil {
+		return api.ApprovalRequestStatus{}, err
+	}
+
+	request, err := s.db.GetApprovalRequestByID(companyID, id)
+	if err != nil {
+		return api.ApprovalRequestStatus{}, err
+	}
+
+	status := api.ApprovalRequestStatus{
+		ApprovalRequestID: request.ID,
+		Status:            api.ApprovalStatus(request.Status),
+	}
+	if request.DeliveredChannel != nil {
+		status.DeliveredChannel = *request.DeliveredChannel
+	}
+	if request.DecidedBy != nil {
+		status.DecidedBy = *request.DecidedBy
+	}
+	if request.DecidedAt != nil {
+		decidedAt, err := time.Parse(time.RFC3339, *request.DecidedAt)
+		if err != nil {
+			return api.ApprovalRequestStatus{}, fmt.Errorf("invalid decided_at of approval request %d: %w", request.ID, err)
+		}
+		status.DecidedAt = &decidedAt
+	}
+	return status, nil
 }
 
 // toAPIApprover converts the database approver to an API approver.
 func toAPIApprover(a db.Approver) api.Approver {
 	return api.Approver{
-		Name:    a.Name,
-		Role:    a.Role,
-		Email:   a.Email,
-		SlackID: a.SlackID,
+		ID:                     a.ID,
+		CompanyID:              a.CompanyID,
+		Name:                   a.Name,
+		Role:                   a.Role,
+		Email:                  a.Email,
+		SlackID:                a.SlackID,
+		TeamsID:                a.TeamsID,
+		Locale:                 a.Locale,
+		NotificationPreference: a.NotificationPreference,
 	}
 }
 
 // toApprovalRequest converts the approver and invoice request to an approval request.
-func toApprovalRequest(approver approver, invoiceReq api.InvoiceRequest) api.ApprovalRequest {
+func toApprovalRequest(id int, company string, approver approver, invoiceReq api.InvoiceRequest) api.ApprovalRequest {
 	invoice := api.InvoiceDetails{
-		Amount: invoiceReq.Amount,
+		Amount:                    invoiceReq.Amount,
+		Department:                invoiceReq.Department,
+		Vendor:                    invoiceReq.Vendor,
+		Description:               invoiceReq.Description,
+		DueDate:                   invoiceReq.DueDate,
+		IsManagerApprovalRequired: invoiceReq.IsManagerApprovalRequired,
+		SubmittedBy:               invoiceReq.SubmittedBy,
 	}
 
 	return api.ApprovalRequest{
+		ID:       id,
+		Company:  company,
 		Approver: approver.approver,
 		Invoice:  invoice,
 	}
 }
 
+// toAPIApprovalRequest converts a recorded approval request and its approver
+// to an API approval request.
+func toAPIApprovalRequest(request db.ApprovalRequest, company string, a db.Approver) api.ApprovalRequest {
+	invoice := api.InvoiceDetails{
+		Amount:                    money.New(request.Amount, money.Currency(request.Currency)),
+		IsManagerApprovalRequired: request.IsManagerApprovalRequired,
+	}
+	if request.Department != nil {
+		invoice.Department = *request.Department
+	}
+	if request.Vendor != nil {
+		invoice.Vendor = *request.Vendor
+	}
+	if request.Description != nil {
+		invoice.Description = *request.Description
+	}
+	if request.DueDate != nil {
+		invoice.DueDate = *request.DueDate
+	}
+	if request.SubmittedBy != nil {
+		invoice.SubmittedBy = *request.SubmittedBy
+	}
+
+	return api.ApprovalRequest{
+		ID:       request.ID,
+		Company:  company,
+		Approver: toAPIApprover(a),
+		Invoice:  invoice,
+	}
+}
+
 // toInvoiceQuery converts the invoice request to an invoice query.
 func toInvoiceQuery(id int, invoiceReq api.InvoiceRequest) invoiceQuery {
 	return invoiceQuery{
@@ -492,6 +1074,21 @@ func WithOptions(options Options) Option {
 		if options.Logger != nil {
 			s.log = options.Logger
 		}
+		if options.RetryPolicy.MaxAttempts > 0 {
+			s.retry.MaxAttempts = options.RetryPolicy.MaxAttempts
+		}
+		if options.RetryPolicy.InitialBackoff > 0 {
+			s.retry.InitialBackoff = options.RetryPolicy.InitialBackoff
+		}
+		if options.RetryPolicy.MaxBackoff > 0 {
+			s.retry.MaxBackoff = options.RetryPolicy.MaxBackoff
+		}
+		if options.PollInterval > 0 {
+			s.pollInterval = options.PollInterval
+		}
+		if options.DedupWindow > 0 {
+			s.dedupWindow = options.DedupWindow
+		}
 	}
 }
 
@@ -501,3 +1098,26 @@ func WithLogger(logger common.Logger) Option {
 		s.log = logger
 	}
 }
+
+// WithRetryPolicy configures how failed notifications are retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(s *service) {
+		s.retry = policy
+	}
+}
+
+// WithPollInterval configures how often the dispatcher looks for due
+// notifications.
+func WithPollInterval(interval time.Duration) Option {
+	return func(s *service) {
+		s.pollInterval = interval
+	}
+}
+
+// WithDedupWindow configures how long an invoice is not sent to the same
+// approver again. A window of zero turns deduplication off.
+func WithDedupWindow(window time.Duration) Option {
+	return func(s *service) {
+		s.dedupWindow = window
+	}
+}
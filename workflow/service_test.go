@@ -2,175 +2,102 @@ package workflow
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/KatrinSalt/backend-challenge-go/api"
 	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestNewService(t *testing.T) {
 	var tests = []struct {
 		name  string
 		input struct {
-			companyName        string
-			companyDepartments []string
-			db                 databaseService
-			slack              notificationService
-			email              notificationService
-			options            []Option
+			companyName string
+			db          databaseService
+			channels    channelRegistry
+			options     []Option
 		}
 		wantErr bool
 	}{
 		{
 			name: "valid service creation",
 			input: struct {
-				companyName        string
-				companyDepartments []string
-				db                 databaseService
-				slack              notificationService
-				email              notificationService
-				options            []Option
+				companyName string
+				db          databaseService
+				channels    channelRegistry
+				options     []Option
 			}{
-				companyName:        "Test Company",
-				companyDepartments: []string{"Engineering", "Sales"},
-				db:                 &mockDatabaseService{},
-				slack:              &mockNotificationService{},
-				email:              &mockNotificationService{},
-				options:            []Option{},
+				companyName: "Test Company",
+				db:          &mockDatabaseService{},
+				channels:    newTestRegistry(t, &mockNotificationService{}),
+				options:     []Option{},
 			},
 			wantErr: false,
 		},
 		{
 			name: "empty company name",
 			input: struct {
-				companyName        string
-				companyDepartments []string
-				db                 databaseService
-				slack              notificationService
-				email              notificationService
-				options            []Option
+				companyName string
+				db          databaseService
+				channels    channelRegistry
+				options     []Option
 			}{
-				companyName:        "",
-				companyDepartments: []string{"Engineering"},
-				db:                 &mockDatabaseService{},
-				slack:              &mockNotificationService{},
-				email:              &mockNotificationService{},
-				options:            []Option{},
-			},
-			wantErr: true,
-		},
-		{
-			name: "empty departments",
-			input: struct {
-				companyName        string
-				companyDepartments []string
-				db                 databaseService
-				slack              notificationService
-				email              notificationService
-				options            []Option
-			}{
-				companyName:        "Test Company",
-				companyDepartments: []string{},
-				db:                 &mockDatabaseService{},
-				slack:              &mockNotificationService{},
-				email:              &mockNotificationService{},
-				options:            []Option{},
-			},
-			wantErr: true,
-		},
-		{
-			name: "nil departments",
-			input: struct {
-				companyName        string
-				companyDepartments []string
-				db                 databaseService
-				slack              notificationService
-				email              notificationService
-				options            []Option
-			}{
-				companyName:        "Test Company",
-				companyDepartments: nil,
-				db:                 &mockDatabaseService{},
-				slack:              &mockNotificationService{},
-				email:              &mockNotificationService{},
-				options:            []Option{},
+				companyName: "",
+				db:          &mockDatabaseService{},
+				channels:    newTestRegistry(t, &mockNotificationService{}),
+				options:     []Option{},
 			},
 			wantErr: true,
 		},
 		{
 			name: "nil database service",
 			input: struct {
-				companyName        string
-				companyDepartments []string
-				db                 databaseService
-				slack              notificationService
-				email              notificationService
-				options            []Option
-			}{
-				companyName:        "Test Company",
-				companyDepartments: []string{"Engineering"},
-				db:                 nil,
-				slack:              &mockNotificationService{},
-				email:              &mockNotificationService{},
-				options:            []Option{},
-			},
-			wantErr: true,
-		},
-		{
-			name: "nil slack service",
-			input: struct {
-				companyName        string
-				companyDepartments []string
-				db                 databaseService
-				slack              notificationService
-				email              notificationService
-				options            []Option
+				companyName string
+				db          databaseService
+				channels    channelRegistry
+				options     []Option
 			}{
-				companyName:        "Test Company",
-				companyDepartments: []string{"Engineering"},
-				db:                 &mockDatabaseService{},
-				slack:              nil,
-				email:              &mockNotificationService{},
-				options:            []Option{},
+				companyName: "Test Company",
+				db:          nil,
+				channels:    newTestRegistry(t, &mockNotificationService{}),
+				options:     []Option{},
 			},
 			wantErr: true,
 		},
 		{
-			name: "nil email service",
+			name: "nil channel registry",
 			input: struct {
-				companyName        string
-				companyDepartments []string
-				db                 databaseService
-				slack              notificationService
-				email              notificationService
-				options            []Option
+				companyName string
+				db          databaseService
+				channels    channelRegistry
+				options     []Option
 			}{
-				companyName:        "Test Company",
-				companyDepartments: []string{"Engineering"},
-				db:                 &mockDatabaseService{},
-				slack:              &mockNotificationService{},
-				email:              nil,
-				options:            []Option{},
+				companyName: "Test Company",
+				db:          &mockDatabaseService{},
+				channels:    nil,
+				options:     []Option{},
 			},
 			wantErr: true,
 		},
 		{
 			name: "with custom logger option",
 			input: struct {
-				companyName        string
-				companyDepartments []string
-				db                 databaseService
-				slack              notificationService
-				email              notificationService
-				options            []Option
+				companyName string
+				db          databaseService
+				channels    channelRegistry
+				options     []Option
 			}{
-				companyName:        "Test Company",
-				companyDepartments: []string{"Engineering"},
-				db:                 &mockDatabaseService{},
-				slack:              &mockNotificationService{},
-				email:              &mockNotificationService{},
-				options:            []Option{WithLogger(&mockLogger{})},
+				companyName: "Test Company",
+				db:          &mockDatabaseService{},
+				channels:    newTestRegistry(t, &mockNotificationService{}),
+				options:     []Option{WithLogger(&mockLogger{})},
 			},
 			wantErr: false,
 		},
@@ -178,7 +105,7 @@ func TestNewService(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got, err := NewService(test.input.companyName, test.input.companyDepartments, test.input.db, test.input.slack, test.input.email, test.input.options...)
+			got, err := NewService(test.input.companyName, test.input.db, test.input.channels, test.input.options...)
 
 			if test.wantErr {
 				if err == nil {
@@ -211,8 +138,11 @@ func TestService_getUserInput(t *testing.T) {
 		name           string
 		service        *service
 		input          string
-		expectedAmount float64
+		expectedAmount money.Money
 		expectedDept   string
+		expectedVendor string
+		expectedDesc   string
+		expectedDue    string
 		expectedMgr    bool
 		wantErr        bool
 	}{
@@ -223,11 +153,14 @@ func TestService_getUserInput(t *testing.T) {
 					name:        "Test Company",
 					departments: []string{"Engineering", "Sales"},
 				},
-				reader: bufio.NewReader(strings.NewReader("100\nEngineering\ny\n")),
+				reader: bufio.NewReader(strings.NewReader("100\nEngineering\nNorthwind\nLaptops\n2026-03-31\ny\n")),
 			},
-			input:          "100\nEngineering\ny\n",
-			expectedAmount: 100,
+			input:          "100\nEngineering\nNorthwind\nLaptops\n2026-03-31\ny\n",
+			expectedAmount: money.New(10000, money.USD),
 			expectedDept:   "Engineering",
+			expectedVendor: "Northwind",
+			expectedDesc:   "Laptops",
+			expectedDue:    "2026-03-31",
 			expectedMgr:    true,
 			wantErr:        false,
 		},
@@ -238,10 +171,10 @@ func TestService_getUserInput(t *testing.T) {
 					name:        "Test Company",
 					departments: []string{"Engineering", "Sales"},
 				},
-				reader: bufio.NewReader(strings.NewReader("\n\n\n")),
+				reader: bufio.NewReader(strings.NewReader("\n\n\n\n\n\n")),
 			},
-			input:          "\n\n\n",
-			expectedAmount: 0,
+			input:          "\n\n\n\n\n\n",
+			expectedAmount: money.New(0, money.USD),
 			expectedDept:   "",
 			expectedMgr:    false,
 			wantErr:        false,
@@ -268,6 +201,15 @@ func TestService_getUserInput(t *testing.T) {
 			if test.service.userInput.department != test.expectedDept {
 				t.Errorf("getUserInput() department = %v, want %v", test.service.userInput.department, test.expectedDept)
 			}
+			if test.service.userInput.vendor != test.expectedVendor {
+				t.Errorf("getUserInput() vendor = %v, want %v", test.service.userInput.vendor, test.expectedVendor)
+			}
+			if test.service.userInput.description != test.expectedDesc {
+				t.Errorf("getUserInput() description = %v, want %v", test.service.userInput.description, test.expectedDesc)
+			}
+			if test.service.userInput.dueDate != test.expectedDue {
+				t.Errorf("getUserInput() due date = %v, want %v", test.service.userInput.dueDate, test.expectedDue)
+			}
 			if test.service.userInput.isManagerApprovalRequired != test.expectedMgr {
 				t.Errorf("getUserInput() manager approval = %v, want %v", test.service.userInput.isManagerApprovalRequired, test.expectedMgr)
 			}
@@ -279,31 +221,37 @@ func TestService_getInvoiceAmount(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    string
-		expected float64
+		expected money.Money
 		wantErr  bool
 	}{
 		{
 			name:     "valid amount",
 			input:    "100.50\n",
-			expected: 100.50,
+			expected: money.New(10050, money.USD),
 			wantErr:  false,
 		},
 		{
 			name:     "empty input",
 			input:    "\n",
-			expected: 0,
+			expected: money.New(0, money.USD),
 			wantErr:  false,
 		},
 		{
 			name:     "invalid amount",
 			input:    "abc\n",
-			expected: 0,
+			expected: money.Money{},
+			wantErr:  true,
+		},
+		{
+			name:     "too many decimal places",
+			input:    "9999.999999\n",
+			expected: money.Money{},
 			wantErr:  true,
 		},
 		{
 			name:     "negative amount",
 			input:    "-50\n",
-			expected: 0,
+			expected: money.Money{},
 			wantErr:  true,
 		},
 	}
@@ -393,6 +341,63 @@ func TestService_getDepartment(t *testing.T) {
 	}
 }
 
+func TestService_getDueDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "valid date",
+			input:    "2026-03-31\n",
+			expected: "2026-03-31",
+			wantErr:  false,
+		},
+		{
+			name:     "empty input",
+			input:    "\n",
+			expected: "",
+			wantErr:  false,
+		},
+		{
+			name:     "invalid date then valid date",
+			input:    "31/03/2026\n2026-04-01\n",
+			expected: "2026-04-01",
+			wantErr:  false,
+		},
+		{
+			name:     "invalid date",
+			input:    "2026-02-30\n",
+			expected: "",
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := &service{
+				reader: bufio.NewReader(strings.NewReader(test.input)),
+			}
+
+			got, err := service.getDueDate()
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("getDueDate() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("getDueDate() unexpected error: %v", err)
+				return
+			}
+			if got != test.expected {
+				t.Errorf("getDueDate() = %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
+
 func TestService_getManagerApprovalRequired(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -505,7 +510,7 @@ func TestService_toInvoiceRequest(t *testing.T) {
 			departments: []string{"Engineering", "Sales"},
 		},
 		userInput: userInput{
-			amount:                    100.50,
+			amount:                    money.New(10050, money.USD),
 			department:                "Engineering",
 			isManagerApprovalRequired: true,
 		},
@@ -513,7 +518,7 @@ func TestService_toInvoiceRequest(t *testing.T) {
 
 	expected := api.InvoiceRequest{
 		CompanyName:               "Test Company",
-		Amount:                    100.50,
+		Amount:                    money.New(10050, money.USD),
 		Department:                "Engineering",
 		IsManagerApprovalRequired: true,
 	}
@@ -547,14 +552,578 @@ func TestService_getCompanyDepartments(t *testing.T) {
 	}
 }
 
+func TestService_ValidateCompany(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *mockDatabaseService
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "loads stored departments",
+			db: &mockDatabaseService{
+				company: db.Company{ID: 1, Name: "Test Company"},
+				departments: []db.Department{
+					{ID: 1, CompanyID: 1, Name: "Engineering"},
+					{ID: 2, CompanyID: 1, Name: "Sales"},
+				},
+			},
+			want: []string{"Engineering", "Sales"},
+		},
+		{
+			name: "company without departments",
+			db: &mockDatabaseService{
+				company: db.Company{ID: 1, Name: "Test Company"},
+			},
+			want: []string{},
+		},
+		{
+			name: "company not found",
+			db: &mockDatabaseService{
+				companyErr: db.ErrCompanyNotFound,
+			},
+			wantErr: true,
+		},
+		{
+			name: "departments query fails",
+			db: &mockDatabaseService{
+				company:        db.Company{ID: 1, Name: "Test Company"},
+				departmentsErr: errors.New("query failed"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := &service{
+				log:     &mockLogger{},
+				company: company{name: "Test Company"},
+				db:      test.db,
+			}
+
+			err := svc.ValidateCompany()
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("ValidateCompany() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateCompany() unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(test.want, svc.getCompanyDepartments()); diff != "" {
+				t.Errorf("ValidateCompany() departments mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_getDepartment_NoDepartments(t *testing.T) {
+	svc := &service{
+		company: company{name: "Test Company"},
+		reader:  bufio.NewReader(strings.NewReader("Engineering\n")),
+	}
+
+	got, err := svc.getDepartment()
+	if err != nil {
+		t.Fatalf("getDepartment() unexpected error: %v", err)
+	}
+	if got != "" {
+		t.Errorf("getDepartment() = %q, want empty department", got)
+	}
+}
+
+func TestService_ProcessInvoice(t *testing.T) {
+	tests := []struct {
+		name           string
+		invoice        api.InvoiceRequest
+		wantDepartment string
+		wantFields     []string
+	}{
+		{
+			name:           "valid invoice with department in another case",
+			invoice:        api.InvoiceRequest{Amount: money.New(1500000, "usd"), Department: "marketing"},
+			wantDepartment: "Marketing",
+		},
+		{
+			name: "invalid fields",
+			invoice: api.InvoiceRequest{
+				CompanyName: "Other Company",
+				Amount:      money.New(0, money.USD),
+				Department:  "Legal",
+				DueDate:     "tomorrow",
+			},
+			wantFields: []string{"amount", "due_date", "company_name", "department"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sender := &flakySender{}
+			svc := &service{
+				log:      &mockLogger{},
+				company:  company{name: "Test Company", departments: []string{"Marketing", "Finance"}},
+				channels: newTestRegistry(t, sender),
+				db: &mockDatabaseService{
+					company:  db.Company{ID: 1, Name: "Test Company"},
+					approver: db.Approver{ID: 3, CompanyID: 1, Name: "Sarah Johnson", Role: "CMO", SlackID: "U345678"},
+					rule:     db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack"},
+				},
+				retry: RetryPolicy{MaxAttempts: 1, InitialBackoff: time.Second, MaxBackoff: time.Minute},
+				now:   time.Now,
+			}
+
+			_, err := svc.ProcessInvoice(test.invoice)
+			if test.wantFields != nil {
+				var verr *api.ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("ProcessInvoice() error = %v, want a validation error", err)
+				}
+				var gotFields []string
+				for _, field := range verr.Fields {
+					gotFields = append(gotFields, field.Field)
+				}
+				if diff := cmp.Diff(test.wantFields, gotFields); diff != "" {
+					t.Errorf("ProcessInvoice() invalid fields mismatch (-want +got)\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessInvoice() unexpected error: %v", err)
+			}
+			if len(sender.sent) != 1 {
+				t.Fatalf("sent %d notifications, want 1", len(sender.sent))
+			}
+			if got := sender.sent[0].Invoice; got.Department != test.wantDepartment || got.Amount.Currency != money.USD {
+				t.Errorf("sent invoice = %+v, want department %s in USD", got, test.wantDepartment)
+			}
+		})
+	}
+}
+
+func TestService_RecordDecision(t *testing.T) {
+	decidedAt := time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC)
+	pending := db.ApprovalRequest{
+		ID:              7,
+		CompanyID:       1,
+		WorkflowRuleID:  1,
+		ApproverID:      3,
+		Amount:          1500000,
+		Currency:        "USD",
+		Department:      stringPtr("Finance"),
+		ApprovalChannel: "slack",
+		Status:          db.ApprovalStatusPending,
+	}
+	cfo := db.Approver{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", SlackID: "U345678"}
+	submittedByCFO := pending
+	submittedByCFO.SubmittedBy = stringPtr("amanda@light.com")
+
+	tests := []struct {
+		name        string
+		db          *mockDatabaseService
+		decision    api.ApprovalDecision
+		want        api.ApprovalRequest
+		wantDecided []string
+		wantErr     error
+	}{
+		{
+			name: "approval by the assigned approver",
+			db: &mockDatabaseService{
+				company:         db.Company{ID: 1, Name: "Test Company"},
+				approvalRequest: pending,
+				approver:        cfo,
+			},
+			decision: api.ApprovalDecision{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusApproved,
+				Channel:           "slack",
+				DecidedBy:         "U345678",
+				DecidedAt:         decidedAt,
+			},
+			want: api.ApprovalRequest{
+				ID:      7,
+				Company: "Test Company",
+				Approver: api.Approver{
+					ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", SlackID: "U345678",
+				},
+				Invoice: api.InvoiceDetails{
+					Amount:     money.New(1500000, money.USD),
+					Department: "Finance",
+				},
+			},
+			wantDecided: []string{"approved by U345678 at 2026-01-02T16:00:00Z"},
+		},
+		{
+			name: "decision without a time is decided now",
+			db: &mockDatabaseService{
+				company:         db.Company{ID: 1, Name: "Test Company"},
+				approvalRequest: pending,
+				approver:        cfo,
+			},
+			decision: api.ApprovalDecision{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusApproved,
+				Channel:           "slack",
+				DecidedBy:         "U345678",
+			},
+			want: api.ApprovalRequest{
+				ID:      7,
+				Company: "Test Company",
+				Approver: api.Approver{
+					ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", SlackID: "U345678",
+				},
+				Invoice: api.InvoiceDetails{
+					Amount:     money.New(1500000, money.USD),
+					Department: "Finance",
+				},
+			},
+			wantDecided: []string{"approved by U345678 at 2026-01-02T17:00:00Z"},
+		},
+		{
+			name: "email address matched ignoring case",
+			db: &mockDatabaseService{
+				company:         db.Company{ID: 1, Name: "Test Company"},
+				approvalRequest: pending,
+				approver:        cfo,
+			},
+			decision: api.ApprovalDecision{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusRejected,
+				Channel:           "email",
+				DecidedBy:         "Amanda@Light.com",
+				DecidedAt:         decidedAt,
+			},
+			want: api.ApprovalRequest{
+				ID:      7,
+				Company: "Test Company",
+				Approver: api.Approver{
+					ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", SlackID: "U345678",
+				},
+				Invoice: api.InvoiceDetails{
+					Amount:     money.New(1500000, money.USD),
+					Department: "Finance",
+				},
+			},
+			wantDecided: []string{"rejected by Amanda@Light.com at 2026-01-02T16:00:00Z"},
+		},
+		{
+			name: "decision by someone else",
+			db: &mockDatabaseService{
+				company:         db.Company{ID: 1, Name: "Test Company"},
+				approvalRequest: pending,
+				approver:        cfo,
+			},
+			decision: api.ApprovalDecision{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusRejected,
+				Channel:           "slack",
+				DecidedBy:         "U999999",
+			},
+			wantErr: ErrNotAssignedApprover,
+		},
+		{
+			name: "decision by the submitter",
+			db: &mockDatabaseService{
+				company:         db.Company{ID: 1, Name: "Test Company"},
+				approvalRequest: submittedByCFO,
+				approver:        cfo,
+			},
+			decision: api.ApprovalDecision{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusApproved,
+				Channel:           "slack",
+				DecidedBy:         "U345678",
+			},
+			wantErr: ErrSelfApproval,
+		},
+		{
+			name: "decision by a user who submitted the invoice",
+			db: &mockDatabaseService{
+				company: db.Company{ID: 1, Name: "Test Company"},
+				approvalRequest: db.ApprovalRequest{
+					ID: 7, CompanyID: 1, WorkflowRuleID: 1, ApproverID: 3, Amount: 1500000, Currency: "USD",
+					ApprovalChannel: "slack", Status: db.ApprovalStatusPending, SubmittedBy: stringPtr("erik@light.com"),
+				},
+				approver: cfo,
+			},
+			decision: api.ApprovalDecision{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusApproved,
+				Channel:           "slack",
+				DecidedBy:         "U345678",
+				User:              "Erik@light.com",
+			},
+			wantErr: ErrSelfApproval,
+		},
+		{
+			name: "invalid decision",
+			db:   &mockDatabaseService{},
+			decision: api.ApprovalDecision{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusPending,
+				Channel:           "slack",
+				DecidedBy:         "U345678",
+			},
+			wantErr: api.ErrInvalidApprovalStatus,
+		},
+		{
+			name: "approval request not found",
+			db: &mockDatabaseService{
+				company:            db.Company{ID: 1, Name: "Test Company"},
+				approvalRequestErr: db.ErrApprovalRequestNotFound,
+			},
+			decision: api.ApprovalDecision{
+				ApprovalRequestID: 99,
+				Status:            api.ApprovalStatusApproved,
+				Channel:           "slack",
+				DecidedBy:         "U345678",
+			},
+			wantErr: db.ErrApprovalRequestNotFound,
+		},
+		{
+			name: "approval request already decided",
+			db: &mockDatabaseService{
+				company:         db.Company{ID: 1, Name: "Test Company"},
+				approvalRequest: pending,
+				approver:        cfo,
+				decideErr:       db.ErrApprovalRequestAlreadyDecided,
+			},
+			decision: api.ApprovalDecision{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusApproved,
+				Channel:           "slack",
+				DecidedBy:         "U345678",
+			},
+			wantErr: db.ErrApprovalRequestAlreadyDecided,
+		},
+		{
+			name: "decision over an unregistered channel",
+			db: &mockDatabaseService{
+				company:         db.Company{ID: 1, Name: "Test Company"},
+				approvalRequest: pending,
+				approver:        cfo,
+			},
+			decision: api.ApprovalDecision{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusApproved,
+				Channel:           "teams",
+				DecidedBy:         "U345678",
+			},
+			wantErr: notification.ErrUnknownChannel,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := &service{
+				log:      &mockLogger{},
+				company:  company{name: "Test Company"},
+				db:       test.db,
+				channels: newTestRegistry(t, &mockNotificationService{}),
+				now:      func() time.Time { return decidedAt.Add(time.Hour) },
+			}
+
+			got, err := svc.RecordDecision(test.decision)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("RecordDecision() error = %v, want %v", err, test.wantErr)
+				}
+				if len(test.db.decided) != 0 {
+					t.Errorf("RecordDecision() recorded %v, want no decision", test.db.decided)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RecordDecision() unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("RecordDecision() mismatch (-want +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantDecided, test.db.decided); diff != "" {
+				t.Errorf("RecordDecision() stored decision mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_routeApprover(t *testing.T) {
+	cfo := db.Approver{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", SlackID: "U345678"}
+	withAlternate := cfo
+	withAlternate.AlternateApproverID = intPtr(4)
+	withAlternate.ManagerID = intPtr(5)
+	withAlternateOnly := withAlternate
+	withAlternateOnly.ManagerID = nil
+	// The alternate is the CFO's second approver account.
+	alternate := db.Approver{ID: 4, CompanyID: 1, Name: "Amanda Svensson (EA)", Role: "CFO", Email: "Amanda@light.com", SlackID: "U444444"}
+	manager := db.Approver{ID: 5, CompanyID: 1, Name: "Erik Lind", Role: "CEO", Email: "erik@light.com", SlackID: "U555555"}
+
+	tests := []struct {
+		name      string
+		approver  db.Approver
+		submitter string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "approver did not submit the invoice",
+			approver:  withAlternate,
+			submitter: "vera@light.com",
+			want:      "Amanda Svensson",
+		},
+		{
+			name:      "alternate submitted the invoice too",
+			approver:  withAlternate,
+			submitter: "amanda@light.com",
+			want:      "Erik Lind",
+		},
+		{
+			name:      "no replacement other than the submitter",
+			approver:  withAlternateOnly,
+			submitter: "amanda@light.com",
+			wantErr:   ErrNoIndependentApprover,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := &service{
+				log: &mockLogger{},
+				db: &mockDatabaseService{
+					approver:     test.approver,
+					replacements: map[int]db.Approver{4: alternate, 5: manager},
+				},
+				channels: newTestRegistry(t, &mockNotificationService{}),
+			}
+
+			got, err := svc.routeApprover(db.WorkflowRule{ID: 1, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack"}, []string{"slack"}, test.submitter)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("routeApprover() error = %v, want %v", err, test.wantErr)
+			}
+			if test.wantErr == nil && got.approver.Name != test.want {
+				t.Errorf("routeApprover() = %s, want %s", got.approver.Name, test.want)
+			}
+		})
+	}
+}
+
+func TestDecisionRefused(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "not the assigned approver", err: ErrNotAssignedApprover, want: true},
+		{name: "already decided", err: fmt.Errorf("approval request 7: %w", db.ErrApprovalRequestAlreadyDecided), want: true},
+		{name: "permission denied", err: &api.PermissionDeniedError{User: "auditor@light.com", Role: api.RoleViewer, Action: api.ActionDecide}, want: true},
+		{name: "database unavailable", err: errors.New("database is locked"), want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DecisionRefused(test.err); got != test.want {
+				t.Errorf("DecisionRefused(%v) = %v, want %v", test.err, got, test.want)
+			}
+		})
+	}
+}
+
+func TestService_ApprovalRequestStatus(t *testing.T) {
+	decidedAt := time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		db      *mockDatabaseService
+		want    api.ApprovalRequestStatus
+		wantErr error
+	}{
+		{
+			name: "pending",
+			db: &mockDatabaseService{
+				company:         db.Company{ID: 1, Name: "Test Company"},
+				approvalRequest: db.ApprovalRequest{ID: 7, CompanyID: 1, ApprovalChannel: "slack", Status: db.ApprovalStatusPending},
+			},
+			want: api.ApprovalRequestStatus{ApprovalRequestID: 7, Status: api.ApprovalStatusPending},
+		},
+		{
+			name: "approved",
+			db: &mockDatabaseService{
+				company: db.Company{ID: 1, Name: "Test Company"},
+				approvalRequest: db.ApprovalRequest{
+					ID:               7,
+					CompanyID:        1,
+					ApprovalChannel:  "slack",
+					DeliveredChannel: stringPtr("email"),
+					Status:           db.ApprovalStatusApproved,
+					DecidedBy:        stringPtr("amanda@light.com"),
+					DecidedAt:        stringPtr("2026-01-02T16:00:00Z"),
+				},
+			},
+			want: api.ApprovalRequestStatus{
+				ApprovalRequestID: 7,
+				Status:            api.ApprovalStatusApproved,
+				DeliveredChannel:  "email",
+				DecidedBy:         "amanda@light.com",
+				DecidedAt:         &decidedAt,
+			},
+		},
+		{
+			name: "not found",
+			db: &mockDatabaseService{
+				company:            db.Company{ID: 1, Name: "Test Company"},
+				approvalRequestErr: db.ErrApprovalRequestNotFound,
+			},
+			wantErr: db.ErrApprovalRequestNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := &service{
+				log:     &mockLogger{},
+				company: company{name: "Test Company"},
+				db:      test.db,
+			}
+
+			got, err := svc.ApprovalRequestStatus(7)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("ApprovalRequestStatus() error = %v, want %v", err, test.wantErr)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ApprovalRequestStatus() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
 // Mock implementations for testing
 type mockDatabaseService struct {
 	company     db.Company
+	departments []db.Department
 	approver    db.Approver
 	rule        db.WorkflowRule
-	companyErr  error
-	approverErr error
-	ruleErr     error
+	// replacements are the approvers found by ID other than approver.
+	replacements   map[int]db.Approver
+	companyErr     error
+	departmentsErr error
+	approverErr    error
+	ruleErr        error
+	// Approval requests
+	approvalRequest    db.ApprovalRequest
+	approvalRequestErr error
+	decideErr          error
+	decided            []string
+	// duplicate is the approval request found for any invoice.
+	duplicate *db.ApprovalRequest
+	// messageRequests are the approval requests found for any message.
+	messageRequests []db.ApprovalRequest
+	// Notification outbox
+	outbox []db.OutboxMessage
+	// deliveredChannel is the channel the approval request was recorded as
+	// delivered over.
+	deliveredChannel string
 }
 
 func (m *mockDatabaseService) GetCompanyByName(name string) (db.Company, error) {
@@ -564,20 +1133,105 @@ func (m *mockDatabaseService) GetCompanyByName(name string) (db.Company, error)
 	return m.company, nil
 }
 
-func (m *mockDatabaseService) GetApproverByID(id int) (db.Approver, error) {
+func (m *mockDatabaseService) ListDepartments(companyID int) ([]db.Department, error) {
+	if m.departmentsErr != nil {
+		return nil, m.departmentsErr
+	}
+	return m.departments, nil
+}
+
+func (m *mockDatabaseService) GetApproverByID(companyID, id int) (db.Approver, error) {
 	if m.approverErr != nil {
 		return db.Approver{}, m.approverErr
 	}
+	if replacement, ok := m.replacements[id]; ok {
+		return replacement, nil
+	}
 	return m.approver, nil
 }
 
-func (m *mockDatabaseService) FindMatchingRule(companyID int, amount float64, department string, requiresManager bool) (db.WorkflowRule, error) {
+func (m *mockDatabaseService) ListApprovers(companyID int) ([]db.Approver, error) {
+	if m.approverErr != nil {
+		return nil, m.approverErr
+	}
+	return []db.Approver{m.approver}, nil
+}
+
+func (m *mockDatabaseService) FindMatchingRule(companyID int, amount money.Money, department string, requiresManager bool) (db.WorkflowRule, error) {
 	if m.ruleErr != nil {
 		return db.WorkflowRule{}, m.ruleErr
 	}
 	return m.rule, nil
 }
 
+func (m *mockDatabaseService) CreateApprovalRequestWithNotification(request db.ApprovalRequest, message db.OutboxMessage) (db.ApprovalRequest, db.OutboxMessage, error) {
+	if m.approvalRequestErr != nil {
+		return db.ApprovalRequest{}, db.OutboxMessage{}, m.approvalRequestErr
+	}
+	request.ID = len(m.outbox) + 1
+	request.Status = db.ApprovalStatusPending
+	message.ID = len(m.outbox) + 1
+	message.CompanyID = request.CompanyID
+	message.ApprovalRequestID = request.ID
+	message.Status = db.OutboxStatusPending
+	m.outbox = append(m.outbox, message)
+	return request, message, nil
+}
+
+func (m *mockDatabaseService) ClaimDueOutboxMessages(companyID int, now, claimedUntil string, limit int) ([]db.OutboxMessage, error) {
+	var due []db.OutboxMessage
+	for i, message := range m.outbox {
+		claimed := message.ClaimedUntil != nil && *message.ClaimedUntil > now
+		if message.Status == db.OutboxStatusPending && message.NextAttemptAt <= now && !claimed && len(due) < limit {
+			m.outbox[i].ClaimedUntil = &claimedUntil
+			due = append(due, m.outbox[i])
+		}
+	}
+	return due, nil
+}
+
+func (m *mockDatabaseService) UpdateOutboxMessage(message db.OutboxMessage) error {
+	for i := range m.outbox {
+		if m.outbox[i].ID == message.ID {
+			message.ClaimedUntil = nil
+			m.outbox[i] = message
+			return nil
+		}
+	}
+	return db.ErrOutboxMessageNotFound
+}
+
+func (m *mockDatabaseService) GetApprovalRequestByID(companyID, id int) (db.ApprovalRequest, error) {
+	if m.approvalRequestErr != nil {
+		return db.ApprovalRequest{}, m.approvalRequestErr
+	}
+	return m.approvalRequest, nil
+}
+
+func (m *mockDatabaseService) FindDuplicateApprovalRequest(request db.ApprovalRequest, since string) (db.ApprovalRequest, error) {
+	if m.duplicate == nil || m.duplicate.CreatedAt < since {
+		return db.ApprovalRequest{}, db.ErrApprovalRequestNotFound
+	}
+	return *m.duplicate, nil
+}
+
+func (m *mockDatabaseService) ListApprovalRequestsByMessageID(companyID int, channel, messageID string) ([]db.ApprovalRequest, error) {
+	return m.messageRequests, nil
+}
+
+func (m *mockDatabaseService) SetApprovalRequestDelivery(companyID, id int, channel, conversationID, messageID string) error {
+	m.deliveredChannel = channel
+	return nil
+}
+
+func (m *mockDatabaseService) DecideApprovalRequest(companyID, id int, status, decidedBy, decidedAt string) error {
+	if m.decideErr != nil {
+		return m.decideErr
+	}
+	m.decided = append(m.decided, status+" by "+decidedBy+" at "+decidedAt)
+	return nil
+}
+
 type mockNotificationService struct {
 	response api.ApprovalResponse
 	err      error
@@ -590,6 +1244,20 @@ func (m *mockNotificationService) SendApprovalRequest(approvalRequest api.Approv
 	return m.response, nil
 }
 
+// newTestRegistry returns a registry with a slack and an email channel
+// that both send over sender.
+func newTestRegistry(t *testing.T, sender notification.Sender) *notification.Registry {
+	t.Helper()
+	registry, err := notification.NewRegistry(
+		notification.Channel{Name: "slack", Sender: sender, ContactID: func(a api.Approver) string { return a.SlackID }},
+		notification.Channel{Name: "email", Sender: sender, ContactID: func(a api.Approver) string { return a.Email }},
+	)
+	if err != nil {
+		t.Fatalf("NewRegistry() unexpected error: %v", err)
+	}
+	return registry
+}
+
 type mockLogger struct{}
 
 func (m *mockLogger) Info(msg string, args ...any)  {}
@@ -0,0 +1,92 @@
+package workflow
+
+import (
+	"errors"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+)
+
+// defaultDedupWindow is how long an invoice is not sent to the same
+// approver again.
+const defaultDedupWindow = 24 * time.Hour
+
+// Suppressed counts the notifications the service suppressed since it
+// started.
+type Suppressed struct {
+	// Duplicates counts the invoices that were not sent for approval
+	// because an approval request for the same invoice and approver was
+	// made within the dedup window.
+	Duplicates int
+	// RateLimited counts the sends that were suppressed, and deferred,
+	// because the approver reached the rate limit of the channel.
+	RateLimited int
+}
+
+// Suppressed returns the counts of the notifications suppressed since the
+// service started.
+func (s *service) Suppressed() Suppressed {
+	s.dispatchMu.Lock()
+	defer s.dispatchMu.Unlock()
+	return s.suppressed
+}
+
+// findDuplicate returns the approval request for the same invoice and
+// approver as request that was made within the dedup window, if any. The
+// caller must hold dispatchMu.
+func (s *service) findDuplicate(request db.ApprovalRequest) (db.ApprovalRequest, bool, error) {
+	if s.dedupWindow <= 0 {
+		return db.ApprovalRequest{}, false, nil
+	}
+
+	since := s.now().Add(-s.dedupWindow).UTC().Format(time.RFC3339)
+	duplicate, err := s.db.FindDuplicateApprovalRequest(request, since)
+	if errors.Is(err, db.ErrApprovalRequestNotFound) {
+		return db.ApprovalRequest{}, false, nil
+	}
+	if err != nil {
+		s.log.Error("failed to look up duplicate approval requests", "approver_id", request.ApproverID, "error", err)
+		return db.ApprovalRequest{}, false, err
+	}
+
+	s.suppressed.Duplicates++
+	s.log.Info("duplicate approval request suppressed",
+		"approval_request_id", duplicate.ID,
+		"approver_id", duplicate.ApproverID,
+		"created_at", duplicate.CreatedAt,
+		"duplicates", s.suppressed.Duplicates,
+	)
+	return duplicate, true, nil
+}
+
+// deferOutboxMessage reschedules an outbox message whose send was
+// suppressed by the rate limit of its channel, for when the approver can
+// receive a notification on the channel again. The suppressed send does
+// not count as a delivery attempt. The caller must have claimed the
+// message.
+func (s *service) deferOutboxMessage(message db.OutboxMessage, sendErr error) {
+	retryAfter := s.retry.InitialBackoff
+	var limitErr *notification.RateLimitError
+	if errors.As(sendErr, &limitErr) && limitErr.RetryAfter > 0 {
+		retryAfter = limitErr.RetryAfter
+	}
+	message.Status = db.OutboxStatusPending
+	message.NextAttemptAt = s.now().Add(retryAfter).UTC().Format(time.RFC3339)
+
+	s.dispatchMu.Lock()
+	s.suppressed.RateLimited++
+	rateLimited := s.suppressed.RateLimited
+	s.dispatchMu.Unlock()
+	s.log.Info("notification suppressed by rate limit, deferred",
+		"outbox_message_id", message.ID,
+		"approval_request_id", message.ApprovalRequestID,
+		"channel", message.Channel,
+		"next_attempt_at", message.NextAttemptAt,
+		"rate_limited", rateLimited,
+	)
+
+	if err := s.db.UpdateOutboxMessage(message); err != nil {
+		s.log.Error("failed to defer rate limited notification", "outbox_message_id", message.ID, "error", err)
+	}
+}
@@ -0,0 +1,150 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KatrinSalt/backend-challenge-go/api"
+	"github.com/KatrinSalt/backend-challenge-go/db"
+	"github.com/KatrinSalt/backend-challenge-go/money"
+	"github.com/KatrinSalt/backend-challenge-go/notification"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestService_processInvoice_Duplicate(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		dedupWindow    time.Duration
+		duplicate      *db.ApprovalRequest
+		wantDuplicate  bool
+		wantID         int
+		wantSends      int
+		wantSuppressed Suppressed
+	}{
+		{
+			name:           "duplicate within the window is not sent",
+			dedupWindow:    time.Hour,
+			duplicate:      &db.ApprovalRequest{ID: 7, ApproverID: 3, CreatedAt: "2026-01-02T14:30:00Z"},
+			wantDuplicate:  true,
+			wantID:         7,
+			wantSuppressed: Suppressed{Duplicates: 1},
+		},
+		{
+			name:        "duplicate before the window is sent again",
+			dedupWindow: time.Hour,
+			duplicate:   &db.ApprovalRequest{ID: 7, ApproverID: 3, CreatedAt: "2026-01-02T13:30:00Z"},
+			wantID:      1,
+			wantSends:   1,
+		},
+		{
+			name:        "deduplication turned off",
+			dedupWindow: 0,
+			duplicate:   &db.ApprovalRequest{ID: 7, ApproverID: 3, CreatedAt: "2026-01-02T14:30:00Z"},
+			wantID:      1,
+			wantSends:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sender := &flakySender{}
+			database := &mockDatabaseService{
+				company:   db.Company{ID: 1, Name: "Test Company"},
+				approver:  db.Approver{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", SlackID: "U345678"},
+				rule:      db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack"},
+				duplicate: test.duplicate,
+			}
+			svc := &service{
+				log:         &mockLogger{},
+				company:     company{name: "Test Company"},
+				db:          database,
+				channels:    newTestRegistry(t, sender),
+				retry:       RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Minute},
+				dedupWindow: test.dedupWindow,
+				now:         func() time.Time { return now },
+			}
+
+			resp, err := svc.processInvoice(api.InvoiceRequest{CompanyName: "Test Company", Amount: money.New(1500000, money.USD)})
+			if err != nil {
+				t.Fatalf("processInvoice() unexpected error: %v", err)
+			}
+			if resp.Duplicate != test.wantDuplicate || resp.ApprovalRequestID != test.wantID {
+				t.Errorf("processInvoice() = %+v, want duplicate %v of request %d", resp, test.wantDuplicate, test.wantID)
+			}
+			if len(sender.sent) != test.wantSends {
+				t.Errorf("sent %d notifications, want %d", len(sender.sent), test.wantSends)
+			}
+			if len(database.outbox) != test.wantSends {
+				t.Errorf("outbox has %d messages, want %d", len(database.outbox), test.wantSends)
+			}
+			if diff := cmp.Diff(test.wantSuppressed, svc.Suppressed()); diff != "" {
+				t.Errorf("Suppressed() mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_DispatchOutbox_RateLimited(t *testing.T) {
+	start := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	limitErr := &notification.RateLimitError{Channel: "slack", ContactID: "U345678", RetryAfter: 30 * time.Second}
+
+	now := start
+	slackSender := &flakySender{errs: []error{limitErr}}
+	emailSender := &flakySender{}
+	registry, err := notification.NewRegistry(
+		notification.Channel{Name: "slack", Sender: slackSender, ContactID: func(a api.Approver) string { return a.SlackID }},
+		notification.Channel{Name: "email", Sender: emailSender, ContactID: func(a api.Approver) string { return a.Email }},
+	)
+	if err != nil {
+		t.Fatalf("NewRegistry() unexpected error: %v", err)
+	}
+	database := &mockDatabaseService{
+		company:  db.Company{ID: 1, Name: "Test Company"},
+		approver: db.Approver{ID: 3, CompanyID: 1, Name: "Amanda Svensson", Role: "CFO", Email: "amanda@light.com", SlackID: "U345678"},
+		rule:     db.WorkflowRule{ID: 4, CompanyID: 1, ApproverID: 3, ApprovalChannel: "slack", FallbackChannels: "email"},
+	}
+	svc := &service{
+		log:      &mockLogger{},
+		company:  company{name: "Test Company"},
+		db:       database,
+		channels: registry,
+		retry:    RetryPolicy{MaxAttempts: 1, InitialBackoff: time.Second, MaxBackoff: time.Minute},
+		now:      func() time.Time { return now },
+	}
+
+	resp, err := svc.processInvoice(api.InvoiceRequest{CompanyName: "Test Company", Amount: money.New(1500000, money.USD)})
+	if err != nil {
+		t.Fatalf("processInvoice() unexpected error: %v", err)
+	}
+	if !resp.Queued {
+		t.Errorf("processInvoice() queued = false, want true")
+	}
+
+	// The suppressed send neither uses up the only attempt nor falls back
+	// to email.
+	message := database.outbox[0]
+	if message.Status != db.OutboxStatusPending || message.Attempts != 0 || message.NextAttemptAt != "2026-01-02T15:00:30Z" {
+		t.Errorf("outbox message = %+v, want pending with no attempts until 15:00:30", message)
+	}
+	if len(emailSender.sent) != 0 {
+		t.Errorf("sent %d email notifications, want 0", len(emailSender.sent))
+	}
+
+	var results []DispatchResult
+	for _, at := range []time.Duration{29 * time.Second, 30 * time.Second} {
+		now = start.Add(at)
+		result, err := svc.DispatchOutbox()
+		if err != nil {
+			t.Fatalf("DispatchOutbox() unexpected error: %v", err)
+		}
+		results = append(results, result)
+	}
+	if diff := cmp.Diff([]DispatchResult{{}, {Delivered: 1}}, results); diff != "" {
+		t.Errorf("DispatchOutbox() mismatch (-want +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(Suppressed{RateLimited: 1}, svc.Suppressed()); diff != "" {
+		t.Errorf("Suppressed() mismatch (-want +got)\n%s", diff)
+	}
+}